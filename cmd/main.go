@@ -13,6 +13,7 @@ import (
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
 	"github.com/horizon-connect-eu/go-taf/pkg/crypto"
 	"github.com/horizon-connect-eu/go-taf/pkg/manager"
+	"github.com/horizon-connect-eu/go-taf/pkg/tlee/differential"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustassessment"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel"
 	"github.com/horizon-connect-eu/go-taf/pkg/web"
@@ -115,6 +116,11 @@ func main() {
 	trustModelManager.SetManagers(managers)
 	trustSourceManager.SetManagers(managers)
 
+	if tafConfig.Debug.RecordTLEEInputs != "" {
+		logger.Info("Recording TLEE inputs", "Directory", tafConfig.Debug.RecordTLEEInputs)
+		trustAssessmentManager.AddTMIListener(differential.NewRecorder(tafConfig.Debug.RecordTLEEInputs, logging.CreateChildLogger(logger, "TLEE Recorder")))
+	}
+
 	//Let's go
 	go communicationInterface.Run()
 	go trustAssessmentManager.Run()
@@ -4,6 +4,7 @@ package main
 
 import _ "github.com/horizon-connect-eu/go-taf/plugins/communication/filebased"
 import _ "github.com/horizon-connect-eu/go-taf/plugins/communication/kafkabased"
+import _ "github.com/horizon-connect-eu/go-taf/plugins/tlee/tleeimplementation"
 import _ "github.com/horizon-connect-eu/go-taf/plugins/trustmodels/brussels"
 import _ "github.com/horizon-connect-eu/go-taf/plugins/trustmodels/brussels/v0_0_1"
 import _ "github.com/horizon-connect-eu/go-taf/plugins/trustmodels/examplemodel"
@@ -2,17 +2,22 @@ package command
 
 import (
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
 )
 
 /*
 HandleTMIUpdate contains 1 or more update operations to be applied on a specified Trust Model Instance.
 In case of more update operations, a worker should apply micro-batching and apply all updates before proceeding (e.g., calling the TLEE).
+If ForceEvaluation is set, the worker computes and reports a new result set even if the updates did not change the TMI.
+PrecedingTags contains the tags of earlier commands that have been coalesced into this command, oldest first.
 */
 type HandleTMIUpdate struct {
-	commandType core.CommandType
-	FullTmiID   string
-	Updates     []core.Update
-	Tag         *string
+	commandType     core.CommandType
+	FullTmiID       string
+	Updates         []core.Update
+	Tag             *string
+	PrecedingTags   []string
+	ForceEvaluation bool
 }
 
 func CreateHandleTMIUpdate(FullTmiID string, tag *string, updates ...core.Update) HandleTMIUpdate {
@@ -30,18 +35,21 @@ func (r HandleTMIUpdate) Type() core.CommandType {
 
 /*
 HandleTMIInit is a command that initiates the existence of a Trust Model Instance for a TAM worker.
+RTLs contains the RTL overrides of the session that apply to the TMI (see HandleRTLUpdate).
 */
 type HandleTMIInit struct {
 	commandType core.CommandType
 	FullTmiID   string
 	TMI         core.TrustModelInstance
+	RTLs        map[string]subjectivelogic.QueryableOpinion
 }
 
-func CreateHandleTMIInit(fullTMIid string, TMI core.TrustModelInstance) HandleTMIInit {
+func CreateHandleTMIInit(fullTMIid string, TMI core.TrustModelInstance, rtls map[string]subjectivelogic.QueryableOpinion) HandleTMIInit {
 	return HandleTMIInit{
 
 		TMI:         TMI,
 		FullTmiID:   fullTMIid,
+		RTLs:        rtls,
 		commandType: core.HANDLE_TMI_INIT,
 	}
 }
@@ -69,6 +77,30 @@ func (r HandleTMIDestroy) Type() core.CommandType {
 	return r.commandType
 }
 
+/*
+HandleRTLUpdate is a command that replaces the RTL overrides of a Trust Model Instance. RTLs is keyed by proposition; the
+entry with the empty key applies to all propositions without an entry of their own, and propositions without any override
+use the RTL of the TMI. The worker re-evaluates the trust decisions of the TMI against its latest ATLs without running the
+TLEE.
+*/
+type HandleRTLUpdate struct {
+	commandType core.CommandType
+	FullTmiID   string
+	RTLs        map[string]subjectivelogic.QueryableOpinion
+}
+
+func CreateHandleRTLUpdate(fullTMI string, rtls map[string]subjectivelogic.QueryableOpinion) HandleRTLUpdate {
+	return HandleRTLUpdate{
+		FullTmiID:   fullTMI,
+		RTLs:        rtls,
+		commandType: core.HANDLE_RTL_UPDATE,
+	}
+}
+
+func (r HandleRTLUpdate) Type() core.CommandType {
+	return r.commandType
+}
+
 /*
 HandleATLUpdate is a command sent from a TAM worker to the TAM that contains new ATL results to be cached by the TAM.
 */
@@ -93,6 +125,31 @@ func (r HandleATLUpdate) Type() core.CommandType {
 	return r.commandType
 }
 
+/*
+HandleTMIUpdateApplied is a command sent from a TAM worker to the TAM once a tagged update has been applied to a TMI
+without resulting in a new ATL result set (i.e., the update did not change the TMI). It allows the TAM to keep track of
+tags that have been reflected by a TMI.
+*/
+type HandleTMIUpdateApplied struct {
+	commandType core.CommandType
+	FullTmiID   string
+	Version     int
+	Tag         *string
+}
+
+func CreateHandleTMIUpdateApplied(fullTMI string, version int, tag *string) HandleTMIUpdateApplied {
+	return HandleTMIUpdateApplied{
+		FullTmiID:   fullTMI,
+		Version:     version,
+		commandType: core.HANDLE_TMI_UPDATE_APPLIED,
+		Tag:         tag,
+	}
+}
+
+func (r HandleTMIUpdateApplied) Type() core.CommandType {
+	return r.commandType
+}
+
 type ObserverEvent uint16
 
 const (
@@ -100,28 +157,58 @@ const (
 	EVENT_NODE_REMOVED
 )
 
-type ObserverSource uint16
-
-const (
-	SOURCE_V2X ObserverSource = iota
-	SOURCE_TCH
-)
-
 type HandleObserverEvent struct {
 	commandType core.CommandType
 	Identifier  string
 	Event       ObserverEvent
-	Source      ObserverSource
+	Trigger     core.SpawnTrigger
 }
 
-func CreateHandleObserverEvent(identifier string, event ObserverEvent, source ObserverSource) HandleObserverEvent {
+func CreateHandleObserverEvent(identifier string, event ObserverEvent, trigger core.SpawnTrigger) HandleObserverEvent {
 	return HandleObserverEvent{
 		commandType: core.HANDLE_OBSERVER_EVENT,
 		Identifier:  identifier,
 		Event:       event,
-		Source:      source,
+		Trigger:     trigger,
 	}
 }
 func (r HandleObserverEvent) Type() core.CommandType {
 	return r.commandType
 }
+
+/*
+HandlePendingRequestTimeout is a command the TAM sends to itself once the deadline of a request waiting for fresh results has expired.
+*/
+type HandlePendingRequestTimeout struct {
+	commandType core.CommandType
+	RequestID   string
+}
+
+func CreateHandlePendingRequestTimeout(requestID string) HandlePendingRequestTimeout {
+	return HandlePendingRequestTimeout{
+		commandType: core.HANDLE_PENDING_REQUEST_TIMEOUT,
+		RequestID:   requestID,
+	}
+}
+
+func (r HandlePendingRequestTimeout) Type() core.CommandType {
+	return r.commandType
+}
+
+/*
+HandleTemplateReload is periodically sent to the TAM to rescan the directory of declarative trust model templates in the
+TAM goroutine.
+*/
+type HandleTemplateReload struct {
+	commandType core.CommandType
+}
+
+func CreateHandleTemplateReload() HandleTemplateReload {
+	return HandleTemplateReload{
+		commandType: core.HANDLE_TEMPLATE_RELOAD,
+	}
+}
+
+func (r HandleTemplateReload) Type() core.CommandType {
+	return r.commandType
+}
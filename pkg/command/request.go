@@ -7,11 +7,11 @@ import (
 )
 
 type request interface {
-	tasmsg.TasInitRequest | tasmsg.TasTeardownRequest | tasmsg.TasTaRequest | taqimsg.TaqiQuery | tasmsg.TasTmtDiscover
+	tasmsg.TasInitRequest | tasmsg.TasTeardownRequest | tasmsg.TasTaRequest | taqimsg.TaqiQuery | tasmsg.TasTmtDiscover | tasmsg.TasRtlUpdateRequest
 }
 
 type subscriptionRequest interface {
-	tasmsg.TasSubscribeRequest | tasmsg.TasUnsubscribeRequest
+	tasmsg.TasSubscribeRequest | tasmsg.TasUnsubscribeRequest | taqimsg.TaqiSubscribeRequest | taqimsg.TaqiUnsubscribeRequest
 }
 
 type HandleRequest[R request] struct {
@@ -68,6 +68,16 @@ func CreateTasTeardownRequest(msg tasmsg.TasTeardownRequest, sender string, requ
 	}
 }
 
+func CreateTasRtlUpdateRequest(msg tasmsg.TasRtlUpdateRequest, sender string, requestID string, responseTopic string) HandleRequest[tasmsg.TasRtlUpdateRequest] {
+	return HandleRequest[tasmsg.TasRtlUpdateRequest]{
+		Request:       msg,
+		Sender:        sender,
+		RequestID:     requestID,
+		ResponseTopic: responseTopic,
+		commandType:   core.HANDLE_TAS_RTL_UPDATE_REQUEST,
+	}
+}
+
 func CreateTasSubscribeRequest(msg tasmsg.TasSubscribeRequest, sender string, requestID string, responseTopic string, subscriberTopic string) HandleSubscriptionRequest[tasmsg.TasSubscribeRequest] {
 	return HandleSubscriptionRequest[tasmsg.TasSubscribeRequest]{
 		Request:         msg,
@@ -100,6 +110,28 @@ func CreateTaqiQuery(msg taqimsg.TaqiQuery, sender string, requestID string, res
 	}
 }
 
+func CreateTaqiSubscribeRequest(msg taqimsg.TaqiSubscribeRequest, sender string, requestID string, responseTopic string, subscriberTopic string) HandleSubscriptionRequest[taqimsg.TaqiSubscribeRequest] {
+	return HandleSubscriptionRequest[taqimsg.TaqiSubscribeRequest]{
+		Request:         msg,
+		Sender:          sender,
+		RequestID:       requestID,
+		ResponseTopic:   responseTopic,
+		SubscriberTopic: subscriberTopic,
+		commandType:     core.HANDLE_TAQI_SUBSCRIBE_REQUEST,
+	}
+}
+
+func CreateTaqiUnsubscribeRequest(msg taqimsg.TaqiUnsubscribeRequest, sender string, requestID string, responseTopic string, subscriberTopic string) HandleSubscriptionRequest[taqimsg.TaqiUnsubscribeRequest] {
+	return HandleSubscriptionRequest[taqimsg.TaqiUnsubscribeRequest]{
+		Request:         msg,
+		Sender:          sender,
+		RequestID:       requestID,
+		ResponseTopic:   responseTopic,
+		SubscriberTopic: subscriberTopic,
+		commandType:     core.HANDLE_TAQI_UNSUBSCRIBE_REQUEST,
+	}
+}
+
 func CreateTasTmtDiscover(msg tasmsg.TasTmtDiscover, sender string, requestID string, responseTopic string) HandleRequest[tasmsg.TasTmtDiscover] {
 	return HandleRequest[tasmsg.TasTmtDiscover]{
 		Request:       msg,
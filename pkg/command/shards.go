@@ -0,0 +1,161 @@
+package command
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+)
+
+/*
+HandleTMIMigrationPrepare signals the target worker of a migration that a TMI is about to be handed over. Until the TMI
+arrives, the worker buffers all commands for this TMI.
+*/
+type HandleTMIMigrationPrepare struct {
+	commandType core.CommandType
+	FullTmiID   string
+}
+
+func CreateHandleTMIMigrationPrepare(fullTMI string) HandleTMIMigrationPrepare {
+	return HandleTMIMigrationPrepare{
+		FullTmiID:   fullTMI,
+		commandType: core.HANDLE_TMI_MIGRATION_PREPARE,
+	}
+}
+
+func (r HandleTMIMigrationPrepare) Type() core.CommandType {
+	return r.commandType
+}
+
+/*
+HandleTMIMigrationRelease signals the source worker of a migration to hand over a TMI. As the worker processes its queue
+in order, all updates for the TMI dispatched before this command have been applied once the TMI is released.
+*/
+type HandleTMIMigrationRelease struct {
+	commandType core.CommandType
+	FullTmiID   string
+}
+
+func CreateHandleTMIMigrationRelease(fullTMI string) HandleTMIMigrationRelease {
+	return HandleTMIMigrationRelease{
+		FullTmiID:   fullTMI,
+		commandType: core.HANDLE_TMI_MIGRATION_RELEASE,
+	}
+}
+
+func (r HandleTMIMigrationRelease) Type() core.CommandType {
+	return r.commandType
+}
+
+/*
+TMIWorkerState is the state a worker keeps for a TMI in addition to the TMI itself. It is handed over together with the
+TMI when the TMI is migrated to another worker.
+*/
+type TMIWorkerState struct {
+	//latest result set of the TMI, nil if there are no results yet
+	Results *core.AtlResultSet
+	//provenance of the latest opinion per trust relationship and trust source, only if explanations are enabled
+	Provenance []core.TrustProvenance
+	//proposition->RTL override (see HandleRTLUpdate)
+	RTLs map[string]subjectivelogic.QueryableOpinion
+	//latest opinion per trust relationship and trust source that is subject to aging
+	Aging []core.AgingOpinion
+}
+
+/*
+HandleTMIReleased is a command sent from the source worker of a migration to the TAM that contains the released TMI and
+its worker state. TMI is nil if the TMI did not exist anymore (e.g., because it has been destroyed before being released).
+*/
+type HandleTMIReleased struct {
+	commandType core.CommandType
+	FullTmiID   string
+	TMI         core.TrustModelInstance
+	State       TMIWorkerState
+}
+
+func CreateHandleTMIReleased(fullTMI string, tmi core.TrustModelInstance, state TMIWorkerState) HandleTMIReleased {
+	return HandleTMIReleased{
+		FullTmiID:   fullTMI,
+		TMI:         tmi,
+		State:       state,
+		commandType: core.HANDLE_TMI_RELEASED,
+	}
+}
+
+func (r HandleTMIReleased) Type() core.CommandType {
+	return r.commandType
+}
+
+/*
+HandleTMIMigrationAdopt hands over a migrated TMI and its worker state to the target worker of a migration.
+*/
+type HandleTMIMigrationAdopt struct {
+	commandType core.CommandType
+	FullTmiID   string
+	TMI         core.TrustModelInstance
+	State       TMIWorkerState
+}
+
+func CreateHandleTMIMigrationAdopt(fullTMI string, tmi core.TrustModelInstance, state TMIWorkerState) HandleTMIMigrationAdopt {
+	return HandleTMIMigrationAdopt{
+		FullTmiID:   fullTMI,
+		TMI:         tmi,
+		State:       state,
+		commandType: core.HANDLE_TMI_MIGRATION_ADOPT,
+	}
+}
+
+func (r HandleTMIMigrationAdopt) Type() core.CommandType {
+	return r.commandType
+}
+
+/*
+HandleWorkerStop signals a worker without remaining TMIs to shut down.
+*/
+type HandleWorkerStop struct {
+	commandType core.CommandType
+}
+
+func CreateHandleWorkerStop() HandleWorkerStop {
+	return HandleWorkerStop{
+		commandType: core.HANDLE_WORKER_STOP,
+	}
+}
+
+func (r HandleWorkerStop) Type() core.CommandType {
+	return r.commandType
+}
+
+/*
+HandleScaleWorkers requests the TAM to change the number of workers at runtime.
+*/
+type HandleScaleWorkers struct {
+	commandType core.CommandType
+	Count       int
+}
+
+func CreateHandleScaleWorkers(count int) HandleScaleWorkers {
+	return HandleScaleWorkers{
+		Count:       count,
+		commandType: core.HANDLE_SCALE_WORKERS,
+	}
+}
+
+func (r HandleScaleWorkers) Type() core.CommandType {
+	return r.commandType
+}
+
+/*
+HandleRebalanceCheck is periodically sent by the TAM to itself to check whether the load of the workers is skewed.
+*/
+type HandleRebalanceCheck struct {
+	commandType core.CommandType
+}
+
+func CreateHandleRebalanceCheck() HandleRebalanceCheck {
+	return HandleRebalanceCheck{
+		commandType: core.HANDLE_REBALANCE_CHECK,
+	}
+}
+
+func (r HandleRebalanceCheck) Type() core.CommandType {
+	return r.commandType
+}
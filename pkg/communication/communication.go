@@ -157,6 +157,16 @@ func (ch CommunicationInterface) handleIncomingMessages() {
 					cmd := command.CreateTasTeardownRequest(tasTeardownReq, rawMsg.Sender, rawMsg.RequestId, rawMsg.ResponseTopic)
 					ch.channels.TAMChannel <- cmd
 				}
+			case messages.TAS_RTL_UPDATE_REQUEST:
+				tasRtlUpdateRequest, err := tasmsg.UnmarshalTasRtlUpdateRequest(msg)
+				if err != nil {
+					ch.tafContext.Logger.Error("Error unmarshalling TAS_RTL_UPDATE_REQUEST: " + err.Error())
+				} else if ok, errs := checkRequestFields(rawMsg); !ok {
+					ch.tafContext.Logger.Error("Incomplete message header for TAS_RTL_UPDATE_REQUEST message: " + errs.Error())
+				} else {
+					cmd := command.CreateTasRtlUpdateRequest(tasRtlUpdateRequest, rawMsg.Sender, rawMsg.RequestId, rawMsg.ResponseTopic)
+					ch.channels.TAMChannel <- cmd
+				}
 			case messages.TAS_TA_REQUEST:
 				tasTaRequest, err := tasmsg.UnmarshalTasTaRequest(msg)
 				if err != nil {
@@ -207,6 +217,26 @@ func (ch CommunicationInterface) handleIncomingMessages() {
 					cmd := command.CreateTaqiResult(taqiResult, rawMsg.Sender, rawMsg.RequestId)
 					ch.channels.TAMChannel <- cmd
 				}
+			case messages.TAQI_SUBSCRIBE_REQUEST:
+				taqiSubscribeRequest, err := taqimsg.UnmarshalTaqiSubscribeRequest(msg)
+				if err != nil {
+					ch.tafContext.Logger.Error("Error unmarshalling TAQI_SUBSCRIBE_REQUEST: " + err.Error())
+				} else if ok, errs := checkSubscriptionRequestFields(rawMsg); !ok {
+					ch.tafContext.Logger.Error("Incomplete message header for TAQI_SUBSCRIBE_REQUEST message: " + errs.Error())
+				} else {
+					cmd := command.CreateTaqiSubscribeRequest(taqiSubscribeRequest, rawMsg.Sender, rawMsg.RequestId, rawMsg.ResponseTopic, rawMsg.SubscriberTopic)
+					ch.channels.TAMChannel <- cmd
+				}
+			case messages.TAQI_UNSUBSCRIBE_REQUEST:
+				taqiUnsubscribeRequest, err := taqimsg.UnmarshalTaqiUnsubscribeRequest(msg)
+				if err != nil {
+					ch.tafContext.Logger.Error("Error unmarshalling TAQI_UNSUBSCRIBE_REQUEST: " + err.Error())
+				} else if ok, errs := checkSubscriptionRequestFields(rawMsg); !ok {
+					ch.tafContext.Logger.Error("Incomplete message header for TAQI_UNSUBSCRIBE_REQUEST message: " + errs.Error())
+				} else {
+					cmd := command.CreateTaqiUnsubscribeRequest(taqiUnsubscribeRequest, rawMsg.Sender, rawMsg.RequestId, rawMsg.ResponseTopic, rawMsg.SubscriberTopic)
+					ch.channels.TAMChannel <- cmd
+				}
 			case messages.AIV_RESPONSE:
 				aivResponse, err := aivmsg.UnmarshalAivResponse(msg)
 				if err != nil {
@@ -429,16 +459,30 @@ func UnmarshallMessage(schema messages.MessageSchema, msg []byte) (interface{},
 		extractedStruct, err = tasmsg.UnmarshalTasTmtDiscover(msg)
 	case messages.TAS_TMT_OFFER:
 		extractedStruct, err = tasmsg.UnmarshalTasTmtOffer(msg)
+	case messages.TAQI_NOTIFY:
+		extractedStruct, err = taqimsg.UnmarshalTaqiNotify(msg)
 	case messages.TAQI_QUERY:
 		extractedStruct, err = taqimsg.UnmarshalTaqiQuery(msg)
 	case messages.TAQI_RESULT:
 		extractedStruct, err = taqimsg.UnmarshalTaqiResult(msg)
+	case messages.TAQI_SUBSCRIBE_REQUEST:
+		extractedStruct, err = taqimsg.UnmarshalTaqiSubscribeRequest(msg)
+	case messages.TAQI_SUBSCRIBE_RESPONSE:
+		extractedStruct, err = taqimsg.UnmarshalTaqiSubscribeResponse(msg)
+	case messages.TAQI_UNSUBSCRIBE_REQUEST:
+		extractedStruct, err = taqimsg.UnmarshalTaqiUnsubscribeRequest(msg)
+	case messages.TAQI_UNSUBSCRIBE_RESPONSE:
+		extractedStruct, err = taqimsg.UnmarshalTaqiUnsubscribeResponse(msg)
 	case messages.TAS_INIT_REQUEST:
 		extractedStruct, err = tasmsg.UnmarshalTasInitRequest(msg)
 	case messages.TAS_INIT_RESPONSE:
 		extractedStruct, err = tasmsg.UnmarshalTasInitResponse(msg)
 	case messages.TAS_NOTIFY:
 		extractedStruct, err = tasmsg.UnmarshalTasNotify(msg)
+	case messages.TAS_RTL_UPDATE_REQUEST:
+		extractedStruct, err = tasmsg.UnmarshalTasRtlUpdateRequest(msg)
+	case messages.TAS_RTL_UPDATE_RESPONSE:
+		extractedStruct, err = tasmsg.UnmarshalTasRtlUpdateResponse(msg)
 	case messages.TAS_SUBSCRIBE_REQUEST:
 		extractedStruct, err = tasmsg.UnmarshalTasSubscribeRequest(msg)
 	case messages.TAS_SUBSCRIBE_RESPONSE:
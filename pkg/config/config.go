@@ -17,8 +17,9 @@ type Configuration struct {
 	Evidence      Evidence
 	Logging       Log
 	TAM           TAM
+	TDE           TDE
 	TLEE          TLEE
-	V2X           V2X
+	TMM           TMM
 	WebUI         WebUI
 }
 
@@ -52,6 +53,7 @@ type Debug struct {
 	FixedSessionID      string // If not empty, the set session ID will be used and override all session IDs.
 	FixedSubscriptionID string // If not empty, the set subscription ID will be used and override all subscription IDs.
 	FixedRequestID      string // If not empty, the set request ID will be used and override all request IDs.
+	RecordTLEEInputs    string // If not empty, the TLEE inputs of all trust model instances are recorded to this directory for differential testing.
 }
 
 /*
@@ -83,23 +85,109 @@ type Log struct {
 
 // TAM-Configuration for settings for the Trust Assessment Manager.
 type TAM struct {
-	TrustModelInstanceShards int //The TAM delegates tasks to workers by partitioning all trust model instances into shards. Each shard is then backed by a single worker. This configuration parameter sets the number of partitions/workers.
+	TrustModelInstanceShards int //The TAM delegates tasks to workers by partitioning all trust model instances into shards. Each shard is then backed by a single worker. This configuration parameter sets the initial number of partitions/workers, which can be changed at runtime.
+	ATLHistory               ATLHistory
+	DefaultRequestDeadline   int //Default time (in msec) a TAS_TA_REQUEST or TAQI_QUERY waits for fresh results or required tags/versions if the request does not specify its own deadline.
+	Rebalancing              Rebalancing
+	Batching                 Batching
+	Aging                    Aging
+}
+
+/*
+Opinion aging configuration. Trust model templates can declare their own aging per trust source, which takes precedence.
+*/
+type Aging struct {
+	CheckInterval int                     //Interval (in msec) in which workers age the atomic trust opinions of their trust model instances. A value of 0 disables aging.
+	Default       OpinionAging            //Aging of opinions from trust sources not listed in TrustSources.
+	TrustSources  map[string]OpinionAging //Trust source name (e.g., "AIV")->aging of its opinions.
+}
+
+/*
+Aging of atomic trust opinions from a trust source.
+*/
+type OpinionAging struct {
+	HalfLife    int //Time (in msec) after which half of the belief and disbelief mass has been shifted into uncertainty. A value of 0 disables the decay.
+	GracePeriod int //Age (in msec) of the evidence before the decay starts.
+	MaxAge      int //Age (in msec) of the evidence after which the opinion is replaced by full uncertainty. A value of 0 disables the limit.
+}
+
+/*
+Worker batching configuration.
+*/
+type Batching struct {
+	Size       int //Maximum number of commands a worker takes from its queue at once. Updates for the same trust model instance within a batch are coalesced and evaluated once. A value of 1 disables batching.
+	MaxLatency int //Time (in msec) a worker waits for further commands before processing an incomplete batch. A value of 0 only takes commands that are already queued.
+}
+
+/*
+Worker rebalancing configuration.
+*/
+type Rebalancing struct {
+	Enabled       bool    //If true, the TAM periodically checks the queue depths of its workers and migrates TMIs from overloaded workers.
+	CheckInterval int     //Interval (in msec) between two checks of the queue depths.
+	SkewFactor    float64 //The load is considered skewed if the deepest queue exceeds the mean queue depth by this factor.
+	MinQueueDepth int     //Queue depth below which the load is never considered skewed.
+	SkewedChecks  int     //Number of consecutive checks with skewed load before TMIs are migrated.
+	MaxMigrations int     //Maximum number of TMIs migrated per rebalancing.
+}
+
+/*
+ATL history configuration.
+*/
+type ATLHistory struct {
+	Size            int    //Number of ATL result sets kept in memory per trust model instance. A value of 0 disables the history.
+	SpillDirectory  string //If not empty, result sets evicted from the in-memory history (and histories of removed trust model instances) are appended to files in this directory.
+	SpillMaxEntries int    //If greater than 0, the spill file of a trust model instance is rotated once it holds this number of result sets, so that at most twice as many spilled result sets are kept per trust model instance.
+}
+
+/*
+Trust decision engine configuration.
+*/
+type TDE struct {
+	Policy           string  //Name of the decision policy used for propositions of trust model templates that do not declare their own policy.
+	HysteresisMargin float64 //Margin around the projected probability of the RTL that the ATL has to cross before the hysteresis policy changes its decision.
+	Explanations     bool    //If set to true, an explanation of the trust decision is produced for each proposition.
 }
 
 /*
 TLEE-related configuration.
 */
 type TLEE struct {
-	UseInternalTLEE bool //If set to true, the TAF will use an internal debugging TLEE instead of the real TLEE for debugging purposes.
+	Backend         string //Name of the TLEE backend used for trust model templates that do not declare their own backend.
+	UseInternalTLEE bool   //If set to true, the TAF will use an internal debugging TLEE instead of the configured default backend for debugging purposes.
+	Incremental     bool   //If set to true, workers cache TLEE results per trust model instance and only re-evaluate scopes whose trust relationships have changed while the structure stays the same. Only applies to backends that evaluate scopes independently.
 	DebuggingMode   bool
 	FilePath        string
 }
 
 /*
-V2X-Observer settings.
+Trust model manager configuration.
 */
-type V2X struct {
-	NodeTTLsec       int //This period (in sec) defines the timeout for nodes considered present by the V2XObserver.
+type TMM struct {
+	TemplateDirectory string        //If not empty, declarative trust model templates (JSON or YAML files) are loaded from this directory at startup.
+	ReloadIntervalSec int           //If greater than 0, the template directory is rescanned in this interval (in sec) to add, deprecate and retire templates at runtime.
+	Manifest          string        //If not empty, the signing hashes of trust model templates are verified against the signed manifest in this file.
+	RequireVerified   bool          //If set to true, trust model templates whose signing hashes are not attested by the manifest are refused; otherwise they are flagged as unverified.
+	SpawnTriggers     SpawnTriggers //Presence of the entities triggering the dynamic spawning of trust model instances.
+}
+
+/*
+Settings of the observers of entities that trigger the dynamic spawning of trust model instances, per spawn trigger.
+*/
+type SpawnTriggers struct {
+	V2XSender  EntityObserver //Senders of V2X_CPM messages.
+	TCHTrustee EntityObserver //Trustees of TCH_NOTIFY messages.
+	MBDSource  EntityObserver //V2X sources reported in MBD_NOTIFY messages.
+	NTMSource  EntityObserver //V2X sources rated in V2X_NTM messages.
+	AIVTrustee EntityObserver //Trustees of AIV_NOTIFY messages.
+	CPMObject  EntityObserver //Objects perceived in V2X_CPM messages.
+}
+
+/*
+Entity observer settings.
+*/
+type EntityObserver struct {
+	TTLsec           int //This period (in sec) defines the timeout for entities considered present by the observer. Trust model instances spawned for an entity are removed after the timeout.
 	CheckIntervalSec int //This value (in sec) specifies in which frequency timeouts should be checked.
 }
 
@@ -125,6 +213,28 @@ var (
 		ChanBufSize: 1_000,
 		TAM: TAM{
 			TrustModelInstanceShards: 1,
+			DefaultRequestDeadline:   1000,
+			Rebalancing: Rebalancing{
+				Enabled:       false,
+				CheckInterval: 500,
+				SkewFactor:    2.0,
+				MinQueueDepth: 32,
+				SkewedChecks:  3,
+				MaxMigrations: 8,
+			},
+			Batching: Batching{
+				Size:       32,
+				MaxLatency: 0,
+			},
+			ATLHistory: ATLHistory{
+				Size:            32,
+				SpillDirectory:  "",
+				SpillMaxEntries: 10000,
+			},
+			Aging: Aging{
+				CheckInterval: 1000,
+				Default:       OpinionAging{},
+			},
 		},
 		Crypto: Crypto{
 			KeyFolder:                 "res/cert/",
@@ -145,20 +255,38 @@ var (
 			FixedSessionID:      "",
 			FixedSubscriptionID: "",
 			FixedRequestID:      "",
+			RecordTLEEInputs:    "",
 		},
 		Evidence: Evidence{
 			AIV: AIV{
 				CheckInterval: 1000,
 			},
 		},
+		TDE: TDE{
+			Policy:           "projected-probability",
+			HysteresisMargin: 0.05,
+			Explanations:     false,
+		},
 		TLEE: TLEE{
+			Backend:         "tlee-implementation",
 			UseInternalTLEE: false,
+			Incremental:     true,
 			DebuggingMode:   false,
 			FilePath:        "debug/",
 		},
-		V2X: V2X{
-			NodeTTLsec:       5,
-			CheckIntervalSec: 1,
+		TMM: TMM{
+			TemplateDirectory: "",
+			ReloadIntervalSec: 0,
+			Manifest:          "",
+			RequireVerified:   false,
+			SpawnTriggers: SpawnTriggers{
+				V2XSender:  EntityObserver{TTLsec: 5, CheckIntervalSec: 1},
+				TCHTrustee: EntityObserver{TTLsec: 5, CheckIntervalSec: 1},
+				MBDSource:  EntityObserver{TTLsec: 5, CheckIntervalSec: 1},
+				NTMSource:  EntityObserver{TTLsec: 5, CheckIntervalSec: 1},
+				AIVTrustee: EntityObserver{TTLsec: 30, CheckIntervalSec: 1},
+				CPMObject:  EntityObserver{TTLsec: 5, CheckIntervalSec: 1},
+			},
 		},
 		WebUI: WebUI{
 			Port: 7778,
@@ -167,7 +295,18 @@ var (
 )
 
 /*
-LoadJSON loads a configuration from a JSON file.
+Deprecated settings of earlier versions, which are mapped onto their replacements when a configuration is loaded.
+*/
+type deprecatedSettings struct {
+	V2X *struct {
+		NodeTTLsec       *int //Replaced by the TTLsec of TMM.SpawnTriggers.V2XSender and TMM.SpawnTriggers.TCHTrustee.
+		CheckIntervalSec *int //Replaced by the CheckIntervalSec of TMM.SpawnTriggers.V2XSender and TMM.SpawnTriggers.TCHTrustee.
+	}
+}
+
+/*
+LoadJSON loads a configuration from a JSON file. Deprecated settings are applied to their replacements, unless the
+replacements are set as well.
 */
 func LoadJSON(filepath string) (Configuration, error) {
 	config := DefaultConfig
@@ -175,6 +314,21 @@ func LoadJSON(filepath string) (Configuration, error) {
 	if err != nil {
 		return Configuration{}, err
 	}
+	var deprecated deprecatedSettings
+	err = json.Unmarshal(raw, &deprecated)
+	if err != nil {
+		return Configuration{}, err
+	}
+	if deprecated.V2X != nil {
+		for _, observer := range []*EntityObserver{&config.TMM.SpawnTriggers.V2XSender, &config.TMM.SpawnTriggers.TCHTrustee} {
+			if deprecated.V2X.NodeTTLsec != nil {
+				observer.TTLsec = *deprecated.V2X.NodeTTLsec
+			}
+			if deprecated.V2X.CheckIntervalSec != nil {
+				observer.CheckIntervalSec = *deprecated.V2X.CheckIntervalSec
+			}
+		}
+	}
 	err = json.Unmarshal(raw, &config)
 	if err != nil {
 		return Configuration{}, err
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"math"
+	"time"
+)
+
+/*
+OpinionAging specifies how atomic trust opinions of a trust source age if no fresh evidence arrives. After a grace
+period, belief and disbelief are shifted into uncertainty with the given half-life. Once the evidence is older than the
+maximum age, or the remaining belief and disbelief mass has become negligible, the opinion is replaced by full
+uncertainty.
+*/
+type OpinionAging struct {
+	//Time after which half of the belief and disbelief mass has been shifted into uncertainty; 0 disables the decay.
+	HalfLife time.Duration
+	//Age of the evidence before the decay starts.
+	GracePeriod time.Duration
+	//Age of the evidence after which the opinion is vacuous; 0 disables the limit.
+	MaxAge time.Duration
+}
+
+/*
+Enabled returns whether opinions age at all under this configuration.
+*/
+func (a OpinionAging) Enabled() bool {
+	return a.HalfLife > 0 || a.MaxAge > 0
+}
+
+// share of belief and disbelief mass below which an aged opinion is considered vacuous, so that opinions also stop aging without maximum age
+const negligibleFactor = 1e-3
+
+/*
+Factor returns the share of belief and disbelief mass that remains for evidence of the given age.
+*/
+func (a OpinionAging) Factor(age time.Duration) float64 {
+	if a.MaxAge > 0 && age >= a.MaxAge {
+		return 0
+	}
+	if a.HalfLife <= 0 || age <= a.GracePeriod {
+		return 1
+	}
+	factor := math.Pow(0.5, float64(age-a.GracePeriod)/float64(a.HalfLife))
+	if factor < negligibleFactor {
+		return 0
+	}
+	return factor
+}
+
+/*
+An OpinionAgingProvider is a TrustModelTemplate that declares how the atomic trust opinions of its instances age. Trust
+sources for which no aging is declared age according to the configuration of the TAM.
+*/
+type OpinionAgingProvider interface {
+	/*
+		OpinionAging returns the aging of opinions from a trust source, or nil to use the configured aging.
+	*/
+	OpinionAging(source TrustSource) *OpinionAging
+}
+
+/*
+An AgingOpinion is the latest atomic trust opinion of a trust relationship and trust source that is subject to aging,
+together with the time its evidence has been received.
+*/
+type AgingOpinion struct {
+	Trustor     string
+	Trustee     string
+	TrustSource TrustSource
+	//opinion as quantified from the evidence, i.e., before aging
+	Opinion  subjectivelogic.QueryableOpinion
+	Received time.Time
+	Aging    OpinionAging
+}
@@ -9,6 +9,8 @@ const (
 	UNDEFINED CommandType = iota
 	HANDLE_TAQI_QUERY
 	HANDLE_TAQI_RESULT
+	HANDLE_TAQI_SUBSCRIBE_REQUEST
+	HANDLE_TAQI_UNSUBSCRIBE_REQUEST
 	HANDLE_TAS_INIT_REQUEST
 	HANDLE_TAS_TEARDOWN_REQUEST
 	HANDLE_TAS_TMT_DISCOVER
@@ -29,13 +31,27 @@ const (
 	HANDLE_TMI_UPDATE
 	HANDLE_TMI_DESTROY
 	HANDLE_ATL_UPDATE
+	HANDLE_TMI_UPDATE_APPLIED
 	HANDLE_OBSERVER_EVENT
+	HANDLE_PENDING_REQUEST_TIMEOUT
+	HANDLE_TMI_MIGRATION_PREPARE
+	HANDLE_TMI_MIGRATION_RELEASE
+	HANDLE_TMI_RELEASED
+	HANDLE_TMI_MIGRATION_ADOPT
+	HANDLE_WORKER_STOP
+	HANDLE_SCALE_WORKERS
+	HANDLE_REBALANCE_CHECK
+	HANDLE_TAS_RTL_UPDATE_REQUEST
+	HANDLE_RTL_UPDATE
+	HANDLE_TEMPLATE_RELOAD
 )
 
 func (c CommandType) String() string {
 	return [...]string{"UNDEFINED",
 		"HANDLE_TAQI_QUERY",
 		"HANDLE_TAQI_RESULT",
+		"HANDLE_TAQI_SUBSCRIBE_REQUEST",
+		"HANDLE_TAQI_UNSUBSCRIBE_REQUEST",
 		"HANDLE_TAS_INIT_REQUEST",
 		"HANDLE_TAS_TEARDOWN_REQUEST",
 		"HANDLE_TAS_TMT_DISCOVER",
@@ -56,7 +72,19 @@ func (c CommandType) String() string {
 		"HANDLE_TMI_UPDATE",
 		"HANDLE_TMI_DESTROY",
 		"HANDLE_ATL_UPDATE",
+		"HANDLE_TMI_UPDATE_APPLIED",
 		"HANDLE_OBSERVER_EVENT",
+		"HANDLE_PENDING_REQUEST_TIMEOUT",
+		"HANDLE_TMI_MIGRATION_PREPARE",
+		"HANDLE_TMI_MIGRATION_RELEASE",
+		"HANDLE_TMI_RELEASED",
+		"HANDLE_TMI_MIGRATION_ADOPT",
+		"HANDLE_WORKER_STOP",
+		"HANDLE_SCALE_WORKERS",
+		"HANDLE_REBALANCE_CHECK",
+		"HANDLE_TAS_RTL_UPDATE_REQUEST",
+		"HANDLE_RTL_UPDATE",
+		"HANDLE_TEMPLATE_RELOAD",
 	}[c]
 }
 
@@ -1,5 +1,7 @@
 package core
 
+import "github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+
 /*
 A TrustDecision represents the output of a trust decision engine execution.
 */
@@ -21,3 +23,20 @@ const (
 	*/
 	UNDECIDABLE
 )
+
+/*
+A DecisionPolicy derives a TrustDecision for a proposition from its actual trust level (ATL) and its required trust
+level (RTL). Policies are shared by all workers and must therefore be stateless; the previous decision for the same
+proposition is passed in instead.
+*/
+type DecisionPolicy interface {
+	/*
+		Name returns the name of the policy as reported to clients.
+	*/
+	Name() string
+	/*
+		Decide returns the trust decision for an ATL given an RTL. previous is nil if there is no earlier decision for the
+		proposition.
+	*/
+	Decide(atl subjectivelogic.QueryableOpinion, rtl subjectivelogic.QueryableOpinion, previous *TrustDecision) TrustDecision
+}
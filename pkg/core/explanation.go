@@ -0,0 +1,97 @@
+package core
+
+import (
+	"encoding/json"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"time"
+)
+
+/*
+A DecisionExplanation describes how the trust decision for a proposition has been derived: the ATL and RTL, the decision
+policy applied to them, and the trust relationships that led to the ATL.
+*/
+type DecisionExplanation struct {
+	ATL                     subjectivelogic.QueryableOpinion
+	RTL                     subjectivelogic.QueryableOpinion
+	ATLProjectedProbability float64
+	RTLProjectedProbability float64
+	Decision                TrustDecision
+	Policy                  string
+	Rule                    string
+	Contributions           []TrustContribution
+}
+
+/*
+A TrustContribution is a trust relationship in the scope of a proposition, together with the provenance of its opinion.
+Provenance is empty for opinions that have not been set by a trust source (e.g., static opinions of the trust model).
+*/
+type TrustContribution struct {
+	Trustor    string
+	Trustee    string
+	Opinion    subjectivelogic.QueryableOpinion
+	Provenance []TrustProvenance
+}
+
+/*
+TrustProvenance records the origin of the latest atomic trust opinion of a trust source on a trust relationship.
+*/
+type TrustProvenance struct {
+	Trustor     string
+	Trustee     string
+	TrustSource TrustSource
+	//evidence the opinion has been quantified from, nil if unknown
+	Evidence  map[EvidenceType]interface{}
+	Tag       *string
+	Timestamp time.Time
+}
+
+func (p TrustProvenance) MarshalJSON() ([]byte, error) {
+	evidence := make(map[string]interface{}, len(p.Evidence))
+	for evidenceType, value := range p.Evidence {
+		evidence[evidenceType.String()] = value
+	}
+	return json.Marshal(&struct {
+		Trustor     string
+		Trustee     string
+		TrustSource string
+		Evidence    map[string]interface{}
+		Tag         *string
+		Timestamp   time.Time
+	}{
+		Trustor:     p.Trustor,
+		Trustee:     p.Trustee,
+		TrustSource: p.TrustSource.String(),
+		Evidence:    evidence,
+		Tag:         p.Tag,
+		Timestamp:   p.Timestamp,
+	})
+}
+
+func (e DecisionExplanation) MarshalJSON() ([]byte, error) {
+	decision := "UNDECIDABLE"
+	switch e.Decision {
+	case TRUSTWORTHY:
+		decision = "TRUSTWORTHY"
+	case NOT_TRUSTWORTHY:
+		decision = "NOT_TRUSTWORTHY"
+	}
+	return json.Marshal(&struct {
+		ATL                     subjectivelogic.QueryableOpinion
+		RTL                     subjectivelogic.QueryableOpinion
+		ATLProjectedProbability float64
+		RTLProjectedProbability float64
+		Decision                string
+		Policy                  string
+		Rule                    string
+		Contributions           []TrustContribution
+	}{
+		ATL:                     e.ATL,
+		RTL:                     e.RTL,
+		ATLProjectedProbability: e.ATLProjectedProbability,
+		RTLProjectedProbability: e.RTLProjectedProbability,
+		Decision:                decision,
+		Policy:                  e.Policy,
+		Rule:                    e.Rule,
+		Contributions:           e.Contributions,
+	})
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"encoding/json"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"time"
 )
 
 /*
@@ -14,14 +15,20 @@ type AtlResultSet struct {
 	slResults map[string]subjectivelogic.QueryableOpinion
 	ppResults map[string]float64
 	tdResults map[string]TrustDecision
-	tag       *string
+	//proposition->name of the decision policy used for the trust decision
+	policies map[string]string
+	//proposition->explanation of the trust decision, only if explanations are enabled
+	explanations map[string]DecisionExplanation
+	tag          *string
+	timestamp    time.Time
 }
 
-func CreateAtlResultSet(tmiID string, version int, tag *string, slResults map[string]subjectivelogic.QueryableOpinion, ppResults map[string]float64, tdResults map[string]TrustDecision) AtlResultSet {
+func CreateAtlResultSet(tmiID string, version int, tag *string, timestamp time.Time, slResults map[string]subjectivelogic.QueryableOpinion, ppResults map[string]float64, tdResults map[string]TrustDecision) AtlResultSet {
 	return AtlResultSet{
 		tmiID:     tmiID,
 		version:   version,
 		tag:       tag,
+		timestamp: timestamp,
 		slResults: slResults,
 		ppResults: ppResults,
 		tdResults: tdResults,
@@ -49,6 +56,13 @@ func (r AtlResultSet) Tag() *string {
 	return r.tag
 }
 
+/*
+Timestamp returns the point in time at which the results have been computed.
+*/
+func (r AtlResultSet) Timestamp() time.Time {
+	return r.timestamp
+}
+
 /*
 ATLs return a map of all propositions and their ATLs.
 */
@@ -70,20 +84,58 @@ func (r AtlResultSet) TrustDecisions() map[string]TrustDecision {
 	return r.tdResults
 }
 
+/*
+WithDecisionPolicies returns a copy of the result set that records the names of the decision policies used for the trust
+decisions of its propositions.
+*/
+func (r AtlResultSet) WithDecisionPolicies(policies map[string]string) AtlResultSet {
+	r.policies = policies
+	return r
+}
+
+/*
+DecisionPolicies return a map of all propositions and the names of the decision policies used for their trust decisions.
+*/
+func (r AtlResultSet) DecisionPolicies() map[string]string {
+	return r.policies
+}
+
+/*
+WithExplanations returns a copy of the result set that contains explanations of the trust decisions of its propositions.
+*/
+func (r AtlResultSet) WithExplanations(explanations map[string]DecisionExplanation) AtlResultSet {
+	r.explanations = explanations
+	return r
+}
+
+/*
+Explanations return a map of all propositions and the explanations of their trust decisions. The map is nil if
+explanations are disabled.
+*/
+func (r AtlResultSet) Explanations() map[string]DecisionExplanation {
+	return r.explanations
+}
+
 func (r AtlResultSet) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
-		TmiID     string
-		Version   int
-		Tag       *string
-		SlResults map[string]subjectivelogic.QueryableOpinion
-		PpResults map[string]float64
-		TdResults map[string]TrustDecision
+		TmiID        string
+		Version      int
+		Tag          *string
+		Timestamp    time.Time
+		SlResults    map[string]subjectivelogic.QueryableOpinion
+		PpResults    map[string]float64
+		TdResults    map[string]TrustDecision
+		Policies     map[string]string
+		Explanations map[string]DecisionExplanation `json:",omitempty"`
 	}{
-		TmiID:     r.tmiID,
-		Version:   r.version,
-		Tag:       r.tag,
-		SlResults: r.slResults,
-		PpResults: r.ppResults,
-		TdResults: r.tdResults,
+		TmiID:        r.tmiID,
+		Version:      r.version,
+		Tag:          r.tag,
+		Timestamp:    r.timestamp,
+		SlResults:    r.slResults,
+		PpResults:    r.ppResults,
+		TdResults:    r.tdResults,
+		Policies:     r.policies,
+		Explanations: r.explanations,
 	})
 }
@@ -0,0 +1,136 @@
+package core
+
+import "slices"
+
+/*
+SpawnTrigger specifies a type of entity whose appearance triggers the dynamic spawning of trust model instances.
+*/
+type SpawnTrigger uint16
+
+const (
+	/*
+		TRIGGER_V2X_SENDER is triggered by previously unknown senders of V2X_CPM messages.
+	*/
+	TRIGGER_V2X_SENDER SpawnTrigger = iota
+	/*
+		TRIGGER_TCH_TRUSTEE is triggered by previously unknown trustees of TCH_NOTIFY messages.
+	*/
+	TRIGGER_TCH_TRUSTEE
+	/*
+		TRIGGER_MBD_SOURCE is triggered by previously unknown V2X sources reported in MBD_NOTIFY messages.
+	*/
+	TRIGGER_MBD_SOURCE
+	/*
+		TRIGGER_NTM_SOURCE is triggered by previously unknown V2X sources rated in V2X_NTM messages.
+	*/
+	TRIGGER_NTM_SOURCE
+	/*
+		TRIGGER_AIV_TRUSTEE is triggered by previously unknown trustees of AIV_NOTIFY messages.
+	*/
+	TRIGGER_AIV_TRUSTEE
+	/*
+		TRIGGER_CPM_OBJECT is triggered by previously unknown objects perceived in V2X_CPM messages.
+	*/
+	TRIGGER_CPM_OBJECT
+)
+
+/*
+SpawnTriggers lists all spawn triggers.
+*/
+var SpawnTriggers = []SpawnTrigger{TRIGGER_V2X_SENDER, TRIGGER_TCH_TRUSTEE, TRIGGER_MBD_SOURCE, TRIGGER_NTM_SOURCE, TRIGGER_AIV_TRUSTEE, TRIGGER_CPM_OBJECT}
+
+func (t SpawnTrigger) String() string {
+	switch t {
+	case TRIGGER_V2X_SENDER:
+		return "V2X_SENDER"
+	case TRIGGER_TCH_TRUSTEE:
+		return "TCH_TRUSTEE"
+	case TRIGGER_MBD_SOURCE:
+		return "MBD_SOURCE"
+	case TRIGGER_NTM_SOURCE:
+		return "NTM_SOURCE"
+	case TRIGGER_AIV_TRUSTEE:
+		return "AIV_TRUSTEE"
+	case TRIGGER_CPM_OBJECT:
+		return "CPM_OBJECT"
+	default:
+		return "UNKNOWN TRIGGER"
+	}
+}
+
+/*
+SpawnTriggerByName returns the spawn trigger with the given name (e.g., "MBD_SOURCE").
+*/
+func SpawnTriggerByName(name string) (SpawnTrigger, bool) {
+	for _, trigger := range SpawnTriggers {
+		if trigger.String() == name {
+			return trigger, true
+		}
+	}
+	return 0, false
+}
+
+/*
+A SpawnTriggerProvider is a TrustModelTemplate of type ENTITY_TRIGGERED_TRUST_MODEL that declares the triggers spawning
+its instances.
+*/
+type SpawnTriggerProvider interface {
+	/*
+		SpawnTriggers returns the triggers spawning instances of the template.
+	*/
+	SpawnTriggers() []SpawnTrigger
+}
+
+/*
+An EntityTriggeredSpawner is a DynamicTrustModelInstanceSpawner that handles arbitrary spawn triggers.
+*/
+type EntityTriggeredSpawner interface {
+	/*
+		OnNewEntity is called in case a new entity has become known by the given trigger. If no instance should be spawned
+		for this entity, the returned TrustModelInstance is nil.
+	*/
+	OnNewEntity(trigger SpawnTrigger, identifier string, params map[string]string) (TrustModelInstance, error)
+}
+
+/*
+TemplateSpawnTriggers returns the triggers spawning instances of a template. Templates triggered by vehicles or trustees
+are spawned by TRIGGER_V2X_SENDER and TRIGGER_TCH_TRUSTEE, respectively.
+*/
+func TemplateSpawnTriggers(tmt TrustModelTemplate) []SpawnTrigger {
+	switch tmt.Type() {
+	case VEHICLE_TRIGGERED_TRUST_MODEL:
+		return []SpawnTrigger{TRIGGER_V2X_SENDER}
+	case TRUSTEE_TRIGGERED_TRUST_MODEL:
+		return []SpawnTrigger{TRIGGER_TCH_TRUSTEE}
+	case ENTITY_TRIGGERED_TRUST_MODEL:
+		if provider, ok := tmt.(SpawnTriggerProvider); ok {
+			return provider.SpawnTriggers()
+		}
+	}
+	return nil
+}
+
+/*
+IsSpawnedBy returns whether instances of a template are spawned by the given trigger.
+*/
+func IsSpawnedBy(tmt TrustModelTemplate, trigger SpawnTrigger) bool {
+	return slices.Contains(TemplateSpawnTriggers(tmt), trigger)
+}
+
+/*
+SpawnOnTrigger calls the callback function of a spawner for a new entity. Spawners that do not implement
+EntityTriggeredSpawner only handle new vehicles and trustees.
+*/
+func SpawnOnTrigger(spawner DynamicTrustModelInstanceSpawner, trigger SpawnTrigger, identifier string, params map[string]string) (TrustModelInstance, error) {
+	if entitySpawner, ok := spawner.(EntityTriggeredSpawner); ok {
+		return entitySpawner.OnNewEntity(trigger, identifier, params)
+	}
+	switch trigger {
+	case TRIGGER_V2X_SENDER:
+		return spawner.OnNewVehicle(identifier, params)
+	case TRIGGER_TCH_TRUSTEE:
+		return spawner.OnNewTrustee(identifier, params)
+	default:
+		return nil, nil
+	}
+}
@@ -78,6 +78,24 @@ type TrustModelInstance interface {
 	String() string
 }
 
+/*
+A SnapshotableTrustModelInstance is a TrustModelInstance that can serialize its internal state (e.g., opinions, trust
+objects, RTLs, and version), so that it can be persisted, handed over, or used as a test fixture.
+*/
+type SnapshotableTrustModelInstance interface {
+	/*
+		Snapshot serializes the internal state of the TMI.
+	*/
+	Snapshot() ([]byte, error)
+
+	/*
+		Restore replaces the internal state of the TMI by the state of a snapshot. The snapshot must have been taken from
+		a TMI of the same TMT; otherwise, or if the snapshot is invalid, the TMI remains unchanged and an error is
+		returned.
+	*/
+	Restore(snapshot []byte) error
+}
+
 /*
 SplitFullTMIIdentifier takes full TMI identifier and returns its components, namely, client, sessionID, TMT@Version, and (short) tmiID.
 */
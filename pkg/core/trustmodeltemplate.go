@@ -1,5 +1,9 @@
 package core
 
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/parameter"
+)
+
 /*
 TrustModelTemplate (TMT) defines a template for a trust model from which concrete instances (TrustModelInstance) can be
 spawned. A TMT specifies the type of trust sources used for a trust model and how evidence gets quantified.
@@ -53,6 +57,41 @@ type TrustModelTemplate interface {
 	SigningHash() string
 }
 
+/*
+A TLEEBackendProvider is a TrustModelTemplate that declares the TLEE backend to be used for evaluating its instances.
+Instances of templates not implementing this interface are evaluated by the configured default backend.
+*/
+type TLEEBackendProvider interface {
+	/*
+		TLEEBackend returns the name of a registered TLEE backend and the options passed to it. An empty name selects the
+		default backend.
+	*/
+	TLEEBackend() (name string, options map[string]string)
+}
+
+/*
+A DecisionPolicyProvider is a TrustModelTemplate that declares the decision policies used for the propositions of its
+instances. Propositions for which no policy is declared are decided by the configured default policy.
+*/
+type DecisionPolicyProvider interface {
+	/*
+		DecisionPolicy returns the decision policy for a proposition, or nil to use the default policy.
+	*/
+	DecisionPolicy(proposition string) DecisionPolicy
+}
+
+/*
+A ParameterSchemaProvider is a TrustModelTemplate that declares the parameters it accepts. The TMM publishes the schema
+and validates the parameters of a TAS_INIT_REQUEST against it before calling Spawn, which thus receives the declared
+parameters by their declared names, including defaults. Parameters not declared in the schema are ignored with a warning.
+*/
+type ParameterSchemaProvider interface {
+	/*
+		ParameterSchema returns the schema of the parameters accepted by Spawn.
+	*/
+	ParameterSchema() parameter.Schema
+}
+
 /*
 DynamicTrustModelInstanceSpawner is a listener that provides callback functions that will be called upon certain triggers.
 A callback function can then spawn a new TrustModelInstance, if appropriate.
@@ -63,7 +102,7 @@ type DynamicTrustModelInstanceSpawner interface {
 	*/
 	OnNewVehicle(identifier string, params map[string]string) (TrustModelInstance, error)
 	/*
-		OnNewVehicle is callback function called in case a new vehicle has become known.
+		OnNewTrustee is callback function called in case a new trustee has become known.
 	*/
 	OnNewTrustee(identifier string, params map[string]string) (TrustModelInstance, error)
 }
@@ -86,6 +125,10 @@ const (
 		A TRUSTEE_TRIGGERED_TRUST_MODEL is a trust model instance that can be spawned with the appearance of previously unknown entity in a TCH messages.
 	*/
 	TRUSTEE_TRIGGERED_TRUST_MODEL
+	/*
+		An ENTITY_TRIGGERED_TRUST_MODEL is a trust model instance that can be spawned with the appearance of previously unknown entities of the spawn triggers declared by its template (see SpawnTriggerProvider).
+	*/
+	ENTITY_TRIGGERED_TRUST_MODEL
 )
 
 func (t TrustModelTemplateType) String() string {
@@ -96,6 +139,8 @@ func (t TrustModelTemplateType) String() string {
 		return "VEHICLE_TRIGGERED_TRUST_MODEL"
 	case TRUSTEE_TRIGGERED_TRUST_MODEL:
 		return "TRUSTEE_TRIGGERED_TRUST_MODEL"
+	case ENTITY_TRIGGERED_TRUST_MODEL:
+		return "ENTITY_TRIGGERED_TRUST_MODEL"
 	default:
 		return "UNKNOWN TYPE"
 	}
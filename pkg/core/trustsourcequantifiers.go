@@ -4,6 +4,8 @@ import "github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
 
 /*
 A Quantifier function takes a list of EvidenceType(s) and their concrete evidence values and calculates a single trust opinion.
+Quantifiers may return a *multinomial.Opinion for multi-valued propositions, which is treated as a binomial opinion on
+its focus values wherever the TAF expects binomial opinions.
 */
 type Quantifier func(values map[EvidenceType]interface{}) subjectivelogic.QueryableOpinion
 
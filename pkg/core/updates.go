@@ -1,6 +1,9 @@
 package core
 
-import "bytes"
+import (
+	"bytes"
+	"strings"
+)
 
 /*
 The UpdateOp identifies the type of Update Operation than can be applied to an existing TrustModelInstance.
@@ -53,3 +56,29 @@ func (u *UpdateOp) MarshalJSON() ([]byte, error) {
 	buffer.WriteString(`"`)
 	return buffer.Bytes(), nil
 }
+
+/*
+ComponentSeparator separates the identifier of a trustee from the identifier of one of its components in the identifier
+of the component, e.g., vehicle_1~ecu. Components are the trust objects of ADD_TRUST_OBJECT and REMOVE_TRUST_OBJECT
+updates emitted by trust source handlers, and the trustees of atomic trust opinions on components.
+*/
+const ComponentSeparator = "~"
+
+/*
+MergeComponentIdentifier creates the identifier of a component of a trustee.
+*/
+func MergeComponentIdentifier(trustee string, component string) string {
+	return trustee + ComponentSeparator + component
+}
+
+/*
+SplitComponentIdentifier splits the identifier of a component into the identifiers of the trustee and the component. If
+the identifier does not denote a component, false is returned.
+*/
+func SplitComponentIdentifier(identifier string) (trustee string, component string, ok bool) {
+	trustee, component, ok = strings.Cut(identifier, ComponentSeparator)
+	if !ok || trustee == "" || component == "" {
+		return "", "", false
+	}
+	return trustee, component, true
+}
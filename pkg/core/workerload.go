@@ -0,0 +1,16 @@
+package core
+
+import "time"
+
+/*
+WorkerLoad is a snapshot of load metrics of a single TAM worker.
+*/
+type WorkerLoad struct {
+	WorkerID            int
+	QueueDepth          int           //number of commands currently waiting in the queue of the worker
+	QueueCapacity       int           //capacity of the queue of the worker
+	TrustModelInstances int           //number of TMIs currently handled by the worker
+	ProcessedCommands   uint64        //total number of commands processed by the worker
+	BusyTime            time.Duration //total time spent processing commands
+	Retiring            bool          //true if the worker is about to be removed by a scale-down operation
+}
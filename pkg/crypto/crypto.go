@@ -162,3 +162,25 @@ func (cr *Crypto) VerifyTchNotify(notify *tchmsg.TchNotify) (bool, error) {
 		return true, nil
 	}
 }
+
+/*
+VerifyTrustModelManifest verifies the signature of a manifest of trust model signing hashes and returns true, false, or
+an error. The signature covers the JSON encoding of the hashes.
+*/
+func (cr *Crypto) VerifyTrustModelManifest(hashes map[string]string, signature string, keyRef string) (bool, error) {
+	if cr.cryptoEnabled {
+		hashesByteStream, err := json.Marshal(hashes)
+		if err != nil {
+			return false, errors.New("failed to encode hashes of trust model manifest")
+		}
+		verificationResult, err := crypto.Verify(hashesByteStream, signature, keyRef+".pem")
+		if err != nil {
+			return false, err
+		} else {
+			return verificationResult, nil
+		}
+	} else {
+		//Don't do anything
+		return true, nil
+	}
+}
@@ -46,9 +46,12 @@ type TrustAssessmentManager interface {
 	HandleTasInitRequest(cmd command.HandleRequest[tasmsg.TasInitRequest])
 	HandleTasTeardownRequest(cmd command.HandleRequest[tasmsg.TasTeardownRequest])
 	HandleTasTaRequest(cmd command.HandleRequest[tasmsg.TasTaRequest])
+	HandleTasRtlUpdateRequest(cmd command.HandleRequest[tasmsg.TasRtlUpdateRequest])
 	HandleTasSubscribeRequest(cmd command.HandleSubscriptionRequest[tasmsg.TasSubscribeRequest])
 	HandleTasUnsubscribeRequest(cmd command.HandleSubscriptionRequest[tasmsg.TasUnsubscribeRequest])
 	HandleTaqiQuery(cmd command.HandleRequest[taqimsg.TaqiQuery])
+	HandleTaqiSubscribeRequest(cmd command.HandleSubscriptionRequest[taqimsg.TaqiSubscribeRequest])
+	HandleTaqiUnsubscribeRequest(cmd command.HandleSubscriptionRequest[taqimsg.TaqiUnsubscribeRequest])
 	DispatchToWorker(session session.Session, tmiID string, cmd core.Command)
 	DispatchToWorkerByFullTMIID(fullTMI string, cmd core.Command)
 	HandleATLUpdate(cmd command.HandleATLUpdate)
@@ -68,6 +71,15 @@ type TrustAssessmentManager interface {
 		executed by the TAM itself in its own context.
 	*/
 	DispatchToSelf(cmd core.Command)
+	/*
+		WorkerLoad returns load metrics of all workers. It may be called from any goroutine.
+	*/
+	WorkerLoad() []core.WorkerLoad
+	/*
+		ScaleWorkers changes the number of workers at runtime by spawning new workers or retiring existing ones. TMIs are
+		migrated between workers without losing pending updates. It may be called from any goroutine.
+	*/
+	ScaleWorkers(count int) error
 	Run()
 }
 
@@ -89,7 +101,7 @@ type TrustSourceManager interface {
 	SubscribeTrustSourceQuantifiers(session session.Session, handler *completionhandler.CompletionHandler)
 	UnsubscribeTrustSourceQuantifiers(session session.Session, handler *completionhandler.CompletionHandler)
 	RegisterCallback(messageType messages.MessageSchema, requestID string, fn func(cmd core.Command))
-	DispatchAivRequest(session session.Session, cmd command.HandleRequest[tasmsg.TasTaRequest])
+	DispatchAivRequest(session session.Session)
 }
 
 /*
@@ -99,10 +111,21 @@ type TrustModelManager interface {
 	SetManagers(managers TafManagers)
 	HandleV2xCpmMessage(cmd command.HandleOneWay[v2xmsg.V2XCpm])
 	HandleTchNotify(cmd command.HandleNotify[tchmsg.TchNotify])
+	HandleMbdNotify(cmd command.HandleNotify[mbdmsg.MBDNotify])
+	HandleV2xNtm(cmd command.HandleNotify[v2xmsg.V2XNtm])
+	HandleAivNotify(cmd command.HandleNotify[aivmsg.AivNotify])
 	HandleTasTmtDiscover(cmd command.HandleRequest[tasmsg.TasTmtDiscover])
 	ResolveTMT(identifier string) core.TrustModelTemplate
+	ValidateParameters(tmt core.TrustModelTemplate, params map[string]string) (map[string]string, error)
+	UnknownParameters(tmt core.TrustModelTemplate, params map[string]string) []string
+	AddTemplate(tmt core.TrustModelTemplate) error
+	DeprecateTemplate(identifier string, deprecated bool) error
+	IsDeprecated(identifier string) bool
+	IsVerified(identifier string) bool
+	RemoveTemplate(identifier string) error
+	HandleTemplateReload(cmd command.HandleTemplateReload)
 	GetAllTMTs() []core.TrustModelTemplate
-	ListRecentV2XNodes() []string
-	ListRecentTrustees() []string
+	ListRecentEntities(trigger core.SpawnTrigger) []string
+	SpawnForRecentEntities(sessionID string)
 	HandleObserverEvent(cmd command.HandleObserverEvent)
 }
@@ -21,13 +21,20 @@ const (
 	MBD_SUBSCRIBE_RESPONSE        = "MBD_SUBSCRIBE_RESPONSE"
 	MBD_UNSUBSCRIBE_REQUEST       = "MBD_UNSUBSCRIBE_REQUEST"
 	MBD_UNSUBSCRIBE_RESPONSE      = "MBD_UNSUBSCRIBE_RESPONSE"
+	TAQI_NOTIFY                   = "TAQI_NOTIFY"
 	TAQI_QUERY                    = "TAQI_QUERY"
 	TAQI_RESULT                   = "TAQI_RESULT"
+	TAQI_SUBSCRIBE_REQUEST        = "TAQI_SUBSCRIBE_REQUEST"
+	TAQI_SUBSCRIBE_RESPONSE       = "TAQI_SUBSCRIBE_RESPONSE"
+	TAQI_UNSUBSCRIBE_REQUEST      = "TAQI_UNSUBSCRIBE_REQUEST"
+	TAQI_UNSUBSCRIBE_RESPONSE     = "TAQI_UNSUBSCRIBE_RESPONSE"
 	TAS_TMT_DISCOVER              = "TAS_TMT_DISCOVER"
 	TAS_TMT_OFFER                 = "TAS_TMT_OFFER"
 	TAS_INIT_REQUEST              = "TAS_INIT_REQUEST"
 	TAS_INIT_RESPONSE             = "TAS_INIT_RESPONSE"
 	TAS_NOTIFY                    = "TAS_NOTIFY"
+	TAS_RTL_UPDATE_REQUEST        = "TAS_RTL_UPDATE_REQUEST"
+	TAS_RTL_UPDATE_RESPONSE       = "TAS_RTL_UPDATE_RESPONSE"
 	TAS_SUBSCRIBE_REQUEST         = "TAS_SUBSCRIBE_REQUEST"
 	TAS_SUBSCRIBE_RESPONSE        = "TAS_SUBSCRIBE_RESPONSE"
 	TAS_TA_REQUEST                = "TAS_TA_REQUEST"
@@ -65,13 +72,20 @@ var SchemaMap = map[string]MessageSchema{
 	MBD_SUBSCRIBE_RESPONSE:        MBD_SUBSCRIBE_RESPONSE,
 	MBD_UNSUBSCRIBE_REQUEST:       MBD_UNSUBSCRIBE_REQUEST,
 	MBD_UNSUBSCRIBE_RESPONSE:      MBD_UNSUBSCRIBE_RESPONSE,
+	TAQI_NOTIFY:                   TAQI_NOTIFY,
 	TAQI_QUERY:                    TAQI_QUERY,
 	TAQI_RESULT:                   TAQI_RESULT,
+	TAQI_SUBSCRIBE_REQUEST:        TAQI_SUBSCRIBE_REQUEST,
+	TAQI_SUBSCRIBE_RESPONSE:       TAQI_SUBSCRIBE_RESPONSE,
+	TAQI_UNSUBSCRIBE_REQUEST:      TAQI_UNSUBSCRIBE_REQUEST,
+	TAQI_UNSUBSCRIBE_RESPONSE:     TAQI_UNSUBSCRIBE_RESPONSE,
 	TAS_TMT_DISCOVER:              TAS_TMT_DISCOVER,
 	TAS_TMT_OFFER:                 TAS_TMT_OFFER,
 	TAS_INIT_REQUEST:              TAS_INIT_REQUEST,
 	TAS_INIT_RESPONSE:             TAS_INIT_RESPONSE,
 	TAS_NOTIFY:                    TAS_NOTIFY,
+	TAS_RTL_UPDATE_REQUEST:        TAS_RTL_UPDATE_REQUEST,
+	TAS_RTL_UPDATE_RESPONSE:       TAS_RTL_UPDATE_RESPONSE,
 	TAS_SUBSCRIBE_REQUEST:         TAS_SUBSCRIBE_REQUEST,
 	TAS_SUBSCRIBE_RESPONSE:        TAS_SUBSCRIBE_RESPONSE,
 	TAS_TA_REQUEST:                TAS_TA_REQUEST,
@@ -109,13 +123,20 @@ var ServiceMap = map[MessageSchema]string{
 	MBD_SUBSCRIBE_RESPONSE:        "ECI",
 	MBD_UNSUBSCRIBE_REQUEST:       "ECI",
 	MBD_UNSUBSCRIBE_RESPONSE:      "ECI",
+	TAQI_NOTIFY:                   "TAQI",
 	TAQI_QUERY:                    "TAQI",
 	TAQI_RESULT:                   "TAQI",
+	TAQI_SUBSCRIBE_REQUEST:        "TAQI",
+	TAQI_SUBSCRIBE_RESPONSE:       "TAQI",
+	TAQI_UNSUBSCRIBE_REQUEST:      "TAQI",
+	TAQI_UNSUBSCRIBE_RESPONSE:     "TAQI",
 	TAS_TMT_DISCOVER:              "TAS",
 	TAS_TMT_OFFER:                 "TAS",
 	TAS_INIT_REQUEST:              "TAS",
 	TAS_INIT_RESPONSE:             "TAS",
 	TAS_NOTIFY:                    "TAS",
+	TAS_RTL_UPDATE_REQUEST:        "TAS",
+	TAS_RTL_UPDATE_RESPONSE:       "TAS",
 	TAS_SUBSCRIBE_REQUEST:         "TAS",
 	TAS_SUBSCRIBE_RESPONSE:        "TAS",
 	TAS_TA_REQUEST:                "TAS",
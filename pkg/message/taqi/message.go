@@ -1,16 +1,43 @@
 // This file was generated from JSON Schema using quicktype, do not modify it directly.
 // To parse and unparse this JSON data, add this code to your project and do:
 //
+//    taqiNotify, err := UnmarshalTaqiNotify(bytes)
+//    bytes, err = taqiNotify.Marshal()
+//
 //    taqiQuery, err := UnmarshalTaqiQuery(bytes)
 //    bytes, err = taqiQuery.Marshal()
 //
 //    taqiResult, err := UnmarshalTaqiResult(bytes)
 //    bytes, err = taqiResult.Marshal()
+//
+//    taqiSubscribeRequest, err := UnmarshalTaqiSubscribeRequest(bytes)
+//    bytes, err = taqiSubscribeRequest.Marshal()
+//
+//    taqiSubscribeResponse, err := UnmarshalTaqiSubscribeResponse(bytes)
+//    bytes, err = taqiSubscribeResponse.Marshal()
+//
+//    taqiUnsubscribeRequest, err := UnmarshalTaqiUnsubscribeRequest(bytes)
+//    bytes, err = taqiUnsubscribeRequest.Marshal()
+//
+//    taqiUnsubscribeResponse, err := UnmarshalTaqiUnsubscribeResponse(bytes)
+//    bytes, err = taqiUnsubscribeResponse.Marshal()
 
 package taqimsg
 
+import "time"
+
 import "encoding/json"
 
+func UnmarshalTaqiNotify(data []byte) (TaqiNotify, error) {
+	var r TaqiNotify
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *TaqiNotify) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
 func UnmarshalTaqiQuery(data []byte) (TaqiQuery, error) {
 	var r TaqiQuery
 	err := json.Unmarshal(data, &r)
@@ -31,33 +58,69 @@ func (r *TaqiResult) Marshal() ([]byte, error) {
 	return json.Marshal(r)
 }
 
-type TaqiQuery struct {
-	// The query parameters
-	Query Query `json:"query"`
+func UnmarshalTaqiSubscribeRequest(data []byte) (TaqiSubscribeRequest, error) {
+	var r TaqiSubscribeRequest
+	err := json.Unmarshal(data, &r)
+	return r, err
 }
 
-// The query parameters
-type Query struct {
-	// Identifier of the trust model instance.
-	Identifier string `json:"identifier"`
-	// A potentially empty list of propositions.
-	Propositions []string `json:"propositions"`
-	// The trust model template to be used at the target turst model instance.
-	Template string `json:"template"`
+func (r *TaqiSubscribeRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
 }
 
-type TaqiResult struct {
-	Error   *string  `json:"error,omitempty"`
-	Results []Result `json:"results,omitempty"`
+func UnmarshalTaqiSubscribeResponse(data []byte) (TaqiSubscribeResponse, error) {
+	var r TaqiSubscribeResponse
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *TaqiSubscribeResponse) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func UnmarshalTaqiUnsubscribeRequest(data []byte) (TaqiUnsubscribeRequest, error) {
+	var r TaqiUnsubscribeRequest
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *TaqiUnsubscribeRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func UnmarshalTaqiUnsubscribeResponse(data []byte) (TaqiUnsubscribeResponse, error) {
+	var r TaqiUnsubscribeResponse
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *TaqiUnsubscribeResponse) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type TaqiNotify struct {
+	// Identifiers of trust model instances matching the subscription that have been removed.
+	Removed []string `json:"removed,omitempty"`
+	// The latest results of trust model instances matching the subscription that have changed.
+	Results        []Result `json:"results,omitempty"`
+	SubscriptionID string   `json:"subscriptionId"`
 }
 
 type Result struct {
 	// The identifier of the trust model instance.
 	ID           string        `json:"id"`
 	Propositions []Proposition `json:"propositions"`
+	// The tag of the latest evidence message included in the result, if available.
+	Tag *string `json:"tag,omitempty"`
+	// The point in time (RFC 3339) at which the result has been computed.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	// The internal version of the trust model instance the result is based upon.
+	Version *int64 `json:"version,omitempty"`
 }
 
 type Proposition struct {
+	// The actual trustworthiness level of the proposition. An empty list indicates that the proposition has been
+	// removed from the trust model instance.
 	ActualTrustworthinessLevel []ActualTrustworthinessLevel `json:"actualTrustworthinessLevel"`
 	// The identifier of the proposition.
 	PropositionID string `json:"propositionId"`
@@ -71,16 +134,120 @@ type ActualTrustworthinessLevel struct {
 }
 
 type Output struct {
-	BaseRate    *float64 `json:"baseRate,omitempty"`
-	Belief      *float64 `json:"belief,omitempty"`
-	Disbelief   *float64 `json:"disbelief,omitempty"`
-	Uncertainty *float64 `json:"uncertainty,omitempty"`
-	Value       *float64 `json:"value,omitempty"`
+	BaseRate  *float64           `json:"baseRate,omitempty"`
+	BaseRates map[string]float64 `json:"baseRates,omitempty"`
+	Belief    *float64           `json:"belief,omitempty"`
+	// Belief masses of values and composite values (values separated by '|').
+	Beliefs   map[string]float64 `json:"beliefs,omitempty"`
+	Disbelief *float64           `json:"disbelief,omitempty"`
+	// The values that fulfill the proposition.
+	Focus                  []string           `json:"focus,omitempty"`
+	ProjectedProbabilities map[string]float64 `json:"projectedProbabilities,omitempty"`
+	Uncertainty            *float64           `json:"uncertainty,omitempty"`
+	Value                  *float64           `json:"value,omitempty"`
+}
+
+type TaqiQuery struct {
+	// The query parameters
+	Query Query `json:"query"`
+}
+
+// The query parameters
+type Query struct {
+	// Maximum time (in msec) to wait for the requirements to be met. If omitted, the default
+	// deadline of the TAF is used.
+	Deadline *int64 `json:"deadline,omitempty"`
+	// Identifier of the trust model instance.
+	Identifier string `json:"identifier"`
+	// A potentially empty list of propositions.
+	Propositions []string `json:"propositions"`
+	// Optional list of requirements on the state of matching trust model instances. The result
+	// is only sent once each matching trust model instance with the given identifier has
+	// reflected the given tag and/or reached the given version, or when the deadline expires.
+	// Cannot be combined with historic queries.
+	Requires []Requirement `json:"requires,omitempty"`
+	// The trust model template to be used at the target turst model instance.
+	Template string `json:"template"`
+	// Optional point in time (RFC 3339). If set, the results that were valid at that time are
+	// returned instead of the latest results.
+	TimePoint *time.Time `json:"timePoint,omitempty"`
+	// Optional time range. If set, all results computed within that range are returned instead
+	// of the latest results.
+	TimeRange *TimeRange `json:"timeRange,omitempty"`
+}
+
+type Requirement struct {
+	// The identifier of the trust model instance.
+	ID string `json:"id"`
+	// Minimum version of the trust model instance the result must be based upon.
+	MinVersion *int64 `json:"minVersion,omitempty"`
+	// Tag of an evidence message that must have been reflected in the result.
+	Tag *string `json:"tag,omitempty"`
+}
+
+// Optional time range. If set, all results computed within that range are returned instead
+// of the latest results.
+type TimeRange struct {
+	// Start of the time range (RFC 3339, inclusive).
+	From time.Time `json:"from"`
+	// End of the time range (RFC 3339, inclusive).
+	To time.Time `json:"to"`
+}
+
+type TaqiResult struct {
+	Error   *string  `json:"error,omitempty"`
+	Results []Result `json:"results,omitempty"`
+}
+
+type TaqiSubscribeRequest struct {
+	// The selector of the trust model instances to be included in the subscription, independent
+	// of the sessions they belong to.
+	Subscribe Subscribe `json:"subscribe"`
+	// The trigger to be used for dispatching notifications upon a change in values. Defaults to
+	// ACTUAL_TRUSTWORTHINESS_LEVEL.
+	Trigger *Trigger `json:"trigger,omitempty"`
+}
+
+// The selector of the trust model instances to be included in the subscription, independent
+// of the sessions they belong to.
+type Subscribe struct {
+	// Identifier of the trust model instances. Supports the wildcard '*' as well as prefix
+	// (e.g., 'vehicle_*') and suffix (e.g., '*_42') patterns.
+	Identifier string `json:"identifier"`
+	// A potentially empty list of propositions. If empty, all propositions are included.
+	Propositions []string `json:"propositions"`
+	// The trust model template of the target trust model instances.
+	Template string `json:"template"`
+}
+
+type TaqiSubscribeResponse struct {
+	Error          *string `json:"error,omitempty"`
+	SubscriptionID *string `json:"subscriptionId,omitempty"`
+	Success        *string `json:"success,omitempty"`
+}
+
+type TaqiUnsubscribeRequest struct {
+	SubscriptionID string `json:"subscriptionId"`
+}
+
+type TaqiUnsubscribeResponse struct {
+	Error   *string `json:"error,omitempty"`
+	Success *string `json:"success,omitempty"`
 }
 
 type Type string
 
 const (
+	MultinomialOpinion     Type = "MULTINOMIAL_OPINION"
 	ProjectedProbability   Type = "PROJECTED_PROBABILITY"
 	SubjectiveLogicOpinion Type = "SUBJECTIVE_LOGIC_OPINION"
 )
+
+// The trigger to be used for dispatching notifications upon a change in values. Defaults to
+// ACTUAL_TRUSTWORTHINESS_LEVEL.
+type Trigger string
+
+const (
+	ActualTrustworthinessLevelTrigger Trigger = "ACTUAL_TRUSTWORTHINESS_LEVEL"
+	TrustDecisionTrigger              Trigger = "TRUST_DECISION"
+)
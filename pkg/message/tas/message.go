@@ -10,6 +10,12 @@
 //    tasNotify, err := UnmarshalTasNotify(bytes)
 //    bytes, err = tasNotify.Marshal()
 //
+//    tasRtlUpdateRequest, err := UnmarshalTasRtlUpdateRequest(bytes)
+//    bytes, err = tasRtlUpdateRequest.Marshal()
+//
+//    tasRtlUpdateResponse, err := UnmarshalTasRtlUpdateResponse(bytes)
+//    bytes, err = tasRtlUpdateResponse.Marshal()
+//
 //    tasSubscribeRequest, err := UnmarshalTasSubscribeRequest(bytes)
 //    bytes, err = tasSubscribeRequest.Marshal()
 //
@@ -74,6 +80,26 @@ func (r *TasNotify) Marshal() ([]byte, error) {
 	return json.Marshal(r)
 }
 
+func UnmarshalTasRtlUpdateRequest(data []byte) (TasRtlUpdateRequest, error) {
+	var r TasRtlUpdateRequest
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *TasRtlUpdateRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func UnmarshalTasRtlUpdateResponse(data []byte) (TasRtlUpdateResponse, error) {
+	var r TasRtlUpdateResponse
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *TasRtlUpdateResponse) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
 func UnmarshalTasSubscribeRequest(data []byte) (TasSubscribeRequest, error) {
 	var r TasSubscribeRequest
 	err := json.Unmarshal(data, &r)
@@ -186,6 +212,9 @@ type TasInitResponse struct {
 	Error                  *string `json:"error,omitempty"`
 	SessionID              *string `json:"sessionId,omitempty"`
 	Success                *string `json:"success,omitempty"`
+	// A warning for the client, e.g., if the requested trust model template version is
+	// deprecated.
+	Warning *string `json:"warning,omitempty"`
 }
 
 type TasNotify struct {
@@ -214,6 +243,8 @@ type UpdateProposition struct {
 	PropositionID string `json:"propositionId"`
 	// The result of the trust decision engine.
 	TrustDecision *bool `json:"trustDecision"`
+	// The name of the decision policy used by the trust decision engine.
+	DecisionPolicy *string `json:"decisionPolicy,omitempty"`
 }
 
 type PurpleActualTrustworthinessLevel struct {
@@ -222,11 +253,17 @@ type PurpleActualTrustworthinessLevel struct {
 }
 
 type PurpleOutput struct {
-	BaseRate    *float64 `json:"baseRate,omitempty"`
-	Belief      *float64 `json:"belief,omitempty"`
-	Disbelief   *float64 `json:"disbelief,omitempty"`
-	Uncertainty *float64 `json:"uncertainty,omitempty"`
-	Value       *float64 `json:"value,omitempty"`
+	BaseRate  *float64           `json:"baseRate,omitempty"`
+	BaseRates map[string]float64 `json:"baseRates,omitempty"`
+	Belief    *float64           `json:"belief,omitempty"`
+	// Belief masses of values and composite values (values separated by '|').
+	Beliefs   map[string]float64 `json:"beliefs,omitempty"`
+	Disbelief *float64           `json:"disbelief,omitempty"`
+	// The values that fulfill the proposition.
+	Focus                  []string           `json:"focus,omitempty"`
+	ProjectedProbabilities map[string]float64 `json:"projectedProbabilities,omitempty"`
+	Uncertainty            *float64           `json:"uncertainty,omitempty"`
+	Value                  *float64           `json:"value,omitempty"`
 }
 
 type TasSubscribeRequest struct {
@@ -258,9 +295,41 @@ type TasSubscribeResponse struct {
 type TasTaRequest struct {
 	// If false, the TAF will recalculate all results without usings its cache.
 	AllowCache *bool `json:"allowCache,omitempty"`
+	// If true, the response includes an explanation of the trust decision for each proposition,
+	// if explanations are enabled in the TAF.
+	Explain *bool `json:"explain,omitempty"`
+	// Optional freshness requirements for the results. If the requirements are not met by the
+	// cached results, the TAF waits for fresh results until the deadline expires.
+	Freshness *Freshness `json:"freshness,omitempty"`
 	// The query selector
-	Query     Query  `json:"query"`
-	SessionID string `json:"sessionId"`
+	Query Query `json:"query"`
+	// Optional list of requirements on the state of target trust model instances. The response
+	// is only sent once each listed trust model instance has reflected the given tag and/or
+	// reached the given version, or when the deadline (see freshness) expires.
+	Requires  []Requirement `json:"requires,omitempty"`
+	SessionID string        `json:"sessionId"`
+}
+
+type Requirement struct {
+	// The identifier of the trust model instance.
+	ID string `json:"id"`
+	// Minimum version of the trust model instance the result must be based upon.
+	MinVersion *int64 `json:"minVersion,omitempty"`
+	// Tag of an evidence message that must have been reflected in the result.
+	Tag *string `json:"tag,omitempty"`
+}
+
+// Optional freshness requirements for the results. If the requirements are not met by the
+// cached results, the TAF waits for fresh results until the deadline expires.
+type Freshness struct {
+	// Maximum time (in msec) to wait for fresh results. If omitted, the default deadline of the
+	// TAF is used.
+	Deadline *int64 `json:"deadline,omitempty"`
+	// Maximum age (in msec) of a cached result to be returned.
+	MaxAge *int64 `json:"maxAge,omitempty"`
+	// Behavior when the deadline expires before fresh results are available: FAIL returns an
+	// error, STALE returns the latest available results. Defaults to FAIL.
+	OnTimeout *OnTimeout `json:"onTimeout,omitempty"`
 }
 
 // The query selector
@@ -276,6 +345,8 @@ type TasTaResponse struct {
 	Error                  *string  `json:"error,omitempty"`
 	Results                []Result `json:"results,omitempty"`
 	SessionID              string   `json:"sessionId"`
+	// Set to true if the deadline expired and the results do not meet the requested freshness.
+	Stale *bool `json:"stale,omitempty"`
 }
 
 type Result struct {
@@ -295,6 +366,45 @@ type ResultProposition struct {
 	PropositionID string `json:"propositionId"`
 	// The result of the trust decision engine.
 	TrustDecision *bool `json:"trustDecision"`
+	// The name of the decision policy used by the trust decision engine.
+	DecisionPolicy *string `json:"decisionPolicy,omitempty"`
+	// Explanation of the trust decision, only included if requested.
+	Explanation *Explanation `json:"explanation,omitempty"`
+}
+
+// Explanation of the trust decision, only included if requested.
+type Explanation struct {
+	ATL                     Opinion `json:"atl"`
+	ATLProjectedProbability float64 `json:"atlProjectedProbability"`
+	// The trust relationships in the scope of the proposition that led to the ATL.
+	Contributions           []Contribution `json:"contributions"`
+	RTL                     Opinion        `json:"rtl"`
+	RTLProjectedProbability float64        `json:"rtlProjectedProbability"`
+	// Textual description of the decision rule that has been applied.
+	Rule string `json:"rule"`
+}
+
+type Opinion struct {
+	BaseRate    float64 `json:"baseRate"`
+	Belief      float64 `json:"belief"`
+	Disbelief   float64 `json:"disbelief"`
+	Uncertainty float64 `json:"uncertainty"`
+}
+
+type Contribution struct {
+	Opinion Opinion `json:"opinion"`
+	// Origin of the latest opinion of each trust source on this trust relationship.
+	Provenance []Provenance `json:"provenance"`
+	Trustee    string       `json:"trustee"`
+	Trustor    string       `json:"trustor"`
+}
+
+type Provenance struct {
+	// Evidence types and values the opinion has been quantified from.
+	Evidence    map[string]interface{} `json:"evidence,omitempty"`
+	Tag         *string                `json:"tag,omitempty"`
+	Timestamp   *string                `json:"timestamp,omitempty"`
+	TrustSource string                 `json:"trustSource"`
 }
 
 type FluffyActualTrustworthinessLevel struct {
@@ -303,11 +413,43 @@ type FluffyActualTrustworthinessLevel struct {
 }
 
 type FluffyOutput struct {
-	BaseRate    *float64 `json:"baseRate,omitempty"`
-	Belief      *float64 `json:"belief,omitempty"`
-	Disbelief   *float64 `json:"disbelief,omitempty"`
-	Uncertainty *float64 `json:"uncertainty,omitempty"`
-	Value       *float64 `json:"value,omitempty"`
+	BaseRate  *float64           `json:"baseRate,omitempty"`
+	BaseRates map[string]float64 `json:"baseRates,omitempty"`
+	Belief    *float64           `json:"belief,omitempty"`
+	// Belief masses of values and composite values (values separated by '|').
+	Beliefs   map[string]float64 `json:"beliefs,omitempty"`
+	Disbelief *float64           `json:"disbelief,omitempty"`
+	// The values that fulfill the proposition.
+	Focus                  []string           `json:"focus,omitempty"`
+	ProjectedProbabilities map[string]float64 `json:"projectedProbabilities,omitempty"`
+	Uncertainty            *float64           `json:"uncertainty,omitempty"`
+	Value                  *float64           `json:"value,omitempty"`
+}
+
+type TasRtlUpdateRequest struct {
+	SessionID string `json:"sessionId"`
+	// List of RTL overrides to be applied. Trust decisions of the affected trust model instances
+	// are re-evaluated immediately.
+	Updates []RtlUpdate `json:"updates"`
+}
+
+type RtlUpdate struct {
+	// The identifier of the trust model instance. If omitted, the RTL applies to all trust model
+	// instances of the session.
+	ID *string `json:"id,omitempty"`
+	// The identifier of the proposition. If omitted, the RTL applies to all propositions.
+	PropositionID *string `json:"propositionId,omitempty"`
+	// The new RTL. If omitted, a previous override for the same trust model instance and
+	// proposition is removed.
+	Rtl *Opinion `json:"rtl,omitempty"`
+}
+
+type TasRtlUpdateResponse struct {
+	// The certificate (*base64 string*) issued by the IAM, attesting to the correct execution
+	// of the TAF within an enclave.
+	AttestationCertificate string  `json:"attestationCertificate"`
+	Error                  *string `json:"error,omitempty"`
+	Success                *string `json:"success,omitempty"`
 }
 
 type TasTeardownRequest struct {
@@ -331,10 +473,43 @@ type TasTmtOffer struct {
 }
 
 type TrustModelTemplate struct {
+	// Whether the template version is deprecated. New sessions can still be initialized, but
+	// clients should switch to another version.
+	Deprecated  *bool  `json:"deprecated,omitempty"`
 	Description string `json:"description"`
 	Hash        string `json:"hash"`
 	Name        string `json:"name"`
-	Version     string `json:"version"`
+	// The parameters accepted in the params of a TAS_INIT_REQUEST for this template. Omitted if
+	// the template does not declare its parameters.
+	ParameterSchema *ParameterSchema `json:"parameterSchema,omitempty"`
+	// Whether the signing hash of the template version is attested by the signed manifest of
+	// the TAF.
+	Verified *bool  `json:"verified,omitempty"`
+	Version  string `json:"version"`
+}
+
+type ParameterSchema struct {
+	Constraints []Constraint `json:"constraints,omitempty"`
+	Parameters  []Parameter  `json:"parameters"`
+}
+
+type Constraint struct {
+	Description *string        `json:"description,omitempty"`
+	Limit       *float64       `json:"limit,omitempty"`
+	Parameters  []string       `json:"parameters"`
+	Type        ConstraintType `json:"type"`
+}
+
+type Parameter struct {
+	Default     *string       `json:"default,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Maximum     *float64      `json:"maximum,omitempty"`
+	Minimum     *float64      `json:"minimum,omitempty"`
+	Name        string        `json:"name"`
+	Required    *bool         `json:"required,omitempty"`
+	Type        ParameterType `json:"type"`
+	// The admissible values of a STRING parameter.
+	Values []string `json:"values,omitempty"`
 }
 
 type TasUnsubscribeRequest struct {
@@ -354,6 +529,7 @@ type TasUnsubscribeResponse struct {
 type Type string
 
 const (
+	MultinomialOpinion     Type = "MULTINOMIAL_OPINION"
 	ProjectedProbability   Type = "PROJECTED_PROBABILITY"
 	SubjectiveLogicOpinion Type = "SUBJECTIVE_LOGIC_OPINION"
 )
@@ -365,3 +541,29 @@ const (
 	ActualTrustworthinessLevel Trigger = "ACTUAL_TRUSTWORTHINESS_LEVEL"
 	TrustDecision              Trigger = "TRUST_DECISION"
 )
+
+// Behavior when the deadline expires before fresh results are available: FAIL returns an
+// error, STALE returns the latest available results. Defaults to FAIL.
+type OnTimeout string
+
+const (
+	Fail  OnTimeout = "FAIL"
+	Stale OnTimeout = "STALE"
+)
+
+type ConstraintType string
+
+const (
+	AllOrNone ConstraintType = "ALL_OR_NONE"
+	SumAtMost ConstraintType = "SUM_AT_MOST"
+	SumEquals ConstraintType = "SUM_EQUALS"
+)
+
+type ParameterType string
+
+const (
+	Boolean ParameterType = "BOOLEAN"
+	Integer ParameterType = "INTEGER"
+	Number  ParameterType = "NUMBER"
+	String  ParameterType = "STRING"
+)
@@ -0,0 +1,109 @@
+package multinomial
+
+import (
+	"fmt"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"slices"
+)
+
+/*
+Discount discounts an opinion by a binomial trust opinion on its source (e.g., a referral path of a trust graph). Belief
+masses are scaled by the projected probability of the trust opinion; the remaining mass becomes uncertainty. Composite
+values are retained, so that discounting a hyper-opinion yields a hyper-opinion.
+*/
+func Discount(trust subjectivelogic.QueryableOpinion, opinion *Opinion) *Opinion {
+	probability := trust.Belief() + trust.Uncertainty()*trust.BaseRate()
+	discounted := &Opinion{
+		domain:      opinion.domain,
+		beliefs:     make(map[string]float64, len(opinion.beliefs)),
+		uncertainty: 1 - probability*(1-opinion.uncertainty),
+		baseRates:   opinion.baseRates,
+		focus:       opinion.focus,
+	}
+	for key, belief := range opinion.beliefs {
+		discounted.beliefs[key] = probability * belief
+	}
+	return discounted
+}
+
+/*
+CumulativeFusion fuses two opinions from independent sources whose evidence accumulates. Hyper-opinions are projected onto
+multinomial opinions before fusion. Both opinions must share domain and focus.
+*/
+func CumulativeFusion(first, second *Opinion) (*Opinion, error) {
+	if err := checkCompatible(first, second); err != nil {
+		return nil, err
+	}
+	first, second = first.Multinomial(), second.Multinomial()
+	u1, u2 := first.uncertainty, second.uncertainty
+	fused := emptyLike(first)
+
+	if u1 == 0 && u2 == 0 {
+		for _, value := range first.domain {
+			fused.beliefs[value] = (first.beliefs[value] + second.beliefs[value]) / 2
+			fused.baseRates[value] = (first.baseRates[value] + second.baseRates[value]) / 2
+		}
+		fused.uncertainty = 0
+		return fused, nil
+	}
+
+	denominator := u1 + u2 - u1*u2
+	for _, value := range first.domain {
+		fused.beliefs[value] = (first.beliefs[value]*u2 + second.beliefs[value]*u1) / denominator
+		a1, a2 := first.baseRates[value], second.baseRates[value]
+		if u1 == 1 && u2 == 1 {
+			fused.baseRates[value] = (a1 + a2) / 2
+		} else {
+			fused.baseRates[value] = (a1*u2 + a2*u1 - (a1+a2)*u1*u2) / (u1 + u2 - 2*u1*u2)
+		}
+	}
+	fused.uncertainty = u1 * u2 / denominator
+	return fused, nil
+}
+
+/*
+AveragingFusion fuses two opinions from dependent sources by averaging their evidence. Hyper-opinions are projected onto
+multinomial opinions before fusion. Both opinions must share domain and focus.
+*/
+func AveragingFusion(first, second *Opinion) (*Opinion, error) {
+	if err := checkCompatible(first, second); err != nil {
+		return nil, err
+	}
+	first, second = first.Multinomial(), second.Multinomial()
+	u1, u2 := first.uncertainty, second.uncertainty
+	fused := emptyLike(first)
+
+	for _, value := range first.domain {
+		fused.baseRates[value] = (first.baseRates[value] + second.baseRates[value]) / 2
+		if u1 == 0 && u2 == 0 {
+			fused.beliefs[value] = (first.beliefs[value] + second.beliefs[value]) / 2
+		} else {
+			fused.beliefs[value] = (first.beliefs[value]*u2 + second.beliefs[value]*u1) / (u1 + u2)
+		}
+	}
+	if u1 == 0 && u2 == 0 {
+		fused.uncertainty = 0
+	} else {
+		fused.uncertainty = 2 * u1 * u2 / (u1 + u2)
+	}
+	return fused, nil
+}
+
+func checkCompatible(first, second *Opinion) error {
+	if !slices.Equal(first.domain, second.domain) {
+		return fmt.Errorf("%w: domains [%v] and [%v] differ", ErrInvalidOpinion, first.domain, second.domain)
+	}
+	if !slices.Equal(first.focus, second.focus) {
+		return fmt.Errorf("%w: focus values [%v] and [%v] differ", ErrInvalidOpinion, first.focus, second.focus)
+	}
+	return nil
+}
+
+func emptyLike(opinion *Opinion) *Opinion {
+	return &Opinion{
+		domain:    opinion.domain,
+		beliefs:   make(map[string]float64, len(opinion.domain)),
+		baseRates: make(map[string]float64, len(opinion.domain)),
+		focus:     opinion.focus,
+	}
+}
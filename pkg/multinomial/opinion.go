@@ -0,0 +1,379 @@
+/*
+Package multinomial provides multinomial opinions and hyper-opinions of subjective logic for propositions with more than
+two mutually exclusive values (e.g., the operational state of a compute node).
+
+An Opinion also implements subjectivelogic.QueryableOpinion, so that it can be used wherever the TAF expects binomial
+opinions. The binomial view of an Opinion is its coarsening onto the focus values, i.e., the values that fulfill the
+proposition (e.g., "RUNNING" and "DEGRADED"): belief is the belief mass of the focus values, disbelief the belief mass of
+all other values, and the base rate the sum of the base rates of the focus values.
+*/
+package multinomial
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+/*
+CompositeSeparator separates the values of a composite value (e.g., "DEGRADED|FAILED"). Belief mass assigned to a composite
+value supports the composite value as a whole, but none of its values in particular, which turns the opinion into a
+hyper-opinion.
+*/
+const CompositeSeparator = "|"
+
+/*
+Precision is the maximum deviation of sums of belief masses and base rates from 1 for valid opinions.
+*/
+const Precision = 1e-9
+
+var ErrInvalidOpinion = errors.New("invalid multinomial opinion")
+
+/*
+An Opinion is a multinomial opinion or hyper-opinion over a domain of values. Opinions are immutable.
+*/
+type Opinion struct {
+	//sorted values of the domain
+	domain []string
+	//value or normalized composite value->belief mass
+	beliefs     map[string]float64
+	uncertainty float64
+	//value->base rate
+	baseRates map[string]float64
+	//sorted values that fulfill the proposition
+	focus []string
+}
+
+/*
+New creates a new opinion. The domain of the opinion is given by the values of baseRates, which must sum up to 1. The keys
+of beliefs are values of the domain or composite values; belief masses and the uncertainty must sum up to 1. Focus lists
+the values that fulfill the proposition; it must neither be empty nor contain all values of the domain.
+*/
+func New(beliefs map[string]float64, uncertainty float64, baseRates map[string]float64, focus []string) (*Opinion, error) {
+	if len(baseRates) < 2 {
+		return nil, fmt.Errorf("%w: domain must contain at least two values", ErrInvalidOpinion)
+	}
+	opinion := &Opinion{
+		domain:      make([]string, 0, len(baseRates)),
+		beliefs:     make(map[string]float64, len(beliefs)),
+		uncertainty: uncertainty,
+		baseRates:   make(map[string]float64, len(baseRates)),
+	}
+	baseRateSum := 0.0
+	for value, baseRate := range baseRates {
+		if value == "" || strings.Contains(value, CompositeSeparator) {
+			return nil, fmt.Errorf("%w: invalid value '%s'", ErrInvalidOpinion, value)
+		}
+		if !isProbability(baseRate) {
+			return nil, fmt.Errorf("%w: base rate of %s out of range", ErrInvalidOpinion, value)
+		}
+		opinion.domain = append(opinion.domain, value)
+		opinion.baseRates[value] = baseRate
+		baseRateSum += baseRate
+	}
+	slices.Sort(opinion.domain)
+	if math.Abs(baseRateSum-1) > Precision {
+		return nil, fmt.Errorf("%w: base rates sum up to %f", ErrInvalidOpinion, baseRateSum)
+	}
+
+	if !isProbability(uncertainty) {
+		return nil, fmt.Errorf("%w: uncertainty out of range", ErrInvalidOpinion)
+	}
+	massSum := uncertainty
+	for value, belief := range beliefs {
+		key, err := opinion.normalize(value)
+		if err != nil {
+			return nil, err
+		}
+		if !isProbability(belief) {
+			return nil, fmt.Errorf("%w: belief of %s out of range", ErrInvalidOpinion, value)
+		}
+		opinion.beliefs[key] += belief
+		massSum += belief
+	}
+	if math.Abs(massSum-1) > Precision {
+		return nil, fmt.Errorf("%w: belief masses and uncertainty sum up to %f", ErrInvalidOpinion, massSum)
+	}
+
+	for _, value := range focus {
+		if _, exists := opinion.baseRates[value]; !exists {
+			return nil, fmt.Errorf("%w: focus value '%s' not in domain", ErrInvalidOpinion, value)
+		}
+		if !slices.Contains(opinion.focus, value) {
+			opinion.focus = append(opinion.focus, value)
+		}
+	}
+	slices.Sort(opinion.focus)
+	if len(opinion.focus) == 0 || len(opinion.focus) == len(opinion.domain) {
+		return nil, fmt.Errorf("%w: focus must be a non-empty, proper subset of the domain", ErrInvalidOpinion)
+	}
+	return opinion, nil
+}
+
+/*
+normalize checks that a value or composite value is part of the domain and returns its canonical form, with the values of
+composite values sorted. Composite values must contain at least two, but not all values of the domain.
+*/
+func (o *Opinion) normalize(value string) (string, error) {
+	values := strings.Split(value, CompositeSeparator)
+	for _, v := range values {
+		if _, exists := o.baseRates[v]; !exists {
+			return "", fmt.Errorf("%w: value '%s' not in domain", ErrInvalidOpinion, v)
+		}
+	}
+	slices.Sort(values)
+	values = slices.Compact(values)
+	if len(values) == len(o.domain) {
+		return "", fmt.Errorf("%w: composite value '%s' covers the whole domain; use uncertainty instead", ErrInvalidOpinion, value)
+	}
+	return strings.Join(values, CompositeSeparator), nil
+}
+
+func isProbability(value float64) bool {
+	return value >= 0 && value <= 1
+}
+
+/*
+Domain returns the sorted values of the domain.
+*/
+func (o *Opinion) Domain() []string {
+	return slices.Clone(o.domain)
+}
+
+/*
+Focus returns the sorted values that fulfill the proposition.
+*/
+func (o *Opinion) Focus() []string {
+	return slices.Clone(o.focus)
+}
+
+/*
+Beliefs returns the belief masses of all values and composite values with belief mass.
+*/
+func (o *Opinion) Beliefs() map[string]float64 {
+	beliefs := make(map[string]float64, len(o.beliefs))
+	for value, belief := range o.beliefs {
+		if belief > 0 {
+			beliefs[value] = belief
+		}
+	}
+	return beliefs
+}
+
+/*
+BeliefOf returns the belief mass of a value or composite value.
+*/
+func (o *Opinion) BeliefOf(value string) float64 {
+	key, err := o.normalize(value)
+	if err != nil {
+		return 0
+	}
+	return o.beliefs[key]
+}
+
+/*
+BaseRates returns the base rates of all values of the domain.
+*/
+func (o *Opinion) BaseRates() map[string]float64 {
+	baseRates := make(map[string]float64, len(o.baseRates))
+	for value, baseRate := range o.baseRates {
+		baseRates[value] = baseRate
+	}
+	return baseRates
+}
+
+/*
+BaseRateOf returns the base rate of a value of the domain.
+*/
+func (o *Opinion) BaseRateOf(value string) float64 {
+	return o.baseRates[value]
+}
+
+/*
+IsHyperOpinion returns whether belief mass is assigned to composite values.
+*/
+func (o *Opinion) IsHyperOpinion() bool {
+	for value, belief := range o.beliefs {
+		if belief > 0 && strings.Contains(value, CompositeSeparator) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Multinomial returns the projection of a hyper-opinion onto a multinomial opinion, which distributes the belief mass of
+each composite value over its values according to their relative base rates. Multinomial opinions are returned as is.
+*/
+func (o *Opinion) Multinomial() *Opinion {
+	if !o.IsHyperOpinion() {
+		return o
+	}
+	projected := &Opinion{
+		domain:      o.domain,
+		beliefs:     make(map[string]float64, len(o.domain)),
+		uncertainty: o.uncertainty,
+		baseRates:   o.baseRates,
+		focus:       o.focus,
+	}
+	for key, belief := range o.beliefs {
+		values := strings.Split(key, CompositeSeparator)
+		compositeBaseRate := 0.0
+		for _, value := range values {
+			compositeBaseRate += o.baseRates[value]
+		}
+		for _, value := range values {
+			share := 1 / float64(len(values))
+			if compositeBaseRate > 0 {
+				share = o.baseRates[value] / compositeBaseRate
+			}
+			projected.beliefs[value] += belief * share
+		}
+	}
+	return projected
+}
+
+/*
+ProjectedProbabilities returns the projected probability of each value of the domain.
+*/
+func (o *Opinion) ProjectedProbabilities() map[string]float64 {
+	multinomial := o.Multinomial()
+	probabilities := make(map[string]float64, len(o.domain))
+	for _, value := range o.domain {
+		probabilities[value] = multinomial.beliefs[value] + o.baseRates[value]*o.uncertainty
+	}
+	return probabilities
+}
+
+/*
+ProjectedProbability returns the projected probability of the focus values, which equals the projected probability of
+the binomial view of the opinion.
+*/
+func (o *Opinion) ProjectedProbability() float64 {
+	return o.Belief() + o.Uncertainty()*o.BaseRate()
+}
+
+/*
+Belief returns the belief mass of the focus values.
+*/
+func (o *Opinion) Belief() float64 {
+	return o.massOf(true)
+}
+
+/*
+Disbelief returns the belief mass of all values besides the focus values.
+*/
+func (o *Opinion) Disbelief() float64 {
+	return o.massOf(false)
+}
+
+func (o *Opinion) massOf(focus bool) float64 {
+	multinomial := o.Multinomial()
+	mass := 0.0
+	for _, value := range o.domain {
+		if slices.Contains(o.focus, value) == focus {
+			mass += multinomial.beliefs[value]
+		}
+	}
+	return mass
+}
+
+/*
+Uncertainty returns the uncertainty mass of the opinion.
+*/
+func (o *Opinion) Uncertainty() float64 {
+	return o.uncertainty
+}
+
+/*
+BaseRate returns the sum of the base rates of the focus values.
+*/
+func (o *Opinion) BaseRate() float64 {
+	baseRate := 0.0
+	for _, value := range o.focus {
+		baseRate += o.baseRates[value]
+	}
+	return baseRate
+}
+
+/*
+Equal returns whether two opinions have the same domain and focus and all masses and base rates differ by less than the
+given precision.
+*/
+func (o *Opinion) Equal(other *Opinion, precision float64) bool {
+	if !slices.Equal(o.domain, other.domain) || !slices.Equal(o.focus, other.focus) {
+		return false
+	}
+	if math.Abs(o.uncertainty-other.uncertainty) >= precision {
+		return false
+	}
+	for _, value := range o.domain {
+		if math.Abs(o.baseRates[value]-other.baseRates[value]) >= precision {
+			return false
+		}
+	}
+	for key := range o.beliefs {
+		if math.Abs(o.beliefs[key]-other.beliefs[key]) >= precision {
+			return false
+		}
+	}
+	for key := range other.beliefs {
+		if math.Abs(o.beliefs[key]-other.beliefs[key]) >= precision {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *Opinion) String() string {
+	beliefs := make([]string, 0, len(o.beliefs))
+	for _, key := range sortedKeys(o.beliefs) {
+		beliefs = append(beliefs, fmt.Sprintf("%s: %v", key, o.beliefs[key]))
+	}
+	baseRates := make([]string, 0, len(o.domain))
+	for _, value := range o.domain {
+		baseRates = append(baseRates, fmt.Sprintf("%s: %v", value, o.baseRates[value]))
+	}
+	return fmt.Sprintf("{%s}, %v, {%s}, [%s]", strings.Join(beliefs, ", "), o.uncertainty, strings.Join(baseRates, ", "), strings.Join(o.focus, ", "))
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+type jsonOpinion struct {
+	Beliefs     map[string]float64 `json:"beliefs"`
+	Uncertainty float64            `json:"uncertainty"`
+	BaseRates   map[string]float64 `json:"baseRates"`
+	Focus       []string           `json:"focus"`
+}
+
+func (o *Opinion) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonOpinion{
+		Beliefs:     o.Beliefs(),
+		Uncertainty: o.uncertainty,
+		BaseRates:   o.baseRates,
+		Focus:       o.focus,
+	})
+}
+
+func (o *Opinion) UnmarshalJSON(data []byte) error {
+	var decoded jsonOpinion
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	opinion, err := New(decoded.Beliefs, decoded.Uncertainty, decoded.BaseRates, decoded.Focus)
+	if err != nil {
+		return err
+	}
+	*o = *opinion
+	return nil
+}
@@ -0,0 +1,132 @@
+package multinomial
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"math"
+	"testing"
+)
+
+const tolerance = 1e-9
+
+var baseRates = map[string]float64{"RUNNING": 0.5, "DEGRADED": 0.3, "FAILED": 0.2}
+
+func nodeState(t *testing.T, beliefs map[string]float64, uncertainty float64) *Opinion {
+	t.Helper()
+	opinion, err := New(beliefs, uncertainty, baseRates, []string{"RUNNING", "DEGRADED"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return opinion
+}
+
+func assertClose(t *testing.T, name string, actual float64, expected float64) {
+	t.Helper()
+	if math.Abs(actual-expected) > tolerance {
+		t.Errorf("%s: expected %v, got %v", name, expected, actual)
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	focus := []string{"RUNNING"}
+	tests := []struct {
+		beliefs     map[string]float64
+		uncertainty float64
+		baseRates   map[string]float64
+		focus       []string
+	}{
+		{map[string]float64{"RUNNING": 0.5}, 0.4, baseRates, focus},
+		{map[string]float64{"RUNNING": 0.5}, 0.5, map[string]float64{"RUNNING": 0.5, "FAILED": 0.4}, focus},
+		{map[string]float64{"STOPPED": 0.5}, 0.5, baseRates, focus},
+		{map[string]float64{"RUNNING|DEGRADED|FAILED": 0.5}, 0.5, baseRates, focus},
+		{map[string]float64{"RUNNING": 0.5}, 0.5, baseRates, []string{}},
+		{map[string]float64{"RUNNING": 0.5}, 0.5, baseRates, []string{"RUNNING", "DEGRADED", "FAILED"}},
+		{map[string]float64{"RUNNING": 1}, 0, map[string]float64{"RUNNING": 1}, focus},
+	}
+	for i, test := range tests {
+		if _, err := New(test.beliefs, test.uncertainty, test.baseRates, test.focus); !errors.Is(err, ErrInvalidOpinion) {
+			t.Errorf("test %d: expected invalid opinion, got %v", i, err)
+		}
+	}
+}
+
+func TestBinomialView(t *testing.T) {
+	opinion := nodeState(t, map[string]float64{"RUNNING": 0.5, "DEGRADED": 0.1, "FAILED": 0.2}, 0.2)
+	var queryable subjectivelogic.QueryableOpinion = opinion
+	assertClose(t, "belief", queryable.Belief(), 0.6)
+	assertClose(t, "disbelief", queryable.Disbelief(), 0.2)
+	assertClose(t, "uncertainty", queryable.Uncertainty(), 0.2)
+	assertClose(t, "base rate", queryable.BaseRate(), 0.8)
+	assertClose(t, "projected probability", opinion.ProjectedProbability(), 0.76)
+
+	probabilities := opinion.ProjectedProbabilities()
+	assertClose(t, "P(RUNNING)", probabilities["RUNNING"], 0.6)
+	assertClose(t, "P(DEGRADED)", probabilities["DEGRADED"], 0.16)
+	assertClose(t, "P(FAILED)", probabilities["FAILED"], 0.24)
+}
+
+func TestHyperOpinion(t *testing.T) {
+	//belief in a malfunction, without knowing whether the node is degraded or has failed
+	opinion := nodeState(t, map[string]float64{"RUNNING": 0.3, "FAILED|DEGRADED": 0.5}, 0.2)
+	if !opinion.IsHyperOpinion() {
+		t.Fatal("expected hyper-opinion")
+	}
+	assertClose(t, "composite belief", opinion.BeliefOf("DEGRADED|FAILED"), 0.5)
+
+	projected := opinion.Multinomial()
+	if projected.IsHyperOpinion() {
+		t.Fatal("expected multinomial opinion")
+	}
+	assertClose(t, "b(DEGRADED)", projected.BeliefOf("DEGRADED"), 0.3)
+	assertClose(t, "b(FAILED)", projected.BeliefOf("FAILED"), 0.2)
+	assertClose(t, "belief", opinion.Belief(), 0.6)
+	assertClose(t, "disbelief", opinion.Disbelief(), 0.2)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	opinion := nodeState(t, map[string]float64{"RUNNING": 0.3, "DEGRADED|FAILED": 0.5}, 0.2)
+	data, err := json.Marshal(opinion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &Opinion{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(opinion, tolerance) {
+		t.Errorf("expected %s, got %s", opinion, decoded)
+	}
+}
+
+func TestOperators(t *testing.T) {
+	first := nodeState(t, map[string]float64{"RUNNING": 0.6, "FAILED": 0.2}, 0.2)
+	second := nodeState(t, map[string]float64{"RUNNING": 0.2, "DEGRADED": 0.4}, 0.4)
+
+	cumulative, err := CumulativeFusion(first, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//u1+u2-u1*u2 = 0.52
+	assertClose(t, "cumulative b(RUNNING)", cumulative.BeliefOf("RUNNING"), (0.6*0.4+0.2*0.2)/0.52)
+	assertClose(t, "cumulative b(DEGRADED)", cumulative.BeliefOf("DEGRADED"), 0.4*0.2/0.52)
+	assertClose(t, "cumulative u", cumulative.Uncertainty(), 0.08/0.52)
+
+	averaging, err := AveragingFusion(first, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "averaging b(RUNNING)", averaging.BeliefOf("RUNNING"), (0.6*0.4+0.2*0.2)/0.6)
+	assertClose(t, "averaging u", averaging.Uncertainty(), 2*0.08/0.6)
+
+	trust, _ := subjectivelogic.NewOpinion(0.4, 0.2, 0.4, 0.5)
+	discounted := Discount(&trust, first)
+	assertClose(t, "discounted b(RUNNING)", discounted.BeliefOf("RUNNING"), 0.36)
+	assertClose(t, "discounted b(FAILED)", discounted.BeliefOf("FAILED"), 0.12)
+	assertClose(t, "discounted u", discounted.Uncertainty(), 0.52)
+
+	other, _ := New(map[string]float64{"RUNNING": 0.5}, 0.5, baseRates, []string{"RUNNING"})
+	if _, err := CumulativeFusion(first, other); !errors.Is(err, ErrInvalidOpinion) {
+		t.Errorf("expected error for different focus values, got %v", err)
+	}
+}
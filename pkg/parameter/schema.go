@@ -0,0 +1,289 @@
+/*
+Package parameter provides typed schemas for the parameters of trust model templates that clients pass in a
+TAS_INIT_REQUEST. Templates declare a Schema, which the TMM publishes via TAS_TMT_OFFER and validates the parameters
+against before spawning. Parameter names are matched case-insensitively.
+*/
+package parameter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidParameters = errors.New("invalid parameters")
+
+/*
+Type is the type of parameter values. Values are always transmitted as strings and parsed according to their type.
+*/
+type Type string
+
+const (
+	STRING  Type = "STRING"
+	INTEGER Type = "INTEGER"
+	NUMBER  Type = "NUMBER"
+	BOOLEAN Type = "BOOLEAN"
+)
+
+/*
+ConstraintType is the type of constraint spanning several parameters.
+*/
+type ConstraintType string
+
+const (
+	/*
+		ALL_OR_NONE requires that either all or none of the parameters are set.
+	*/
+	ALL_OR_NONE ConstraintType = "ALL_OR_NONE"
+	/*
+		SUM_AT_MOST requires that the sum of all set parameters does not exceed the limit.
+	*/
+	SUM_AT_MOST ConstraintType = "SUM_AT_MOST"
+	/*
+		SUM_EQUALS requires that the sum of the parameters equals the limit, if all of them are set.
+	*/
+	SUM_EQUALS ConstraintType = "SUM_EQUALS"
+)
+
+// tolerance for comparing sums of parameter values
+const tolerance = 1e-9
+
+/*
+A Definition declares a single parameter. Minimum and Maximum apply to INTEGER and NUMBER parameters; Values restricts
+the admissible values of STRING parameters.
+*/
+type Definition struct {
+	Name        string   `json:"name"`
+	Type        Type     `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Default     *string  `json:"default,omitempty"`
+	Minimum     *float64 `json:"minimum,omitempty"`
+	Maximum     *float64 `json:"maximum,omitempty"`
+	Values      []string `json:"values,omitempty"`
+}
+
+/*
+A Constraint restricts the values of several NUMBER or INTEGER parameters in combination.
+*/
+type Constraint struct {
+	Type        ConstraintType `json:"type"`
+	Parameters  []string       `json:"parameters"`
+	Limit       float64        `json:"limit,omitempty"`
+	Description string         `json:"description,omitempty"`
+}
+
+/*
+A Schema declares the parameters accepted by a trust model template. Parameters not declared in the schema are ignored
+by Validate, so that clients of earlier template versions keep working; Unknown reports them. Given parameters are
+matched to declared parameters regardless of case, with exact matches taking precedence.
+*/
+type Schema struct {
+	Parameters  []Definition `json:"parameters"`
+	Constraints []Constraint `json:"constraints,omitempty"`
+}
+
+/*
+Validate checks the given parameters against the schema and returns their typed values, including defaults for parameters
+that have not been set. All violations are reported in a single error wrapping ErrInvalidParameters.
+*/
+func (s Schema) Validate(params map[string]string) (Values, error) {
+	values, problems := s.parse(params)
+
+	for _, constraint := range s.Constraints {
+		if err := constraint.check(values); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return Values{}, fmt.Errorf("%w: %s", ErrInvalidParameters, strings.Join(problems, "; "))
+	}
+	return values, nil
+}
+
+/*
+Values returns the typed values of parameters that have already been validated, including defaults for parameters that
+have not been set. Parameters that cannot be parsed are left unset and constraints are not checked.
+*/
+func (s Schema) Values(params map[string]string) Values {
+	values, _ := s.parse(params)
+	return values
+}
+
+/*
+parse returns the typed values of all declared parameters that are set or have a default, using the declared names,
+and a description of all parameters that are missing or cannot be parsed.
+*/
+func (s Schema) parse(params map[string]string) (Values, []string) {
+	values := Values{
+		raw:   make(map[string]string, len(s.Parameters)),
+		typed: make(map[string]interface{}, len(s.Parameters)),
+	}
+	problems := make([]string, 0)
+
+	for _, definition := range s.Parameters {
+		raw, exists := lookup(params, definition.Name)
+		if !exists {
+			if definition.Required {
+				problems = append(problems, fmt.Sprintf("parameter %s is required", definition.Name))
+				continue
+			}
+			if definition.Default == nil {
+				continue
+			}
+			raw = *definition.Default
+		}
+		value, err := definition.parse(raw)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("parameter %s: %s", definition.Name, err.Error()))
+			continue
+		}
+		values.raw[definition.Name] = raw
+		values.typed[definition.Name] = value
+	}
+	return values, problems
+}
+
+/*
+lookup returns the value of the parameter with the given name, preferring an exact match over a case-insensitive one.
+*/
+func lookup(params map[string]string, name string) (string, bool) {
+	if value, exists := params[name]; exists {
+		return value, true
+	}
+	for key, value := range params {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+/*
+Unknown returns the sorted names of the given parameters that are not declared in the schema.
+*/
+func (s Schema) Unknown(params map[string]string) []string {
+	declared := make(map[string]bool, len(s.Parameters))
+	for _, definition := range s.Parameters {
+		declared[strings.ToUpper(definition.Name)] = true
+	}
+	unknown := make([]string, 0)
+	for name := range params {
+		if !declared[strings.ToUpper(name)] {
+			unknown = append(unknown, name)
+		}
+	}
+	slices.Sort(unknown)
+	return unknown
+}
+
+func (d Definition) parse(raw string) (interface{}, error) {
+	switch d.Type {
+	case STRING:
+		if len(d.Values) > 0 && !slices.Contains(d.Values, raw) {
+			return nil, fmt.Errorf("'%s' is not one of %s", raw, strings.Join(d.Values, ", "))
+		}
+		return raw, nil
+	case BOOLEAN:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a boolean", raw)
+		}
+		return value, nil
+	case INTEGER:
+		// integral numbers such as 2.0 are accepted, as clients used to send integers as numbers
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value != math.Trunc(value) || math.Abs(value) > math.MaxInt32 {
+			return nil, fmt.Errorf("'%s' is not an integer", raw)
+		}
+		return int(value), d.checkRange(value)
+	case NUMBER:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("'%s' is not a number", raw)
+		}
+		return value, d.checkRange(value)
+	default:
+		return nil, fmt.Errorf("unknown type %s", d.Type)
+	}
+}
+
+func (d Definition) checkRange(value float64) error {
+	if d.Minimum != nil && value < *d.Minimum {
+		return fmt.Errorf("%v is less than the minimum of %v", value, *d.Minimum)
+	}
+	if d.Maximum != nil && value > *d.Maximum {
+		return fmt.Errorf("%v is greater than the maximum of %v", value, *d.Maximum)
+	}
+	return nil
+}
+
+func (c Constraint) check(values Values) error {
+	set := make([]string, 0, len(c.Parameters))
+	sum := 0.0
+	for _, name := range c.Parameters {
+		if values.IsSet(name) {
+			set = append(set, name)
+			sum += values.Float(name)
+		}
+	}
+	switch c.Type {
+	case ALL_OR_NONE:
+		if len(set) > 0 && len(set) < len(c.Parameters) {
+			missing := make([]string, 0, len(c.Parameters)-len(set))
+			for _, name := range c.Parameters {
+				if !slices.Contains(set, name) {
+					missing = append(missing, name)
+				}
+			}
+			return fmt.Errorf("parameters %s must be set together with %s", strings.Join(missing, ", "), strings.Join(set, ", "))
+		}
+	case SUM_AT_MOST:
+		if sum > c.Limit+tolerance {
+			return fmt.Errorf("parameters %s sum up to %v, which is more than %v", strings.Join(set, ", "), sum, c.Limit)
+		}
+	case SUM_EQUALS:
+		if len(set) == len(c.Parameters) && math.Abs(sum-c.Limit) > tolerance {
+			return fmt.Errorf("parameters %s sum up to %v instead of %v", strings.Join(set, ", "), sum, c.Limit)
+		}
+	default:
+		return fmt.Errorf("unknown constraint type %s", c.Type)
+	}
+	return nil
+}
+
+/*
+Range returns pointers to the given bounds to be used as Minimum and Maximum of a Definition.
+*/
+func Range(minimum float64, maximum float64) (*float64, *float64) {
+	return &minimum, &maximum
+}
+
+/*
+Opinion declares four NUMBER parameters PREFIX_BELIEF, PREFIX_DISBELIEF, PREFIX_UNCERTAINTY and PREFIX_BASERATE that
+together describe a subjective logic opinion, as well as the constraints for a valid opinion.
+*/
+func Opinion(prefix string, description string) ([]Definition, []Constraint) {
+	components := []string{"BELIEF", "DISBELIEF", "UNCERTAINTY", "BASERATE"}
+	definitions := make([]Definition, 0, len(components))
+	names := make([]string, 0, len(components))
+	for _, component := range components {
+		minimum, maximum := Range(0, 1)
+		definitions = append(definitions, Definition{
+			Name:        prefix + "_" + component,
+			Type:        NUMBER,
+			Description: fmt.Sprintf("%s (%s)", description, strings.ToLower(component)),
+			Minimum:     minimum,
+			Maximum:     maximum,
+		})
+		names = append(names, prefix+"_"+component)
+	}
+	return definitions, []Constraint{
+		{Type: ALL_OR_NONE, Parameters: names},
+		{Type: SUM_EQUALS, Parameters: names[:3], Limit: 1, Description: "belief, disbelief and uncertainty must sum up to 1"},
+	}
+}
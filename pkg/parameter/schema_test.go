@@ -0,0 +1,128 @@
+package parameter
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testSchema() Schema {
+	threshold := "0.5"
+	minimum, maximum := Range(0, 1)
+	schema := Schema{
+		Parameters: []Definition{
+			{Name: "MODE", Type: STRING, Values: []string{"FAST", "SAFE"}, Required: true},
+			{Name: "THRESHOLD", Type: NUMBER, Default: &threshold, Minimum: minimum, Maximum: maximum},
+			{Name: "RETRIES", Type: INTEGER},
+			{Name: "STRICT", Type: BOOLEAN},
+			{Name: "W1", Type: NUMBER},
+			{Name: "W2", Type: NUMBER},
+		},
+		Constraints: []Constraint{
+			{Type: ALL_OR_NONE, Parameters: []string{"W1", "W2"}},
+			{Type: SUM_AT_MOST, Parameters: []string{"W1", "W2"}, Limit: 1},
+		},
+	}
+	definitions, constraints := Opinion("RTL", "Required trustworthiness level")
+	schema.Parameters = append(schema.Parameters, definitions...)
+	schema.Constraints = append(schema.Constraints, constraints...)
+	return schema
+}
+
+func TestValidate(t *testing.T) {
+	values, err := testSchema().Validate(map[string]string{
+		"MODE":            "SAFE",
+		"RETRIES":         "3",
+		"STRICT":          "true",
+		"W1":              "0.25",
+		"W2":              "0.75",
+		"RTL_BELIEF":      "0.7",
+		"RTL_DISBELIEF":   "0.2",
+		"RTL_UNCERTAINTY": "0.1",
+		"RTL_BASERATE":    "0.5",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.String("MODE") != "SAFE" || values.Int("RETRIES") != 3 || !values.Bool("STRICT") || values.Float("W2") != 0.75 {
+		t.Errorf("unexpected values: %+v", values)
+	}
+	if !values.IsSet("THRESHOLD") || values.Float("THRESHOLD") != 0.5 || values.Map()["THRESHOLD"] != "0.5" {
+		t.Errorf("expected default value for THRESHOLD, got %v", values.Map())
+	}
+	rtl, err := values.Opinion("RTL")
+	if err != nil || rtl.Belief() != 0.7 {
+		t.Errorf("unexpected RTL %v: %v", rtl, err)
+	}
+}
+
+func TestIntegralNumbers(t *testing.T) {
+	values, err := testSchema().Validate(map[string]string{"MODE": "FAST", "RETRIES": "2.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Int("RETRIES") != 2 {
+		t.Errorf("expected 2.0 to be accepted as integer 2, got %d", values.Int("RETRIES"))
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	tests := map[string]struct {
+		params   map[string]string
+		problems []string
+	}{
+		"required": {
+			params:   map[string]string{},
+			problems: []string{"parameter MODE is required"},
+		},
+		"types and ranges": {
+			params:   map[string]string{"MODE": "SLOW", "THRESHOLD": "1.5", "RETRIES": "3.5", "STRICT": "maybe"},
+			problems: []string{"parameter MODE: 'SLOW' is not one of FAST, SAFE", "parameter THRESHOLD: 1.5 is greater than the maximum of 1", "parameter RETRIES: '3.5' is not an integer", "parameter STRICT: 'maybe' is not a boolean"},
+		},
+		"constraints": {
+			params:   map[string]string{"MODE": "FAST", "W1": "0.8", "RTL_BELIEF": "0.5", "RTL_DISBELIEF": "0.5", "RTL_UNCERTAINTY": "0.5", "RTL_BASERATE": "0.5"},
+			problems: []string{"parameters W2 must be set together with W1", "parameters RTL_BELIEF, RTL_DISBELIEF, RTL_UNCERTAINTY sum up to 1.5 instead of 1"},
+		},
+		"sum": {
+			params:   map[string]string{"MODE": "FAST", "W1": "0.8", "W2": "0.3"},
+			problems: []string{"parameters W1, W2 sum up to 1.1, which is more than 1"},
+		},
+	}
+	for name, test := range tests {
+		_, err := testSchema().Validate(test.params)
+		if !errors.Is(err, ErrInvalidParameters) {
+			t.Errorf("%s: expected invalid parameters, got %v", name, err)
+			continue
+		}
+		for _, problem := range test.problems {
+			if !strings.Contains(err.Error(), problem) {
+				t.Errorf("%s: expected '%s' in error '%s'", name, problem, err.Error())
+			}
+		}
+	}
+}
+
+func TestUnknownParameters(t *testing.T) {
+	params := map[string]string{"MODE": "FAST", "FOO": "1", "BAR": "2"}
+	if _, err := testSchema().Validate(params); err != nil {
+		t.Errorf("expected undeclared parameters to be ignored, got %v", err)
+	}
+	if unknown := testSchema().Unknown(params); !slices.Equal(unknown, []string{"BAR", "FOO"}) {
+		t.Errorf("expected BAR and FOO to be unknown, got %v", unknown)
+	}
+}
+
+func TestParameterNamesIgnoreCase(t *testing.T) {
+	params := map[string]string{"mode": "FAST", "Retries": "2", "w1": "0.5", "W2": "0.5"}
+	values, err := testSchema().Validate(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.String("MODE") != "FAST" || values.Int("RETRIES") != 2 || values.Map()["W1"] != "0.5" {
+		t.Errorf("expected parameters to be matched regardless of case, got %v", values.Map())
+	}
+	if unknown := testSchema().Unknown(params); len(unknown) != 0 {
+		t.Errorf("expected no unknown parameters, got %v", unknown)
+	}
+}
@@ -0,0 +1,70 @@
+package parameter
+
+import (
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+)
+
+/*
+Values are parameters that have been validated against a Schema. Accessors return the zero value for parameters that are
+neither set nor have a default, or that have been declared with a different type.
+*/
+type Values struct {
+	raw   map[string]string
+	typed map[string]interface{}
+}
+
+/*
+IsSet returns whether a value (or a default) exists for the parameter.
+*/
+func (v Values) IsSet(name string) bool {
+	_, exists := v.typed[name]
+	return exists
+}
+
+/*
+Map returns the values as strings by their declared names, including defaults. The TMM passes this map to
+TrustModelTemplate.Spawn after validating the parameters of a TAS_INIT_REQUEST.
+*/
+func (v Values) Map() map[string]string {
+	params := make(map[string]string, len(v.raw))
+	for name, value := range v.raw {
+		params[name] = value
+	}
+	return params
+}
+
+func (v Values) String(name string) string {
+	value, _ := v.typed[name].(string)
+	return value
+}
+
+func (v Values) Bool(name string) bool {
+	value, _ := v.typed[name].(bool)
+	return value
+}
+
+func (v Values) Int(name string) int {
+	value, _ := v.typed[name].(int)
+	return value
+}
+
+/*
+Float returns the value of a NUMBER parameter; values of INTEGER parameters are converted.
+*/
+func (v Values) Float(name string) float64 {
+	switch value := v.typed[name].(type) {
+	case float64:
+		return value
+	case int:
+		return float64(value)
+	default:
+		return 0
+	}
+}
+
+/*
+Opinion returns the opinion described by parameters declared via the Opinion function.
+*/
+func (v Values) Opinion(prefix string) (subjectivelogic.Opinion, error) {
+	return subjectivelogic.NewOpinion(v.Float(prefix+"_BELIEF"), v.Float(prefix+"_DISBELIEF"), v.Float(prefix+"_UNCERTAINTY"), v.Float(prefix+"_BASERATE"))
+}
@@ -0,0 +1,81 @@
+package quantifier
+
+import (
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustsource"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"math"
+)
+
+/*
+DefaultGrowth is the default base of the exponential decrease of uncertainty with the sum of detector weights.
+*/
+const DefaultGrowth = 1.3
+
+/*
+MisbehaviorDetection configures a quantifier for MBD misbehavior reports, i.e., bit masks in which bit i is set if
+detector i has detected misbehavior. Detectors with and without detection add their weights to disbelief and belief,
+respectively. The uncertainty decreases exponentially with the sum of all weights.
+*/
+type MisbehaviorDetection struct {
+	DetectionWeights   map[trustsource.MisbehaviorDetector]float64
+	NoDetectionWeights map[trustsource.MisbehaviorDetector]float64
+	//Base of the exponential decrease of uncertainty; DefaultGrowth if 0.
+	Growth float64
+}
+
+/*
+NewMisbehaviorDetection validates the configuration and creates the quantifier. Weights must not be negative and the
+growth must be greater than 1. Reports are expected as evidence of type MBD_MISBEHAVIOR_REPORT; without report, the
+quantifier returns full uncertainty.
+*/
+func NewMisbehaviorDetection(config MisbehaviorDetection) (core.Quantifier, error) {
+	growth := config.Growth
+	if growth == 0 {
+		growth = DefaultGrowth
+	}
+	if growth <= 1 {
+		return nil, fmt.Errorf("%w: growth %v is not greater than 1", ErrInvalidWeights, growth)
+	}
+	detectionWeights := make([]float64, trustsource.MBD_UNKNOWN)
+	noDetectionWeights := make([]float64, trustsource.MBD_UNKNOWN)
+	for _, weights := range []struct {
+		configured map[trustsource.MisbehaviorDetector]float64
+		target     []float64
+	}{{config.DetectionWeights, detectionWeights}, {config.NoDetectionWeights, noDetectionWeights}} {
+		for detector, weight := range weights.configured {
+			if detector >= trustsource.MBD_UNKNOWN {
+				return nil, fmt.Errorf("%w: unknown detector %d", ErrInvalidWeights, detector)
+			}
+			if weight < 0 {
+				return nil, fmt.Errorf("%w: negative weight %v of %s", ErrInvalidWeights, weight, detector.String())
+			}
+			weights.target[detector] = weight
+		}
+	}
+
+	return func(values map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
+		report, ok := values[core.MBD_MISBEHAVIOR_REPORT].(int)
+		if !ok {
+			return Vacuous(0.5)
+		}
+		sumBelief, sumDisbelief := 0.0, 0.0
+		for detector := range detectionWeights {
+			if report&(1<<detector) == 0 {
+				sumBelief += noDetectionWeights[detector]
+			} else {
+				sumDisbelief += detectionWeights[detector]
+			}
+		}
+		sumWeights := sumBelief + sumDisbelief
+		if sumWeights == 0 {
+			return Vacuous(0.5)
+		}
+		certainty := 1 - math.Pow(growth, -sumWeights)
+		belief := sumBelief / sumWeights * certainty
+		disbelief := sumDisbelief / sumWeights * certainty
+		opinion, _ := subjectivelogic.NewOpinion(belief, disbelief, 1-belief-disbelief, 0.5)
+		return &opinion
+	}, nil
+}
@@ -0,0 +1,30 @@
+package quantifier
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+)
+
+/*
+PassThrough creates a quantifier that returns an opinion received as evidence unchanged, e.g., a remote opinion from
+NTM. Without such an opinion, the quantifier returns full uncertainty.
+*/
+func PassThrough(evidenceType core.EvidenceType) core.Quantifier {
+	return func(values map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
+		if opinion, ok := values[evidenceType].(subjectivelogic.QueryableOpinion); ok && opinion != nil {
+			return opinion
+		}
+		return Vacuous(0.5)
+	}
+}
+
+/*
+RemoteOpinion creates a quantifier for remote opinions received from NTM, discounted by the trust in the remote source.
+If trust is nil, remote opinions are passed through unchanged.
+*/
+func RemoteOpinion(trust subjectivelogic.QueryableOpinion) core.Quantifier {
+	if trust == nil {
+		return PassThrough(core.NTM_REMOTE_OPINION)
+	}
+	return Discount(trust, PassThrough(core.NTM_REMOTE_OPINION))
+}
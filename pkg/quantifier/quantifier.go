@@ -0,0 +1,80 @@
+/*
+Package quantifier provides building blocks for the quantifiers of trust model templates, i.e., functions that map the
+evidence of a trust source to a trust opinion: weighted security controls for AIV and TCH claims, detector weights for MBD
+reports, pass-through of remote opinions from NTM, and helpers to discount and fuse the opinions of quantifiers. All
+building blocks validate their configuration when they are created instead of when they are called.
+*/
+package quantifier
+
+import (
+	"errors"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+)
+
+var ErrInvalidWeights = errors.New("invalid quantifier weights")
+
+/*
+FusionOperator is a binomial fusion operator of the subjective logic library, e.g., subjectivelogic.CumulativeFusion.
+*/
+type FusionOperator func(opinion1 *subjectivelogic.Opinion, opinion2 *subjectivelogic.Opinion) (subjectivelogic.Opinion, error)
+
+/*
+Vacuous returns an opinion with full uncertainty and the given base rate.
+*/
+func Vacuous(baseRate float64) subjectivelogic.QueryableOpinion {
+	opinion, _ := subjectivelogic.NewOpinion(0, 0, 1, baseRate)
+	return &opinion
+}
+
+/*
+Discount creates a quantifier that discounts the opinions of another quantifier by a trust opinion on their source.
+Multinomial opinions remain multinomial opinions.
+*/
+func Discount(trust subjectivelogic.QueryableOpinion, quantifier core.Quantifier) core.Quantifier {
+	trustOpinion := binomial(trust)
+	return func(values map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
+		opinion := quantifier(values)
+		if multinomialOpinion, ok := opinion.(*multinomial.Opinion); ok {
+			return multinomial.Discount(trust, multinomialOpinion)
+		}
+		binomialOpinion := binomial(opinion)
+		discounted, err := subjectivelogic.TrustDiscounting(&trustOpinion, &binomialOpinion)
+		if err != nil {
+			return Vacuous(opinion.BaseRate())
+		}
+		return &discounted
+	}
+}
+
+/*
+Fuse creates a quantifier that fuses the opinions of several quantifiers on the same evidence with the given operator.
+Multinomial opinions are fused as binomial opinions on their focus values. If the operator fails, the opinions fused so
+far are returned.
+*/
+func Fuse(operator FusionOperator, quantifiers ...core.Quantifier) core.Quantifier {
+	return func(values map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
+		if len(quantifiers) == 0 {
+			return Vacuous(0.5)
+		}
+		fused := binomial(quantifiers[0](values))
+		for _, quantifier := range quantifiers[1:] {
+			opinion := binomial(quantifier(values))
+			result, err := operator(&fused, &opinion)
+			if err != nil {
+				break
+			}
+			fused = result
+		}
+		return &fused
+	}
+}
+
+func binomial(opinion subjectivelogic.QueryableOpinion) subjectivelogic.Opinion {
+	result, err := subjectivelogic.NewOpinion(opinion.Belief(), opinion.Disbelief(), opinion.Uncertainty(), opinion.BaseRate())
+	if err != nil {
+		result, _ = subjectivelogic.NewOpinion(0, 0, 1, opinion.BaseRate())
+	}
+	return result
+}
@@ -0,0 +1,166 @@
+package quantifier
+
+import (
+	"errors"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustsource"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"math"
+	"testing"
+)
+
+const tolerance = 1e-9
+
+var tchExistenceWeights = map[core.EvidenceType]float64{
+	core.TCH_SECURE_BOOT:                          0.24,
+	core.TCH_ACCESS_CONTROL:                       0.16,
+	core.TCH_CONTROL_FLOW_INTEGRITY:               0.08,
+	core.TCH_SECURE_OTA:                           0.08,
+	core.TCH_APPLICATION_ISOLATION:                0.16,
+	core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION: 0.24,
+}
+
+var tchOutputWeights = map[core.EvidenceType]OutputWeight{
+	core.TCH_SECURE_BOOT:                          FAILURE_IS_CRITICAL,
+	core.TCH_ACCESS_CONTROL:                       FAILURE_ADDS_DISBELIEF,
+	core.TCH_CONTROL_FLOW_INTEGRITY:               FAILURE_IS_CRITICAL,
+	core.TCH_SECURE_OTA:                           FAILURE_ADDS_BELIEF,
+	core.TCH_APPLICATION_ISOLATION:                FAILURE_ADDS_BELIEF,
+	core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION: FAILURE_IS_CRITICAL,
+}
+
+func assertOpinion(t *testing.T, actual subjectivelogic.QueryableOpinion, belief float64, disbelief float64, uncertainty float64) {
+	t.Helper()
+	if math.Abs(actual.Belief()-belief) > tolerance || math.Abs(actual.Disbelief()-disbelief) > tolerance || math.Abs(actual.Uncertainty()-uncertainty) > tolerance {
+		t.Errorf("expected (%v, %v, %v), got (%v, %v, %v)", belief, disbelief, uncertainty, actual.Belief(), actual.Disbelief(), actual.Uncertainty())
+	}
+}
+
+func appraisals(appraisal int, exceptions map[core.EvidenceType]int) map[core.EvidenceType]interface{} {
+	values := make(map[core.EvidenceType]interface{})
+	for control := range tchExistenceWeights {
+		values[control] = appraisal
+	}
+	for control, exception := range exceptions {
+		values[control] = exception
+	}
+	return values
+}
+
+func TestSecurityControls(t *testing.T) {
+	quantifier, err := NewSecurityControls(SecurityControls{ExistenceWeights: tchExistenceWeights, OutputWeights: tchOutputWeights})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOpinion(t, quantifier(appraisals(APPRAISAL_PASSED, nil)), 0.96, 0, 0.04)
+	assertOpinion(t, quantifier(appraisals(APPRAISAL_PASSED, map[core.EvidenceType]int{core.TCH_ACCESS_CONTROL: APPRAISAL_FAILED, core.TCH_SECURE_OTA: APPRAISAL_FAILED})), 0.80, 0.16, 0.04)
+	assertOpinion(t, quantifier(appraisals(APPRAISAL_PASSED, map[core.EvidenceType]int{core.TCH_SECURE_BOOT: APPRAISAL_FAILED})), 0, 1, 0)
+	assertOpinion(t, quantifier(appraisals(APPRAISAL_NOT_IMPLEMENTED, nil)), 0, 0.96, 0.04)
+	assertOpinion(t, quantifier(appraisals(-2, nil)), 0, 0, 1)
+	assertOpinion(t, quantifier(map[core.EvidenceType]interface{}{core.TCH_SECURE_BOOT: APPRAISAL_PASSED}), 0.24, 0, 0.76)
+}
+
+func TestSecurityControlsWithPrior(t *testing.T) {
+	prior, _ := subjectivelogic.NewOpinion(0.2, 0.1, 0.7, 0.5)
+	quantifier, err := NewSecurityControls(SecurityControls{
+		ExistenceWeights: map[core.EvidenceType]float64{core.AIV_SECURE_BOOT: 0.5, core.AIV_ACCESS_CONTROL: 0.3},
+		OutputWeights:    map[core.EvidenceType]OutputWeight{core.AIV_SECURE_BOOT: FAILURE_IS_CRITICAL, core.AIV_ACCESS_CONTROL: FAILURE_ADDS_DISBELIEF},
+		Prior:            &prior,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOpinion(t, quantifier(map[core.EvidenceType]interface{}{core.AIV_SECURE_BOOT: APPRAISAL_PASSED, core.AIV_ACCESS_CONTROL: APPRAISAL_FAILED}), 0.55, 0.31, 0.14)
+	assertOpinion(t, quantifier(map[core.EvidenceType]interface{}{}), 0.2, 0.1, 0.7)
+}
+
+func TestSecurityControlsNormalization(t *testing.T) {
+	quantifier, err := NewSecurityControls(SecurityControls{ExistenceWeights: map[core.EvidenceType]float64{core.TCH_SECURE_BOOT: 1, core.TCH_SECURE_OTA: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOpinion(t, quantifier(map[core.EvidenceType]interface{}{core.TCH_SECURE_BOOT: APPRAISAL_PASSED}), 0.5, 0, 0.5)
+
+	quantifier, err = NewSecurityControls(SecurityControls{ExistenceWeights: map[core.EvidenceType]float64{core.TCH_SECURE_BOOT: 1.5, core.TCH_SECURE_OTA: 0.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOpinion(t, quantifier(map[core.EvidenceType]interface{}{core.TCH_SECURE_BOOT: APPRAISAL_PASSED}), 0.75, 0, 0.25)
+}
+
+func TestInvalidWeights(t *testing.T) {
+	for name, config := range map[string]SecurityControls{
+		"negative existence weight": {ExistenceWeights: map[core.EvidenceType]float64{core.TCH_SECURE_BOOT: -0.1}},
+		"unknown output weight":     {OutputWeights: map[core.EvidenceType]OutputWeight{core.TCH_SECURE_BOOT: 3}},
+	} {
+		if _, err := NewSecurityControls(config); !errors.Is(err, ErrInvalidWeights) {
+			t.Errorf("%s: expected %v, got %v", name, ErrInvalidWeights, err)
+		}
+	}
+	for name, config := range map[string]MisbehaviorDetection{
+		"negative weight":  {DetectionWeights: map[trustsource.MisbehaviorDetector]float64{trustsource.MBD_DIST_PLAU: -1}},
+		"unknown detector": {NoDetectionWeights: map[trustsource.MisbehaviorDetector]float64{trustsource.MBD_UNKNOWN: 1}},
+		"growth":           {Growth: 0.5},
+	} {
+		if _, err := NewMisbehaviorDetection(config); !errors.Is(err, ErrInvalidWeights) {
+			t.Errorf("%s: expected %v, got %v", name, ErrInvalidWeights, err)
+		}
+	}
+}
+
+func TestMisbehaviorDetection(t *testing.T) {
+	detectionWeights := make(map[trustsource.MisbehaviorDetector]float64)
+	noDetectionWeights := make(map[trustsource.MisbehaviorDetector]float64)
+	for detector := trustsource.MBD_DIST_PLAU; detector < trustsource.MBD_UNKNOWN; detector++ {
+		detectionWeights[detector] = 2
+		noDetectionWeights[detector] = 1
+	}
+	quantifier, err := NewMisbehaviorDetection(MisbehaviorDetection{DetectionWeights: detectionWeights, NoDetectionWeights: noDetectionWeights})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certainty := 1 - math.Pow(DefaultGrowth, -8)
+	assertOpinion(t, quantifier(map[core.EvidenceType]interface{}{core.MBD_MISBEHAVIOR_REPORT: 0}), certainty, 0, 1-certainty)
+
+	//detectors 0 and 7 have detected misbehavior
+	certainty = 1 - math.Pow(DefaultGrowth, -10)
+	assertOpinion(t, quantifier(map[core.EvidenceType]interface{}{core.MBD_MISBEHAVIOR_REPORT: 0b10000001}), 0.6*certainty, 0.4*certainty, 1-certainty)
+
+	assertOpinion(t, quantifier(map[core.EvidenceType]interface{}{}), 0, 0, 1)
+}
+
+func TestRemoteOpinion(t *testing.T) {
+	remote, _ := subjectivelogic.NewOpinion(0.8, 0.2, 0, 0.5)
+	values := map[core.EvidenceType]interface{}{core.NTM_REMOTE_OPINION: &remote}
+
+	assertOpinion(t, RemoteOpinion(nil)(values), 0.8, 0.2, 0)
+	assertOpinion(t, RemoteOpinion(nil)(map[core.EvidenceType]interface{}{}), 0, 0, 1)
+
+	trust, _ := subjectivelogic.NewOpinion(0.5, 0, 0.5, 0.5)
+	assertOpinion(t, RemoteOpinion(&trust)(values), 0.6, 0.15, 0.25)
+
+	multinomialRemote, err := multinomial.New(map[string]float64{"A": 0.8, "B": 0.2}, 0, map[string]float64{"A": 0.5, "B": 0.5}, []string{"A"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	discounted := RemoteOpinion(&trust)(map[core.EvidenceType]interface{}{core.NTM_REMOTE_OPINION: multinomialRemote})
+	if _, ok := discounted.(*multinomial.Opinion); !ok {
+		t.Errorf("expected multinomial opinion, got %T", discounted)
+	}
+	assertOpinion(t, discounted, 0.6, 0.15, 0.25)
+}
+
+func TestFuse(t *testing.T) {
+	first, _ := subjectivelogic.NewOpinion(0.5, 0, 0.5, 0.5)
+	second, _ := subjectivelogic.NewOpinion(0, 0.5, 0.5, 0.5)
+	constant := func(opinion subjectivelogic.QueryableOpinion) core.Quantifier {
+		return func(map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
+			return opinion
+		}
+	}
+	fused := Fuse(subjectivelogic.CumulativeFusion, constant(&first), constant(&second))(nil)
+	assertOpinion(t, fused, 1.0/3, 1.0/3, 1.0/3)
+	assertOpinion(t, Fuse(subjectivelogic.CumulativeFusion)(nil), 0, 0, 1)
+}
@@ -0,0 +1,115 @@
+package quantifier
+
+import (
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"slices"
+)
+
+/*
+OutputWeight specifies how a failed security control affects the opinion.
+*/
+type OutputWeight int
+
+const (
+	/*
+		FAILURE_ADDS_BELIEF adds the existence weight of a failed control to belief nonetheless, as the control exists.
+	*/
+	FAILURE_ADDS_BELIEF OutputWeight = iota
+	/*
+		FAILURE_ADDS_DISBELIEF adds the existence weight of a failed control to disbelief.
+	*/
+	FAILURE_ADDS_DISBELIEF
+	/*
+		FAILURE_IS_CRITICAL results in complete disbelief if the control fails.
+	*/
+	FAILURE_IS_CRITICAL
+)
+
+/*
+Appraisals of security controls as reported by AIV and TCH.
+*/
+const (
+	APPRAISAL_NOT_IMPLEMENTED = -1
+	APPRAISAL_FAILED          = 0
+	APPRAISAL_PASSED          = 1
+)
+
+/*
+SecurityControls configures a quantifier for the appraisals of security controls (AIV and TCH claims). Each appraisal
+moves the existence weight of its control (scaled by the uncertainty of the prior) from uncertainty to belief (passed)
+or disbelief (not implemented); failed controls are handled according to their output weight. Controls without appraisal
+or with other appraisals (e.g., -2 for unknown) leave their weight in uncertainty.
+*/
+type SecurityControls struct {
+	ExistenceWeights map[core.EvidenceType]float64
+	OutputWeights    map[core.EvidenceType]OutputWeight
+	//Opinion the appraisals are applied to. If nil, the appraisals are applied to full uncertainty with a base rate of 0.5.
+	Prior subjectivelogic.QueryableOpinion
+}
+
+/*
+NewSecurityControls validates the configuration and creates the quantifier. Existence weights must not be negative;
+weights summing up to more than 1 are normalized.
+*/
+func NewSecurityControls(config SecurityControls) (core.Quantifier, error) {
+	sum := 0.0
+	for control, weight := range config.ExistenceWeights {
+		if weight < 0 {
+			return nil, fmt.Errorf("%w: existence weight %v of %s is negative", ErrInvalidWeights, weight, control.String())
+		}
+		sum += weight
+	}
+	for control, weight := range config.OutputWeights {
+		if !slices.Contains([]OutputWeight{FAILURE_ADDS_BELIEF, FAILURE_ADDS_DISBELIEF, FAILURE_IS_CRITICAL}, weight) {
+			return nil, fmt.Errorf("%w: unknown output weight %d of %s", ErrInvalidWeights, weight, control.String())
+		}
+	}
+	existenceWeights := make(map[core.EvidenceType]float64, len(config.ExistenceWeights))
+	for control, weight := range config.ExistenceWeights {
+		if sum > 1 {
+			existenceWeights[control] = weight / sum
+		} else {
+			existenceWeights[control] = weight
+		}
+	}
+	outputWeights := make(map[core.EvidenceType]OutputWeight, len(config.OutputWeights))
+	for control, weight := range config.OutputWeights {
+		outputWeights[control] = weight
+	}
+	prior := config.Prior
+	if prior == nil {
+		prior = Vacuous(0.5)
+	}
+	belief, disbelief, uncertainty, baseRate := prior.Belief(), prior.Disbelief(), prior.Uncertainty(), prior.BaseRate()
+
+	return func(values map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
+		b, d := belief, disbelief
+		for control, weight := range existenceWeights {
+			appraisal, ok := values[control].(int)
+			if !ok {
+				continue
+			}
+			delta := weight * uncertainty
+			switch appraisal {
+			case APPRAISAL_PASSED:
+				b += delta
+			case APPRAISAL_NOT_IMPLEMENTED:
+				d += delta
+			case APPRAISAL_FAILED:
+				switch outputWeights[control] {
+				case FAILURE_ADDS_BELIEF:
+					b += delta
+				case FAILURE_ADDS_DISBELIEF:
+					d += delta
+				case FAILURE_IS_CRITICAL:
+					opinion, _ := subjectivelogic.NewOpinion(0, 1, 0, baseRate)
+					return &opinion
+				}
+			}
+		}
+		opinion, _ := subjectivelogic.NewOpinion(b, d, max(0, 1-b-d), baseRate)
+		return &opinion
+	}, nil
+}
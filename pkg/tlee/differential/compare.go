@@ -0,0 +1,203 @@
+package differential
+
+import (
+	"fmt"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/tleeinterface"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+/*
+A Difference describes a proposition for which two TLEEs disagree. A nil opinion means that the corresponding TLEE did
+not return a result for the proposition.
+*/
+type Difference struct {
+	Proposition string
+	Reference   subjectivelogic.QueryableOpinion
+	Candidate   subjectivelogic.QueryableOpinion
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: reference %s, candidate %s", d.Proposition, formatOpinion(d.Reference), formatOpinion(d.Candidate))
+}
+
+/*
+A Result is the outcome of running two TLEEs on the same input.
+*/
+type Result struct {
+	Differences  []Difference
+	ReferenceErr error
+	CandidateErr error
+}
+
+/*
+Failed returns true if the TLEEs disagree, i.e., if their results differ or only one of them returned an error.
+*/
+func (r Result) Failed() bool {
+	return len(r.Differences) > 0 || (r.ReferenceErr == nil) != (r.CandidateErr == nil)
+}
+
+func (r Result) String() string {
+	if !r.Failed() {
+		return "no differences"
+	}
+	lines := make([]string, 0, len(r.Differences)+1)
+	if r.ReferenceErr != nil || r.CandidateErr != nil {
+		lines = append(lines, fmt.Sprintf("reference error: %v, candidate error: %v", r.ReferenceErr, r.CandidateErr))
+	}
+	for _, difference := range r.Differences {
+		lines = append(lines, difference.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+/*
+A Harness runs a reference and a candidate TLEE on the same inputs and compares their results per proposition.
+*/
+type Harness struct {
+	Reference tleeinterface.TLEE
+	Candidate tleeinterface.TLEE
+	//maximum absolute difference of belief, disbelief, uncertainty and base rate
+	Tolerance float64
+}
+
+func NewHarness(reference tleeinterface.TLEE, candidate tleeinterface.TLEE, tolerance float64) *Harness {
+	return &Harness{
+		Reference: reference,
+		Candidate: candidate,
+		Tolerance: tolerance,
+	}
+}
+
+/*
+Compare runs both TLEEs on an input. An error is only returned if the input itself is invalid; errors of the TLEEs are
+part of the result.
+*/
+func (h *Harness) Compare(input Input) (Result, error) {
+	values, err := input.TrustRelationships()
+	if err != nil {
+		return Result{}, err
+	}
+	var result Result
+	var referenceResults, candidateResults map[string]subjectivelogic.QueryableOpinion
+	referenceResults, result.ReferenceErr = h.Reference.RunTLEE(input.TrustModelID, input.Version, input.Fingerprint, input.Structure(), values)
+	candidateResults, result.CandidateErr = h.Candidate.RunTLEE(input.TrustModelID, input.Version, input.Fingerprint, input.Structure(), values)
+	if result.ReferenceErr != nil || result.CandidateErr != nil {
+		return result, nil
+	}
+
+	propositions := make([]string, 0, len(referenceResults))
+	for proposition := range referenceResults {
+		propositions = append(propositions, proposition)
+	}
+	for proposition := range candidateResults {
+		if _, exists := referenceResults[proposition]; !exists {
+			propositions = append(propositions, proposition)
+		}
+	}
+	slices.Sort(propositions)
+	for _, proposition := range propositions {
+		reference, candidate := referenceResults[proposition], candidateResults[proposition]
+		if !h.equal(reference, candidate) {
+			result.Differences = append(result.Differences, Difference{
+				Proposition: proposition,
+				Reference:   reference,
+				Candidate:   candidate,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (h *Harness) equal(reference subjectivelogic.QueryableOpinion, candidate subjectivelogic.QueryableOpinion) bool {
+	if reference == nil || candidate == nil {
+		return reference == nil && candidate == nil
+	}
+	return math.Abs(reference.Belief()-candidate.Belief()) <= h.Tolerance &&
+		math.Abs(reference.Disbelief()-candidate.Disbelief()) <= h.Tolerance &&
+		math.Abs(reference.Uncertainty()-candidate.Uncertainty()) <= h.Tolerance &&
+		math.Abs(reference.BaseRate()-candidate.BaseRate()) <= h.Tolerance
+}
+
+/*
+Minimize reduces a failing input to a smaller input on which the TLEEs still disagree. It first reduces the input to a
+single scope and then repeatedly removes trust relationships and adjacency list entries as long as the disagreement
+persists. If the input does not fail, it is returned unchanged.
+*/
+func (h *Harness) Minimize(input Input) Input {
+	if !h.fails(input) {
+		return input
+	}
+	minimized := input.clone()
+
+	scopes := make([]string, 0, len(minimized.Values))
+	for scope := range minimized.Values {
+		scopes = append(scopes, scope)
+	}
+	slices.Sort(scopes)
+	for _, scope := range scopes {
+		candidate := minimized.clone()
+		candidate.Values = map[string][]Relationship{scope: candidate.Values[scope]}
+		if h.fails(candidate) {
+			minimized = candidate
+			break
+		}
+	}
+
+	for reduced := true; reduced; {
+		reduced = false
+		for _, scope := range scopes {
+			for i := 0; i < len(minimized.Values[scope]); i++ {
+				candidate := minimized.clone()
+				candidate.Values[scope] = slices.Delete(candidate.Values[scope], i, i+1)
+				if len(candidate.Values[scope]) == 0 {
+					delete(candidate.Values, scope)
+				}
+				if h.fails(candidate) {
+					minimized = candidate
+					reduced = true
+					i--
+				}
+			}
+		}
+		for i := 0; i < len(minimized.AdjacencyList); i++ {
+			for j := 0; j < len(minimized.AdjacencyList[i].Targets); j++ {
+				candidate := minimized.clone()
+				candidate.AdjacencyList[i].Targets = slices.Delete(candidate.AdjacencyList[i].Targets, j, j+1)
+				if len(candidate.AdjacencyList[i].Targets) == 0 {
+					candidate.AdjacencyList = slices.Delete(candidate.AdjacencyList, i, i+1)
+				}
+				if h.fails(candidate) {
+					minimized = candidate
+					reduced = true
+					break
+				}
+			}
+		}
+	}
+	return minimized
+}
+
+func (h *Harness) fails(input Input) bool {
+	result, err := h.Compare(input)
+	return err == nil && result.Failed()
+}
+
+func formatOpinion(opinion subjectivelogic.QueryableOpinion) string {
+	if opinion == nil {
+		return "<none>"
+	}
+	return fmt.Sprintf("(%v, %v, %v, %v)", opinion.Belief(), opinion.Disbelief(), opinion.Uncertainty(), opinion.BaseRate())
+}
+
+/*
+WriteCounterexample minimizes a failing input and stores it as <name>.json in the given directory, so that it can be
+replayed as a recorded input. The path of the file is returned.
+*/
+func (h *Harness) WriteCounterexample(directory string, name string, input Input) (string, error) {
+	path := filepath.Join(directory, name+".json")
+	return path, WriteInput(path, h.Minimize(input))
+}
@@ -0,0 +1,96 @@
+package differential
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/listener"
+	internaltlee "github.com/horizon-connect-eu/go-taf/pkg/tlee"
+	internaltrustmodelstructure "github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelstructure"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"io"
+	"log/slog"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+/*
+defaultDiscountTLEE is a faulty TLEE that ignores the discount operator of the structure.
+*/
+type defaultDiscountTLEE struct {
+	backend *internaltlee.TLEE
+}
+
+func (d *defaultDiscountTLEE) RunTLEE(trustmodelID string, version int, fingerprint uint32, structure trustmodelstructure.TrustGraphStructure, values map[string][]trustmodelstructure.TrustRelationship) (map[string]subjectivelogic.QueryableOpinion, error) {
+	structure = internaltrustmodelstructure.NewTrustGraphDTO(structure.Operator(), trustmodelstructure.DefaultDiscount, structure.AdjacencyList())
+	return d.backend.RunTLEE(trustmodelID, version, fingerprint, structure, values)
+}
+
+func TestHarnessAgreesWithItself(t *testing.T) {
+	harness := NewHarness(internaltlee.SpawnNewTLEE(logger, "", false), internaltlee.SpawnNewTLEE(logger, "", false), 1e-9)
+	random := rand.New(rand.NewSource(1))
+	for range 20 {
+		result, err := harness.Compare(RandomInput(random, trustmodelstructure.CumulativeFusion, trustmodelstructure.OppositeBeliefDiscount, 3, 3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Failed() {
+			t.Errorf("unexpected differences: %s", result)
+		}
+	}
+}
+
+func TestHarnessMinimizesCounterexample(t *testing.T) {
+	harness := NewHarness(internaltlee.SpawnNewTLEE(logger, "", false), &defaultDiscountTLEE{backend: internaltlee.SpawnNewTLEE(logger, "", false)}, 1e-9)
+	random := rand.New(rand.NewSource(1))
+	input := RandomInput(random, trustmodelstructure.CumulativeFusion, trustmodelstructure.OppositeBeliefDiscount, 4, 3)
+	result, err := harness.Compare(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Failed() {
+		t.Fatal("expected the faulty TLEE to differ")
+	}
+
+	directory := t.TempDir()
+	path, err := harness.WriteCounterexample(directory, "counterexample", input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minimized, err := ReadInput(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := harness.Compare(minimized); !result.Failed() {
+		t.Error("expected the minimized input to still fail")
+	}
+	if len(minimized.Values) != 1 {
+		t.Errorf("expected a single scope, got %d", len(minimized.Values))
+	}
+	//The discount operators only differ for paths with at least two edges
+	for scope, relationships := range minimized.Values {
+		if len(relationships) != 2 {
+			t.Errorf("expected two trust relationships in scope %s, got %+v", scope, relationships)
+		}
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	directory := t.TempDir()
+	recorder := NewRecorder(directory, logger)
+	input := RandomInput(rand.New(rand.NewSource(1)), trustmodelstructure.AveragingFusion, trustmodelstructure.DefaultDiscount, 2, 2)
+	values, err := input.TrustRelationships()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.OnTrustModelInstanceUpdated(listener.NewTrustModelInstanceUpdatedEvent(input.TrustModelID, "//client/SES-1/T@0.0.1/1", input.Version, input.Fingerprint, input.Structure(), values, nil, nil))
+
+	recorded, err := ReadInputs(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(recorded["client_SES-1_T_0.0.1_1-1"], input) {
+		t.Errorf("recorded input does not match: %+v", recorded)
+	}
+}
@@ -0,0 +1,242 @@
+package differential
+
+import (
+	"encoding/json"
+	"fmt"
+	internaltrustmodelstructure "github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelstructure"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+/*
+An Input is a serializable TLEE input, i.e., the trust graph structure and the values of a trust model instance at a
+certain version.
+*/
+type Input struct {
+	TrustModelID     string                               `json:"trustModelID"`
+	Version          int                                  `json:"version"`
+	Fingerprint      uint32                               `json:"fingerprint"`
+	FusionOperator   trustmodelstructure.FusionOperator   `json:"fusionOperator"`
+	DiscountOperator trustmodelstructure.DiscountOperator `json:"discountOperator"`
+	AdjacencyList    []AdjacencyEntry                     `json:"adjacencyList"`
+	//scope->trust relationships
+	Values map[string][]Relationship `json:"values"`
+}
+
+type AdjacencyEntry struct {
+	Source  string   `json:"source"`
+	Targets []string `json:"targets"`
+}
+
+type Relationship struct {
+	Source      string  `json:"source"`
+	Destination string  `json:"destination"`
+	Belief      float64 `json:"belief"`
+	Disbelief   float64 `json:"disbelief"`
+	Uncertainty float64 `json:"uncertainty"`
+	BaseRate    float64 `json:"baseRate"`
+}
+
+/*
+NewInput creates an Input from the structure and values of a trust model instance.
+*/
+func NewInput(trustModelID string, version int, fingerprint uint32, structure trustmodelstructure.TrustGraphStructure, values map[string][]trustmodelstructure.TrustRelationship) Input {
+	input := Input{
+		TrustModelID:     trustModelID,
+		Version:          version,
+		Fingerprint:      fingerprint,
+		FusionOperator:   structure.Operator(),
+		DiscountOperator: structure.DiscountOperator(),
+		AdjacencyList:    make([]AdjacencyEntry, 0, len(structure.AdjacencyList())),
+		Values:           make(map[string][]Relationship, len(values)),
+	}
+	for _, entry := range structure.AdjacencyList() {
+		input.AdjacencyList = append(input.AdjacencyList, AdjacencyEntry{
+			Source:  entry.SourceNode(),
+			Targets: slices.Clone(entry.TargetNodes()),
+		})
+	}
+	for scope, relationships := range values {
+		input.Values[scope] = make([]Relationship, 0, len(relationships))
+		for _, relationship := range relationships {
+			recorded := Relationship{
+				Source:      relationship.Source(),
+				Destination: relationship.Destination(),
+			}
+			if opinion := relationship.Opinion(); opinion != nil {
+				recorded.Belief = opinion.Belief()
+				recorded.Disbelief = opinion.Disbelief()
+				recorded.Uncertainty = opinion.Uncertainty()
+				recorded.BaseRate = opinion.BaseRate()
+			}
+			input.Values[scope] = append(input.Values[scope], recorded)
+		}
+	}
+	return input
+}
+
+/*
+Structure returns the trust graph structure of the input as expected by a TLEE.
+*/
+func (input Input) Structure() trustmodelstructure.TrustGraphStructure {
+	entries := make([]trustmodelstructure.AdjacencyListEntry, 0, len(input.AdjacencyList))
+	for _, entry := range input.AdjacencyList {
+		entries = append(entries, internaltrustmodelstructure.NewAdjacencyEntryDTO(entry.Source, slices.Clone(entry.Targets)))
+	}
+	return internaltrustmodelstructure.NewTrustGraphDTO(input.FusionOperator, input.DiscountOperator, entries)
+}
+
+/*
+TrustRelationships returns the values of the input as expected by a TLEE.
+*/
+func (input Input) TrustRelationships() (map[string][]trustmodelstructure.TrustRelationship, error) {
+	values := make(map[string][]trustmodelstructure.TrustRelationship, len(input.Values))
+	for scope, relationships := range input.Values {
+		values[scope] = make([]trustmodelstructure.TrustRelationship, 0, len(relationships))
+		for _, relationship := range relationships {
+			opinion, err := subjectivelogic.NewOpinion(relationship.Belief, relationship.Disbelief, relationship.Uncertainty, relationship.BaseRate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid opinion from %s to %s in scope %s: %w", relationship.Source, relationship.Destination, scope, err)
+			}
+			values[scope] = append(values[scope], internaltrustmodelstructure.NewTrustRelationshipDTO(relationship.Source, relationship.Destination, &opinion))
+		}
+	}
+	return values, nil
+}
+
+/*
+clone returns a deep copy of the input.
+*/
+func (input Input) clone() Input {
+	cloned := input
+	cloned.AdjacencyList = make([]AdjacencyEntry, 0, len(input.AdjacencyList))
+	for _, entry := range input.AdjacencyList {
+		cloned.AdjacencyList = append(cloned.AdjacencyList, AdjacencyEntry{Source: entry.Source, Targets: slices.Clone(entry.Targets)})
+	}
+	cloned.Values = make(map[string][]Relationship, len(input.Values))
+	for scope, relationships := range input.Values {
+		cloned.Values[scope] = slices.Clone(relationships)
+	}
+	return cloned
+}
+
+/*
+WriteInput stores an input as JSON file.
+*/
+func WriteInput(path string, input Input) error {
+	bytes, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, bytes, 0644)
+}
+
+/*
+ReadInput loads an input from a JSON file.
+*/
+func ReadInput(path string) (Input, error) {
+	var input Input
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return input, err
+	}
+	if err := json.Unmarshal(bytes, &input); err != nil {
+		return input, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+	return input, nil
+}
+
+/*
+ReadInputs loads all inputs stored as JSON files in a directory, ordered by file name.
+*/
+func ReadInputs(directory string) (map[string]Input, error) {
+	files, err := filepath.Glob(filepath.Join(directory, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	inputs := make(map[string]Input, len(files))
+	for _, file := range files {
+		input, err := ReadInput(file)
+		if err != nil {
+			return nil, err
+		}
+		inputs[strings.TrimSuffix(filepath.Base(file), ".json")] = input
+	}
+	return inputs, nil
+}
+
+/*
+RandomInput creates an input with random opinions in the shape of the trust models used by the TAF: in each scope, the
+trustor V_ego has a direct opinion on the target of the scope and reaches it via up to maxHops intermediate nodes along
+one or more parallel paths.
+*/
+func RandomInput(random *rand.Rand, fusionOperator trustmodelstructure.FusionOperator, discountOperator trustmodelstructure.DiscountOperator, scopes int, maxHops int) Input {
+	input := Input{
+		TrustModelID:     fmt.Sprintf("random-%d", random.Int63()),
+		Version:          1,
+		Fingerprint:      random.Uint32(),
+		FusionOperator:   fusionOperator,
+		DiscountOperator: discountOperator,
+		Values:           make(map[string][]Relationship, scopes),
+	}
+	//source->targets
+	adjacency := make(map[string][]string)
+	addEdge := func(scope string, source string, destination string) {
+		input.Values[scope] = append(input.Values[scope], randomRelationship(random, source, destination))
+		if !slices.Contains(adjacency[source], destination) {
+			adjacency[source] = append(adjacency[source], destination)
+		}
+	}
+	for i := range scopes {
+		scope := fmt.Sprintf("C_%d", i)
+		addEdge(scope, "V_ego", scope)
+		paths := 1 + random.Intn(2)
+		for p := range paths {
+			previous := "V_ego"
+			hops := 1 + random.Intn(max(maxHops, 1))
+			for h := range hops {
+				node := fmt.Sprintf("V_%d_%d_%d", i, p, h)
+				addEdge(scope, previous, node)
+				previous = node
+			}
+			addEdge(scope, previous, scope)
+		}
+	}
+	sources := make([]string, 0, len(adjacency))
+	for source := range adjacency {
+		sources = append(sources, source)
+	}
+	slices.Sort(sources)
+	for _, source := range sources {
+		input.AdjacencyList = append(input.AdjacencyList, AdjacencyEntry{Source: source, Targets: adjacency[source]})
+	}
+	return input
+}
+
+func randomRelationship(random *rand.Rand, source string, destination string) Relationship {
+	belief := random.Float64()
+	disbelief := random.Float64() * (1 - belief)
+	//Occasionally use dogmatic or vacuous opinions, as they are edge cases for most operators
+	switch random.Intn(10) {
+	case 0:
+		belief, disbelief = 1, 0
+	case 1:
+		belief, disbelief = 0, 0
+	}
+	return Relationship{
+		Source:      source,
+		Destination: destination,
+		Belief:      belief,
+		Disbelief:   disbelief,
+		Uncertainty: 1 - belief - disbelief,
+		BaseRate:    0.5,
+	}
+}
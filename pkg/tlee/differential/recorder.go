@@ -0,0 +1,61 @@
+package differential
+
+import (
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/listener"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+/*
+A Recorder is a TMI listener that stores the TLEE input of each new version of a trust model instance in a directory.
+The recorded inputs can be replayed with a Harness.
+*/
+type Recorder struct {
+	directory string
+	logger    *slog.Logger
+	//TMI listeners are called by all workers
+	mutex sync.Mutex
+}
+
+func NewRecorder(directory string, logger *slog.Logger) *Recorder {
+	return &Recorder{
+		directory: directory,
+		logger:    logger,
+	}
+}
+
+func (r *Recorder) OnTrustModelInstanceSpawned(event listener.TrustModelInstanceSpawnedEvent) {
+	r.record(NewInput(event.ID, event.Version, event.Fingerprint, event.Structure, event.Values), event.FullTMI)
+}
+
+func (r *Recorder) OnTrustModelInstanceUpdated(event listener.TrustModelInstanceUpdatedEvent) {
+	r.record(NewInput(event.ID, event.Version, event.Fingerprint, event.Structure, event.Values), event.FullTMI)
+}
+
+func (r *Recorder) OnTrustModelInstanceDeleted(event listener.TrustModelInstanceDeletedEvent) {}
+
+func (r *Recorder) record(input Input, fullTMI string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	path := filepath.Join(r.directory, fmt.Sprintf("%s-%d.json", fileName(fullTMI), input.Version))
+	if err := WriteInput(path, input); err != nil {
+		r.logger.Warn("Could not record TLEE input", "TMI", fullTMI, "Error", err)
+	}
+}
+
+/*
+fileName turns a full TMI ID into a string that can safely be used as file name.
+*/
+func fileName(fullTMI string) string {
+	return strings.Trim(strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, fullTMI), "_")
+}
@@ -0,0 +1,145 @@
+package tlee
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/tleeinterface"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"slices"
+)
+
+/*
+IncrementalTLEE wraps a TLEE backend and caches the last evaluation of each trust model. As long as the fingerprint of
+a trust model does not change, only the scopes whose trust relationships have changed since the last run are passed to
+the backend; the results of all other scopes are reused. This requires the backend to evaluate each scope independently
+of the others. Changed fingerprints and backends that return results not keyed by scope always cause a full run.
+*/
+type IncrementalTLEE struct {
+	backend tleeinterface.TLEE
+	//trust model ID->cached evaluation
+	entries map[string]*incrementalEntry
+}
+
+type incrementalEntry struct {
+	fingerprint uint32
+	//false if the results of the backend cannot be attributed to scopes
+	incremental bool
+	//scope->relationships used for the cached results
+	values  map[string][]relationshipSnapshot
+	results map[string]subjectivelogic.QueryableOpinion
+}
+
+type relationshipSnapshot struct {
+	source      string
+	destination string
+	belief      float64
+	disbelief   float64
+	uncertainty float64
+	baseRate    float64
+	//full distribution of multinomial opinions, which may change without changing their binomial view
+	multinomial string
+}
+
+func NewIncrementalTLEE(backend tleeinterface.TLEE) *IncrementalTLEE {
+	return &IncrementalTLEE{
+		backend: backend,
+		entries: make(map[string]*incrementalEntry),
+	}
+}
+
+func (t *IncrementalTLEE) RunTLEE(trustmodelID string, version int, fingerprint uint32, structure trustmodelstructure.TrustGraphStructure, values map[string][]trustmodelstructure.TrustRelationship) (map[string]subjectivelogic.QueryableOpinion, error) {
+	snapshots := make(map[string][]relationshipSnapshot, len(values))
+	for scope, relationships := range values {
+		snapshots[scope] = snapshotRelationships(relationships)
+	}
+
+	entry, exists := t.entries[trustmodelID]
+	if !exists || !entry.incremental || entry.fingerprint != fingerprint {
+		results, err := t.backend.RunTLEE(trustmodelID, version, fingerprint, structure, values)
+		if err != nil {
+			delete(t.entries, trustmodelID)
+			return nil, err
+		}
+		t.entries[trustmodelID] = &incrementalEntry{
+			fingerprint: fingerprint,
+			incremental: resultsKeyedByScope(results, values),
+			values:      snapshots,
+			results:     results,
+		}
+		return copyResults(results), nil
+	}
+
+	changed := make(map[string][]trustmodelstructure.TrustRelationship)
+	for scope, relationships := range values {
+		if cached, exists := entry.values[scope]; !exists || !slices.Equal(cached, snapshots[scope]) {
+			changed[scope] = relationships
+		}
+	}
+	var partial map[string]subjectivelogic.QueryableOpinion
+	if len(changed) > 0 {
+		var err error
+		partial, err = t.backend.RunTLEE(trustmodelID, version, fingerprint, structure, changed)
+		if err != nil {
+			delete(t.entries, trustmodelID)
+			return nil, err
+		}
+	}
+	results := make(map[string]subjectivelogic.QueryableOpinion, len(values))
+	for scope := range values {
+		if _, isChanged := changed[scope]; isChanged {
+			if result, exists := partial[scope]; exists {
+				results[scope] = result
+			}
+		} else if result, exists := entry.results[scope]; exists {
+			results[scope] = result
+		}
+	}
+	entry.values = snapshots
+	entry.results = results
+	return copyResults(results), nil
+}
+
+/*
+Forget removes the cached evaluation of a trust model, e.g., after the trust model instance has been destroyed.
+*/
+func (t *IncrementalTLEE) Forget(trustmodelID string) {
+	delete(t.entries, trustmodelID)
+}
+
+func snapshotRelationships(relationships []trustmodelstructure.TrustRelationship) []relationshipSnapshot {
+	snapshot := make([]relationshipSnapshot, 0, len(relationships))
+	for _, relationship := range relationships {
+		entry := relationshipSnapshot{
+			source:      relationship.Source(),
+			destination: relationship.Destination(),
+		}
+		if opinion := relationship.Opinion(); opinion != nil {
+			entry.belief = opinion.Belief()
+			entry.disbelief = opinion.Disbelief()
+			entry.uncertainty = opinion.Uncertainty()
+			entry.baseRate = opinion.BaseRate()
+			if multinomialOpinion, ok := opinion.(*multinomial.Opinion); ok {
+				entry.multinomial = multinomialOpinion.String()
+			}
+		}
+		snapshot = append(snapshot, entry)
+	}
+	return snapshot
+}
+
+func resultsKeyedByScope(results map[string]subjectivelogic.QueryableOpinion, values map[string][]trustmodelstructure.TrustRelationship) bool {
+	for key := range results {
+		if _, isScope := values[key]; !isScope {
+			return false
+		}
+	}
+	return true
+}
+
+func copyResults(results map[string]subjectivelogic.QueryableOpinion) map[string]subjectivelogic.QueryableOpinion {
+	copied := make(map[string]subjectivelogic.QueryableOpinion, len(results))
+	for key, result := range results {
+		copied[key] = result
+	}
+	return copied
+}
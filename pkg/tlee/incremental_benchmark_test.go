@@ -0,0 +1,69 @@
+package tlee_test
+
+import (
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	internaltlee "github.com/horizon-connect-eu/go-taf/pkg/tlee"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	trustmodel_ima_standalone_v0_0_1 "github.com/horizon-connect-eu/go-taf/plugins/trustmodels/intersectionmovementassist/trustmodel-ima-standalone-v0.0.1"
+	smtd_v0_0_1 "github.com/horizon-connect-eu/go-taf/plugins/trustmodels/smtd/trustmodel-smtd-v0.0.1"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/tleeinterface"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+/*
+benchmarkTLEE applies an MBD opinion update to one of the objects of a vehicle TMI and evaluates the TMI in each
+iteration, either with full or with incremental TLEE runs.
+*/
+func benchmarkTLEE(b *testing.B, tmt core.TrustModelTemplate, incremental bool) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	_, _, spawner, err := tmt.Spawn(nil, core.TafContext{Logger: logger, Identifier: "taf"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	tmi, err := spawner.OnNewVehicle("1", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	tmi.Initialize(nil)
+	objects := make([]string, 0, 32)
+	for i := range 32 {
+		objects = append(objects, fmt.Sprintf("%d", i+2))
+	}
+	tmi.Update(trustmodelupdate.CreateRefreshCPM("1", objects))
+
+	var tlee tleeinterface.TLEE = internaltlee.SpawnNewTLEE(logger, "", false)
+	if incremental {
+		tlee = internaltlee.NewIncrementalTLEE(tlee)
+	}
+	trusted, _ := subjectivelogic.NewOpinion(0.8, 0.1, 0.1, 0.5)
+	distrusted, _ := subjectivelogic.NewOpinion(0.1, 0.8, 0.1, 0.5)
+	opinions := []subjectivelogic.QueryableOpinion{&trusted, &distrusted}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		object := fmt.Sprintf("C_1_%s", objects[i%len(objects)])
+		tmi.Update(trustmodelupdate.CreateAtomicTrustOpinionUpdate(opinions[(i/len(objects))%2], "V_ego", object, core.MBD))
+		if _, err := tlee.RunTLEE(tmi.ID(), tmi.Version(), tmi.Fingerprint(), tmi.Structure(), tmi.Values()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIncrementalTLEE(b *testing.B) {
+	templates := []core.TrustModelTemplate{
+		trustmodel_ima_standalone_v0_0_1.CreateTrustModelTemplate("IMA_STANDALONE", "0.0.1"),
+		smtd_v0_0_1.CreateTrustModelTemplate("SMTD", "0.0.1"),
+	}
+	for _, tmt := range templates {
+		b.Run(tmt.TemplateName()+"/full", func(b *testing.B) {
+			benchmarkTLEE(b, tmt, false)
+		})
+		b.Run(tmt.TemplateName()+"/incremental", func(b *testing.B) {
+			benchmarkTLEE(b, tmt, true)
+		})
+	}
+}
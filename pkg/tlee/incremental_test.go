@@ -0,0 +1,107 @@
+package tlee
+
+import (
+	internaltrustmodelstructure "github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelstructure"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"io"
+	"log/slog"
+	"math/rand"
+	"testing"
+)
+
+/*
+countingTLEE records the scopes passed to the wrapped TLEE.
+*/
+type countingTLEE struct {
+	backend *TLEE
+	scopes  []string
+}
+
+func (c *countingTLEE) RunTLEE(trustmodelID string, version int, fingerprint uint32, structure trustmodelstructure.TrustGraphStructure, values map[string][]trustmodelstructure.TrustRelationship) (map[string]subjectivelogic.QueryableOpinion, error) {
+	for scope := range values {
+		c.scopes = append(c.scopes, scope)
+	}
+	return c.backend.RunTLEE(trustmodelID, version, fingerprint, structure, values)
+}
+
+func randomOpinion(random *rand.Rand) subjectivelogic.QueryableOpinion {
+	belief := random.Float64()
+	disbelief := random.Float64() * (1 - belief)
+	opinion, _ := subjectivelogic.NewOpinion(belief, disbelief, 1-belief-disbelief, 0.5)
+	return &opinion
+}
+
+func createScopedValues(scopes []string, opinions map[string]subjectivelogic.QueryableOpinion) (trustmodelstructure.TrustGraphStructure, map[string][]trustmodelstructure.TrustRelationship) {
+	fullBelief, _ := subjectivelogic.NewOpinion(1, 0, 0, 0.5)
+	targets := make([]string, 0, len(scopes))
+	values := make(map[string][]trustmodelstructure.TrustRelationship)
+	for _, scope := range scopes {
+		targets = append(targets, scope)
+		values[scope] = []trustmodelstructure.TrustRelationship{
+			internaltrustmodelstructure.NewTrustRelationshipDTO("V_1", scope, &fullBelief),
+			internaltrustmodelstructure.NewTrustRelationshipDTO("V_ego", scope, opinions[scope]),
+			internaltrustmodelstructure.NewTrustRelationshipDTO("V_ego", "V_1", opinions["V_1"]),
+		}
+	}
+	structure := internaltrustmodelstructure.NewTrustGraphDTO(trustmodelstructure.CumulativeFusion, trustmodelstructure.DefaultDiscount, []trustmodelstructure.AdjacencyListEntry{
+		internaltrustmodelstructure.NewAdjacencyEntryDTO("V_ego", append([]string{"V_1"}, targets...)),
+		internaltrustmodelstructure.NewAdjacencyEntryDTO("V_1", targets),
+	})
+	return structure, values
+}
+
+func TestIncrementalTLEEMatchesFullRun(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	full := SpawnNewTLEE(logger, "", false)
+	counting := &countingTLEE{backend: SpawnNewTLEE(logger, "", false)}
+	incremental := NewIncrementalTLEE(counting)
+
+	random := rand.New(rand.NewSource(42))
+	scopes := []string{"C_1_1", "C_1_2", "C_1_3", "C_1_4"}
+	opinions := map[string]subjectivelogic.QueryableOpinion{"V_1": randomOpinion(random)}
+	for _, scope := range scopes {
+		opinions[scope] = randomOpinion(random)
+	}
+
+	for version := 1; version <= 20; version++ {
+		//change a single scope most of the time, and the opinion shared by all scopes every 5th version
+		if version%5 == 0 {
+			opinions["V_1"] = randomOpinion(random)
+		} else {
+			opinions[scopes[random.Intn(len(scopes))]] = randomOpinion(random)
+		}
+		structure, values := createScopedValues(scopes, opinions)
+		counting.scopes = nil
+
+		expected, err := full.RunTLEE("tmi", version, 1, structure, values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := incremental.RunTLEE("tmi", version, 1, structure, values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(expected) != len(actual) {
+			t.Fatalf("version %d: expected %d results, got %d", version, len(expected), len(actual))
+		}
+		for scope, opinion := range expected {
+			if actual[scope] == nil || actual[scope].String() != opinion.String() {
+				t.Errorf("version %d: result of scope %s differs from full run: %v != %v", version, scope, actual[scope], opinion)
+			}
+		}
+		if version > 1 && version%5 != 0 && len(counting.scopes) != 1 {
+			t.Errorf("version %d: expected a single scope to be evaluated, got %v", version, counting.scopes)
+		}
+	}
+
+	//A changed fingerprint always causes a full run
+	structure, values := createScopedValues(scopes, opinions)
+	counting.scopes = nil
+	if _, err := incremental.RunTLEE("tmi", 21, 2, structure, values); err != nil {
+		t.Fatal(err)
+	}
+	if len(counting.scopes) != len(scopes) {
+		t.Errorf("expected full run after fingerprint change, got %v", counting.scopes)
+	}
+}
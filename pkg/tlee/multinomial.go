@@ -0,0 +1,122 @@
+package tlee
+
+import (
+	"errors"
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"strings"
+)
+
+var ErrMultinomialEdge = errors.New("multinomial opinions are only supported on edges into the target")
+
+type multinomialFusionFunction func(opinion1 *multinomial.Opinion, opinion2 *multinomial.Opinion) (*multinomial.Opinion, error)
+
+func hasMultinomialOpinions(relationships []trustmodelstructure.TrustRelationship) bool {
+	for _, relationship := range relationships {
+		if _, ok := relationship.Opinion().(*multinomial.Opinion); ok {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+multinomialFusionOperator returns the multinomial variant of a fusion operator of a trust graph. Like fusionOperator, nil
+is returned for NoFusion.
+*/
+func multinomialFusionOperator(operator trustmodelstructure.FusionOperator) (multinomialFusionFunction, error) {
+	switch operator {
+	case trustmodelstructure.AveragingFusion:
+		return multinomial.AveragingFusion, nil
+	case trustmodelstructure.CumulativeFusion:
+		return multinomial.CumulativeFusion, nil
+	case trustmodelstructure.NoFusion:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: fusion operator %d for multinomial opinions", ErrUnsupportedOperator, operator)
+	}
+}
+
+func fuseAllMultinomial(opinions []*multinomial.Opinion, fuse multinomialFusionFunction) (*multinomial.Opinion, error) {
+	if len(opinions) == 1 {
+		return opinions[0], nil
+	}
+	if fuse == nil {
+		return nil, ErrFusionRequired
+	}
+	fused := opinions[0]
+	for i := 1; i < len(opinions); i++ {
+		var err error
+		fused, err = fuse(fused, opinions[i])
+		if err != nil {
+			return nil, fmt.Errorf("cannot fuse opinions: %w", err)
+		}
+	}
+	return fused, nil
+}
+
+/*
+evaluateMultinomial evaluates a scope whose edges into the target carry multinomial opinions. The graph itself only
+provides the structure and the binomial referral opinions; the multinomial opinions are taken from the relationships.
+*/
+func (g *scopeGraph) evaluateMultinomial(scope string, relationships []trustmodelstructure.TrustRelationship, operator trustmodelstructure.FusionOperator, discount discountFunction) (*multinomial.Opinion, error) {
+	fuse, err := multinomialFusionOperator(operator)
+	if err != nil {
+		return nil, err
+	}
+	if cycle := g.findCycle(); cycle != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCycle, strings.Join(cycle, " -> "))
+	}
+	root, target, err := g.endpoints(scope)
+	if err != nil {
+		return nil, err
+	}
+	if root == target {
+		return nil, fmt.Errorf("%w: target %s is the root trustor", ErrNoPath, target)
+	}
+
+	//source->multinomial opinions on the edge from source to target
+	edgeOpinions := make(map[string][]*multinomial.Opinion)
+	for _, relationship := range relationships {
+		opinion, isMultinomial := relationship.Opinion().(*multinomial.Opinion)
+		switch {
+		case isMultinomial && relationship.Destination() != target:
+			return nil, fmt.Errorf("%w: edge from %s to %s", ErrMultinomialEdge, relationship.Source(), relationship.Destination())
+		case !isMultinomial && relationship.Destination() == target:
+			return nil, fmt.Errorf("%w: binomial opinion on edge from %s to %s", ErrMultinomialEdge, relationship.Source(), target)
+		case isMultinomial:
+			edgeOpinions[relationship.Source()] = append(edgeOpinions[relationship.Source()], opinion)
+		}
+	}
+
+	paths, err := g.nodePaths(root, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: %s to %s", ErrNoPath, root, target)
+	}
+	discounted := make([]*multinomial.Opinion, 0, len(paths))
+	for _, nodes := range paths {
+		source := nodes[len(nodes)-2]
+		opinion, err := fuseAllMultinomial(edgeOpinions[source], fuse)
+		if err != nil {
+			return nil, fmt.Errorf("edge from %s to %s: %w", source, target, err)
+		}
+		if len(nodes) > 2 {
+			referral := make([]subjectivelogic.Opinion, 0, len(nodes)-2)
+			for i := 1; i < len(nodes)-1; i++ {
+				referral = append(referral, g.edges[nodes[i-1]][nodes[i]])
+			}
+			trust, err := discount(referral)
+			if err != nil {
+				return nil, fmt.Errorf("cannot discount opinions: %w", err)
+			}
+			opinion = multinomial.Discount(&trust, opinion)
+		}
+		discounted = append(discounted, opinion)
+	}
+	return fuseAllMultinomial(discounted, fuse)
+}
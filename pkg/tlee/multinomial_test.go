@@ -0,0 +1,66 @@
+package tlee
+
+import (
+	"errors"
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
+	internaltrustmodelstructure "github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelstructure"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func nodeState(t *testing.T, running float64, degraded float64, failed float64, uncertainty float64) *multinomial.Opinion {
+	t.Helper()
+	opinion, err := multinomial.New(map[string]float64{"RUNNING": running, "DEGRADED": degraded, "FAILED": failed}, uncertainty,
+		map[string]float64{"RUNNING": 0.5, "DEGRADED": 0.3, "FAILED": 0.2}, []string{"RUNNING", "DEGRADED"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return opinion
+}
+
+func runMultinomialScope(relationships []trustmodelstructure.TrustRelationship) (subjectivelogic.QueryableOpinion, error) {
+	structure := internaltrustmodelstructure.NewTrustGraphDTO(trustmodelstructure.CumulativeFusion, trustmodelstructure.DefaultDiscount, nil)
+	tlee := SpawnNewTLEE(slog.New(slog.NewTextHandler(io.Discard, nil)), "", false)
+	results, err := tlee.RunTLEE("tmi", 1, 1, structure, map[string][]trustmodelstructure.TrustRelationship{"node": relationships})
+	if err != nil {
+		return nil, err
+	}
+	return results["node"], nil
+}
+
+func TestMultinomialTarget(t *testing.T) {
+	referral, _ := subjectivelogic.NewOpinion(0.6, 0.2, 0.2, 0.5)
+	direct := nodeState(t, 0.6, 0.1, 0.1, 0.2)
+	recommended := nodeState(t, 0.2, 0.2, 0.4, 0.2)
+
+	result, err := runMultinomialScope([]trustmodelstructure.TrustRelationship{
+		internaltrustmodelstructure.NewTrustRelationshipDTO("taf", "node", direct),
+		internaltrustmodelstructure.NewTrustRelationshipDTO("taf", "monitor", &referral),
+		internaltrustmodelstructure.NewTrustRelationshipDTO("monitor", "node", recommended),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opinion, ok := result.(*multinomial.Opinion)
+	if !ok {
+		t.Fatalf("expected multinomial opinion, got %T", result)
+	}
+	expected, _ := multinomial.CumulativeFusion(multinomial.Discount(&referral, recommended), direct)
+	if !opinion.Equal(expected, tolerance) {
+		t.Errorf("expected %s, got %s", expected, opinion)
+	}
+}
+
+func TestMultinomialReferralRejected(t *testing.T) {
+	referral, _ := subjectivelogic.NewOpinion(0.6, 0.2, 0.2, 0.5)
+	_, err := runMultinomialScope([]trustmodelstructure.TrustRelationship{
+		internaltrustmodelstructure.NewTrustRelationshipDTO("taf", "monitor", nodeState(t, 0.6, 0.1, 0.1, 0.2)),
+		internaltrustmodelstructure.NewTrustRelationshipDTO("monitor", "node", &referral),
+	})
+	if !errors.Is(err, ErrMultinomialEdge) {
+		t.Errorf("expected multinomial edge error, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package tlee
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/tleeinterface"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+var ErrUnknownBackend = errors.New("unknown TLEE backend")
+
+/*
+BackendConfig contains the configuration passed to a TLEE backend when it is instantiated.
+*/
+type BackendConfig struct {
+	FilePath      string
+	DebuggingMode bool
+	//backend-specific options as declared by a trust model template
+	Options map[string]string
+}
+
+/*
+A BackendFactory creates a new instance of a TLEE backend. Each worker creates its own instances, so the returned TLEE
+does not need to be safe for concurrent use.
+*/
+type BackendFactory func(logger *slog.Logger, config BackendConfig) (tleeinterface.TLEE, error)
+
+/*
+A BackendCapability is a property of a TLEE backend that allows the TAF to optimize how the backend is used. Backends
+declare their capabilities when they are registered; backends without capabilities are always used as they are.
+*/
+type BackendCapability int
+
+const (
+	/*
+		IndependentScopes declares that the backend evaluates each scope independently of all other scopes of a trust model,
+		so that results of unchanged scopes can be reused (see IncrementalTLEE).
+	*/
+	IndependentScopes BackendCapability = iota
+)
+
+type backend struct {
+	factory      BackendFactory
+	capabilities []BackendCapability
+}
+
+var backends = map[string]backend{}
+
+/*
+RegisterBackend makes a TLEE backend available under the given name. It is meant to be called from the init function
+of the package providing the backend.
+*/
+func RegisterBackend(name string, factory BackendFactory, capabilities ...BackendCapability) {
+	backends[name] = backend{
+		factory:      factory,
+		capabilities: capabilities,
+	}
+}
+
+/*
+NewBackend creates a new instance of the TLEE backend registered under the given name.
+*/
+func NewBackend(name string, logger *slog.Logger, config BackendConfig) (tleeinterface.TLEE, error) {
+	registered, exists := backends[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, name)
+	}
+	return registered.factory(logger, config)
+}
+
+/*
+HasCapability returns true if the TLEE backend registered under the given name has declared the given capability.
+*/
+func HasCapability(name string, capability BackendCapability) bool {
+	return slices.Contains(backends[name].capabilities, capability)
+}
+
+/*
+Backends returns the names of all registered TLEE backends.
+*/
+func Backends() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+/*
+BackendKey returns a key that identifies a backend together with its options, so that instances with identical
+configurations can be shared.
+*/
+func BackendKey(name string, options map[string]string) string {
+	keys := make([]string, 0, len(options))
+	for option := range options {
+		keys = append(keys, option)
+	}
+	slices.Sort(keys)
+	var key strings.Builder
+	key.WriteString(name)
+	for _, option := range keys {
+		key.WriteString(fmt.Sprintf(";%s=%s", option, options[option]))
+	}
+	return key.String()
+}
+
+func init() {
+	RegisterBackend(InternalBackend, func(logger *slog.Logger, config BackendConfig) (tleeinterface.TLEE, error) {
+		return SpawnNewTLEE(logger, config.FilePath, config.DebuggingMode), nil
+	}, IndependentScopes)
+}
+
+/*
+InternalBackend is the name of the internal debugging TLEE.
+*/
+const InternalBackend = "internal"
@@ -0,0 +1,46 @@
+package tlee
+
+import (
+	"errors"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/tleeinterface"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+func TestBackendRegistry(t *testing.T) {
+	var received BackendConfig
+	RegisterBackend("test-backend", func(logger *slog.Logger, config BackendConfig) (tleeinterface.TLEE, error) {
+		received = config
+		return SpawnNewTLEE(logger, config.FilePath, config.DebuggingMode), nil
+	})
+	if !slices.Contains(Backends(), "test-backend") || !slices.Contains(Backends(), InternalBackend) {
+		t.Errorf("expected test and internal backend to be registered, got %v", Backends())
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if _, err := NewBackend("test-backend", logger, BackendConfig{Options: map[string]string{"mode": "fast"}}); err != nil {
+		t.Fatal(err)
+	}
+	if received.Options["mode"] != "fast" {
+		t.Error("expected options to be passed to the backend factory")
+	}
+	if _, err := NewBackend("missing-backend", logger, BackendConfig{}); !errors.Is(err, ErrUnknownBackend) {
+		t.Errorf("expected ErrUnknownBackend, got %v", err)
+	}
+	if HasCapability("test-backend", IndependentScopes) || !HasCapability(InternalBackend, IndependentScopes) {
+		t.Error("expected only the internal backend to evaluate scopes independently")
+	}
+}
+
+func TestBackendKey(t *testing.T) {
+	first := BackendKey("test-backend", map[string]string{"a": "1", "b": "2"})
+	second := BackendKey("test-backend", map[string]string{"b": "2", "a": "1"})
+	if first != second {
+		t.Errorf("expected keys to be independent of option order: %s != %s", first, second)
+	}
+	if BackendKey("test-backend", nil) == first {
+		t.Error("expected different options to result in different keys")
+	}
+}
@@ -2,26 +2,50 @@ package tlee
 
 import (
 	"errors"
+	"fmt"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
 	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
 	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
 )
 
+var (
+	ErrUnsupportedOperator = errors.New("unsupported operator")
+	ErrCycle               = errors.New("trust graph contains a cycle")
+	ErrAmbiguousGraph      = errors.New("ambiguous trust graph")
+	ErrNoPath              = errors.New("no trust path between trustor and target")
+	ErrFusionRequired      = errors.New("no fusion operator provided, although required")
+	ErrMissingOpinion      = errors.New("trust relationship without opinion")
+	ErrTooManyPaths        = errors.New("too many trust paths")
+)
+
+/*
+maxPaths limits the number of paths from the root trustor to the target that are evaluated per scope, as the number of
+paths can grow exponentially with the size of the trust graph.
+*/
+const maxPaths = 4096
+
 /*
 TLEE represents an internal TLEE as part of the TAF that can be used for debugging purposes, independent of the actual
 TLEE implementation.
+
+For each scope, the TLEE builds a trust graph from the trust relationships of the scope. Opinions on the same edge are
+fused first. The TLEE then enumerates all paths from the root trustor (the only node without incoming edges) to the
+target of the scope (the node named like the scope, or otherwise the only node without outgoing edges), discounts the
+opinions along each path with the discount operator of the structure and fuses the opinions of all paths with its fusion
+operator. Graphs with cycles and scopes with more than maxPaths paths are rejected.
+
+Multinomial opinions (see package multinomial) are supported on the edges into the target. In this case, the opinions
+of each path are discounted by the binomial referral trust along the path and fused with the multinomial variant of the
+fusion operator.
 */
 type TLEE struct {
 	logger        *slog.Logger
 	debuggingMode bool
 }
 
-type CurrentEntry struct {
-	source      string
-	destination string
-	opinions    []subjectivelogic.Opinion
-}
-
 func SpawnNewTLEE(logger *slog.Logger, filePath string, debuggingMode bool) *TLEE {
 	return &TLEE{
 		logger:        logger,
@@ -30,207 +54,370 @@ func SpawnNewTLEE(logger *slog.Logger, filePath string, debuggingMode bool) *TLE
 
 }
 
+type fusionFunction func(opinion1 *subjectivelogic.Opinion, opinion2 *subjectivelogic.Opinion) (subjectivelogic.Opinion, error)
+
+type discountFunction func(path []subjectivelogic.Opinion) (subjectivelogic.Opinion, error)
+
 func (t *TLEE) RunTLEE(trustmodelID string, version int, fingerprint uint32, structure trustmodelstructure.TrustGraphStructure, values map[string][]trustmodelstructure.TrustRelationship) (map[string]subjectivelogic.QueryableOpinion, error) {
-	results := make(map[string]subjectivelogic.QueryableOpinion)
+	fuse, err := fusionOperator(structure.Operator())
+	if err != nil {
+		return nil, err
+	}
+	discount, err := discountOperator(structure.DiscountOperator())
+	if err != nil {
+		return nil, err
+	}
 
-	var ff func(opinion1 *subjectivelogic.Opinion, opinion2 *subjectivelogic.Opinion) (subjectivelogic.Opinion, error)
+	results := make(map[string]subjectivelogic.QueryableOpinion)
+	for scope, relationships := range values {
+		graph, err := newScopeGraph(relationships, fuse)
+		if err != nil {
+			return nil, fmt.Errorf("scope %s: %w", scope, err)
+		}
+		var opinion subjectivelogic.QueryableOpinion
+		if hasMultinomialOpinions(relationships) {
+			opinion, err = graph.evaluateMultinomial(scope, relationships, structure.Operator(), discount)
+		} else {
+			var binomial subjectivelogic.Opinion
+			binomial, err = graph.evaluate(scope, fuse, discount)
+			opinion = &binomial
+		}
+		if err != nil {
+			return nil, fmt.Errorf("scope %s: %w", scope, err)
+		}
+		if t.debuggingMode {
+			t.logger.Debug("Evaluated scope", "TMI", trustmodelID, "Version", version, "Scope", scope, "Opinion", opinion.String())
+		}
+		results[scope] = opinion
+	}
+	return results, nil
+}
 
-	switch structure.Operator() {
+/*
+fusionOperator returns the subjective logic operator for a fusion operator of a trust graph. For NoFusion, nil is
+returned, so that any attempt to fuse opinions results in an error.
+*/
+func fusionOperator(operator trustmodelstructure.FusionOperator) (fusionFunction, error) {
+	switch operator {
 	case trustmodelstructure.AveragingFusion:
-		ff = subjectivelogic.AveragingFusion
+		return subjectivelogic.AveragingFusion, nil
 	case trustmodelstructure.ConstraintFusion:
-		ff = subjectivelogic.ConstraintFusion
+		return subjectivelogic.ConstraintFusion, nil
 	case trustmodelstructure.CumulativeFusion:
-		ff = subjectivelogic.CumulativeFusion
+		return subjectivelogic.CumulativeFusion, nil
 	case trustmodelstructure.WeightedFusion:
-		ff = subjectivelogic.WeightedFusion
+		return subjectivelogic.WeightedFusion, nil
 	case trustmodelstructure.NoFusion:
-		for scope, relationships := range values {
-			if len(relationships) != 1 {
-				return nil, errors.New("No Fusion Operator Provided, although required.")
-			}
-			results[scope] = relationships[0].Opinion()
-		}
+		return nil, nil
 	default:
-		return nil, errors.New("Unsupported Fusion Operator.") // + structure.Operator())
+		return nil, fmt.Errorf("%w: fusion operator %d", ErrUnsupportedOperator, operator)
 	}
+}
 
-	for scope, relationships := range values {
-		indegrees := make(map[string]int)
-		current := make(map[string]CurrentEntry)
-
-		numVertices := 0
-		nodes := make(map[string]int)
-
-		for _, relationship := range relationships {
-			indegrees[relationship.Destination()]++
-
-			key := relationship.Source() + ":" + relationship.Destination()
-
-			opinion, err := subjectivelogic.NewOpinion(
-				relationship.Opinion().Belief(),
-				relationship.Opinion().Disbelief(),
-				relationship.Opinion().Uncertainty(),
-				relationship.Opinion().BaseRate(),
-			)
-			if err != nil {
-				return nil, errors.New("subjective logic error" + err.Error())
+/*
+discountOperator returns a function that discounts the opinions along a trust path, ordered from the root trustor to
+the target.
+*/
+func discountOperator(operator trustmodelstructure.DiscountOperator) (discountFunction, error) {
+	switch operator {
+	case trustmodelstructure.DefaultDiscount:
+		return func(path []subjectivelogic.Opinion) (subjectivelogic.Opinion, error) {
+			if len(path) == 1 {
+				return path[0], nil
 			}
-
-			entry, exists := current[key]
-			if !exists {
-				current[key] = CurrentEntry{
-					source:      relationship.Source(),
-					destination: relationship.Destination(),
-					opinions:    []subjectivelogic.Opinion{opinion},
+			return subjectivelogic.MultiEdgeTrustDisc(path)
+		}, nil
+	case trustmodelstructure.OppositeBeliefDiscount:
+		return func(path []subjectivelogic.Opinion) (subjectivelogic.Opinion, error) {
+			discounted := path[0]
+			for i := 1; i < len(path); i++ {
+				var err error
+				discounted, err = subjectivelogic.TrustDiscountingOppositeBelief(&discounted, &path[i])
+				if err != nil {
+					return subjectivelogic.Opinion{}, err
 				}
-			} else {
-				entry.opinions = append(entry.opinions, opinion)
-				current[key] = entry
 			}
+			return discounted, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: discount operator %d", ErrUnsupportedOperator, operator)
+	}
+}
 
-			if _, exists := nodes[relationship.Source()]; !exists {
-				nodes[relationship.Source()] = numVertices
-				numVertices += 1
-			}
-			if _, exists := nodes[relationship.Destination()]; !exists {
-				nodes[relationship.Destination()] = numVertices
-				numVertices += 1
-			}
+/*
+fuseAll fuses a list of opinions pairwise from left to right.
+*/
+func fuseAll(opinions []subjectivelogic.Opinion, fuse fusionFunction) (subjectivelogic.Opinion, error) {
+	if len(opinions) == 1 {
+		return opinions[0], nil
+	}
+	if fuse == nil {
+		return subjectivelogic.Opinion{}, ErrFusionRequired
+	}
+	fused := opinions[0]
+	for i := 1; i < len(opinions); i++ {
+		var err error
+		fused, err = fuse(&fused, &opinions[i])
+		if err != nil {
+			return subjectivelogic.Opinion{}, fmt.Errorf("cannot fuse opinions: %w", err)
 		}
+	}
+	return fused, nil
+}
 
-		for {
-			// Initializing result matrix and filling it up with same values as given graph
-			reverseNodes := make([]string, numVertices)
-			for node, i := range nodes {
-				reverseNodes[i] = node
-			}
+/*
+A scopeGraph is the trust graph of a single scope, with one (fused) opinion per edge.
+*/
+type scopeGraph struct {
+	nodes []string
+	//source->destination->opinion
+	edges    map[string]map[string]subjectivelogic.Opinion
+	indegree map[string]int
+}
 
-			prev := make([][]int, numVertices)
-			dist := make([][]int, numVertices)
-			for i := 0; i < numVertices; i++ {
-				prev[i] = make([]int, numVertices)
-				dist[i] = make([]int, numVertices)
-				for j := 0; j < numVertices; j++ {
-					prev[i][j] = -1
-					dist[i][j] = 999
-				}
+func newScopeGraph(relationships []trustmodelstructure.TrustRelationship, fuse fusionFunction) (*scopeGraph, error) {
+	graph := &scopeGraph{
+		nodes:    make([]string, 0),
+		edges:    make(map[string]map[string]subjectivelogic.Opinion),
+		indegree: make(map[string]int),
+	}
+	//source->destination->opinions
+	edgeOpinions := make(map[string]map[string][]subjectivelogic.Opinion)
+	for _, relationship := range relationships {
+		source, destination := relationship.Source(), relationship.Destination()
+		if isNilOpinion(relationship.Opinion()) {
+			return nil, fmt.Errorf("%w: from %s to %s", ErrMissingOpinion, source, destination)
+		}
+		opinion, err := subjectivelogic.NewOpinion(
+			relationship.Opinion().Belief(),
+			relationship.Opinion().Disbelief(),
+			relationship.Opinion().Uncertainty(),
+			relationship.Opinion().BaseRate(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid opinion from %s to %s: %w", source, destination, err)
+		}
+		for _, node := range []string{source, destination} {
+			if _, exists := graph.indegree[node]; !exists {
+				graph.indegree[node] = 0
+				graph.nodes = append(graph.nodes, node)
 			}
-
-			for key, entry := range current {
-				prev[nodes[entry.source]][nodes[entry.destination]] = nodes[entry.source]
-				dist[nodes[entry.source]][nodes[entry.destination]] = -1
-
-				if len(entry.opinions) > 1 {
-					// fuse
-					var prev subjectivelogic.Opinion
-					for i, v := range entry.opinions {
-						if i == 0 {
-							prev = v
-						} else {
-							fused, err := ff(&prev, &v)
-							if err != nil {
-								return nil, errors.New("cannot fuse opinions" + err.Error())
-							}
-
-							prev = fused
-						}
-					}
-
-					// store fused opinion
-					entry.opinions = []subjectivelogic.Opinion{prev}
-					current[key] = entry
-				}
+		}
+		if edgeOpinions[source] == nil {
+			edgeOpinions[source] = make(map[string][]subjectivelogic.Opinion)
+			graph.edges[source] = make(map[string]subjectivelogic.Opinion)
+		}
+		if len(edgeOpinions[source][destination]) == 0 {
+			graph.indegree[destination]++
+		}
+		edgeOpinions[source][destination] = append(edgeOpinions[source][destination], opinion)
+	}
+	slices.Sort(graph.nodes)
+	for source, destinations := range edgeOpinions {
+		for destination, opinions := range destinations {
+			fused, err := fuseAll(opinions, fuse)
+			if err != nil {
+				return nil, fmt.Errorf("edge from %s to %s: %w", source, destination, err)
 			}
+			graph.edges[source][destination] = fused
+		}
+	}
+	return graph, nil
+}
 
-			if len(current) == 1 {
-				for _, u := range current {
-					results[scope] = &u.opinions[0]
-				}
+/*
+isNilOpinion returns true if an opinion is nil, including nil pointers wrapped in the interface.
+*/
+func isNilOpinion(opinion subjectivelogic.QueryableOpinion) bool {
+	if opinion == nil {
+		return true
+	}
+	value := reflect.ValueOf(opinion)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
 
-				break
-			}
+/*
+successors returns the destinations of all outgoing edges of a node in a deterministic order.
+*/
+func (g *scopeGraph) successors(node string) []string {
+	successors := make([]string, 0, len(g.edges[node]))
+	for destination := range g.edges[node] {
+		successors = append(successors, destination)
+	}
+	slices.Sort(successors)
+	return successors
+}
 
-			var lowestSource int
-			var lowestDestination int
-
-			// Running over the result matrix and following the algorithm
-			for k := 0; k < numVertices; k++ {
-				for i := 0; i < numVertices; i++ {
-					for j := 0; j < numVertices; j++ {
-						// If there is a less costly path from i to j node, remembering it
-						if dist[i][j] > dist[i][k]+dist[k][j] {
-							dist[i][j] = dist[i][k] + dist[k][j]
-							prev[i][j] = prev[k][j]
-
-							if dist[i][j] < dist[lowestSource][lowestDestination] {
-								lowestSource = i
-								lowestDestination = j
-							}
-						}
-					}
+/*
+findCycle returns the nodes of a cycle in the graph, or nil if the graph is acyclic.
+*/
+func (g *scopeGraph) findCycle() []string {
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	state := make(map[string]int)
+	stack := make([]string, 0)
+	var visit func(node string) []string
+	visit = func(node string) []string {
+		state[node] = active
+		stack = append(stack, node)
+		for _, successor := range g.successors(node) {
+			switch state[successor] {
+			case active:
+				start := slices.Index(stack, successor)
+				return append(slices.Clone(stack[start:]), successor)
+			case unvisited:
+				if cycle := visit(successor); cycle != nil {
+					return cycle
 				}
 			}
-
-			if prev[lowestSource][lowestDestination] == -1 {
-				return nil, errors.New("no longest path found")
-			}
-
-			path := []string{}
-			opinions := []subjectivelogic.Opinion{}
-			targetSource := reverseNodes[lowestSource]
-			targetDestination := reverseNodes[lowestDestination]
-			targetKey := targetSource + ":" + targetDestination
-
-			for lowestSource != lowestDestination {
-				target := reverseNodes[lowestDestination]
-				lowestDestination = prev[lowestSource][lowestDestination]
-				key := reverseNodes[lowestDestination] + ":" + target
-				if len(current[key].opinions) != 1 {
-					return nil, errors.New("too many opinions for discounting")
-				}
-				path = append([]string{key}, path...)
-				opinions = append([]subjectivelogic.Opinion{current[key].opinions[0]}, opinions...)
-				delete(current, key)
+		}
+		stack = stack[:len(stack)-1]
+		state[node] = done
+		return nil
+	}
+	for _, node := range g.nodes {
+		if state[node] == unvisited {
+			if cycle := visit(node); cycle != nil {
+				return cycle
 			}
+		}
+	}
+	return nil
+}
 
-			var discounted subjectivelogic.Opinion
-
-			switch len(opinions) {
-			case 0:
-				return nil, errors.New("an out of opinions without result")
-
-			case 1:
-				results[scope] = &opinions[0]
-
-			case 2:
-				tmp, err := subjectivelogic.TrustDiscounting(&opinions[0], &opinions[1])
-				if err != nil {
-					return nil, errors.New("subjective logic error")
-				}
-				discounted = tmp
+/*
+endpoints determines the root trustor and the target of the scope.
+*/
+func (g *scopeGraph) endpoints(scope string) (string, string, error) {
+	roots := make([]string, 0, 1)
+	sinks := make([]string, 0, 1)
+	for _, node := range g.nodes {
+		if g.indegree[node] == 0 {
+			roots = append(roots, node)
+		}
+		if len(g.edges[node]) == 0 {
+			sinks = append(sinks, node)
+		}
+	}
+	if len(roots) != 1 {
+		return "", "", fmt.Errorf("%w: expected exactly one root trustor, found %v", ErrAmbiguousGraph, roots)
+	}
+	if _, isNode := g.indegree[scope]; isNode {
+		return roots[0], scope, nil
+	}
+	if len(sinks) != 1 {
+		return "", "", fmt.Errorf("%w: expected exactly one target, found %v", ErrAmbiguousGraph, sinks)
+	}
+	return roots[0], sinks[0], nil
+}
 
-			default:
-				tmp, err := subjectivelogic.MultiEdgeTrustDisc(opinions)
-				if err != nil {
-					return nil, errors.New("subjective logic error")
-				}
-				discounted = tmp
+/*
+reaching returns the set of nodes from which the target can be reached, including the target itself.
+*/
+func (g *scopeGraph) reaching(target string) map[string]bool {
+	predecessors := make(map[string][]string)
+	for source, destinations := range g.edges {
+		for destination := range destinations {
+			predecessors[destination] = append(predecessors[destination], source)
+		}
+	}
+	reaching := map[string]bool{target: true}
+	queue := []string{target}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, predecessor := range predecessors[node] {
+			if !reaching[predecessor] {
+				reaching[predecessor] = true
+				queue = append(queue, predecessor)
 			}
+		}
+	}
+	return reaching
+}
 
-			entry, exists := current[targetKey]
-			if !exists {
-				current[targetKey] = CurrentEntry{
-					source:      targetSource,
-					destination: targetDestination,
-					opinions:    []subjectivelogic.Opinion{discounted},
-				}
-			} else {
-				entry.opinions = append(entry.opinions, discounted)
-				current[targetKey] = entry
+/*
+nodePaths enumerates the nodes along all paths from root to target. Only nodes from which the target can be reached are
+visited, so that the enumeration stops after maxPaths paths at the latest.
+*/
+func (g *scopeGraph) nodePaths(root string, target string) ([][]string, error) {
+	reaching := g.reaching(target)
+	paths := make([][]string, 0)
+	current := []string{root}
+	var walk func(node string) error
+	walk = func(node string) error {
+		if node == target {
+			if len(paths) == maxPaths {
+				return fmt.Errorf("%w: more than %d paths from %s to %s", ErrTooManyPaths, maxPaths, root, target)
 			}
+			paths = append(paths, slices.Clone(current))
+			return nil
 		}
+		for _, successor := range g.successors(node) {
+			if !reaching[successor] {
+				continue
+			}
+			current = append(current, successor)
+			if err := walk(successor); err != nil {
+				return err
+			}
+			current = current[:len(current)-1]
+		}
+		return nil
+	}
+	if err := walk(root); err != nil {
+		return nil, err
 	}
+	return paths, nil
+}
 
-	return results, nil
+/*
+paths enumerates the opinions along all paths from root to target.
+*/
+func (g *scopeGraph) paths(root string, target string) ([][]subjectivelogic.Opinion, error) {
+	nodePaths, err := g.nodePaths(root, target)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([][]subjectivelogic.Opinion, 0, len(nodePaths))
+	for _, nodes := range nodePaths {
+		path := make([]subjectivelogic.Opinion, 0, len(nodes)-1)
+		for i := 1; i < len(nodes); i++ {
+			path = append(path, g.edges[nodes[i-1]][nodes[i]])
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func (g *scopeGraph) evaluate(scope string, fuse fusionFunction, discount discountFunction) (subjectivelogic.Opinion, error) {
+	if cycle := g.findCycle(); cycle != nil {
+		return subjectivelogic.Opinion{}, fmt.Errorf("%w: %s", ErrCycle, strings.Join(cycle, " -> "))
+	}
+	root, target, err := g.endpoints(scope)
+	if err != nil {
+		return subjectivelogic.Opinion{}, err
+	}
+	if root == target {
+		return subjectivelogic.Opinion{}, fmt.Errorf("%w: target %s is the root trustor", ErrNoPath, target)
+	}
+	paths, err := g.paths(root, target)
+	if err != nil {
+		return subjectivelogic.Opinion{}, err
+	}
+	if len(paths) == 0 {
+		return subjectivelogic.Opinion{}, fmt.Errorf("%w: %s to %s", ErrNoPath, root, target)
+	}
+	discounted := make([]subjectivelogic.Opinion, 0, len(paths))
+	for _, path := range paths {
+		opinion, err := discount(path)
+		if err != nil {
+			return subjectivelogic.Opinion{}, fmt.Errorf("cannot discount opinions: %w", err)
+		}
+		discounted = append(discounted, opinion)
+	}
+	return fuseAll(discounted, fuse)
 }
@@ -0,0 +1,174 @@
+package tlee
+
+import (
+	"errors"
+	"fmt"
+	internaltrustmodelstructure "github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelstructure"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"io"
+	"log/slog"
+	"math"
+	"testing"
+)
+
+const tolerance = 1e-9
+
+type edge struct {
+	source      string
+	destination string
+	opinion     [3]float64
+}
+
+func runScope(fusion trustmodelstructure.FusionOperator, discount trustmodelstructure.DiscountOperator, scope string, edges []edge) (subjectivelogic.QueryableOpinion, error) {
+	relationships := make([]trustmodelstructure.TrustRelationship, 0, len(edges))
+	for _, e := range edges {
+		opinion, err := subjectivelogic.NewOpinion(e.opinion[0], e.opinion[1], e.opinion[2], 0.5)
+		if err != nil {
+			return nil, err
+		}
+		relationships = append(relationships, internaltrustmodelstructure.NewTrustRelationshipDTO(e.source, e.destination, &opinion))
+	}
+	structure := internaltrustmodelstructure.NewTrustGraphDTO(fusion, discount, nil)
+	tlee := SpawnNewTLEE(slog.New(slog.NewTextHandler(io.Discard, nil)), "", false)
+	results, err := tlee.RunTLEE("tmi", 1, 1, structure, map[string][]trustmodelstructure.TrustRelationship{scope: relationships})
+	if err != nil {
+		return nil, err
+	}
+	return results[scope], nil
+}
+
+func assertOpinion(t *testing.T, actual subjectivelogic.QueryableOpinion, belief float64, disbelief float64, uncertainty float64) {
+	t.Helper()
+	if math.Abs(actual.Belief()-belief) > tolerance || math.Abs(actual.Disbelief()-disbelief) > tolerance || math.Abs(actual.Uncertainty()-uncertainty) > tolerance {
+		t.Errorf("expected (%v, %v, %v), got (%v, %v, %v)", belief, disbelief, uncertainty, actual.Belief(), actual.Disbelief(), actual.Uncertainty())
+	}
+}
+
+func TestSingleEdge(t *testing.T) {
+	result, err := runScope(trustmodelstructure.CumulativeFusion, trustmodelstructure.DefaultDiscount, "B", []edge{
+		{"A", "B", [3]float64{0.7, 0.2, 0.1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOpinion(t, result, 0.7, 0.2, 0.1)
+}
+
+func TestMultiHopDiscounting(t *testing.T) {
+	chain := []edge{
+		{"A", "B", [3]float64{0.8, 0.1, 0.1}},
+		{"B", "C", [3]float64{0.6, 0.2, 0.2}},
+	}
+
+	//P(AB) = 0.8 + 0.5*0.1 = 0.85, i.e., b = 0.85*0.6, d = 0.85*0.2, u = 1 - b - d
+	result, err := runScope(trustmodelstructure.CumulativeFusion, trustmodelstructure.DefaultDiscount, "C", chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOpinion(t, result, 0.51, 0.17, 0.32)
+
+	//b = 0.8*0.6 + 0.1*0.2, d = 0.8*0.2 + 0.1*0.6, u = 1 - b - d
+	result, err = runScope(trustmodelstructure.CumulativeFusion, trustmodelstructure.OppositeBeliefDiscount, "C", chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOpinion(t, result, 0.50, 0.22, 0.28)
+}
+
+func TestParallelPathFusion(t *testing.T) {
+	//The path via B yields (0.85, 0, 0.15), which is fused cumulatively with the direct opinion (0.6, 0.2, 0.2):
+	//b = (0.85*0.2 + 0.6*0.15)/0.32, d = (0.2*0.15)/0.32, u = (0.15*0.2)/0.32
+	result, err := runScope(trustmodelstructure.CumulativeFusion, trustmodelstructure.DefaultDiscount, "C", []edge{
+		{"A", "B", [3]float64{0.8, 0.1, 0.1}},
+		{"B", "C", [3]float64{1, 0, 0}},
+		{"A", "C", [3]float64{0.6, 0.2, 0.2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOpinion(t, result, 0.8125, 0.09375, 0.09375)
+}
+
+func TestSameEdgeFusion(t *testing.T) {
+	//Both opinions on the edge are fused as in TestParallelPathFusion
+	result, err := runScope(trustmodelstructure.CumulativeFusion, trustmodelstructure.DefaultDiscount, "B", []edge{
+		{"A", "B", [3]float64{0.85, 0, 0.15}},
+		{"A", "B", [3]float64{0.6, 0.2, 0.2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOpinion(t, result, 0.8125, 0.09375, 0.09375)
+}
+
+func TestInvalidGraphs(t *testing.T) {
+	tests := []struct {
+		name     string
+		fusion   trustmodelstructure.FusionOperator
+		scope    string
+		edges    []edge
+		expected error
+	}{
+		{"cycle", trustmodelstructure.CumulativeFusion, "C", []edge{
+			{"A", "B", [3]float64{0.5, 0, 0.5}},
+			{"B", "C", [3]float64{0.5, 0, 0.5}},
+			{"C", "B", [3]float64{0.5, 0, 0.5}},
+		}, ErrCycle},
+		{"parallel paths without fusion", trustmodelstructure.NoFusion, "C", []edge{
+			{"A", "B", [3]float64{0.5, 0, 0.5}},
+			{"B", "C", [3]float64{0.5, 0, 0.5}},
+			{"A", "C", [3]float64{0.5, 0, 0.5}},
+		}, ErrFusionRequired},
+		{"multiple roots", trustmodelstructure.CumulativeFusion, "C", []edge{
+			{"A", "C", [3]float64{0.5, 0, 0.5}},
+			{"B", "C", [3]float64{0.5, 0, 0.5}},
+		}, ErrAmbiguousGraph},
+		{"multiple targets", trustmodelstructure.CumulativeFusion, "E", []edge{
+			{"A", "B", [3]float64{0.5, 0, 0.5}},
+			{"A", "D", [3]float64{0.5, 0, 0.5}},
+		}, ErrAmbiguousGraph},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := runScope(test.fusion, trustmodelstructure.DefaultDiscount, test.scope, test.edges)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestMissingOpinion(t *testing.T) {
+	tlee := SpawnNewTLEE(slog.New(slog.NewTextHandler(io.Discard, nil)), "", false)
+	structure := internaltrustmodelstructure.NewTrustGraphDTO(trustmodelstructure.CumulativeFusion, trustmodelstructure.DefaultDiscount, nil)
+	for _, opinion := range []subjectivelogic.QueryableOpinion{nil, (*subjectivelogic.Opinion)(nil)} {
+		values := map[string][]trustmodelstructure.TrustRelationship{
+			"B": {internaltrustmodelstructure.NewTrustRelationshipDTO("A", "B", opinion)},
+		}
+		if _, err := tlee.RunTLEE("tmi", 1, 1, structure, values); !errors.Is(err, ErrMissingOpinion) {
+			t.Errorf("expected %v, got %v", ErrMissingOpinion, err)
+		}
+	}
+}
+
+func TestTooManyPaths(t *testing.T) {
+	//each layer of two nodes doubles the number of paths from A to T
+	edges := make([]edge, 0)
+	previous := []string{"A"}
+	for layer := 0; layer < 13; layer++ {
+		current := []string{fmt.Sprintf("L%d_1", layer), fmt.Sprintf("L%d_2", layer)}
+		for _, source := range previous {
+			for _, destination := range current {
+				edges = append(edges, edge{source, destination, [3]float64{0.5, 0, 0.5}})
+			}
+		}
+		previous = current
+	}
+	for _, source := range previous {
+		edges = append(edges, edge{source, "T", [3]float64{0.5, 0, 0.5}})
+	}
+	if _, err := runScope(trustmodelstructure.CumulativeFusion, trustmodelstructure.DefaultDiscount, "T", edges); !errors.Is(err, ErrTooManyPaths) {
+		t.Errorf("expected %v, got %v", ErrTooManyPaths, err)
+	}
+}
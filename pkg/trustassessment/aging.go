@@ -0,0 +1,200 @@
+package trustassessment
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/command"
+	"github.com/horizon-connect-eu/go-taf/pkg/config"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"math"
+	"slices"
+	"time"
+)
+
+// tolerance for comparing aged ATLs with the previous ones
+const atlTolerance = 1e-9
+
+/*
+createOpinionAging converts the configured aging of a trust source.
+*/
+func createOpinionAging(aging config.OpinionAging) core.OpinionAging {
+	return core.OpinionAging{
+		HalfLife:    time.Duration(aging.HalfLife) * time.Millisecond,
+		GracePeriod: time.Duration(aging.GracePeriod) * time.Millisecond,
+		MaxAge:      time.Duration(aging.MaxAge) * time.Millisecond,
+	}
+}
+
+/*
+createSourceAging returns the configured aging for each trust source.
+*/
+func createSourceAging(aging config.Aging) map[core.TrustSource]core.OpinionAging {
+	sourceAging := make(map[core.TrustSource]core.OpinionAging)
+	for _, source := range []core.TrustSource{core.AIV, core.MBD, core.TCH, core.NTM} {
+		if configured, exists := aging.TrustSources[source.String()]; exists {
+			sourceAging[source] = createOpinionAging(configured)
+		} else {
+			sourceAging[source] = createOpinionAging(aging.Default)
+		}
+	}
+	return sourceAging
+}
+
+/*
+agingFor returns the aging of opinions from a trust source for a TMI, which is either declared by the template of the TMI
+or configured.
+*/
+func (worker *Worker) agingFor(tmi core.TrustModelInstance, source core.TrustSource) core.OpinionAging {
+	if provider, ok := tmi.Template().(core.OpinionAgingProvider); ok {
+		if aging := provider.OpinionAging(source); aging != nil {
+			return *aging
+		}
+	}
+	return worker.sourceAging[source]
+}
+
+/*
+trackAging records the time an atomic trust opinion has been received, so that it can be aged until fresh evidence for
+the same trust relationship and trust source arrives. Opinions that have not been applied to the TMI are only tracked if
+they refresh an opinion that is already tracked. Opinions of removed trust relationships are no longer tracked.
+*/
+func (worker *Worker) trackAging(fullTmiID string, tmi core.TrustModelInstance, update core.Update, applied bool) {
+	if removal, isRemoval := update.(trustmodelupdate.RemoveTrustObject); isRemoval && applied {
+		worker.untrackRemoved(fullTmiID, removal)
+		return
+	}
+	ato, isATO := update.(trustmodelupdate.UpdateAtomicTrustOpinion)
+	if !isATO || worker.agingInterval <= 0 {
+		return
+	}
+	aging := worker.agingFor(tmi, ato.TrustSource())
+	tracked := core.AgingOpinion{
+		Trustor:     ato.Trustor(),
+		Trustee:     ato.Trustee(),
+		TrustSource: ato.TrustSource(),
+		Opinion:     ato.Opinion(),
+		Received:    time.Now(),
+		Aging:       aging,
+	}
+	opinions := worker.aging[fullTmiID]
+	for i, existing := range opinions {
+		if existing.Trustor == tracked.Trustor && existing.Trustee == tracked.Trustee && existing.TrustSource == tracked.TrustSource {
+			if aging.Enabled() {
+				opinions[i] = tracked
+			} else {
+				worker.aging[fullTmiID] = append(opinions[:i], opinions[i+1:]...)
+			}
+			return
+		}
+	}
+	if applied && aging.Enabled() {
+		worker.aging[fullTmiID] = append(opinions, tracked)
+	}
+}
+
+/*
+untrackRemoved stops aging the opinions of trust relationships that have been removed from a TMI. Opinions without
+trustor are no longer tracked once their trustee has been removed.
+*/
+func (worker *Worker) untrackRemoved(fullTmiID string, removal trustmodelupdate.RemoveTrustObject) {
+	opinions := slices.DeleteFunc(worker.aging[fullTmiID], func(tracked core.AgingOpinion) bool {
+		if tracked.Trustor == "" {
+			return len(removal.Relationships()) == 0 && tracked.Trustee == removal.TrustObject()
+		}
+		return removal.Removes(trustmodelupdate.TrustRelationship{Trustor: tracked.Trustor, Trustee: tracked.Trustee})
+	})
+	if len(opinions) > 0 {
+		worker.aging[fullTmiID] = opinions
+	} else {
+		delete(worker.aging, fullTmiID)
+	}
+}
+
+/*
+ageOpinions applies synthetic atomic trust opinion updates with the aged opinions of all tracked trust relationships and
+re-evaluates the affected TMIs. Opinions that have become vacuous are no longer tracked. Results are only sent to the TAM
+if the ATLs of a TMI have changed.
+*/
+func (worker *Worker) ageOpinions(now time.Time) {
+	for fullTmiID, opinions := range worker.aging {
+		tmi, exists := worker.tmis[fullTmiID]
+		if !exists {
+			continue
+		}
+		runTlee := false
+		remaining := opinions[:0]
+		for _, tracked := range opinions {
+			factor := tracked.Aging.Factor(now.Sub(tracked.Received))
+			if factor > 0 {
+				remaining = append(remaining, tracked)
+			}
+			if factor >= 1 {
+				continue
+			}
+			update := trustmodelupdate.CreateAtomicTrustOpinionUpdate(ageOpinion(tracked.Opinion, factor), tracked.Trustor, tracked.Trustee, tracked.TrustSource)
+			if tmi.Update(update) {
+				runTlee = true
+				worker.notifyTMIUpdated(fullTmiID, tmi, update)
+			}
+		}
+		if len(remaining) > 0 {
+			worker.aging[fullTmiID] = remaining
+		} else {
+			delete(worker.aging, fullTmiID)
+		}
+		if !runTlee {
+			continue
+		}
+		worker.logger.Debug("Aged opinions of Trust Model Instance", "TMI", fullTmiID)
+		atls, err := worker.executeTLEE(fullTmiID, tmi)
+		if err != nil {
+			worker.logger.Info("TLEE returned error:" + err.Error())
+			continue
+		}
+		if previous, hasResults := worker.results[fullTmiID]; hasResults && sameATLs(previous.ATLs(), atls) {
+			continue
+		}
+		resultSet := worker.executeTDE(fullTmiID, tmi, nil, atls)
+		worker.workersToTam <- command.CreateHandleATLUpdate(resultSet, nil, fullTmiID)
+	}
+}
+
+/*
+sameATLs returns whether two sets of ATLs contain the same propositions with opinions that are equal up to rounding errors.
+*/
+func sameATLs(previous map[string]subjectivelogic.QueryableOpinion, current map[string]subjectivelogic.QueryableOpinion) bool {
+	if len(previous) != len(current) {
+		return false
+	}
+	for proposition, opinion := range current {
+		previousOpinion, exists := previous[proposition]
+		if !exists || previousOpinion == nil || opinion == nil {
+			return false
+		}
+		if math.Abs(previousOpinion.Belief()-opinion.Belief()) > atlTolerance ||
+			math.Abs(previousOpinion.Disbelief()-opinion.Disbelief()) > atlTolerance ||
+			math.Abs(previousOpinion.Uncertainty()-opinion.Uncertainty()) > atlTolerance ||
+			math.Abs(previousOpinion.BaseRate()-opinion.BaseRate()) > atlTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+ageOpinion scales belief and disbelief mass of an opinion by the given factor and shifts the remaining mass into
+uncertainty. Multinomial opinions remain multinomial opinions.
+*/
+func ageOpinion(opinion subjectivelogic.QueryableOpinion, factor float64) subjectivelogic.QueryableOpinion {
+	if multinomialOpinion, ok := opinion.(*multinomial.Opinion); ok {
+		trust, _ := subjectivelogic.NewOpinion(factor, 1-factor, 0, 0.5)
+		return multinomial.Discount(&trust, multinomialOpinion)
+	}
+	belief, disbelief := factor*opinion.Belief(), factor*opinion.Disbelief()
+	aged, err := subjectivelogic.NewOpinion(belief, disbelief, 1-belief-disbelief, opinion.BaseRate())
+	if err != nil {
+		aged, _ = subjectivelogic.NewOpinion(0, 0, 1, opinion.BaseRate())
+	}
+	return &aged
+}
@@ -0,0 +1,169 @@
+package trustassessment
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/command"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustdecision"
+	internaltrustmodelstructure "github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelstructure"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/tleeinterface"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"io"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+)
+
+/*
+agingTestTMI is a minimal TMI with a single trust relationship that keeps the latest opinion per trust source.
+*/
+type agingTestTMI struct {
+	rtlTestTMI
+	template core.TrustModelTemplate
+	opinions map[core.TrustSource]subjectivelogic.QueryableOpinion
+}
+
+func (tmi *agingTestTMI) Template() core.TrustModelTemplate                          { return tmi.template }
+func (tmi *agingTestTMI) Fingerprint() uint32                                        { return 0 }
+func (tmi *agingTestTMI) Values() map[string][]trustmodelstructure.TrustRelationship { return nil }
+
+func (tmi *agingTestTMI) Structure() trustmodelstructure.TrustGraphStructure {
+	return internaltrustmodelstructure.NewTrustGraphDTO(trustmodelstructure.CumulativeFusion, trustmodelstructure.DefaultDiscount, []trustmodelstructure.AdjacencyListEntry{
+		internaltrustmodelstructure.NewAdjacencyEntryDTO("V_ego", []string{"vehicle_1"}),
+	})
+}
+
+func (tmi *agingTestTMI) Update(update core.Update) bool {
+	ato := update.(trustmodelupdate.UpdateAtomicTrustOpinion)
+	tmi.opinions[ato.TrustSource()] = ato.Opinion()
+	return true
+}
+
+/*
+agingTestTLEE returns the latest AIV opinion of a TMI as its only ATL.
+*/
+type agingTestTLEE struct {
+	tmi *agingTestTMI
+}
+
+func (tlee agingTestTLEE) RunTLEE(trustmodelID string, version int, fingerprint uint32, structure trustmodelstructure.TrustGraphStructure, values map[string][]trustmodelstructure.TrustRelationship) (map[string]subjectivelogic.QueryableOpinion, error) {
+	return map[string]subjectivelogic.QueryableOpinion{"vehicle_1": tlee.tmi.opinions[core.AIV]}, nil
+}
+
+/*
+agingTestTemplate declares that opinions from MBD never age.
+*/
+type agingTestTemplate struct {
+	core.TrustModelTemplate
+}
+
+func (template agingTestTemplate) OpinionAging(source core.TrustSource) *core.OpinionAging {
+	if source == core.MBD {
+		return &core.OpinionAging{}
+	}
+	return nil
+}
+
+func assertAgedOpinion(t *testing.T, opinion subjectivelogic.QueryableOpinion, belief float64, disbelief float64) {
+	t.Helper()
+	if math.Abs(opinion.Belief()-belief) > 1e-9 || math.Abs(opinion.Disbelief()-disbelief) > 1e-9 || math.Abs(opinion.Uncertainty()-(1-belief-disbelief)) > 1e-9 {
+		t.Errorf("expected belief %v and disbelief %v, got %v", belief, disbelief, opinion)
+	}
+}
+
+func TestOpinionAgingFactor(t *testing.T) {
+	aging := core.OpinionAging{HalfLife: time.Minute, GracePeriod: time.Minute, MaxAge: 10 * time.Minute}
+	for age, expected := range map[time.Duration]float64{
+		0:                1,
+		30 * time.Second: 1,
+		2 * time.Minute:  0.5,
+		3 * time.Minute:  0.25,
+		10 * time.Minute: 0,
+	} {
+		if factor := aging.Factor(age); math.Abs(factor-expected) > 1e-9 {
+			t.Errorf("expected factor %v for age %v, got %v", expected, age, factor)
+		}
+	}
+	//Without maximum age, evidence is fully aged once the remaining mass is negligible
+	aging.MaxAge = 0
+	if factor := aging.Factor(12 * time.Minute); factor != 0 {
+		t.Errorf("expected negligible factor to be 0, got %v", factor)
+	}
+	if (core.OpinionAging{GracePeriod: time.Minute}).Enabled() {
+		t.Error("expected aging without half-life and maximum age to be disabled")
+	}
+}
+
+func TestWorkerOpinionAging(t *testing.T) {
+	workersToTam := make(chan core.Command, 4)
+	fullTmiID := "//client/SES-1/T@0.0.1/vehicle_1"
+	aging := core.OpinionAging{HalfLife: time.Minute, GracePeriod: time.Minute, MaxAge: 5 * time.Minute}
+	worker := Worker{
+		logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		tmis:          make(map[string]core.TrustModelInstance),
+		tmiSessions:   make(map[string]string),
+		workersToTam:  workersToTam,
+		incoming:      make(map[string][]core.Command),
+		defaultPolicy: trustdecision.ProjectedProbabilityPolicy{},
+		results:       make(map[string]core.AtlResultSet),
+		rtls:          make(map[string]map[string]subjectivelogic.QueryableOpinion),
+		agingInterval: time.Second,
+		sourceAging:   map[core.TrustSource]core.OpinionAging{core.AIV: aging, core.MBD: aging},
+		aging:         make(map[string][]core.AgingOpinion),
+		tlees:         make(map[string]tleeinterface.TLEE),
+	}
+	tmi := &agingTestTMI{template: agingTestTemplate{}, opinions: make(map[core.TrustSource]subjectivelogic.QueryableOpinion)}
+	worker.tmis[fullTmiID] = tmi
+	worker.tlees[worker.defaultBackend] = agingTestTLEE{tmi: tmi}
+
+	aiv, _ := subjectivelogic.NewOpinion(0.8, 0.1, 0.1, 0.5)
+	worker.handle(command.CreateHandleTMIUpdate(fullTmiID, nil,
+		trustmodelupdate.CreateAtomicTrustOpinionUpdate(&aiv, "", "vehicle_1", core.AIV),
+		createATOUpdate("vehicle_1", 0.5, core.MBD),
+	))
+	<-workersToTam
+
+	//Opinions from MBD do not age, as declared by the template
+	tracked := worker.aging[fullTmiID]
+	if len(tracked) != 1 || tracked[0].TrustSource != core.AIV {
+		t.Fatalf("expected only the AIV opinion to be tracked, got %+v", tracked)
+	}
+	received := tracked[0].Received
+
+	//No synthetic updates within the grace period
+	worker.ageOpinions(received.Add(30 * time.Second))
+	if len(workersToTam) != 0 {
+		t.Error("expected no results within the grace period")
+	}
+
+	worker.ageOpinions(received.Add(2 * time.Minute))
+	assertAgedOpinion(t, tmi.opinions[core.AIV], 0.4, 0.05)
+	assertAgedOpinion(t, tmi.opinions[core.MBD], 0.5, 0)
+	if _, isATLUpdate := (<-workersToTam).(command.HandleATLUpdate); !isATLUpdate {
+		t.Error("expected results after aging")
+	}
+
+	//No results if the ATLs have not changed since the last aging
+	worker.ageOpinions(received.Add(2 * time.Minute))
+	if len(workersToTam) != 0 {
+		t.Error("expected no results for unchanged ATLs")
+	}
+
+	//Fresh evidence resets the age of the opinion
+	worker.handle(command.CreateHandleTMIUpdate(fullTmiID, nil, trustmodelupdate.CreateAtomicTrustOpinionUpdate(&aiv, "", "vehicle_1", core.AIV)))
+	<-workersToTam
+	if refreshed := worker.aging[fullTmiID]; len(refreshed) != 1 || !refreshed[0].Received.After(received) {
+		t.Fatalf("expected refreshed AIV opinion, got %+v", refreshed)
+	}
+	received = worker.aging[fullTmiID][0].Received
+
+	//Opinions are vacuous after the maximum age and no longer tracked
+	worker.ageOpinions(received.Add(5 * time.Minute))
+	assertAgedOpinion(t, tmi.opinions[core.AIV], 0, 0)
+	<-workersToTam
+	if _, exists := worker.aging[fullTmiID]; exists {
+		t.Error("expected vacuous opinion to be no longer tracked")
+	}
+}
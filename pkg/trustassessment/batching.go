@@ -0,0 +1,115 @@
+package trustassessment
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/command"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"time"
+)
+
+/*
+collectBatch takes up to the configured batch size of commands from the worker queue, starting with the given command.
+If a maximum latency is configured, the worker waits up to this time for further commands; otherwise, only commands that
+are already queued are taken.
+*/
+func (worker *Worker) collectBatch(first core.Command) []core.Command {
+	batch := []core.Command{first}
+	var deadline <-chan time.Time
+	if worker.batchLatency > 0 {
+		timer := time.NewTimer(worker.batchLatency)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	for len(batch) < worker.batchSize {
+		if _, stop := batch[len(batch)-1].(command.HandleWorkerStop); stop {
+			break
+		}
+		if deadline == nil {
+			select {
+			case cmd := <-worker.workerQueue:
+				batch = append(batch, cmd)
+			default:
+				return batch
+			}
+		} else {
+			select {
+			case cmd := <-worker.workerQueue:
+				batch = append(batch, cmd)
+			case <-deadline:
+				return batch
+			}
+		}
+	}
+	return batch
+}
+
+/*
+coalesceBatch merges the updates for the same TMI within a batch into a single command at the position of the first
+update. Any other command (e.g., a TMI being destroyed or migrated) ends the coalescing so that no update is moved across
+it.
+*/
+func coalesceBatch(batch []core.Command) []core.Command {
+	//full tmiID->index of the pending update command in commands
+	pending := make(map[string]int)
+	commands := make([]core.Command, 0, len(batch))
+	for _, cmd := range batch {
+		update, isUpdate := cmd.(command.HandleTMIUpdate)
+		if !isUpdate {
+			clear(pending)
+			commands = append(commands, cmd)
+			continue
+		}
+		if index, exists := pending[update.FullTmiID]; exists {
+			commands[index] = coalesceTMIUpdates(commands[index].(command.HandleTMIUpdate), update)
+			continue
+		}
+		pending[update.FullTmiID] = len(commands)
+		commands = append(commands, cmd)
+	}
+	return commands
+}
+
+/*
+coalesceTMIUpdates merges two consecutive update commands for the same TMI. The tag of the later command is used for
+the result, while the tag of the earlier command is kept as a preceding tag.
+*/
+func coalesceTMIUpdates(earlier command.HandleTMIUpdate, later command.HandleTMIUpdate) command.HandleTMIUpdate {
+	merged := later
+	merged.Updates = coalesceUpdates(append(append(make([]core.Update, 0, len(earlier.Updates)+len(later.Updates)), earlier.Updates...), later.Updates...))
+	merged.ForceEvaluation = earlier.ForceEvaluation || later.ForceEvaluation
+	merged.PrecedingTags = append(make([]string, 0, len(earlier.PrecedingTags)+len(later.PrecedingTags)+1), earlier.PrecedingTags...)
+	if later.Tag == nil {
+		merged.Tag = earlier.Tag
+	} else if earlier.Tag != nil {
+		merged.PrecedingTags = append(merged.PrecedingTags, *earlier.Tag)
+	}
+	merged.PrecedingTags = append(merged.PrecedingTags, later.PrecedingTags...)
+	return merged
+}
+
+type atoKey struct {
+	trustor     string
+	trustee     string
+	trustSource core.TrustSource
+}
+
+/*
+coalesceUpdates drops all atomic trust opinion updates that are superseded by a later update of the same trust
+relationship and trust source. All other updates (e.g., changes of the topology) are kept in order.
+*/
+func coalesceUpdates(updates []core.Update) []core.Update {
+	latest := make(map[atoKey]int)
+	for i, update := range updates {
+		if ato, isATO := update.(trustmodelupdate.UpdateAtomicTrustOpinion); isATO {
+			latest[atoKey{ato.Trustor(), ato.Trustee(), ato.TrustSource()}] = i
+		}
+	}
+	coalesced := make([]core.Update, 0, len(updates))
+	for i, update := range updates {
+		if ato, isATO := update.(trustmodelupdate.UpdateAtomicTrustOpinion); isATO && latest[atoKey{ato.Trustor(), ato.Trustee(), ato.TrustSource()}] != i {
+			continue
+		}
+		coalesced = append(coalesced, update)
+	}
+	return coalesced
+}
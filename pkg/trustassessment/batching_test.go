@@ -0,0 +1,66 @@
+package trustassessment
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/command"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func createATOUpdate(trustee string, belief float64, source core.TrustSource) core.Update {
+	opinion, _ := subjectivelogic.NewOpinion(belief, 0, 1-belief, 0.5)
+	return trustmodelupdate.CreateAtomicTrustOpinionUpdate(&opinion, "taf", trustee, source)
+}
+
+func TestCoalesceUpdates(t *testing.T) {
+	first := createATOUpdate("vehicle_1", 0.1, core.MBD)
+	other := createATOUpdate("vehicle_1", 0.2, core.AIV)
+	topology := trustmodelupdate.CreateRefreshCPM("vehicle_1", []string{"vehicle_2"})
+	second := createATOUpdate("vehicle_2", 0.3, core.MBD)
+	last := createATOUpdate("vehicle_1", 0.4, core.MBD)
+
+	coalesced := coalesceUpdates([]core.Update{first, other, topology, second, last})
+	expected := []core.Update{other, topology, second, last}
+	if len(coalesced) != len(expected) {
+		t.Fatalf("expected %d updates, got %d", len(expected), len(coalesced))
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(coalesced[i], expected[i]) {
+			t.Errorf("unexpected update at position %d: %+v", i, coalesced[i])
+		}
+	}
+}
+
+func TestCoalesceBatch(t *testing.T) {
+	tmiA := "//client/SES-1/T@0.0.1/A"
+	tmiB := "//client/SES-1/T@0.0.1/B"
+	tag := func(value string) *string {
+		return &value
+	}
+	batch := []core.Command{
+		command.CreateHandleTMIUpdate(tmiA, tag("1"), createATOUpdate("A", 0.1, core.MBD)),
+		command.CreateHandleTMIUpdate(tmiB, nil, createATOUpdate("B", 0.1, core.MBD)),
+		command.CreateHandleTMIUpdate(tmiA, nil, createATOUpdate("A", 0.2, core.MBD)),
+		command.CreateHandleTMIUpdate(tmiA, tag("3"), createATOUpdate("A", 0.3, core.MBD)),
+		command.CreateHandleTMIDestroy(tmiB),
+		command.CreateHandleTMIUpdate(tmiA, tag("4"), createATOUpdate("A", 0.4, core.MBD)),
+	}
+	commands := coalesceBatch(batch)
+	if len(commands) != 4 {
+		t.Fatalf("expected 4 commands, got %d", len(commands))
+	}
+	merged := commands[0].(command.HandleTMIUpdate)
+	if merged.FullTmiID != tmiA || len(merged.Updates) != 1 || *merged.Tag != "3" || !slices.Equal(merged.PrecedingTags, []string{"1"}) {
+		t.Errorf("unexpected coalesced update: %+v", merged)
+	}
+	if _, isDestroy := commands[2].(command.HandleTMIDestroy); !isDestroy {
+		t.Errorf("expected destroy command to keep its position, got %v", commands[2].Type())
+	}
+	//Updates must not be coalesced across other commands
+	if after := commands[3].(command.HandleTMIUpdate); *after.Tag != "4" || len(after.PrecedingTags) != 0 {
+		t.Errorf("unexpected update after destroy command: %+v", after)
+	}
+}
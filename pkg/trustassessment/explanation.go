@@ -0,0 +1,69 @@
+package trustassessment
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustdecision"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"time"
+)
+
+/*
+recordProvenance keeps track of the origin of atomic trust opinion updates applied to a TMI, so that trust decisions can
+be explained later on. Only the latest update per trust relationship and trust source is kept.
+*/
+func (worker *Worker) recordProvenance(fullTmiID string, update core.Update, tag *string) {
+	ato, isATO := update.(trustmodelupdate.UpdateAtomicTrustOpinion)
+	if !isATO {
+		return
+	}
+	provenance := core.TrustProvenance{
+		Trustor:     ato.Trustor(),
+		Trustee:     ato.Trustee(),
+		TrustSource: ato.TrustSource(),
+		Evidence:    ato.Evidence(),
+		Tag:         tag,
+		Timestamp:   time.Now(),
+	}
+	recorded := worker.provenance[fullTmiID]
+	for i, existing := range recorded {
+		if existing.Trustor == provenance.Trustor && existing.Trustee == provenance.Trustee && existing.TrustSource == provenance.TrustSource {
+			recorded[i] = provenance
+			return
+		}
+	}
+	worker.provenance[fullTmiID] = append(recorded, provenance)
+}
+
+/*
+explainDecision creates the explanation of the trust decision for a proposition. The contributing trust relationships
+are the ones in the scope of the proposition; each is linked to the recorded provenance of updates for the same
+trustee and trustor (updates without trustor, e.g. from the AIV, are linked by trustee only).
+*/
+func explainDecision(tmi core.TrustModelInstance, proposition string, atl subjectivelogic.QueryableOpinion, rtl subjectivelogic.QueryableOpinion, decision core.TrustDecision, policy core.DecisionPolicy, provenance []core.TrustProvenance) core.DecisionExplanation {
+	explanation := core.DecisionExplanation{
+		ATL:                     atl,
+		RTL:                     rtl,
+		ATLProjectedProbability: trustdecision.ProjectProbability(atl),
+		RTLProjectedProbability: trustdecision.ProjectProbability(rtl),
+		Decision:                decision,
+		Policy:                  policy.Name(),
+		Rule:                    trustdecision.Rule(policy),
+		Contributions:           make([]core.TrustContribution, 0),
+	}
+	for _, relationship := range tmi.Values()[proposition] {
+		contribution := core.TrustContribution{
+			Trustor:    relationship.Source(),
+			Trustee:    relationship.Destination(),
+			Opinion:    relationship.Opinion(),
+			Provenance: make([]core.TrustProvenance, 0),
+		}
+		for _, entry := range provenance {
+			if entry.Trustee == contribution.Trustee && (entry.Trustor == "" || entry.Trustor == contribution.Trustor) {
+				contribution.Provenance = append(contribution.Provenance, entry)
+			}
+		}
+		explanation.Contributions = append(explanation.Contributions, contribution)
+	}
+	return explanation
+}
@@ -0,0 +1,83 @@
+package trustassessment
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"testing"
+)
+
+func TestRecordProvenance(t *testing.T) {
+	tmi := "//client/SES-1/T@0.0.1/A"
+	worker := &Worker{provenance: make(map[string][]core.TrustProvenance)}
+	tag := func(value string) *string {
+		return &value
+	}
+
+	evidence := map[core.EvidenceType]interface{}{core.AIV_SECURE_BOOT: true}
+	worker.recordProvenance(tmi, createATOUpdate("vehicle_1", 0.1, core.MBD), tag("1"))
+	worker.recordProvenance(tmi, createATOUpdate("vehicle_1", 0.2, core.AIV).(trustmodelupdate.UpdateAtomicTrustOpinion).WithEvidence(evidence), tag("2"))
+	worker.recordProvenance(tmi, trustmodelupdate.CreateRefreshCPM("vehicle_1", []string{"vehicle_2"}), tag("3"))
+	worker.recordProvenance(tmi, createATOUpdate("vehicle_1", 0.4, core.MBD), tag("4"))
+
+	recorded := worker.provenance[tmi]
+	if len(recorded) != 2 {
+		t.Fatalf("expected provenance of 2 trust sources, got %d", len(recorded))
+	}
+	if recorded[0].TrustSource != core.MBD || *recorded[0].Tag != "4" {
+		t.Errorf("expected latest MBD update with tag 4, got %s with tag %s", recorded[0].TrustSource.String(), *recorded[0].Tag)
+	}
+	if recorded[1].TrustSource != core.AIV || recorded[1].Evidence[core.AIV_SECURE_BOOT] != true {
+		t.Errorf("expected AIV update with evidence, got %+v", recorded[1])
+	}
+}
+
+func TestExplanationMsgStruct(t *testing.T) {
+	atl, _ := subjectivelogic.NewOpinion(0.6, 0.2, 0.2, 0.5)
+	rtl, _ := subjectivelogic.NewOpinion(0.5, 0.1, 0.4, 0.5)
+	tag := "tag-1"
+	explanation := core.DecisionExplanation{
+		ATL:                     &atl,
+		RTL:                     &rtl,
+		ATLProjectedProbability: 0.7,
+		RTLProjectedProbability: 0.7,
+		Decision:                core.TRUSTWORTHY,
+		Policy:                  "projected-probability",
+		Rule:                    "PP(ATL) >= PP(RTL)",
+		Contributions: []core.TrustContribution{{
+			Trustor: "taf",
+			Trustee: "vehicle_1",
+			Opinion: &atl,
+			Provenance: []core.TrustProvenance{{
+				Trustor:     "taf",
+				Trustee:     "vehicle_1",
+				TrustSource: core.AIV,
+				Evidence:    map[core.EvidenceType]interface{}{core.AIV_SECURE_BOOT: true},
+				Tag:         &tag,
+			}},
+		}},
+	}
+
+	msg := toExplanationMsgStruct(explanation)
+	if msg.ATL.Belief != 0.6 || msg.RTL.Uncertainty != 0.4 || msg.Rule != explanation.Rule {
+		t.Errorf("unexpected explanation: %+v", msg)
+	}
+	if len(msg.Contributions) != 1 || len(msg.Contributions[0].Provenance) != 1 {
+		t.Fatalf("expected one contribution with one provenance entry, got %+v", msg.Contributions)
+	}
+	provenance := msg.Contributions[0].Provenance[0]
+	if provenance.TrustSource != core.AIV.String() || provenance.Evidence[core.AIV_SECURE_BOOT.String()] != true || *provenance.Tag != tag {
+		t.Errorf("unexpected provenance: %+v", provenance)
+	}
+	if provenance.Timestamp != nil {
+		t.Errorf("expected no timestamp for zero time, got %s", *provenance.Timestamp)
+	}
+
+	entry := ResultEntry{TmiID: "A", Propositions: []Proposition{{PropositionID: "p", ATL: &atl, Explanation: &explanation}}}
+	if entry.toTarResultMsgStruct(false).Propositions[0].Explanation != nil {
+		t.Errorf("expected no explanation unless requested")
+	}
+	if entry.toTarResultMsgStruct(true).Propositions[0].Explanation == nil {
+		t.Errorf("expected explanation when requested")
+	}
+}
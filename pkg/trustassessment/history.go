@@ -0,0 +1,474 @@
+package trustassessment
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"hash/fnv"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const atlHistorySpillFileSuffix = ".atlhistory.jsonl"
+
+// suffix of the rotated spill file of a TMI, which holds the result sets preceding those of the current spill file
+const atlHistoryRotatedSpillFileSuffix = ".rotated" + atlHistorySpillFileSuffix
+
+/*
+The AtlHistory keeps a bounded history of ATL result sets per TMI. For each TMI, the latest result sets are kept in a ring
+buffer in memory. If a spill directory is configured, result sets that are evicted from a ring buffer are appended to a
+file per TMI, which is kept open while the TMI exists, so that older results remain available for historic queries. Once
+a spill file holds the maximum number of entries, it replaces the rotated spill file of the TMI and a new spill file is
+started; hence, between spillMaxEntries and twice as many spilled result sets are kept per TMI. Like the rest of the TAM
+state, the history is not safe for concurrent use and must only be accessed from the TAM goroutine.
+*/
+type AtlHistory struct {
+	size            int
+	spillDirectory  string
+	spillMaxEntries int
+	logger          *slog.Logger
+	//full TMI ID -> ring buffer of latest results
+	buffers map[string]*atlRingBuffer
+	//full TMI ID -> open spill file
+	spillFiles map[string]*atlSpillFile
+	//index of all TMIs with historic results, including removed TMIs whose results have been spilled to disk
+	tmis *TrustModelInstanceTable
+}
+
+/*
+atlSpillFile is the open spill file of a TMI and the number of result sets it holds.
+*/
+type atlSpillFile struct {
+	file    *os.File
+	encoder *json.Encoder
+	entries int
+}
+
+/*
+CreateAtlHistory creates a new history keeping size result sets per TMI in memory. A size of 0 disables the history.
+If spillDirectory is not empty, the directory is created if necessary and scanned for results of prior runs. If
+spillMaxEntries is greater than 0, spill files are rotated once they hold that many result sets.
+*/
+func CreateAtlHistory(size int, spillDirectory string, spillMaxEntries int, logger *slog.Logger) *AtlHistory {
+	history := &AtlHistory{
+		size:            size,
+		spillDirectory:  spillDirectory,
+		spillMaxEntries: spillMaxEntries,
+		logger:          logger,
+		buffers:         make(map[string]*atlRingBuffer),
+		spillFiles:      make(map[string]*atlSpillFile),
+		tmis:            CreateTrustModelInstanceTable(),
+	}
+	if history.Enabled() && spillDirectory != "" {
+		if err := os.MkdirAll(spillDirectory, 0755); err != nil {
+			logger.Error("Could not create ATL history spill directory; spilling disabled", "Directory", spillDirectory, "Error", err.Error())
+			history.spillDirectory = ""
+		} else {
+			history.indexSpillFiles()
+		}
+	}
+	return history
+}
+
+/*
+Enabled returns whether the history records any results at all.
+*/
+func (h *AtlHistory) Enabled() bool {
+	return h.size > 0
+}
+
+/*
+Record adds a new result set to the history of a TMI.
+*/
+func (h *AtlHistory) Record(fullTmiID string, resultSet core.AtlResultSet) {
+	if !h.Enabled() {
+		return
+	}
+	buffer, exists := h.buffers[fullTmiID]
+	if !exists {
+		client, session, tmt, tmi, err := parseFullTMIIdentifier(fullTmiID)
+		if err != nil {
+			h.logger.Warn("Not recording ATL history for malformed TMI identifier", "TMI", fullTmiID, "Error", err.Error())
+			return
+		}
+		buffer = newAtlRingBuffer(h.size)
+		h.buffers[fullTmiID] = buffer
+		h.tmis.RegisterTMI(client, session, tmt, tmi)
+	}
+	if evicted, wasEvicted := buffer.push(resultSet); wasEvicted {
+		h.spill(fullTmiID, []core.AtlResultSet{evicted})
+	}
+}
+
+/*
+Release is called once a TMI has been removed. Its in-memory results are spilled to disk (if configured) and dropped
+from memory. Without a spill directory, the history of the TMI is discarded.
+*/
+func (h *AtlHistory) Release(fullTmiID string) {
+	buffer, exists := h.buffers[fullTmiID]
+	if !exists {
+		return
+	}
+	delete(h.buffers, fullTmiID)
+	if h.spillDirectory != "" {
+		h.spill(fullTmiID, buffer.entries())
+		h.closeSpillFile(fullTmiID)
+	} else if client, session, tmt, tmi, err := parseFullTMIIdentifier(fullTmiID); err == nil {
+		h.tmis.UnregisterTMI(client, session, tmt, tmi)
+	}
+}
+
+/*
+Close closes all open spill files.
+*/
+func (h *AtlHistory) Close() {
+	for fullTmiID := range h.spillFiles {
+		h.closeSpillFile(fullTmiID)
+	}
+}
+
+/*
+QueryTMIs returns all TMIs matching the query (see TrustModelInstanceTable.QueryTMIs) for which historic results exist.
+*/
+func (h *AtlHistory) QueryTMIs(query string) ([]string, error) {
+	return h.tmis.QueryTMIs(query)
+}
+
+/*
+At returns the result set of a TMI that was valid at the given point in time, i.e., the latest result set computed
+at or before that time. Spilled results are only read if no result set kept in memory qualifies.
+*/
+func (h *AtlHistory) At(fullTmiID string, timestamp time.Time) (core.AtlResultSet, bool) {
+	if buffer, exists := h.buffers[fullTmiID]; exists {
+		inMemory := buffer.entries()
+		for i := len(inMemory) - 1; i >= 0; i-- {
+			if !inMemory[i].Timestamp().After(timestamp) {
+				return inMemory[i], true
+			}
+		}
+	}
+	if h.spillDirectory == "" {
+		return core.AtlResultSet{}, false
+	}
+	candidates := h.readSpilled(fullTmiID, time.Time{}, timestamp)
+	if len(candidates) == 0 {
+		return core.AtlResultSet{}, false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Timestamp().Before(candidates[j].Timestamp())
+	})
+	return candidates[len(candidates)-1], true
+}
+
+/*
+Range returns all result sets of a TMI computed within the closed interval [from, to], in chronological order.
+*/
+func (h *AtlHistory) Range(fullTmiID string, from time.Time, to time.Time) []core.AtlResultSet {
+	results := make([]core.AtlResultSet, 0)
+	inMemory := make([]core.AtlResultSet, 0)
+	if buffer, exists := h.buffers[fullTmiID]; exists {
+		inMemory = buffer.entries()
+	}
+	//only consult spilled results if the requested interval starts before the oldest result kept in memory
+	if h.spillDirectory != "" && (len(inMemory) == 0 || from.Before(inMemory[0].Timestamp())) {
+		results = append(results, h.readSpilled(fullTmiID, from, to)...)
+	}
+	for _, resultSet := range inMemory {
+		if !resultSet.Timestamp().Before(from) && !resultSet.Timestamp().After(to) {
+			results = append(results, resultSet)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Timestamp().Before(results[j].Timestamp())
+	})
+	return results
+}
+
+/*
+spillFilePath returns the path of the spill file for a TMI, or of its rotated spill file.
+*/
+func (h *AtlHistory) spillFilePath(fullTmiID string, rotated bool) string {
+	algorithm := fnv.New64a()
+	_, _ = algorithm.Write([]byte(fullTmiID))
+	suffix := atlHistorySpillFileSuffix
+	if rotated {
+		suffix = atlHistoryRotatedSpillFileSuffix
+	}
+	return filepath.Join(h.spillDirectory, fmt.Sprintf("%016x%s", algorithm.Sum64(), suffix))
+}
+
+func (h *AtlHistory) spill(fullTmiID string, resultSets []core.AtlResultSet) {
+	if h.spillDirectory == "" || len(resultSets) == 0 {
+		return
+	}
+	spillFile, err := h.openSpillFile(fullTmiID)
+	if err != nil {
+		h.logger.Error("Could not open ATL history spill file", "TMI", fullTmiID, "Error", err.Error())
+		return
+	}
+	for _, resultSet := range resultSets {
+		if h.spillMaxEntries > 0 && spillFile.entries >= h.spillMaxEntries {
+			if spillFile, err = h.rotateSpillFile(fullTmiID); err != nil {
+				h.logger.Error("Could not rotate ATL history spill file", "TMI", fullTmiID, "Error", err.Error())
+				return
+			}
+		}
+		if err := spillFile.encoder.Encode(newSpilledAtlResultSet(fullTmiID, resultSet)); err != nil {
+			h.logger.Error("Could not write to ATL history spill file", "TMI", fullTmiID, "Error", err.Error())
+			return
+		}
+		spillFile.entries++
+	}
+}
+
+/*
+openSpillFile returns the open spill file of a TMI. Spill files of prior runs are appended to.
+*/
+func (h *AtlHistory) openSpillFile(fullTmiID string) (*atlSpillFile, error) {
+	if spillFile, exists := h.spillFiles[fullTmiID]; exists {
+		return spillFile, nil
+	}
+	path := h.spillFilePath(fullTmiID, false)
+	entries := 0
+	if h.spillMaxEntries > 0 {
+		entries = countLines(path)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	spillFile := &atlSpillFile{file: file, encoder: json.NewEncoder(file), entries: entries}
+	h.spillFiles[fullTmiID] = spillFile
+	return spillFile, nil
+}
+
+/*
+rotateSpillFile replaces the rotated spill file of a TMI by its current spill file, dropping the oldest spilled result
+sets, and starts a new spill file.
+*/
+func (h *AtlHistory) rotateSpillFile(fullTmiID string) (*atlSpillFile, error) {
+	h.closeSpillFile(fullTmiID)
+	if err := os.Rename(h.spillFilePath(fullTmiID, false), h.spillFilePath(fullTmiID, true)); err != nil {
+		return nil, err
+	}
+	return h.openSpillFile(fullTmiID)
+}
+
+func (h *AtlHistory) closeSpillFile(fullTmiID string) {
+	if spillFile, exists := h.spillFiles[fullTmiID]; exists {
+		if err := spillFile.file.Close(); err != nil {
+			h.logger.Warn("Could not close ATL history spill file", "TMI", fullTmiID, "Error", err.Error())
+		}
+		delete(h.spillFiles, fullTmiID)
+	}
+}
+
+/*
+readSpilled returns the spilled result sets of a TMI computed within the closed interval [from, to], starting with those
+of the rotated spill file.
+*/
+func (h *AtlHistory) readSpilled(fullTmiID string, from time.Time, to time.Time) []core.AtlResultSet {
+	results := make([]core.AtlResultSet, 0)
+	for _, rotated := range []bool{true, false} {
+		file, err := os.Open(h.spillFilePath(fullTmiID, rotated))
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			var record spilledAtlResultSet
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				h.logger.Warn("Skipping malformed ATL history record", "TMI", fullTmiID, "Error", err.Error())
+				continue
+			}
+			//spill files are named by a hash, so make sure to skip records of colliding TMIs
+			if record.FullTmiID == fullTmiID && !record.Timestamp.Before(from) && !record.Timestamp.After(to) {
+				results = append(results, record.toAtlResultSet())
+			}
+		}
+		file.Close()
+	}
+	return results
+}
+
+/*
+countLines returns the number of lines of a file, or 0 if it cannot be read.
+*/
+func countLines(path string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lines := 0
+	for scanner.Scan() {
+		lines++
+	}
+	return lines
+}
+
+/*
+indexSpillFiles registers all TMIs found in the spill directory, so that results of removed TMIs and prior runs remain queryable.
+*/
+func (h *AtlHistory) indexSpillFiles() {
+	files, err := os.ReadDir(h.spillDirectory)
+	if err != nil {
+		h.logger.Warn("Could not read ATL history spill directory", "Directory", h.spillDirectory, "Error", err.Error())
+		return
+	}
+	for _, entry := range files {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), atlHistorySpillFileSuffix) {
+			continue
+		}
+		file, err := os.Open(filepath.Join(h.spillDirectory, entry.Name()))
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		seen := make(map[string]struct{})
+		for scanner.Scan() {
+			var record struct {
+				FullTmiID string
+			}
+			if json.Unmarshal(scanner.Bytes(), &record) != nil || record.FullTmiID == "" {
+				continue
+			}
+			if _, exists := seen[record.FullTmiID]; exists {
+				continue
+			}
+			seen[record.FullTmiID] = struct{}{}
+			if client, session, tmt, tmi, err := parseFullTMIIdentifier(record.FullTmiID); err == nil {
+				h.tmis.RegisterTMI(client, session, tmt, tmi)
+			}
+		}
+		file.Close()
+	}
+}
+
+/*
+atlRingBuffer is a fixed-size ring buffer of result sets.
+*/
+type atlRingBuffer struct {
+	items []core.AtlResultSet
+	start int
+	count int
+}
+
+func newAtlRingBuffer(size int) *atlRingBuffer {
+	return &atlRingBuffer{
+		items: make([]core.AtlResultSet, size),
+	}
+}
+
+/*
+push adds a new result set. If the buffer is full, the oldest result set is evicted and returned.
+*/
+func (b *atlRingBuffer) push(resultSet core.AtlResultSet) (core.AtlResultSet, bool) {
+	if b.count < len(b.items) {
+		b.items[(b.start+b.count)%len(b.items)] = resultSet
+		b.count++
+		return core.AtlResultSet{}, false
+	}
+	evicted := b.items[b.start]
+	b.items[b.start] = resultSet
+	b.start = (b.start + 1) % len(b.items)
+	return evicted, true
+}
+
+/*
+entries returns all result sets in the order they have been added.
+*/
+func (b *atlRingBuffer) entries() []core.AtlResultSet {
+	entries := make([]core.AtlResultSet, b.count)
+	for i := 0; i < b.count; i++ {
+		entries[i] = b.items[(b.start+i)%len(b.items)]
+	}
+	return entries
+}
+
+/*
+spilledAtlResultSet is the on-disk representation of a result set.
+*/
+type spilledAtlResultSet struct {
+	FullTmiID    string
+	TmiID        string
+	Version      int
+	Tag          *string
+	Timestamp    time.Time
+	Propositions map[string]spilledProposition
+}
+
+type spilledProposition struct {
+	Belief         float64
+	Disbelief      float64
+	Uncertainty    float64
+	BaseRate       float64
+	PP             float64
+	TrustDecision  core.TrustDecision
+	DecisionPolicy string
+	//full distribution of multinomial ATLs, nil for binomial ATLs
+	Multinomial *multinomial.Opinion `json:",omitempty"`
+}
+
+func newSpilledAtlResultSet(fullTmiID string, resultSet core.AtlResultSet) spilledAtlResultSet {
+	propositions := make(map[string]spilledProposition, len(resultSet.ATLs()))
+	for propositionID, atl := range resultSet.ATLs() {
+		propositions[propositionID] = spilledProposition{
+			Belief:         atl.Belief(),
+			Disbelief:      atl.Disbelief(),
+			Uncertainty:    atl.Uncertainty(),
+			BaseRate:       atl.BaseRate(),
+			PP:             resultSet.ProjectedProbabilities()[propositionID],
+			TrustDecision:  resultSet.TrustDecisions()[propositionID],
+			DecisionPolicy: resultSet.DecisionPolicies()[propositionID],
+		}
+		if opinion, ok := atl.(*multinomial.Opinion); ok {
+			proposition := propositions[propositionID]
+			proposition.Multinomial = opinion
+			propositions[propositionID] = proposition
+		}
+	}
+	return spilledAtlResultSet{
+		FullTmiID:    fullTmiID,
+		TmiID:        resultSet.TmiID(),
+		Version:      resultSet.Version(),
+		Tag:          resultSet.Tag(),
+		Timestamp:    resultSet.Timestamp(),
+		Propositions: propositions,
+	}
+}
+
+func (s spilledAtlResultSet) toAtlResultSet() core.AtlResultSet {
+	atls := make(map[string]subjectivelogic.QueryableOpinion, len(s.Propositions))
+	pps := make(map[string]float64, len(s.Propositions))
+	tds := make(map[string]core.TrustDecision, len(s.Propositions))
+	policies := make(map[string]string, len(s.Propositions))
+	for propositionID, proposition := range s.Propositions {
+		if proposition.Multinomial != nil {
+			atls[propositionID] = proposition.Multinomial
+		} else {
+			opinion, err := subjectivelogic.NewOpinion(proposition.Belief, proposition.Disbelief, proposition.Uncertainty, proposition.BaseRate)
+			if err != nil {
+				continue
+			}
+			atls[propositionID] = &opinion
+		}
+		pps[propositionID] = proposition.PP
+		tds[propositionID] = proposition.TrustDecision
+		if proposition.DecisionPolicy != "" {
+			policies[propositionID] = proposition.DecisionPolicy
+		}
+	}
+	return core.CreateAtlResultSet(s.TmiID, s.Version, s.Tag, s.Timestamp, atls, pps, tds).WithDecisionPolicies(policies)
+}
@@ -0,0 +1,111 @@
+package trustassessment
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var historyBaseTime = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func createHistoricResultSet(version int, belief float64) core.AtlResultSet {
+	opinion, _ := subjectivelogic.NewOpinion(belief, 0, 1-belief, 0.5)
+	tag := "tag-" + string(rune('a'+version))
+	return core.CreateAtlResultSet("42", version, &tag, historyBaseTime.Add(time.Duration(version)*time.Second),
+		map[string]subjectivelogic.QueryableOpinion{"P": &opinion},
+		map[string]float64{"P": belief + (1-belief)*0.5},
+		map[string]core.TrustDecision{"P": core.UNDECIDABLE},
+	)
+}
+
+func TestHistoryRingBuffer(t *testing.T) {
+	history := CreateAtlHistory(3, "", 0, slog.Default())
+	id := "//client/SES-1/IMA@0.0.1/42"
+	for version := 0; version < 5; version++ {
+		history.Record(id, createHistoricResultSet(version, 0.1*float64(version)))
+	}
+
+	all := history.Range(id, time.Time{}, historyBaseTime.Add(time.Hour))
+	if len(all) != 3 || all[0].Version() != 2 || all[2].Version() != 4 {
+		t.Fatalf("expected versions 2..4 to be kept in memory, got %d entries", len(all))
+	}
+
+	atl, exists := history.At(id, historyBaseTime.Add(3500*time.Millisecond))
+	if !exists || atl.Version() != 3 {
+		t.Errorf("expected version 3 at t+3.5s, got %v (exists: %v)", atl.Version(), exists)
+	}
+	if _, exists := history.At(id, historyBaseTime.Add(time.Second)); exists {
+		t.Error("evicted results must not be returned without spilling")
+	}
+
+	history.Release(id)
+	if matches, _ := history.QueryTMIs("//*/*/*/42"); len(matches) != 0 {
+		t.Error("released TMI without spill directory must not remain queryable")
+	}
+}
+
+func TestHistorySpill(t *testing.T) {
+	directory := t.TempDir()
+	history := CreateAtlHistory(2, directory, 0, slog.Default())
+	id := "//client/SES-1/IMA@0.0.1/42"
+	for version := 0; version < 5; version++ {
+		history.Record(id, createHistoricResultSet(version, 0.1*float64(version)))
+	}
+
+	all := history.Range(id, time.Time{}, historyBaseTime.Add(time.Hour))
+	if len(all) != 5 {
+		t.Fatalf("expected all 5 results from memory and spill file, got %d", len(all))
+	}
+	for i, resultSet := range all {
+		if resultSet.Version() != i {
+			t.Errorf("results not in chronological order: position %d has version %d", i, resultSet.Version())
+		}
+	}
+
+	spilled, exists := history.At(id, historyBaseTime.Add(1500*time.Millisecond))
+	if !exists || spilled.Version() != 1 || *spilled.Tag() != "tag-b" {
+		t.Fatal("expected spilled version 1 including its tag")
+	}
+	if diff := spilled.ATLs()["P"].Belief() - 0.1; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("unexpected belief of restored opinion: %f", spilled.ATLs()["P"].Belief())
+	}
+
+	//removed TMIs remain queryable, also for a new history instance reading the same directory
+	history.Release(id)
+	restarted := CreateAtlHistory(2, directory, 0, slog.Default())
+	if matches, _ := restarted.QueryTMIs("//*/*/IMA@0.0.1/42"); len(matches) != 1 || matches[0] != id {
+		t.Fatalf("expected spilled TMI to be indexed, got %v", matches)
+	}
+	if len(restarted.Range(id, historyBaseTime, historyBaseTime.Add(2*time.Second))) != 3 {
+		t.Error("expected three results within the closed range [t, t+2s]")
+	}
+}
+
+func TestHistorySpillRetention(t *testing.T) {
+	directory := t.TempDir()
+	history := CreateAtlHistory(1, directory, 2, slog.Default())
+	id := "//client/SES-1/IMA@0.0.1/42"
+	for version := 0; version < 8; version++ {
+		history.Record(id, createHistoricResultSet(version, 0.1*float64(version)))
+	}
+
+	//versions 0..6 have been spilled, but only the latest two spill files with two entries each are kept
+	all := history.Range(id, time.Time{}, historyBaseTime.Add(time.Hour))
+	if len(all) != 4 || all[0].Version() != 4 || all[3].Version() != 7 {
+		t.Fatalf("expected versions 4..7 to be retained, got %d entries", len(all))
+	}
+
+	//the latest result is served from memory, even if the spill files are gone
+	history.Close()
+	files, _ := os.ReadDir(directory)
+	for _, file := range files {
+		_ = os.Remove(filepath.Join(directory, file.Name()))
+	}
+	if atl, exists := history.At(id, historyBaseTime.Add(time.Hour)); !exists || atl.Version() != 7 {
+		t.Error("expected the latest result to be served from memory")
+	}
+}
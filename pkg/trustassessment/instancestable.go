@@ -1,100 +1,346 @@
 package trustassessment
 
-import "github.com/horizon-connect-eu/go-taf/pkg/core"
+import (
+	"errors"
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"sort"
+	"strings"
+)
+
+/*
+ErrInvalidTMIQuery is returned (wrapped) whenever a TMI query expression or full TMI identifier is malformed.
+*/
+var ErrInvalidTMIQuery = errors.New("invalid TMI query")
+
+const tmiQueryWildcard = "*"
 
 /*
 The TrustModelInstanceTable is an internal data structure of the TAM to organize currently existing TMIs and query them.
+TMIs are indexed in a trie with one level per identifier segment (client -> session -> TMT -> TMI), so that queries only
+descend into matching branches instead of scanning all known TMIs.
 */
 type TrustModelInstanceTable struct {
-	//map full TMI ID -> [client, sessionID, tmtID, tmiID]
-	tmis map[string][]string //TODO: replace with trie for more efficient lookup/storage later
+	root *tmiTableNode
+	size int
+}
+
+/*
+A tmiTableNode is a node of the trie. Children are kept in a map for exact lookups and in a sorted slice of keys for
+deterministic ordering and prefix range scans.
+*/
+type tmiTableNode struct {
+	children map[string]*tmiTableNode
+	keys     []string
+	//full TMI ID, only set on leaf nodes
+	fullID string
+}
+
+func newTmiTableNode() *tmiTableNode {
+	return &tmiTableNode{
+		children: make(map[string]*tmiTableNode),
+		keys:     make([]string, 0),
+	}
+}
+
+func (n *tmiTableNode) child(key string) (*tmiTableNode, bool) {
+	child, exists := n.children[key]
+	return child, exists
+}
+
+func (n *tmiTableNode) addChild(key string) *tmiTableNode {
+	child := newTmiTableNode()
+	n.children[key] = child
+	idx := sort.SearchStrings(n.keys, key)
+	n.keys = append(n.keys, "")
+	copy(n.keys[idx+1:], n.keys[idx:])
+	n.keys[idx] = key
+	return child
+}
+
+func (n *tmiTableNode) removeChild(key string) {
+	delete(n.children, key)
+	idx := sort.SearchStrings(n.keys, key)
+	if idx < len(n.keys) && n.keys[idx] == key {
+		n.keys = append(n.keys[:idx], n.keys[idx+1:]...)
+	}
 }
 
 func CreateTrustModelInstanceTable() *TrustModelInstanceTable {
 	return &TrustModelInstanceTable{
-		tmis: make(map[string][]string),
+		root: newTmiTableNode(),
+		size: 0,
 	}
 }
 
 /*
-RegisterTMI adds a new TMI.
+RegisterTMI adds a new TMI. It returns false if the TMI already exists or one of the identifier parts contains a '/'.
 */
 func (t *TrustModelInstanceTable) RegisterTMI(client string, sessionID string, tmtID string, tmiID string) bool {
-	id := core.MergeFullTMIIdentifier(client, sessionID, tmtID, tmiID)
-	if _, exists := t.tmis[id]; exists {
+	parts := []string{client, sessionID, tmtID, tmiID}
+	for _, part := range parts {
+		if validateIdentifierSegment(part) != nil {
+			return false
+		}
+	}
+	node := t.root
+	for _, part := range parts {
+		child, exists := node.child(part)
+		if !exists {
+			child = node.addChild(part)
+		}
+		node = child
+	}
+	if node.fullID != "" {
 		return false
-	} else {
-		t.tmis[id] = []string{client, sessionID, tmtID, tmiID}
-		return true
 	}
+	node.fullID = core.MergeFullTMIIdentifier(client, sessionID, tmtID, tmiID)
+	t.size++
+	return true
 }
 
 /*
-UnregisterTMI removes a TMI.
+UnregisterTMI removes a TMI. Branches of the trie that become empty are pruned.
 */
 func (t *TrustModelInstanceTable) UnregisterTMI(client string, sessionID string, tmtID string, tmiID string) bool {
-	id := core.MergeFullTMIIdentifier(client, sessionID, tmtID, tmiID)
-	exists := t.ExistsTMI(client, sessionID, tmtID, tmiID)
-	if exists == true {
-		delete(t.tmis, id)
-		return true
-	} else {
+	parts := []string{client, sessionID, tmtID, tmiID}
+	path := make([]*tmiTableNode, 0, len(parts)+1)
+	node := t.root
+	path = append(path, node)
+	for _, part := range parts {
+		child, exists := node.child(part)
+		if !exists {
+			return false
+		}
+		node = child
+		path = append(path, node)
+	}
+	if node.fullID == "" {
 		return false
 	}
+	node.fullID = ""
+	t.size--
+	//prune empty branches bottom-up
+	for i := len(parts) - 1; i >= 0; i-- {
+		if len(path[i+1].children) > 0 || path[i+1].fullID != "" {
+			break
+		}
+		path[i].removeChild(parts[i])
+	}
+	return true
 }
 
 /*
 ExistsTMI checks whether a TMI already exists.
 */
 func (t *TrustModelInstanceTable) ExistsTMI(client string, sessionID string, tmtID string, tmiID string) bool {
-	id := core.MergeFullTMIIdentifier(client, sessionID, tmtID, tmiID)
-	if _, exists := t.tmis[id]; exists {
-		delete(t.tmis, id)
-		return true
-	} else {
-		return false
+	node := t.root
+	for _, part := range []string{client, sessionID, tmtID, tmiID} {
+		child, exists := node.child(part)
+		if !exists {
+			return false
+		}
+		node = child
 	}
+	return node.fullID != ""
+}
+
+/*
+Size returns the number of registered TMIs.
+*/
+func (t *TrustModelInstanceTable) Size() int {
+	return t.size
 }
 
-// QueryTMIs allows to query for existing TMIs based on the full TMI ID and returns matches.
-// This functions allows wildcards in the TMI search expression, to include any match.
+// QueryTMIs allows to query for existing TMIs based on the full TMI ID and returns matches in lexicographical order.
+// Each segment of the query is either an exact value, the wildcard '*' to include any match, a prefix glob
+// (e.g., 'TMT*') or a suffix glob (e.g., '*@0.0.1'). Malformed queries are rejected with an error wrapping ErrInvalidTMIQuery.
 // Examples:
 //
 //	//*/*/TMT@0.0.1/* -> any TMI of template TMT@0.0.1
-//	//clientA/*/*/* -> any TMI of clientA in all sessisons
-//	//*/*/TMT-X@0.0.1/19 -> any TMI of template TMT@0.0.1 and ID 19
-//
-// ...
+//	//clientA/*/*/* -> any TMI of clientA in all sessions
+//	//*/*/TMT-X@0.0.1/19 -> any TMI of template TMT-X@0.0.1 and ID 19
+//	//*/*/IMA_STANDALONE@*/* -> any TMI of any version of template IMA_STANDALONE
+//	//*/SES-*/*@0.0.1/*7 -> any TMI of a template in version 0.0.1 whose ID ends with 7
 func (t *TrustModelInstanceTable) QueryTMIs(query string) ([]string, error) {
-	clientQueryPart, sessionQueryPart, tmtQueryPart, tmiQueryPart := core.SplitFullTMIIdentifier(query)
-	//TODO: check whether parts are valid,
+	results, _, err := t.QueryTMIsPaginated(query, 0, 0)
+	return results, err
+}
+
+/*
+QueryTMIsPaginated works like QueryTMIs, but only returns a page of the (ordered) result list. The page starts after
+skipping offset matches and contains at most limit entries; a limit of 0 returns all remaining matches. The bool return
+value indicates whether there are more matches after the returned page.
+*/
+func (t *TrustModelInstanceTable) QueryTMIsPaginated(query string, offset int, limit int) ([]string, bool, error) {
+	if offset < 0 || limit < 0 {
+		return nil, false, fmt.Errorf("%w: offset and limit must not be negative", ErrInvalidTMIQuery)
+	}
+	patterns, err := parseTMIQuery(query)
+	if err != nil {
+		return nil, false, err
+	}
 
 	results := make([]string, 0)
+	skipped := 0
+	more := false
 
-	//TODO: later use trie to prevent O(N) in all cases
-	for id, parts := range t.tmis {
-		if parts[0] == clientQueryPart || clientQueryPart == "*" {
-			if parts[1] == sessionQueryPart || sessionQueryPart == "*" {
-				if parts[2] == tmtQueryPart || tmtQueryPart == "*" {
-					if parts[3] == tmiQueryPart || tmiQueryPart == "*" {
-						results = append(results, id)
-					}
-				}
+	var walk func(node *tmiTableNode, level int) bool
+	//walk returns false as soon as the traversal can be stopped
+	walk = func(node *tmiTableNode, level int) bool {
+		if level == len(patterns) {
+			if node.fullID == "" {
+				return true
+			}
+			if skipped < offset {
+				skipped++
+				return true
+			}
+			if limit > 0 && len(results) == limit {
+				more = true
+				return false
+			}
+			results = append(results, node.fullID)
+			return true
+		}
+		for _, key := range patterns[level].candidates(node) {
+			if !walk(node.children[key], level+1) {
+				return false
 			}
 		}
+		return true
 	}
-	return results, nil
+	walk(t.root, 0)
+
+	return results, more, nil
 }
 
 /*
 GetAllTMIs returns a list of all existing TMIs by listing their full identifiers.
 */
 func (t *TrustModelInstanceTable) GetAllTMIs() []string {
-	i := 0
-	keys := make([]string, len(t.tmis))
-	for k := range t.tmis {
-		keys[i] = k
-		i++
+	results, _ := t.QueryTMIs("//*/*/*/*")
+	return results
+}
+
+/*
+A segmentPattern is the parsed form of a single segment of a TMI query.
+*/
+type segmentPattern struct {
+	value    string
+	wildcard bool
+	prefix   bool
+	suffix   bool
+}
+
+/*
+candidates returns the keys of the child nodes that match the pattern, in lexicographical order.
+*/
+func (p segmentPattern) candidates(node *tmiTableNode) []string {
+	switch {
+	case p.wildcard:
+		return node.keys
+	case p.prefix:
+		start := sort.SearchStrings(node.keys, p.value)
+		end := start
+		for end < len(node.keys) && strings.HasPrefix(node.keys[end], p.value) {
+			end++
+		}
+		return node.keys[start:end]
+	case p.suffix:
+		matches := make([]string, 0)
+		for _, key := range node.keys {
+			if strings.HasSuffix(key, p.value) {
+				matches = append(matches, key)
+			}
+		}
+		return matches
+	default:
+		if _, exists := node.children[p.value]; exists {
+			return []string{p.value}
+		}
+		return nil
+	}
+}
+
+/*
+matches checks whether a single identifier segment matches the pattern.
+*/
+func (p segmentPattern) matches(segment string) bool {
+	switch {
+	case p.wildcard:
+		return true
+	case p.prefix:
+		return strings.HasPrefix(segment, p.value)
+	case p.suffix:
+		return strings.HasSuffix(segment, p.value)
+	default:
+		return segment == p.value
+	}
+}
+
+/*
+parseTMIQuery validates a query expression of the form //client/session/tmt/tmi and parses its four segments.
+*/
+func parseTMIQuery(query string) ([]segmentPattern, error) {
+	if !strings.HasPrefix(query, "//") {
+		return nil, fmt.Errorf("%w: '%s' does not start with '//'", ErrInvalidTMIQuery, query)
+	}
+	segments := strings.Split(query[2:], "/")
+	if len(segments) != 4 {
+		return nil, fmt.Errorf("%w: '%s' must consist of exactly four segments (client, session, template, instance), found %d", ErrInvalidTMIQuery, query, len(segments))
+	}
+	patterns := make([]segmentPattern, len(segments))
+	for i, segment := range segments {
+		pattern, err := parseSegmentPattern(segment)
+		if err != nil {
+			return nil, fmt.Errorf("%w: segment %d of '%s': %s", ErrInvalidTMIQuery, i+1, query, err.Error())
+		}
+		patterns[i] = pattern
+	}
+	return patterns, nil
+}
+
+func parseSegmentPattern(segment string) (segmentPattern, error) {
+	if segment == tmiQueryWildcard {
+		return segmentPattern{wildcard: true}, nil
+	}
+	if segment == "" {
+		return segmentPattern{}, errors.New("segment must not be empty")
+	}
+	count := strings.Count(segment, tmiQueryWildcard)
+	switch {
+	case count == 0:
+		return segmentPattern{value: segment}, nil
+	case count == 1 && strings.HasSuffix(segment, tmiQueryWildcard):
+		return segmentPattern{value: strings.TrimSuffix(segment, tmiQueryWildcard), prefix: true}, nil
+	case count == 1 && strings.HasPrefix(segment, tmiQueryWildcard):
+		return segmentPattern{value: strings.TrimPrefix(segment, tmiQueryWildcard), suffix: true}, nil
+	default:
+		return segmentPattern{}, errors.New("'" + segment + "' uses a wildcard in an unsupported position; only '*', 'prefix*' and '*suffix' are allowed")
+	}
+}
+
+/*
+parseFullTMIIdentifier validates a full TMI identifier of the form //client/session/tmt/tmi (without wildcards) and returns its parts.
+*/
+func parseFullTMIIdentifier(identifier string) (client string, sessionID string, tmtID string, tmiID string, err error) {
+	if !strings.HasPrefix(identifier, "//") {
+		return "", "", "", "", fmt.Errorf("%w: identifier '%s' does not start with '//'", ErrInvalidTMIQuery, identifier)
+	}
+	segments := strings.Split(identifier[2:], "/")
+	if len(segments) != 4 {
+		return "", "", "", "", fmt.Errorf("%w: identifier '%s' must consist of exactly four segments", ErrInvalidTMIQuery, identifier)
+	}
+	return segments[0], segments[1], segments[2], segments[3], nil
+}
+
+/*
+validateIdentifierSegment checks whether a string can be used as a segment of a full TMI identifier.
+*/
+func validateIdentifierSegment(segment string) error {
+	if strings.Contains(segment, "/") {
+		return errors.New("identifier segment '" + segment + "' must not contain '/'")
 	}
-	return keys
+	return nil
 }
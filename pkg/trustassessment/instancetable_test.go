@@ -1,7 +1,11 @@
 package trustassessment
 
 import (
+	"errors"
+	"fmt"
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"slices"
+	"strconv"
 	"testing"
 )
 
@@ -43,3 +47,144 @@ func TestTable(t *testing.T) {
 		t.Log(hit)
 	}
 }
+
+func TestTableExistsAndUnregister(t *testing.T) {
+	table := CreateTrustModelInstanceTable()
+	table.RegisterTMI("A", "B", "CACC@1.2.3", "4711")
+
+	if !table.ExistsTMI("A", "B", "CACC@1.2.3", "4711") {
+		t.Fatal("registered TMI not found")
+	}
+	if !table.ExistsTMI("A", "B", "CACC@1.2.3", "4711") {
+		t.Fatal("ExistsTMI must not remove the TMI")
+	}
+	if table.RegisterTMI("A", "B", "CACC@1.2.3", "4711") {
+		t.Fatal("duplicate registration must fail")
+	}
+	if table.RegisterTMI("A", "B/C", "CACC@1.2.3", "4711") {
+		t.Fatal("identifier segments containing '/' must be rejected")
+	}
+	if !table.UnregisterTMI("A", "B", "CACC@1.2.3", "4711") {
+		t.Fatal("unregistering existing TMI failed")
+	}
+	if table.UnregisterTMI("A", "B", "CACC@1.2.3", "4711") {
+		t.Fatal("unregistering removed TMI must fail")
+	}
+	if table.Size() != 0 || len(table.root.children) != 0 {
+		t.Fatal("table not empty after removing all TMIs")
+	}
+}
+
+func TestTableQuery(t *testing.T) {
+	table := CreateTrustModelInstanceTable()
+	table.RegisterTMI("A", "SES-1", "IMA_STANDALONE@0.0.1", "17")
+	table.RegisterTMI("A", "SES-1", "IMA_STANDALONE@0.0.1", "27")
+	table.RegisterTMI("A", "SES-2", "IMA_STANDALONE@0.0.2", "17")
+	table.RegisterTMI("B", "SES-3", "SMTD@0.0.1", "17")
+	table.RegisterTMI("B", "OTHER", "VCM@0.0.1", "1")
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"//*/*/*/*", []string{
+			"//A/SES-1/IMA_STANDALONE@0.0.1/17",
+			"//A/SES-1/IMA_STANDALONE@0.0.1/27",
+			"//A/SES-2/IMA_STANDALONE@0.0.2/17",
+			"//B/OTHER/VCM@0.0.1/1",
+			"//B/SES-3/SMTD@0.0.1/17",
+		}},
+		{"//*/*/IMA_STANDALONE@0.0.1/17", []string{"//A/SES-1/IMA_STANDALONE@0.0.1/17"}},
+		{"//*/*/IMA_STANDALONE@*/17", []string{"//A/SES-1/IMA_STANDALONE@0.0.1/17", "//A/SES-2/IMA_STANDALONE@0.0.2/17"}},
+		{"//*/SES-*/*@0.0.1/*7", []string{"//A/SES-1/IMA_STANDALONE@0.0.1/17", "//A/SES-1/IMA_STANDALONE@0.0.1/27", "//B/SES-3/SMTD@0.0.1/17"}},
+		{"//B/*/*/*", []string{"//B/OTHER/VCM@0.0.1/1", "//B/SES-3/SMTD@0.0.1/17"}},
+		{"//C/*/*/*", []string{}},
+	}
+	for _, test := range tests {
+		hits, err := table.QueryTMIs(test.query)
+		if err != nil {
+			t.Fatalf("query %s failed: %s", test.query, err)
+		}
+		if !slices.Equal(hits, test.expected) {
+			t.Errorf("query %s: expected %v, got %v", test.query, test.expected, hits)
+		}
+	}
+}
+
+func TestTableInvalidQueries(t *testing.T) {
+	table := CreateTrustModelInstanceTable()
+	for _, query := range []string{"", "A/B/C/D", "//A/B/C", "//A/B/C/D/E", "//A//C/D", "//A/B/C/*x*", "//A/B/C/x*y", "//**/B/C/D"} {
+		if _, err := table.QueryTMIs(query); !errors.Is(err, ErrInvalidTMIQuery) {
+			t.Errorf("query '%s' should be rejected, got error %v", query, err)
+		}
+	}
+	if _, _, err := table.QueryTMIsPaginated("//*/*/*/*", -1, 0); !errors.Is(err, ErrInvalidTMIQuery) {
+		t.Error("negative offset should be rejected")
+	}
+}
+
+func TestTablePagination(t *testing.T) {
+	table := CreateTrustModelInstanceTable()
+	for i := 0; i < 25; i++ {
+		table.RegisterTMI("A", "B", "IMA@0.0.1", fmt.Sprintf("%03d", i))
+	}
+	collected := make([]string, 0)
+	offset := 0
+	for {
+		page, more, err := table.QueryTMIsPaginated("//*/*/IMA@0.0.1/*", offset, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		collected = append(collected, page...)
+		offset += len(page)
+		if !more {
+			break
+		}
+	}
+	if !slices.Equal(collected, table.GetAllTMIs()) {
+		t.Errorf("paginated results differ from full result list: %v", collected)
+	}
+	if len(collected) != 25 {
+		t.Errorf("expected 25 results, got %d", len(collected))
+	}
+}
+
+func populateTable(numSessions int, numTMIs int) *TrustModelInstanceTable {
+	table := CreateTrustModelInstanceTable()
+	for s := 0; s < numSessions; s++ {
+		for i := 0; i < numTMIs; i++ {
+			table.RegisterTMI(fmt.Sprintf("client%d", s), fmt.Sprintf("SES-%d", s), "IMA_STANDALONE@0.0.2", strconv.Itoa(i))
+		}
+	}
+	return table
+}
+
+func benchmarkExactQuery(b *testing.B, numTMIs int) {
+	table := populateTable(4, numTMIs/4)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = table.QueryTMIs("//*/*/IMA_STANDALONE@0.0.2/" + strconv.Itoa(i%(numTMIs/4)))
+	}
+}
+
+func BenchmarkQueryExact1k(b *testing.B)   { benchmarkExactQuery(b, 1000) }
+func BenchmarkQueryExact10k(b *testing.B)  { benchmarkExactQuery(b, 10000) }
+func BenchmarkQueryExact50k(b *testing.B)  { benchmarkExactQuery(b, 50000) }
+func BenchmarkQueryExact100k(b *testing.B) { benchmarkExactQuery(b, 100000) }
+
+func BenchmarkQueryPrefixPage50k(b *testing.B) {
+	table := populateTable(4, 12500)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = table.QueryTMIsPaginated("//*/*/IMA_STANDALONE@*/12*", 0, 20)
+	}
+}
+
+func BenchmarkRegisterUnregister50k(b *testing.B) {
+	table := populateTable(4, 12500)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		table.RegisterTMI("client0", "SES-0", "IMA_STANDALONE@0.0.2", "new")
+		table.UnregisterTMI("client0", "SES-0", "IMA_STANDALONE@0.0.2", "new")
+	}
+}
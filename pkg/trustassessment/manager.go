@@ -2,6 +2,7 @@ package trustassessment
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/horizon-connect-eu/go-taf/internal/flow/completionhandler"
@@ -20,20 +21,33 @@ import (
 	tasmsg "github.com/horizon-connect-eu/go-taf/pkg/message/tas"
 	tchmsg "github.com/horizon-connect-eu/go-taf/pkg/message/tch"
 	v2xmsg "github.com/horizon-connect-eu/go-taf/pkg/message/v2x"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustdecision"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/session"
-	"hash/fnv"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
 	"log/slog"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 )
 
 type Manager struct {
 	config       config.Configuration
-	tamToWorkers []chan core.Command
 	workersToTam chan core.Command
-	logger       *slog.Logger
-	tafContext   core.TafContext
-	channels     core.TafChannels
+	//all running workers, including retiring ones; modified by the TAM goroutine only
+	workers      []*workerHandle
+	workersLock  sync.RWMutex
+	nextWorkerID int
+	//full tmiID->ID of the worker handling the TMI
+	placement map[string]int
+	//full tmiID->ongoing migration of the TMI
+	migrating map[string]migration
+	//full tmiID->number of commands dispatched since the worker load became skewed
+	dispatchCounts map[string]uint64
+	skewedChecks   int
+	logger         *slog.Logger
+	tafContext     core.TafContext
+	channels       core.TafChannels
 	//sessionID->Session
 	sessions map[string]session.Session
 	outbox   chan core.Message
@@ -42,18 +56,32 @@ type Manager struct {
 	crypto   *crypto.Crypto
 	//tmiID->latest ATLs/PPs/TDs
 	atlResults map[string]core.AtlResultSet
+	//bounded history of ATLs/PPs/TDs per TMI
+	atlHistory *AtlHistory
 	//tas sub ID->sessionID
 	tasSubscriptionsToSessionID map[string]string
 	//tas sub ID->Subscription
 	tasSubscriptions map[string]Subscription
+	//cross-session TAQI subscriptions
+	taqiSubscriptions *taqiSubscriptionRegistry
+	//requests waiting for fresh results
+	pendingRequests *pendingRequestRegistry
+	//latest tags reflected by each TMI
+	reflectedTags *reflectedTagLog
 	//Queryable table of all TMIs
 	tmiTable         *TrustModelInstanceTable
 	sessionListeners map[listener.SessionListener]bool
 	atlListeners     map[listener.ActualTrustLevelListener]bool
 	tmiListeners     map[listener.TrustModelInstanceListener]bool
+	//decision policy used by all workers unless a template declares its own policy
+	defaultPolicy core.DecisionPolicy
 }
 
 func NewManager(tafContext core.TafContext, channels core.TafChannels) (*Manager, error) {
+	defaultPolicy, err := trustdecision.NewPolicy(tafContext.Configuration.TDE.Policy, tafContext.Configuration.TDE)
+	if err != nil {
+		return nil, err
+	}
 	tam := &Manager{
 		config:                      tafContext.Configuration,
 		tafContext:                  tafContext,
@@ -64,13 +92,22 @@ func NewManager(tafContext core.TafContext, channels core.TafChannels) (*Manager
 		crypto:                      tafContext.Crypto,
 		outbox:                      channels.OutgoingMessageChannel,
 		atlResults:                  make(map[string]core.AtlResultSet),
+		atlHistory:                  CreateAtlHistory(tafContext.Configuration.TAM.ATLHistory.Size, tafContext.Configuration.TAM.ATLHistory.SpillDirectory, tafContext.Configuration.TAM.ATLHistory.SpillMaxEntries, logging.CreateChildLogger(tafContext.Logger, "TAM-ATL-HISTORY")),
 		tasSubscriptionsToSessionID: make(map[string]string),
 		tasSubscriptions:            make(map[string]Subscription),
 		tmiTable:                    CreateTrustModelInstanceTable(),
 		sessionListeners:            make(map[listener.SessionListener]bool),
 		atlListeners:                make(map[listener.ActualTrustLevelListener]bool),
 		tmiListeners:                make(map[listener.TrustModelInstanceListener]bool),
+		placement:                   make(map[string]int),
+		migrating:                   make(map[string]migration),
+		dispatchCounts:              make(map[string]uint64),
+		defaultPolicy:               defaultPolicy,
 	}
+	tam.taqiSubscriptions = newTaqiSubscriptionRegistry(tam.sendTaqiNotify)
+	tam.pendingRequests = newPendingRequestRegistry(tam.scheduleRequestTimeout)
+	tam.reflectedTags = newReflectedTagLog()
+	tam.AddATLListener(tam.taqiSubscriptions)
 	tam.logger.Info("Initializing Trust Assessment Manager", "Worker Count", tam.config.TAM.TrustModelInstanceShards)
 	return tam, nil
 }
@@ -84,18 +121,18 @@ func (tam *Manager) SetManagers(managers manager.TafManagers) {
 func (tam *Manager) Run() {
 
 	defer func() {
+		tam.atlHistory.Close()
 		tam.logger.Info("Shutting down")
 	}()
 
 	tsm := tam.tsm
 	tmm := tam.tmm
 
-	tam.tamToWorkers = make([]chan core.Command, 0, tam.config.TAM.TrustModelInstanceShards)
-	for i := range tam.config.TAM.TrustModelInstanceShards {
-		channel := make(chan core.Command, tam.config.ChanBufSize)
-		tam.tamToWorkers = append(tam.tamToWorkers, channel)
-		worker := tam.SpawnNewWorker(i, channel, tam.workersToTam, tam.tafContext, tam.tmiListeners)
-		go worker.Run()
+	for range tam.config.TAM.TrustModelInstanceShards {
+		tam.spawnWorker()
+	}
+	if tam.config.TAM.Rebalancing.Enabled {
+		go tam.runRebalancer()
 	}
 
 	for {
@@ -113,6 +150,10 @@ func (tam *Manager) Run() {
 			switch cmd := incomingCmd.(type) {
 			case command.HandleATLUpdate:
 				tam.HandleATLUpdate(cmd)
+			case command.HandleTMIUpdateApplied:
+				tam.HandleTMIUpdateApplied(cmd)
+			case command.HandleTMIReleased:
+				tam.HandleTMIReleased(cmd)
 			default:
 				tam.logger.Warn("Command with no associated handling logic received by TAM from Worker", "Command Type", cmd.Type())
 			}
@@ -122,6 +163,10 @@ func (tam *Manager) Run() {
 				switch cmd := incomingCmd.(type) {
 				case command.HandleATLUpdate:
 					tam.HandleATLUpdate(cmd)
+				case command.HandleTMIUpdateApplied:
+					tam.HandleTMIUpdateApplied(cmd)
+				case command.HandleTMIReleased:
+					tam.HandleTMIReleased(cmd)
 				default:
 					tam.logger.Warn("Command with no associated handling logic received by TAM from Worker", "Command Type", cmd.Type())
 				}
@@ -134,12 +179,24 @@ func (tam *Manager) Run() {
 					tam.HandleTasTeardownRequest(cmd)
 				case command.HandleRequest[tasmsg.TasTaRequest]:
 					tam.HandleTasTaRequest(cmd)
+				case command.HandleRequest[tasmsg.TasRtlUpdateRequest]:
+					tam.HandleTasRtlUpdateRequest(cmd)
 				case command.HandleSubscriptionRequest[tasmsg.TasSubscribeRequest]:
 					tam.HandleTasSubscribeRequest(cmd)
 				case command.HandleSubscriptionRequest[tasmsg.TasUnsubscribeRequest]:
 					tam.HandleTasUnsubscribeRequest(cmd)
 				case command.HandleRequest[taqimsg.TaqiQuery]:
 					tam.HandleTaqiQuery(cmd)
+				case command.HandleSubscriptionRequest[taqimsg.TaqiSubscribeRequest]:
+					tam.HandleTaqiSubscribeRequest(cmd)
+				case command.HandleSubscriptionRequest[taqimsg.TaqiUnsubscribeRequest]:
+					tam.HandleTaqiUnsubscribeRequest(cmd)
+				case command.HandlePendingRequestTimeout:
+					tam.pendingRequests.expire(cmd.RequestID)
+				case command.HandleScaleWorkers:
+					tam.handleScaleWorkers(cmd)
+				case command.HandleRebalanceCheck:
+					tam.handleRebalanceCheck()
 				// TSM Message Handling
 				case command.HandleResponse[aivmsg.AivResponse]:
 					tsm.HandleAivResponse(cmd)
@@ -148,17 +205,20 @@ func (tam *Manager) Run() {
 				case command.HandleResponse[aivmsg.AivUnsubscribeResponse]:
 					tsm.HandleAivUnsubscribeResponse(cmd)
 				case command.HandleNotify[aivmsg.AivNotify]:
+					tmm.HandleAivNotify(cmd) //handle potential trigger based on trustee
 					tsm.HandleAivNotify(cmd)
 				case command.HandleResponse[mbdmsg.MBDSubscribeResponse]:
 					tsm.HandleMbdSubscribeResponse(cmd)
 				case command.HandleResponse[mbdmsg.MBDUnsubscribeResponse]:
 					tsm.HandleMbdUnsubscribeResponse(cmd)
 				case command.HandleNotify[mbdmsg.MBDNotify]:
+					tmm.HandleMbdNotify(cmd) //handle potential trigger based on V2X source
 					tsm.HandleMbdNotify(cmd)
 				case command.HandleNotify[tchmsg.TchNotify]:
 					tmm.HandleTchNotify(cmd) //handle potential trigger based on trustee
 					tsm.HandleTchNotify(cmd) //handle evidence from TCH
 				case command.HandleNotify[v2xmsg.V2XNtm]:
+					tmm.HandleV2xNtm(cmd) //handle potential triggers based on V2X sources
 					tsm.HandleV2xNtm(cmd)
 				// TMM Message Handling
 				case command.HandleOneWay[v2xmsg.V2XCpm]:
@@ -167,6 +227,8 @@ func (tam *Manager) Run() {
 					tmm.HandleTasTmtDiscover(cmd)
 				case command.HandleObserverEvent:
 					tmm.HandleObserverEvent(cmd)
+				case command.HandleTemplateReload:
+					tmm.HandleTemplateReload(cmd)
 				default:
 					tam.logger.Warn("Command with no associated handling logic received by TAM from Communication Handler", "Command Type", cmd.Type())
 				}
@@ -210,6 +272,15 @@ func (tam *Manager) HandleTasInitRequest(cmd command.HandleRequest[tasmsg.TasIni
 		sendErrorResponse(errorMsg)
 		return
 	}
+	if unknown := tam.tmm.UnknownParameters(tmt, cmd.Request.Params); len(unknown) > 0 {
+		tam.logger.Warn("Ignoring parameters not declared by trust model template", "TMT", tmt.Identifier(), "Client", cmd.Sender, "Parameters", strings.Join(unknown, ", "))
+	}
+	params, err := tam.tmm.ValidateParameters(tmt, cmd.Request.Params)
+	if err != nil {
+		tam.logger.Warn("Invalid parameters for trust model template", "TMT", tmt.Identifier(), "Client", cmd.Sender, "Error", err.Error())
+		sendErrorResponse("Error initializing session: " + err.Error())
+		return
+	}
 	//create session ID for client
 	sessionId := tam.generateSessionId()
 	//create Session
@@ -220,7 +291,7 @@ func (tam *Manager) HandleTasInitRequest(cmd command.HandleRequest[tasmsg.TasIni
 	tam.logger.Info("Session created:", "Session ID", newSession.ID(), "Client", newSession.Client())
 
 	//create new TMI and/or dynamic spawn function for session
-	tsqs, tMI, dynamicSpawner, err := tmt.Spawn(cmd.Request.Params, tam.tafContext)
+	tsqs, tMI, dynamicSpawner, err := tmt.Spawn(params, tam.tafContext)
 	if err != nil {
 		delete(tam.sessions, sessionId)
 		sendErrorResponse("Error initializing session: " + err.Error())
@@ -236,33 +307,8 @@ func (tam *Manager) HandleTasInitRequest(cmd command.HandleRequest[tasmsg.TasIni
 			//register spawn function at session
 			newSession.SetDynamicSpawner(dynamicSpawner)
 
-			//iterate over existing triggers and spawn TMIs for these
-			if tmt.Type() == core.VEHICLE_TRIGGERED_TRUST_MODEL {
-				for _, nodeIdentifier := range tam.tmm.ListRecentV2XNodes() {
-					tmi, err := dynamicSpawner.OnNewVehicle(nodeIdentifier, nil)
-					if err != nil {
-						tam.logger.Error("Error while spawning trust model instance", "TMT", newSession.TrustModelTemplate(), "Identifier used for dynamic spawning", nodeIdentifier)
-					} else {
-						tmi.Initialize(map[string]interface{}{
-							"SourceId": nodeIdentifier,
-						})
-						tam.AddNewTrustModelInstance(tmi, sessionId)
-					}
-				}
-			} else if tmt.Type() == core.TRUSTEE_TRIGGERED_TRUST_MODEL {
-				for _, trusteeIdentifier := range tam.tmm.ListRecentTrustees() {
-					tmi, err := dynamicSpawner.OnNewTrustee(trusteeIdentifier, nil)
-					if err != nil {
-						tam.logger.Error("Error while spawning trust model instance", "TMT", newSession.TrustModelTemplate(), "Identifier used for dynamic spawning", trusteeIdentifier)
-					} else {
-						tmi.Initialize(map[string]interface{}{
-							"trusteeId": trusteeIdentifier,
-						})
-						tam.AddNewTrustModelInstance(tmi, sessionId)
-					}
-
-				}
-			}
+			//iterate over entities of the spawn triggers and spawn TMIs for these
+			tam.tmm.SpawnForRecentEntities(sessionId)
 		}
 	}
 
@@ -277,7 +323,8 @@ func (tam *Manager) HandleTasInitRequest(cmd command.HandleRequest[tasmsg.TasIni
 
 			//Dispatch new TMI instance to worker
 			fullTmiID := core.MergeFullTMIIdentifier(newSession.Client(), newSession.ID(), newSession.TrustModelTemplate().Identifier(), tMI.ID())
-			tmiInitCmd := command.CreateHandleTMIInit(fullTmiID, tMI)
+			tam.tmiTable.RegisterTMI(newSession.Client(), newSession.ID(), newSession.TrustModelTemplate().Identifier(), tMI.ID())
+			tmiInitCmd := command.CreateHandleTMIInit(fullTmiID, tMI, nil)
 			tam.DispatchToWorker(newSession, tMI.ID(), tmiInitCmd)
 		}
 
@@ -289,6 +336,11 @@ func (tam *Manager) HandleTasInitRequest(cmd command.HandleRequest[tasmsg.TasIni
 			SessionID:              &sessionId,
 			Success:                &success,
 		}
+		if tam.tmm.IsDeprecated(tmt.Identifier()) {
+			warning := "Trust model template '" + tmt.Identifier() + "' is deprecated."
+			response.Warning = &warning
+			tam.logger.Warn("Session created with deprecated trust model template", "TMT", tmt.Identifier(), "Session ID", sessionId, "Client", newSession.Client())
+		}
 
 		bytes, errr := communication.BuildResponse(tam.config.Communication.TafEndpoint, messages.TAS_INIT_RESPONSE, cmd.RequestID, response)
 		if errr != nil {
@@ -313,6 +365,8 @@ func (tam *Manager) HandleTasInitRequest(cmd command.HandleRequest[tasmsg.TasIni
 			tam.DispatchToWorker(newSession, tMI.ID(), command.CreateHandleTMIDestroy(fullTMIid))
 			//remove ATL cache entries for this session
 			delete(tam.atlResults, fullTMIid)
+			tam.atlHistory.Release(fullTMIid)
+			tam.reflectedTags.forget(fullTMIid)
 		}
 		delete(tam.sessions, sessionId)
 	}
@@ -369,8 +423,12 @@ func (tam *Manager) HandleTasTeardownRequest(cmd command.HandleRequest[tasmsg.Ta
 	for tmiID, fullTMIID := range currentSession.TrustModelInstances() {
 		//signal worker to destroy TMI
 		tam.DispatchToWorker(currentSession, tmiID, command.CreateHandleTMIDestroy(fullTMIID))
+		tam.tmiTable.UnregisterTMI(currentSession.Client(), currentSession.ID(), currentSession.TrustModelTemplate().Identifier(), tmiID)
 		//remove ATL cache entries for this session
 		delete(tam.atlResults, fullTMIID)
+		tam.atlHistory.Release(fullTMIID)
+		tam.reflectedTags.forget(fullTMIID)
+		tam.notifyATLRemoved(fullTMIID)
 		//remove TMI(s) associated to this session
 		delete(currentSession.TrustModelInstances(), tmiID)
 	}
@@ -445,65 +503,284 @@ func (tam *Manager) HandleTasTaRequest(cmd command.HandleRequest[tasmsg.TasTaReq
 
 	tam.logger.Debug("TAS_TA_Request Query Targets", "List", fmt.Sprintf("%v", targets))
 
-	if cmd.Request.AllowCache == nil || *cmd.Request.AllowCache == true {
+	//Requirements on tags or versions of TMIs (read-your-writes)
+	requirements := make(map[string]ResultCondition)
+	requiredTags := make(map[string][]string)
+	for _, requirement := range cmd.Request.Requires {
+		if !tmiSession.HasTMI(requirement.ID) {
+			sendErrorResponse("Required TMI '" + requirement.ID + "' not found.")
+			return
+		} else if requirement.Tag == nil && requirement.MinVersion == nil {
+			sendErrorResponse("Requirement for TMI '" + requirement.ID + "' must specify a tag or a minimum version.")
+			return
+		}
+		fullTmiID := tmiSession.TrustModelInstances()[requirement.ID]
+		requirements[fullTmiID] = allConditions(requirements[fullTmiID], versionCondition(requirement.MinVersion))
+		if requirement.Tag != nil && !tam.reflectedTags.contains(fullTmiID, *requirement.Tag) {
+			requiredTags[fullTmiID] = append(requiredTags[fullTmiID], *requirement.Tag)
+		}
+	}
+
+	nonCached := cmd.Request.AllowCache != nil && !*cmd.Request.AllowCache
+	freshness := cmd.Request.Freshness
+	if !nonCached && freshness == nil && len(requirements) == 0 {
 		//Directly send response
-		taResponseResults := make([]tasmsg.Result, 0)
+		tam.sendTaResponse(cmd, targets, false)
+		return
+	}
 
-		//Iterate over TMI IDs in the Target Set
-		for _, fullTmiID := range targets {
-			atlResultSet, exists := tam.atlResults[fullTmiID]
+	acceptedAt := time.Now()
+	deadline := time.Duration(tam.config.TAM.DefaultRequestDeadline) * time.Millisecond
+	onTimeout := tasmsg.Fail
+	var maxAge *time.Duration = nil
+	if freshness != nil {
+		if freshness.Deadline != nil {
+			if *freshness.Deadline < 0 {
+				sendErrorResponse("Deadline must not be negative.")
+				return
+			}
+			deadline = time.Duration(*freshness.Deadline) * time.Millisecond
+		}
+		if freshness.MaxAge != nil {
+			if *freshness.MaxAge < 0 {
+				sendErrorResponse("Maximum age must not be negative.")
+				return
+			}
+			age := time.Duration(*freshness.MaxAge) * time.Millisecond
+			maxAge = &age
+		}
+		if freshness.OnTimeout != nil {
+			if *freshness.OnTimeout != tasmsg.Fail && *freshness.OnTimeout != tasmsg.Stale {
+				sendErrorResponse("Unknown timeout behavior: '" + string(*freshness.OnTimeout) + "'")
+				return
+			}
+			onTimeout = *freshness.OnTimeout
+		}
+	}
 
-			if exists {
-				propositions := make([]Proposition, 0)
-				for propositionID := range atlResultSet.ATLs() {
-					propositions = append(propositions, NewPropositionEntry(atlResultSet, propositionID))
-				}
-				_, _, _, tmiID := core.SplitFullTMIIdentifier(fullTmiID)
+	//Collect the targets for which the cached results do not meet the requested freshness
+	conditions := make(map[string]ResultCondition)
+	refresh := false
+	for _, fullTmiID := range targets {
+		var condition ResultCondition
+		if nonCached {
+			//only results computed after the request has been accepted are acceptable
+			condition = func(resultSet core.AtlResultSet) bool {
+				return !resultSet.Timestamp().Before(acceptedAt)
+			}
+		} else if maxAge != nil {
+			age := *maxAge
+			condition = func(resultSet core.AtlResultSet) bool {
+				return time.Since(resultSet.Timestamp()) <= age
+			}
+		} else {
+			continue
+		}
+		if atlResultSet, exists := tam.atlResults[fullTmiID]; exists && condition(atlResultSet) {
+			continue
+		}
+		conditions[fullTmiID] = condition
+		refresh = true
+	}
+	//Add requirements that are not yet fulfilled by the cached results; these are only awaited, not actively refreshed
+	for fullTmiID, requirement := range requirements {
+		if requirement(tam.atlResults[fullTmiID]) {
+			continue
+		}
+		conditions[fullTmiID] = allConditions(conditions[fullTmiID], requirement)
+	}
 
-				result := ResultEntry{
-					TmiID:        tmiID,
-					Propositions: propositions,
-					version:      atlResultSet.Version(),
-					tag:          atlResultSet.Tag(),
-				}
-				taResponseResults = append(taResponseResults, result.toTarResultMsgStruct())
+	if refresh {
+		//Static trust models relying on AIV evidence can be refreshed actively by requesting evidence from the AIV.
+		//Otherwise, the request has to wait for regular updates of the TMIs.
+		if supportsAivRequests(tmiSession.TrustModelTemplate()) {
+			if len(tmiSession.TrustModelInstances()) == 0 {
+				sendErrorResponse("No trust model instances found in this session")
+				return
 			}
+			tam.tsm.DispatchAivRequest(tmiSession)
+		} else if nonCached {
+			sendErrorResponse("Trust model template type does not allow non-cached requests.")
+			return
 		}
+	}
 
-		response := tasmsg.TasTaResponse{
+	tam.pendingRequests.await(conditions, requiredTags, deadline, func(timedOut bool) {
+		if _, exists := tam.sessions[sessionID]; !exists {
+			sendErrorResponse("Session has been torn down before fresh results were available")
+		} else if timedOut && onTimeout == tasmsg.Fail {
+			sendErrorResponse("Deadline expired before fresh results meeting all requirements were available")
+		} else {
+			tam.sendTaResponse(cmd, targets, timedOut)
+		}
+	})
+}
+
+/*
+sendTaResponse sends a TAS_TA_RESPONSE containing the cached results of the given target TMIs. If stale is true, the
+response indicates that the results do not meet the requested freshness.
+*/
+func (tam *Manager) sendTaResponse(cmd command.HandleRequest[tasmsg.TasTaRequest], targets []string, stale bool) {
+	taResponseResults := make([]tasmsg.Result, 0)
+	explain := cmd.Request.Explain != nil && *cmd.Request.Explain
+
+	//Iterate over TMI IDs in the Target Set
+	for _, fullTmiID := range targets {
+		atlResultSet, exists := tam.atlResults[fullTmiID]
+
+		if exists {
+			propositions := make([]Proposition, 0)
+			for propositionID := range atlResultSet.ATLs() {
+				propositions = append(propositions, NewPropositionEntry(atlResultSet, propositionID))
+			}
+			_, _, _, tmiID := core.SplitFullTMIIdentifier(fullTmiID)
+
+			result := ResultEntry{
+				TmiID:        tmiID,
+				Propositions: propositions,
+				version:      atlResultSet.Version(),
+				tag:          atlResultSet.Tag(),
+			}
+			taResponseResults = append(taResponseResults, result.toTarResultMsgStruct(explain))
+		}
+	}
+
+	var staleFlag *bool = nil
+	if stale {
+		staleFlag = &stale
+	}
+	response := tasmsg.TasTaResponse{
+		AttestationCertificate: tam.crypto.AttestationCertificate(),
+		Error:                  nil,
+		Results:                taResponseResults,
+		SessionID:              cmd.Request.SessionID,
+		Stale:                  staleFlag,
+	}
+	bytes, err := communication.BuildResponse(tam.config.Communication.TafEndpoint, messages.TAS_TA_RESPONSE, cmd.RequestID, response)
+	if err != nil {
+		tam.logger.Error("Error marshalling response", "error", err)
+		return
+	}
+	tam.outbox <- core.NewMessage(bytes, "", cmd.ResponseTopic)
+}
+
+/*
+supportsAivRequests checks whether fresh evidence for TMIs of a template can be requested from the AIV on demand.
+*/
+func supportsAivRequests(tmt core.TrustModelTemplate) bool {
+	return tmt.Type() == core.STATIC_TRUST_MODEL &&
+		(slices.Contains(tmt.EvidenceTypes(), core.AIV_APPLICATION_ISOLATION) ||
+			slices.Contains(tmt.EvidenceTypes(), core.AIV_ACCESS_CONTROL) ||
+			slices.Contains(tmt.EvidenceTypes(), core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION) ||
+			slices.Contains(tmt.EvidenceTypes(), core.AIV_CONTROL_FLOW_INTEGRITY) ||
+			slices.Contains(tmt.EvidenceTypes(), core.AIV_SECURE_OTA) ||
+			slices.Contains(tmt.EvidenceTypes(), core.AIV_SECURE_BOOT))
+}
+
+/*
+scheduleRequestTimeout starts a timer that signals the expiry of a pending request to the TAM goroutine.
+*/
+func (tam *Manager) scheduleRequestTimeout(deadline time.Duration, requestID string) *time.Timer {
+	return time.AfterFunc(deadline, func() {
+		select {
+		case tam.channels.TAMChannel <- command.CreateHandlePendingRequestTimeout(requestID):
+		case <-tam.tafContext.Context.Done():
+		}
+	})
+}
+
+/*
+HandleTasRtlUpdateRequest overrides RTLs of a session, a TMI or a single proposition at runtime. The overrides are kept in
+the session and passed to the workers of all affected TMIs, which re-evaluate their trust decisions against the latest
+ATLs. Subscribers are notified about resulting changes of trust decisions as for any other ATL update.
+*/
+func (tam *Manager) HandleTasRtlUpdateRequest(cmd command.HandleRequest[tasmsg.TasRtlUpdateRequest]) {
+	tam.logger.Debug("Received TAS_RTL_UPDATE_REQUEST command", "Session ID", cmd.Request.SessionID, "Client", cmd.Sender)
+
+	sendResponse := func(success *string, errMsg *string) {
+		response := tasmsg.TasRtlUpdateResponse{
 			AttestationCertificate: tam.crypto.AttestationCertificate(),
-			Error:                  nil,
-			Results:                taResponseResults,
-			SessionID:              sessionID,
+			Error:                  errMsg,
+			Success:                success,
 		}
-		bytes, err := communication.BuildResponse(tam.config.Communication.TafEndpoint, messages.TAS_TA_RESPONSE, cmd.RequestID, response)
+		bytes, err := communication.BuildResponse(tam.config.Communication.TafEndpoint, messages.TAS_RTL_UPDATE_RESPONSE, cmd.RequestID, response)
 		if err != nil {
 			tam.logger.Error("Error marshalling response", "error", err)
 			return
 		}
 		tam.outbox <- core.NewMessage(bytes, "", cmd.ResponseTopic)
-	} else {
-		if tam.sessions[sessionID].TrustModelTemplate().Type() == core.STATIC_TRUST_MODEL &&
-			(slices.Contains(tam.sessions[sessionID].TrustModelTemplate().EvidenceTypes(), core.AIV_APPLICATION_ISOLATION) ||
-				slices.Contains(tam.sessions[sessionID].TrustModelTemplate().EvidenceTypes(), core.AIV_ACCESS_CONTROL) ||
-				slices.Contains(tam.sessions[sessionID].TrustModelTemplate().EvidenceTypes(), core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION) ||
-				slices.Contains(tam.sessions[sessionID].TrustModelTemplate().EvidenceTypes(), core.AIV_CONTROL_FLOW_INTEGRITY) ||
-				slices.Contains(tam.sessions[sessionID].TrustModelTemplate().EvidenceTypes(), core.AIV_SECURE_OTA) ||
-				slices.Contains(tam.sessions[sessionID].TrustModelTemplate().EvidenceTypes(), core.AIV_SECURE_BOOT)) {
-			//We need to call AIV Req first and wait for a response
-			if len(tam.sessions[sessionID].TrustModelInstances()) > 0 {
-				//dispatch AIV request and pass the original request to be replayed later
-				tam.tsm.DispatchAivRequest(tmiSession, cmd)
-			} else {
-				sendErrorResponse("No trust model instances found in this session")
-				return
+	}
+	sendErrorResponse := func(errMsg string) {
+		sendResponse(nil, &errMsg)
+	}
+
+	tmiSession, exists := tam.sessions[cmd.Request.SessionID]
+	if !exists {
+		sendErrorResponse("Unknown session")
+		return
+	} else if tmiSession.State() != session.ESTABLISHED {
+		sendErrorResponse("Session not in established state")
+		return
+	} else if len(cmd.Request.Updates) == 0 {
+		sendErrorResponse("No RTL updates specified.")
+		return
+	}
+
+	//Validate all updates before applying any of them
+	type rtlUpdate struct {
+		tmiID       string
+		proposition string
+		rtl         subjectivelogic.QueryableOpinion
+	}
+	updates := make([]rtlUpdate, 0, len(cmd.Request.Updates))
+	errors := make([]string, 0)
+	for _, requested := range cmd.Request.Updates {
+		update := rtlUpdate{}
+		if requested.ID != nil {
+			update.tmiID = *requested.ID
+			if !tmiSession.HasTMI(update.tmiID) {
+				errors = append(errors, "Target ID '"+update.tmiID+"' not found.")
+				continue
+			}
+		}
+		if requested.PropositionID != nil {
+			update.proposition = *requested.PropositionID
+		}
+		if requested.Rtl != nil {
+			rtl, err := subjectivelogic.NewOpinion(requested.Rtl.Belief, requested.Rtl.Disbelief, requested.Rtl.Uncertainty, requested.Rtl.BaseRate)
+			if err != nil {
+				errors = append(errors, "Invalid RTL for target ID '"+update.tmiID+"' and proposition '"+update.proposition+"': "+err.Error())
+				continue
+			}
+			update.rtl = &rtl
+		}
+		updates = append(updates, update)
+	}
+	if len(errors) > 0 {
+		sendErrorResponse(strings.Join(errors, "\n"))
+		return
+	}
+
+	//Apply updates to the session and collect affected TMIs
+	affected := make(map[string]bool)
+	for _, update := range updates {
+		tmiSession.SetRTL(update.tmiID, update.proposition, update.rtl)
+		if update.tmiID == "" {
+			for tmiID := range tmiSession.TrustModelInstances() {
+				affected[tmiID] = true
 			}
 		} else {
-			sendErrorResponse("Trust model template type does not allow non-cached requests.")
-			return
+			affected[update.tmiID] = true
 		}
 	}
 
+	//Let the workers re-evaluate the trust decisions of the affected TMIs
+	for tmiID := range affected {
+		tam.DispatchToWorker(tmiSession, tmiID, command.CreateHandleRTLUpdate(tmiSession.TrustModelInstances()[tmiID], tmiSession.RTLs(tmiID)))
+	}
+
+	success := fmt.Sprintf("RTLs of %d trust model instance(s) updated.", len(affected))
+	sendResponse(&success, nil)
 }
 
 func (tam *Manager) HandleTasSubscribeRequest(cmd command.HandleSubscriptionRequest[tasmsg.TasSubscribeRequest]) {
@@ -734,8 +1011,33 @@ func (tam *Manager) HandleTaqiQuery(cmd command.HandleRequest[taqimsg.TaqiQuery]
 
 	tmiQuery := fmt.Sprintf("//*/*/%s/%s", targetTemplate, targetIdentifier)
 
-	matches, err := tam.QueryTMIs(tmiQuery)
-	if err != nil {
+	timePoint := cmd.Request.Query.TimePoint
+	timeRange := cmd.Request.Query.TimeRange
+	if timePoint != nil && timeRange != nil {
+		sendErrorResponse("A TAQI query must not contain both a time point and a time range.")
+		return
+	}
+	if timeRange != nil && timeRange.From.After(timeRange.To) {
+		sendErrorResponse("Invalid time range: start of range is after its end.")
+		return
+	}
+	if (timePoint != nil || timeRange != nil) && !tam.atlHistory.Enabled() {
+		sendErrorResponse("Historic TAQI queries are not supported, as the ATL history is disabled.")
+		return
+	}
+
+	var matches []string
+	var err error
+	if timePoint != nil || timeRange != nil {
+		//historic queries may also target TMIs that have been removed in the meantime
+		matches, err = tam.atlHistory.QueryTMIs(tmiQuery)
+	} else {
+		matches, err = tam.QueryTMIs(tmiQuery)
+	}
+	if errors.Is(err, ErrInvalidTMIQuery) {
+		sendErrorResponse("Invalid TAQI query: " + err.Error())
+		return
+	} else if err != nil {
 		sendErrorResponse("Internal error while processing TAQI query.")
 		tam.logger.Warn("Internal error while processing TAQI query.", "Error", err.Error())
 		return
@@ -747,35 +1049,235 @@ func (tam *Manager) HandleTaqiQuery(cmd command.HandleRequest[taqimsg.TaqiQuery]
 		targetPropositionMap[proposition] = struct{}{}
 	}
 
-	taqiResults := make([]taqimsg.Result, 0)
-	for _, fullTmiID := range matches {
-		atlResultSet, exists := tam.atlResults[fullTmiID] //get cached ATL entry from TMI using the full ID
-		if exists {
-			propositions := make([]Proposition, 0)
-			for propositionID := range atlResultSet.ATLs() {
-				//To include the proposition, the list of targetted props must either be empty, or the proposition at hand must be included.
-				if _, propExistAsTarget := targetPropositionMap[propositionID]; propExistAsTarget || len(targetPropositions) == 0 {
-					propositions = append(propositions, NewPropositionEntry(atlResultSet, propositionID))
+	sendResults := func() {
+		taqiResults := make([]taqimsg.Result, 0)
+		for _, fullTmiID := range matches {
+			for _, atlResultSet := range tam.lookupResultSets(fullTmiID, timePoint, timeRange) {
+				propositions := make([]Proposition, 0)
+				for propositionID := range atlResultSet.ATLs() {
+					//To include the proposition, the list of targetted props must either be empty, or the proposition at hand must be included.
+					if _, propExistAsTarget := targetPropositionMap[propositionID]; propExistAsTarget || len(targetPropositions) == 0 {
+						propositions = append(propositions, NewPropositionEntry(atlResultSet, propositionID))
+					}
 				}
+				_, _, _, tmiID := core.SplitFullTMIIdentifier(fullTmiID)
+				result := ResultEntry{
+					TmiID:        tmiID,
+					Propositions: propositions,
+					version:      atlResultSet.Version(),
+					tag:          atlResultSet.Tag(),
+					timestamp:    atlResultSet.Timestamp(),
+				}
+				taqiResults = append(taqiResults, result.toTaqiResultMsgStruct())
 			}
-			_, _, _, tmiID := core.SplitFullTMIIdentifier(fullTmiID)
-			result := ResultEntry{
-				TmiID:        tmiID,
-				Propositions: propositions,
+		}
+		response := taqimsg.TaqiResult{
+			Results: taqiResults,
+		}
+
+		bytes, err := communication.BuildResponse(tam.config.Communication.TafEndpoint, messages.TAQI_RESULT, cmd.RequestID, response)
+		if err != nil {
+			tam.logger.Error("Error marshalling response", "error", err)
+			return
+		}
+		tam.outbox <- core.NewMessage(bytes, "", cmd.ResponseTopic)
+	}
+
+	if len(cmd.Request.Query.Requires) == 0 {
+		sendResults()
+		return
+	}
+
+	//Requirements on tags or versions of TMIs (read-your-writes)
+	if timePoint != nil || timeRange != nil {
+		sendErrorResponse("Requirements cannot be combined with historic TAQI queries.")
+		return
+	}
+	deadline := time.Duration(tam.config.TAM.DefaultRequestDeadline) * time.Millisecond
+	if cmd.Request.Query.Deadline != nil {
+		if *cmd.Request.Query.Deadline < 0 {
+			sendErrorResponse("Deadline must not be negative.")
+			return
+		}
+		deadline = time.Duration(*cmd.Request.Query.Deadline) * time.Millisecond
+	}
+	conditions := make(map[string]ResultCondition)
+	requiredTags := make(map[string][]string)
+	for _, requirement := range cmd.Request.Query.Requires {
+		if requirement.Tag == nil && requirement.MinVersion == nil {
+			sendErrorResponse("Requirement for TMI '" + requirement.ID + "' must specify a tag or a minimum version.")
+			return
+		}
+		found := false
+		for _, fullTmiID := range matches {
+			if _, _, _, tmiID := core.SplitFullTMIIdentifier(fullTmiID); tmiID != requirement.ID {
+				continue
+			}
+			found = true
+			if condition := versionCondition(requirement.MinVersion); condition != nil && !condition(tam.atlResults[fullTmiID]) {
+				conditions[fullTmiID] = allConditions(conditions[fullTmiID], condition)
+			}
+			if requirement.Tag != nil && !tam.reflectedTags.contains(fullTmiID, *requirement.Tag) {
+				requiredTags[fullTmiID] = append(requiredTags[fullTmiID], *requirement.Tag)
 			}
-			taqiResults = append(taqiResults, result.toTaqiResultMsgStruct())
 		}
+		if !found {
+			sendErrorResponse("Required TMI '" + requirement.ID + "' does not match the query.")
+			return
+		}
+	}
+	tam.pendingRequests.await(conditions, requiredTags, deadline, func(timedOut bool) {
+		if timedOut {
+			sendErrorResponse("Deadline expired before results meeting all requirements were available")
+		} else {
+			sendResults()
+		}
+	})
+}
+
+/*
+lookupResultSets returns the result sets of a TMI to be included in a TAQI result: the latest result set if neither a
+time point nor a time range is given, the result set valid at the time point, or all result sets within the time range.
+*/
+func (tam *Manager) lookupResultSets(fullTmiID string, timePoint *time.Time, timeRange *taqimsg.TimeRange) []core.AtlResultSet {
+	if timePoint != nil {
+		if atlResultSet, exists := tam.atlHistory.At(fullTmiID, *timePoint); exists {
+			return []core.AtlResultSet{atlResultSet}
+		}
+		return nil
+	} else if timeRange != nil {
+		return tam.atlHistory.Range(fullTmiID, timeRange.From, timeRange.To)
+	} else if atlResultSet, exists := tam.atlResults[fullTmiID]; exists { //get cached ATL entry from TMI using the full ID
+		return []core.AtlResultSet{atlResultSet}
+	}
+	return nil
+}
+
+func (tam *Manager) HandleTaqiSubscribeRequest(cmd command.HandleSubscriptionRequest[taqimsg.TaqiSubscribeRequest]) {
+	tam.logger.Debug("Received TAQI_SUBSCRIBE_REQUEST command", "Target Template", cmd.Request.Subscribe.Template, "Target Identifier", cmd.Request.Subscribe.Identifier, "Target Propositions", cmd.Request.Subscribe.Propositions, "Client", cmd.Sender)
+	targetTemplate := cmd.Request.Subscribe.Template
+	targetIdentifier := cmd.Request.Subscribe.Identifier
+
+	sendErrorResponse := func(errMsg string) {
+		response := taqimsg.TaqiSubscribeResponse{
+			Error: &errMsg,
+		}
+		bytes, err := communication.BuildSubscriptionResponse(tam.config.Communication.TafEndpoint, messages.TAQI_SUBSCRIBE_RESPONSE, cmd.RequestID, response)
+		if err != nil {
+			tam.logger.Error("Error marshalling response", "error", err)
+			return
+		}
+		tam.outbox <- core.NewMessage(bytes, "", cmd.ResponseTopic)
+	}
+
+	if nil == tam.tmm.ResolveTMT(targetTemplate) {
+		sendErrorResponse("Unknown Trust Model Template used as target: '" + targetTemplate + "'")
+		return
+	}
+	if targetIdentifier == "" {
+		sendErrorResponse("Target Identifier must not be empty.")
+		return
+	}
+
+	trigger := ACTUAL_TRUSTWORTHINESS_LEVEL
+	if cmd.Request.Trigger != nil {
+		switch *cmd.Request.Trigger {
+		case taqimsg.ActualTrustworthinessLevelTrigger:
+			trigger = ACTUAL_TRUSTWORTHINESS_LEVEL
+		case taqimsg.TrustDecisionTrigger:
+			trigger = TRUST_DECISION
+		default:
+			sendErrorResponse("Unknown subscription trigger: '" + string(*cmd.Request.Trigger) + "'")
+			return
+		}
+	}
+
+	subscriptionID := tam.generateSubscriptionID()
+	subscription, err := NewTaqiSubscription(subscriptionID, cmd.Sender, cmd.SubscriberTopic, targetTemplate, targetIdentifier, cmd.Request.Subscribe.Propositions, trigger)
+	if err != nil {
+		sendErrorResponse("Invalid TAQI subscription: " + err.Error())
+		return
+	}
+	tam.taqiSubscriptions.add(subscription)
+
+	success := "Subscription created"
+	response := taqimsg.TaqiSubscribeResponse{
+		Success:        &success,
+		SubscriptionID: &subscriptionID,
+	}
+	bytes, err := communication.BuildSubscriptionResponse(tam.config.Communication.TafEndpoint, messages.TAQI_SUBSCRIBE_RESPONSE, cmd.RequestID, response)
+	if err != nil {
+		tam.logger.Error("Error marshalling response", "error", err)
+		return
+	}
+	tam.outbox <- core.NewMessage(bytes, "", cmd.ResponseTopic)
+	tam.logger.Debug("TAQI Subscription created", "Subscription ID", subscriptionID, "Query", subscription.Query())
+
+	//send initial TAQI_NOTIFY containing the current results of all matching TMIs
+	matches, err := tam.QueryTMIs(subscription.Query())
+	if err != nil {
+		tam.logger.Warn("Could not query TMIs for initial TAQI notification", "Subscription ID", subscriptionID, "Error", err.Error())
+		return
+	}
+	results := make([]taqimsg.Result, 0)
+	for _, fullTmiID := range matches {
+		if atlResultSet, exists := tam.atlResults[fullTmiID]; exists {
+			results = append(results, subscription.resultEntry(fullTmiID, atlResultSet).toTaqiResultMsgStruct())
+		}
+	}
+	tam.sendTaqiNotify(cmd.SubscriberTopic, taqimsg.TaqiNotify{
+		SubscriptionID: subscriptionID,
+		Results:        results,
+	})
+}
+
+func (tam *Manager) HandleTaqiUnsubscribeRequest(cmd command.HandleSubscriptionRequest[taqimsg.TaqiUnsubscribeRequest]) {
+	tam.logger.Debug("Received TAQI_UNSUBSCRIBE_REQUEST command", "Subscription ID", cmd.Request.SubscriptionID, "Client", cmd.Sender)
+	subscriptionID := cmd.Request.SubscriptionID
+
+	sendErrorResponse := func(errMsg string) {
+		response := taqimsg.TaqiUnsubscribeResponse{
+			Error: &errMsg,
+		}
+		bytes, err := communication.BuildSubscriptionResponse(tam.config.Communication.TafEndpoint, messages.TAQI_UNSUBSCRIBE_RESPONSE, cmd.RequestID, response)
+		if err != nil {
+			tam.logger.Error("Error marshalling response", "error", err)
+			return
+		}
+		tam.outbox <- core.NewMessage(bytes, "", cmd.ResponseTopic)
 	}
-	response := taqimsg.TaqiResult{
-		Results: taqiResults,
+
+	//only the client that created a subscription is allowed to terminate it
+	subscription, exists := tam.taqiSubscriptions.get(subscriptionID)
+	if !exists || subscription.Client() != cmd.Sender {
+		sendErrorResponse("Unknown subscription with ID '" + subscriptionID + "'")
+		return
 	}
+	tam.taqiSubscriptions.remove(subscriptionID)
 
-	bytes, err := communication.BuildResponse(tam.config.Communication.TafEndpoint, messages.TAQI_RESULT, cmd.RequestID, response)
+	success := "Subscription with ID '" + subscriptionID + "' successfully terminated."
+	response := taqimsg.TaqiUnsubscribeResponse{
+		Success: &success,
+	}
+	bytes, err := communication.BuildSubscriptionResponse(tam.config.Communication.TafEndpoint, messages.TAQI_UNSUBSCRIBE_RESPONSE, cmd.RequestID, response)
 	if err != nil {
 		tam.logger.Error("Error marshalling response", "error", err)
 		return
 	}
 	tam.outbox <- core.NewMessage(bytes, "", cmd.ResponseTopic)
+	tam.logger.Debug("TAQI Subscription terminated", "Subscription ID", subscriptionID)
+}
+
+/*
+sendTaqiNotify sends a TAQI_NOTIFY message to the subscriber of a TAQI subscription.
+*/
+func (tam *Manager) sendTaqiNotify(subscriberTopic string, notify taqimsg.TaqiNotify) {
+	bytes, err := communication.BuildOneWayMessage(tam.config.Communication.TafEndpoint, messages.TAQI_NOTIFY, notify)
+	if err != nil {
+		tam.logger.Error("Error marshalling notification", "error", err)
+		return
+	}
+	tam.outbox <- core.NewMessage(bytes, "", subscriberTopic)
 }
 
 func (tam *Manager) HandleATLUpdate(cmd command.HandleATLUpdate) {
@@ -818,30 +1320,53 @@ func (tam *Manager) HandleATLUpdate(cmd command.HandleATLUpdate) {
 
 	//overwrite result cache with new values
 	tam.atlResults[cmd.FullTmiID] = cmd.ResultSet
+	tam.atlHistory.Record(cmd.FullTmiID, cmd.ResultSet)
+	tam.reflectedTags.record(cmd.FullTmiID, cmd.ResultSet.Tag())
+	tam.pendingRequests.reflectTag(cmd.FullTmiID, cmd.ResultSet.Tag())
+	tam.pendingRequests.handleResultSet(cmd.FullTmiID, cmd.ResultSet)
 	//TODO: make copies of both results and fill cache with new values *before* doing the subscription checks
 
 	tam.notifyATLUpdated(cmd.FullTmiID, oldATLResults, cmd.ResultSet)
 }
 
+/*
+HandleTMIUpdateApplied records the tag of an update that has been applied to a TMI without changing its results, so that
+requests waiting for this tag can be answered.
+*/
+func (tam *Manager) HandleTMIUpdateApplied(cmd command.HandleTMIUpdateApplied) {
+	_, sessionID, _, _ := core.SplitFullTMIIdentifier(cmd.FullTmiID)
+	if _, exists := tam.sessions[sessionID]; !exists {
+		return
+	}
+	tam.reflectedTags.record(cmd.FullTmiID, cmd.Tag)
+	tam.pendingRequests.reflectTag(cmd.FullTmiID, cmd.Tag)
+	tam.pendingRequests.handleResultSet(cmd.FullTmiID, tam.atlResults[cmd.FullTmiID])
+}
+
 func (tam *Manager) DispatchToWorker(session session.Session, tmiID string, cmd core.Command) {
 	id := core.MergeFullTMIIdentifier(session.Client(), session.ID(), session.TrustModelTemplate().Identifier(), tmiID)
 	tam.DispatchToWorkerByFullTMIID(id, cmd)
 }
 
 func (tam *Manager) DispatchToWorkerByFullTMIID(fullTMI string, cmd core.Command) {
-	workerId := tam.getShardWorkerById(fullTMI)
-	tam.tamToWorkers[workerId] <- cmd
-}
-
-// Get shard worker based on provided ID and configured number of shards
-func (tam *Manager) getShardWorkerById(stringID string) int {
-	algorithm := fnv.New32a()
-	_, err := algorithm.Write([]byte(stringID))
-	if err != nil {
-		return 0
-	} else {
-		id := int(algorithm.Sum32())
-		return id % tam.config.TAM.TrustModelInstanceShards
+	workerId, placed := tam.placement[fullTMI]
+	if !placed {
+		workerId = tam.getShardWorkerById(fullTMI)
+	}
+	switch cmd.(type) {
+	case command.HandleTMIInit:
+		tam.placement[fullTMI] = workerId
+	case command.HandleTMIDestroy:
+		delete(tam.placement, fullTMI)
+		delete(tam.dispatchCounts, fullTMI)
+	default:
+		if placed && tam.skewedChecks > 0 {
+			tam.dispatchCounts[fullTMI]++
+		}
+	}
+	tam.worker(workerId).queue <- cmd
+	if _, isDestroy := cmd.(command.HandleTMIDestroy); isDestroy {
+		tam.stopDrainedWorkers()
 	}
 }
 
@@ -877,7 +1402,7 @@ func (tam *Manager) AddNewTrustModelInstance(instance core.TrustModelInstance, s
 	//init TMI
 	fullTmiID := core.MergeFullTMIIdentifier(sess.Client(), sess.ID(), sess.TrustModelTemplate().Identifier(), instance.ID())
 
-	tmiInitCmd := command.CreateHandleTMIInit(fullTmiID, instance)
+	tmiInitCmd := command.CreateHandleTMIInit(fullTmiID, instance, sess.RTLs(tmiID))
 	tam.DispatchToWorker(sess, tmiID, tmiInitCmd)
 }
 
@@ -890,9 +1415,11 @@ func (tam *Manager) RemoveTrustModelInstance(fullTMIid string, sessionID string)
 	} else {
 		_, _, _, tmiID := core.SplitFullTMIIdentifier(fullTMIid)
 		tam.logger.Debug("Removing TMI from Session", "Session", sessionID, "TMI", fullTMIid)
-		tam.DispatchToWorker(sess, fullTMIid, command.CreateHandleTMIDestroy(fullTMIid))
+		tam.DispatchToWorkerByFullTMIID(fullTMIid, command.CreateHandleTMIDestroy(fullTMIid))
 		tam.tmiTable.UnregisterTMI(sess.Client(), sess.ID(), sess.TrustModelTemplate().Identifier(), tmiID)
 		delete(tam.atlResults, fullTMIid)
+		tam.atlHistory.Release(fullTMIid)
+		tam.reflectedTags.forget(fullTMIid)
 		tam.notifyATLRemoved(fullTMIid)
 		delete(sess.TrustModelInstances(), tmiID)
 	}
@@ -937,7 +1464,7 @@ func (tam *Manager) RemoveATLListener(listener listener.ActualTrustLevelListener
 }
 
 func (tam *Manager) notifyATLUpdated(fullTMI string, oldATLs core.AtlResultSet, newATLs core.AtlResultSet) {
-	if len(tam.sessionListeners) > 0 {
+	if len(tam.atlListeners) > 0 {
 		event := listener.NewATLUpdatedEvent(fullTMI, newATLs.Version(), oldATLs, newATLs)
 		for sessionListener := range tam.atlListeners {
 			sessionListener.OnATLUpdated(event)
@@ -946,7 +1473,7 @@ func (tam *Manager) notifyATLUpdated(fullTMI string, oldATLs core.AtlResultSet,
 }
 
 func (tam *Manager) notifyATLRemoved(fullTMI string) {
-	if len(tam.sessionListeners) > 0 {
+	if len(tam.atlListeners) > 0 {
 		event := listener.NewATLRemovedEvent(fullTMI)
 		for sessionListener := range tam.atlListeners {
 			sessionListener.OnATLRemoved(event)
@@ -0,0 +1,230 @@
+package trustassessment
+
+import (
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"time"
+)
+
+/*
+A ResultCondition decides whether a result set of a TMI is sufficient to answer a pending request.
+*/
+type ResultCondition func(resultSet core.AtlResultSet) bool
+
+/*
+A pendingRequest is a request that is held back by the TAM until the result sets of all its target TMIs fulfill the
+respective conditions, or until its deadline expires.
+*/
+type pendingRequest struct {
+	id string
+	//full TMI ID->condition that is not yet fulfilled; nil if only tags are awaited
+	conditions map[string]ResultCondition
+	//full TMI ID->required tags that have not been reflected yet
+	tags map[string]map[string]bool
+	//called once, either after all conditions have been fulfilled or after the deadline has expired
+	complete func(timedOut bool)
+	timer    *time.Timer
+}
+
+/*
+The pendingRequestRegistry keeps track of all pending requests of the TAM and indexes them by the TMIs they are waiting for.
+Like the rest of the TAM state, it must only be accessed from the TAM goroutine.
+*/
+type pendingRequestRegistry struct {
+	counter int
+	//request ID->request
+	requests map[string]*pendingRequest
+	//full TMI ID->request ID->request
+	requestsByTMI map[string]map[string]*pendingRequest
+	//used to schedule the expiry of a request; the passed function is called from another goroutine
+	schedule func(deadline time.Duration, requestID string) *time.Timer
+}
+
+func newPendingRequestRegistry(schedule func(deadline time.Duration, requestID string) *time.Timer) *pendingRequestRegistry {
+	return &pendingRequestRegistry{
+		counter:       0,
+		requests:      make(map[string]*pendingRequest),
+		requestsByTMI: make(map[string]map[string]*pendingRequest),
+		schedule:      schedule,
+	}
+}
+
+/*
+await registers a new pending request. Conditions that are already fulfilled by the current result sets and tags that
+have already been reflected should not be passed. The required tags of a request are tracked by the request itself until
+they are reflected, so that they are not affected by the limited size of the reflectedTagLog. If neither conditions nor
+tags are given, complete is called immediately.
+*/
+func (r *pendingRequestRegistry) await(conditions map[string]ResultCondition, tags map[string][]string, deadline time.Duration, complete func(timedOut bool)) {
+	if len(conditions) == 0 && len(tags) == 0 {
+		complete(false)
+		return
+	}
+	r.counter++
+	request := &pendingRequest{
+		id:         fmt.Sprintf("PENDING-%d", r.counter),
+		conditions: conditions,
+		tags:       make(map[string]map[string]bool),
+		complete:   complete,
+	}
+	for fullTmiID, required := range tags {
+		if _, exists := conditions[fullTmiID]; !exists {
+			conditions[fullTmiID] = nil
+		}
+		request.tags[fullTmiID] = make(map[string]bool)
+		for _, tag := range required {
+			request.tags[fullTmiID][tag] = true
+		}
+	}
+	r.requests[request.id] = request
+	for fullTmiID := range conditions {
+		if r.requestsByTMI[fullTmiID] == nil {
+			r.requestsByTMI[fullTmiID] = make(map[string]*pendingRequest)
+		}
+		r.requestsByTMI[fullTmiID][request.id] = request
+	}
+	request.timer = r.schedule(deadline, request.id)
+}
+
+/*
+handleResultSet checks a new result set of a TMI against all requests waiting for this TMI and completes the requests
+for which no conditions remain.
+*/
+func (r *pendingRequestRegistry) handleResultSet(fullTmiID string, resultSet core.AtlResultSet) {
+	waiting, exists := r.requestsByTMI[fullTmiID]
+	if !exists {
+		return
+	}
+	for requestID, request := range waiting {
+		if len(request.tags[fullTmiID]) > 0 {
+			continue
+		}
+		if condition := request.conditions[fullTmiID]; condition != nil && !condition(resultSet) {
+			continue
+		}
+		delete(request.conditions, fullTmiID)
+		delete(waiting, requestID)
+		if len(request.conditions) == 0 {
+			r.remove(request)
+			request.complete(false)
+		}
+	}
+	if len(waiting) == 0 {
+		delete(r.requestsByTMI, fullTmiID)
+	}
+}
+
+/*
+reflectTag marks a tag as reflected by a TMI for all requests waiting for this tag. handleResultSet has to be called
+afterwards to complete the requests whose conditions are fulfilled.
+*/
+func (r *pendingRequestRegistry) reflectTag(fullTmiID string, tag *string) {
+	if tag == nil {
+		return
+	}
+	for _, request := range r.requestsByTMI[fullTmiID] {
+		delete(request.tags[fullTmiID], *tag)
+	}
+}
+
+/*
+expire completes a request whose deadline has expired. Requests that have already been completed are ignored.
+*/
+func (r *pendingRequestRegistry) expire(requestID string) {
+	request, exists := r.requests[requestID]
+	if !exists {
+		return
+	}
+	r.remove(request)
+	request.complete(true)
+}
+
+func (r *pendingRequestRegistry) remove(request *pendingRequest) {
+	if request.timer != nil {
+		request.timer.Stop()
+	}
+	delete(r.requests, request.id)
+	for fullTmiID := range request.conditions {
+		if waiting, exists := r.requestsByTMI[fullTmiID]; exists {
+			delete(waiting, request.id)
+			if len(waiting) == 0 {
+				delete(r.requestsByTMI, fullTmiID)
+			}
+		}
+	}
+}
+
+/*
+reflectedTagsPerTMI limits the number of tags remembered per TMI.
+*/
+const reflectedTagsPerTMI = 64
+
+/*
+The reflectedTagLog keeps track of the latest tags that have been reflected by each TMI. As results only carry the tag
+of the latest update, this allows requirements on tags to be checked when a request arrives even if later updates have
+already been applied. Tags awaited by pending requests are tracked by the requests themselves.
+*/
+type reflectedTagLog struct {
+	//full TMI ID->latest tags, oldest first
+	tags map[string][]string
+}
+
+func newReflectedTagLog() *reflectedTagLog {
+	return &reflectedTagLog{
+		tags: make(map[string][]string),
+	}
+}
+
+func (l *reflectedTagLog) record(fullTmiID string, tag *string) {
+	if tag == nil {
+		return
+	}
+	tags := l.tags[fullTmiID]
+	if len(tags) > 0 && tags[len(tags)-1] == *tag {
+		return
+	}
+	if len(tags) == reflectedTagsPerTMI {
+		tags = tags[1:]
+	}
+	l.tags[fullTmiID] = append(tags, *tag)
+}
+
+func (l *reflectedTagLog) contains(fullTmiID string, tag string) bool {
+	for _, reflected := range l.tags[fullTmiID] {
+		if reflected == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *reflectedTagLog) forget(fullTmiID string) {
+	delete(l.tags, fullTmiID)
+}
+
+/*
+versionCondition creates a condition that is fulfilled once a TMI has reached the given version. If minVersion is nil,
+nil is returned.
+*/
+func versionCondition(minVersion *int64) ResultCondition {
+	if minVersion == nil {
+		return nil
+	}
+	return func(resultSet core.AtlResultSet) bool {
+		return int64(resultSet.Version()) >= *minVersion
+	}
+}
+
+/*
+allConditions combines conditions so that all of them have to be fulfilled. Nil conditions are skipped.
+*/
+func allConditions(conditions ...ResultCondition) ResultCondition {
+	return func(resultSet core.AtlResultSet) bool {
+		for _, condition := range conditions {
+			if condition != nil && !condition(resultSet) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,159 @@
+package trustassessment
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"testing"
+	"time"
+)
+
+func createPendingTestRegistry() (*pendingRequestRegistry, map[string]time.Duration) {
+	scheduled := make(map[string]time.Duration)
+	registry := newPendingRequestRegistry(func(deadline time.Duration, requestID string) *time.Timer {
+		scheduled[requestID] = deadline
+		return nil
+	})
+	return registry, scheduled
+}
+
+func minVersion(version int) ResultCondition {
+	return func(resultSet core.AtlResultSet) bool {
+		return resultSet.Version() >= version
+	}
+}
+
+func TestPendingRequestCompletion(t *testing.T) {
+	registry, scheduled := createPendingTestRegistry()
+	completions := make([]bool, 0)
+	registry.await(map[string]ResultCondition{
+		"//c/S/T@0.0.1/1": minVersion(2),
+		"//c/S/T@0.0.1/2": minVersion(1),
+	}, nil, 50*time.Millisecond, func(timedOut bool) {
+		completions = append(completions, timedOut)
+	})
+	if len(scheduled) != 1 {
+		t.Fatal("expected deadline to be scheduled")
+	}
+
+	registry.handleResultSet("//c/S/T@0.0.1/1", createTaqiResultSet("1", 1, nil, nil))
+	registry.handleResultSet("//c/S/T@0.0.1/2", createTaqiResultSet("2", 1, nil, nil))
+	if len(completions) != 0 {
+		t.Fatal("request must not complete before all conditions are fulfilled")
+	}
+	registry.handleResultSet("//c/S/T@0.0.1/1", createTaqiResultSet("1", 2, nil, nil))
+	if len(completions) != 1 || completions[0] {
+		t.Fatalf("expected request to complete without timeout, got %v", completions)
+	}
+
+	//late expiry and further updates must be ignored
+	for requestID := range scheduled {
+		registry.expire(requestID)
+	}
+	registry.handleResultSet("//c/S/T@0.0.1/1", createTaqiResultSet("1", 3, nil, nil))
+	if len(completions) != 1 || len(registry.requests) != 0 || len(registry.requestsByTMI) != 0 {
+		t.Error("completed request must be removed from the registry")
+	}
+}
+
+func TestPendingRequestTimeout(t *testing.T) {
+	registry, scheduled := createPendingTestRegistry()
+	completions := make([]bool, 0)
+	registry.await(map[string]ResultCondition{"//c/S/T@0.0.1/1": minVersion(5)}, nil, time.Second, func(timedOut bool) {
+		completions = append(completions, timedOut)
+	})
+	for requestID := range scheduled {
+		registry.expire(requestID)
+	}
+	if len(completions) != 1 || !completions[0] {
+		t.Fatalf("expected request to time out, got %v", completions)
+	}
+	registry.handleResultSet("//c/S/T@0.0.1/1", createTaqiResultSet("1", 5, nil, nil))
+	if len(completions) != 1 || len(registry.requestsByTMI) != 0 {
+		t.Error("expired request must not complete again")
+	}
+
+	//requests without open conditions complete immediately
+	registry.await(map[string]ResultCondition{}, nil, time.Second, func(timedOut bool) {
+		completions = append(completions, timedOut)
+	})
+	if len(completions) != 2 || completions[1] {
+		t.Error("expected request without conditions to complete immediately")
+	}
+}
+
+func TestRequirementConditions(t *testing.T) {
+	tags := newReflectedTagLog()
+	fullTmiID := "//c/S/T@0.0.1/1"
+	tagA, tagB := "A", "B"
+	minimum := int64(3)
+	condition := versionCondition(&minimum)
+
+	if versionCondition(nil) != nil {
+		t.Error("expected no condition without minimum version")
+	}
+	if condition(createTaqiResultSet("1", 2, nil, nil)) {
+		t.Error("version requirement must not be fulfilled by version 2")
+	}
+	if !condition(createTaqiResultSet("1", 3, nil, nil)) {
+		t.Error("expected version 3 to fulfill the requirement")
+	}
+
+	//later tags must not hide earlier ones
+	tags.record(fullTmiID, &tagA)
+	tags.record(fullTmiID, &tagB)
+	if !tags.contains(fullTmiID, tagA) {
+		t.Error("expected tag A to be reflected")
+	}
+
+	for i := 0; i < reflectedTagsPerTMI; i++ {
+		tag := string(rune('a' + i%26))
+		tags.record(fullTmiID, &tag)
+	}
+	if tags.contains(fullTmiID, tagA) || len(tags.tags[fullTmiID]) > reflectedTagsPerTMI {
+		t.Error("expected tag log to be bounded")
+	}
+	tags.forget(fullTmiID)
+	if tags.contains(fullTmiID, tagB) {
+		t.Error("forgotten tags must not fulfill requirements")
+	}
+}
+
+func TestPendingRequestTags(t *testing.T) {
+	registry, _ := createPendingTestRegistry()
+	fullTmiID := "//c/S/T@0.0.1/1"
+	tagA, tagB := "A", "B"
+	minimum := int64(3)
+	completions := make([]bool, 0)
+	registry.await(map[string]ResultCondition{fullTmiID: versionCondition(&minimum)}, map[string][]string{fullTmiID: {tagA}}, time.Second, func(timedOut bool) {
+		completions = append(completions, timedOut)
+	})
+
+	registry.reflectTag(fullTmiID, &tagB)
+	registry.handleResultSet(fullTmiID, createTaqiResultSet("1", 3, nil, nil))
+	if len(completions) != 0 {
+		t.Fatal("request must not complete before the required tag has been reflected")
+	}
+	registry.reflectTag(fullTmiID, &tagA)
+	registry.handleResultSet(fullTmiID, createTaqiResultSet("1", 2, nil, nil))
+	if len(completions) != 0 {
+		t.Fatal("request must not complete before the required version has been reached")
+	}
+	//the tag remains reflected for the request even if many later tags are applied before the version is reached
+	for i := 0; i < 2*reflectedTagsPerTMI; i++ {
+		tag := string(rune('a' + i%26))
+		registry.reflectTag(fullTmiID, &tag)
+	}
+	registry.handleResultSet(fullTmiID, createTaqiResultSet("1", 3, nil, nil))
+	if len(completions) != 1 || completions[0] || len(registry.requests) != 0 {
+		t.Fatalf("expected request to complete without timeout, got %v", completions)
+	}
+
+	//requests waiting only for tags complete once the tags have been reflected
+	registry.await(map[string]ResultCondition{}, map[string][]string{fullTmiID: {tagB}}, time.Second, func(timedOut bool) {
+		completions = append(completions, timedOut)
+	})
+	registry.reflectTag(fullTmiID, &tagB)
+	registry.handleResultSet(fullTmiID, createTaqiResultSet("1", 4, nil, nil))
+	if len(completions) != 2 || completions[1] || len(registry.requestsByTMI) != 0 {
+		t.Fatalf("expected tag-only request to complete, got %v", completions)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
 	taqimsg "github.com/horizon-connect-eu/go-taf/pkg/message/taqi"
 	tasmsg "github.com/horizon-connect-eu/go-taf/pkg/message/tas"
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"time"
 )
 
 /*
@@ -16,32 +18,41 @@ type ResultEntry struct {
 	Propositions []Proposition
 	tag          *string
 	version      int
+	timestamp    time.Time
 }
 
 type Proposition struct {
-	PropositionID string
-	ATL           subjectivelogic.QueryableOpinion
-	PP            float64
-	TrustDecision core.TrustDecision
+	PropositionID  string
+	ATL            subjectivelogic.QueryableOpinion
+	PP             float64
+	TrustDecision  core.TrustDecision
+	DecisionPolicy string                    //name of the decision policy, empty if unknown
+	Explanation    *core.DecisionExplanation //explanation of the trust decision, nil if explanations are disabled
 }
 
 /*
 The NewPropositionEntry function is a helper function that creates proposition structs based on an existing AtlResultSets and a proposition ID.
 */
 func NewPropositionEntry(set core.AtlResultSet, propositionID string) Proposition {
+	var explanation *core.DecisionExplanation
+	if entry, exists := set.Explanations()[propositionID]; exists {
+		explanation = &entry
+	}
 	return Proposition{
-		PropositionID: propositionID,
-		ATL:           set.ATLs()[propositionID],
-		PP:            set.ProjectedProbabilities()[propositionID],
-		TrustDecision: set.TrustDecisions()[propositionID],
+		PropositionID:  propositionID,
+		ATL:            set.ATLs()[propositionID],
+		PP:             set.ProjectedProbabilities()[propositionID],
+		TrustDecision:  set.TrustDecisions()[propositionID],
+		DecisionPolicy: set.DecisionPolicies()[propositionID],
+		Explanation:    explanation,
 	}
 }
 
 /*
 toMsgStruct takes an internal representation of a TMI/proposition result and converts into message struct auto-generated from JSON Schema.
-Result variant.
+Result variant. Explanations of trust decisions are only included if explain is true.
 */
-func (r ResultEntry) toTarResultMsgStruct() tasmsg.Result {
+func (r ResultEntry) toTarResultMsgStruct(explain bool) tasmsg.Result {
 
 	propositions := make([]tasmsg.ResultProposition, 0)
 
@@ -80,10 +91,30 @@ func (r ResultEntry) toTarResultMsgStruct() tasmsg.Result {
 			Type: tasmsg.ProjectedProbability,
 		})
 
+		if opinion, ok := proposition.ATL.(*multinomial.Opinion); ok {
+			atl = append(atl, tasmsg.FluffyActualTrustworthinessLevel{
+				Output: tasmsg.FluffyOutput{
+					BaseRates:              opinion.BaseRates(),
+					Beliefs:                opinion.Beliefs(),
+					Focus:                  opinion.Focus(),
+					ProjectedProbabilities: opinion.ProjectedProbabilities(),
+					Uncertainty:            &uncertainty,
+				},
+				Type: tasmsg.MultinomialOpinion,
+			})
+		}
+
+		var explanation *tasmsg.Explanation = nil
+		if explain && proposition.Explanation != nil {
+			explanation = toExplanationMsgStruct(*proposition.Explanation)
+		}
+
 		propositions = append(propositions, tasmsg.ResultProposition{
 			ActualTrustworthinessLevel: atl,
 			PropositionID:              proposition.PropositionID,
 			TrustDecision:              tdValue,
+			DecisionPolicy:             decisionPolicy(proposition),
+			Explanation:                explanation,
 		})
 	}
 
@@ -141,10 +172,24 @@ func (r ResultEntry) toUpdateMsgStruct() tasmsg.Update {
 			Type: tasmsg.ProjectedProbability,
 		})
 
+		if opinion, ok := proposition.ATL.(*multinomial.Opinion); ok {
+			atl = append(atl, tasmsg.PurpleActualTrustworthinessLevel{
+				Output: tasmsg.PurpleOutput{
+					BaseRates:              opinion.BaseRates(),
+					Beliefs:                opinion.Beliefs(),
+					Focus:                  opinion.Focus(),
+					ProjectedProbabilities: opinion.ProjectedProbabilities(),
+					Uncertainty:            &uncertainty,
+				},
+				Type: tasmsg.MultinomialOpinion,
+			})
+		}
+
 		propositions = append(propositions, tasmsg.UpdateProposition{
 			ActualTrustworthinessLevel: atl,
 			PropositionID:              proposition.PropositionID,
 			TrustDecision:              tdValue,
+			DecisionPolicy:             decisionPolicy(proposition),
 		})
 	}
 
@@ -161,7 +206,7 @@ func (r ResultEntry) toUpdateMsgStruct() tasmsg.Update {
 
 /*
 toTaqiResultMsgStruct takes an internal representation of a TMI/proposition result and converts into message struct auto-generated from JSON Schema.
-Taqi Result variant.
+Taqi Result variant. Propositions without an ATL, i.e., removed propositions, are converted with an empty list of ATLs.
 */
 func (r ResultEntry) toTaqiResultMsgStruct() taqimsg.Result {
 
@@ -179,6 +224,14 @@ func (r ResultEntry) toTaqiResultMsgStruct() taqimsg.Result {
 		}
 
 		atl := make([]taqimsg.ActualTrustworthinessLevel, 0)
+		if proposition.ATL == nil {
+			propositions = append(propositions, taqimsg.Proposition{
+				ActualTrustworthinessLevel: atl,
+				PropositionID:              proposition.PropositionID,
+				TrustDecision:              tdValue,
+			})
+			continue
+		}
 		baseRate := proposition.ATL.BaseRate()
 		belief := proposition.ATL.Belief()
 		disbelief := proposition.ATL.Disbelief()
@@ -202,6 +255,19 @@ func (r ResultEntry) toTaqiResultMsgStruct() taqimsg.Result {
 			Type: taqimsg.ProjectedProbability,
 		})
 
+		if opinion, ok := proposition.ATL.(*multinomial.Opinion); ok {
+			atl = append(atl, taqimsg.ActualTrustworthinessLevel{
+				Output: taqimsg.Output{
+					BaseRates:              opinion.BaseRates(),
+					Beliefs:                opinion.Beliefs(),
+					Focus:                  opinion.Focus(),
+					ProjectedProbabilities: opinion.ProjectedProbabilities(),
+					Uncertainty:            &uncertainty,
+				},
+				Type: taqimsg.MultinomialOpinion,
+			})
+		}
+
 		propositions = append(propositions, taqimsg.Proposition{
 			ActualTrustworthinessLevel: atl,
 			PropositionID:              proposition.PropositionID,
@@ -209,8 +275,83 @@ func (r ResultEntry) toTaqiResultMsgStruct() taqimsg.Result {
 		})
 	}
 
+	atlVersion := int64(r.version)
+	var timestamp *time.Time = nil
+	if !r.timestamp.IsZero() {
+		timestamp = &r.timestamp
+	}
+
 	return taqimsg.Result{
 		ID:           r.TmiID,
 		Propositions: propositions,
+		Tag:          r.tag,
+		Timestamp:    timestamp,
+		Version:      &atlVersion,
+	}
+}
+
+/*
+decisionPolicy returns the name of the decision policy of a proposition for message structs, omitting unknown policies.
+*/
+func decisionPolicy(proposition Proposition) *string {
+	if proposition.DecisionPolicy == "" {
+		return nil
+	}
+	return &proposition.DecisionPolicy
+}
+
+/*
+toExplanationMsgStruct converts the explanation of a trust decision into the message struct used in TAS_TA_RESPONSE messages.
+*/
+func toExplanationMsgStruct(explanation core.DecisionExplanation) *tasmsg.Explanation {
+	contributions := make([]tasmsg.Contribution, 0, len(explanation.Contributions))
+	for _, contribution := range explanation.Contributions {
+		provenance := make([]tasmsg.Provenance, 0, len(contribution.Provenance))
+		for _, entry := range contribution.Provenance {
+			var evidence map[string]interface{} = nil
+			if len(entry.Evidence) > 0 {
+				evidence = make(map[string]interface{}, len(entry.Evidence))
+				for evidenceType, value := range entry.Evidence {
+					evidence[evidenceType.String()] = value
+				}
+			}
+			var timestamp *string = nil
+			if !entry.Timestamp.IsZero() {
+				value := entry.Timestamp.Format(time.RFC3339Nano)
+				timestamp = &value
+			}
+			provenance = append(provenance, tasmsg.Provenance{
+				Evidence:    evidence,
+				Tag:         entry.Tag,
+				Timestamp:   timestamp,
+				TrustSource: entry.TrustSource.String(),
+			})
+		}
+		contributions = append(contributions, tasmsg.Contribution{
+			Opinion:    toOpinionMsgStruct(contribution.Opinion),
+			Provenance: provenance,
+			Trustee:    contribution.Trustee,
+			Trustor:    contribution.Trustor,
+		})
+	}
+	return &tasmsg.Explanation{
+		ATL:                     toOpinionMsgStruct(explanation.ATL),
+		ATLProjectedProbability: explanation.ATLProjectedProbability,
+		Contributions:           contributions,
+		RTL:                     toOpinionMsgStruct(explanation.RTL),
+		RTLProjectedProbability: explanation.RTLProjectedProbability,
+		Rule:                    explanation.Rule,
+	}
+}
+
+func toOpinionMsgStruct(opinion subjectivelogic.QueryableOpinion) tasmsg.Opinion {
+	if opinion == nil {
+		return tasmsg.Opinion{}
+	}
+	return tasmsg.Opinion{
+		BaseRate:    opinion.BaseRate(),
+		Belief:      opinion.Belief(),
+		Disbelief:   opinion.Disbelief(),
+		Uncertainty: opinion.Uncertainty(),
 	}
 }
@@ -0,0 +1,91 @@
+package trustassessment
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/command"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustdecision"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+/*
+rtlTestTMI is a minimal TMI with fixed RTLs; all other methods are not used by the TDE.
+*/
+type rtlTestTMI struct {
+	core.TrustModelInstance
+	rtls map[string]subjectivelogic.QueryableOpinion
+}
+
+func (tmi rtlTestTMI) ID() string                                        { return "vehicle_1" }
+func (tmi rtlTestTMI) Version() int                                      { return 3 }
+func (tmi rtlTestTMI) Template() core.TrustModelTemplate                 { return nil }
+func (tmi rtlTestTMI) RTLs() map[string]subjectivelogic.QueryableOpinion { return tmi.rtls }
+
+func createOpinion(belief float64, disbelief float64) subjectivelogic.QueryableOpinion {
+	opinion, _ := subjectivelogic.NewOpinion(belief, disbelief, 1-belief-disbelief, 0.5)
+	return &opinion
+}
+
+func TestWorkerRTLUpdate(t *testing.T) {
+	workersToTam := make(chan core.Command, 4)
+	fullTmiID := "//client/SES-1/T@0.0.1/vehicle_1"
+	worker := Worker{
+		logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		tmis:          make(map[string]core.TrustModelInstance),
+		tmiSessions:   make(map[string]string),
+		workersToTam:  workersToTam,
+		incoming:      make(map[string][]core.Command),
+		defaultPolicy: trustdecision.ProjectedProbabilityPolicy{},
+		results:       make(map[string]core.AtlResultSet),
+		rtls:          make(map[string]map[string]subjectivelogic.QueryableOpinion),
+	}
+	tmi := rtlTestTMI{rtls: map[string]subjectivelogic.QueryableOpinion{
+		"p1": createOpinion(0.5, 0),
+		"p2": createOpinion(0.5, 0),
+	}}
+	worker.tmis[fullTmiID] = tmi
+
+	//PP(ATL) = 0.8 exceeds PP(RTL) = 0.75 for both propositions
+	tag := "tag-1"
+	atls := map[string]subjectivelogic.QueryableOpinion{"p1": createOpinion(0.7, 0.1), "p2": createOpinion(0.7, 0.1)}
+	initial := worker.executeTDE(fullTmiID, tmi, &tag, atls)
+	if initial.TrustDecisions()["p1"] != core.TRUSTWORTHY || initial.TrustDecisions()["p2"] != core.TRUSTWORTHY {
+		t.Fatalf("unexpected initial decisions: %v", initial.TrustDecisions())
+	}
+
+	//Tightening the RTL of p1 changes its decision without touching the ATLs
+	worker.handle(command.CreateHandleRTLUpdate(fullTmiID, map[string]subjectivelogic.QueryableOpinion{"p1": createOpinion(0.9, 0)}))
+	update := (<-workersToTam).(command.HandleATLUpdate)
+	if update.ResultSet.TrustDecisions()["p1"] != core.NOT_TRUSTWORTHY || update.ResultSet.TrustDecisions()["p2"] != core.TRUSTWORTHY {
+		t.Errorf("unexpected decisions after RTL update: %v", update.ResultSet.TrustDecisions())
+	}
+	if update.ResultSet.Version() != 3 || update.ResultSet.Tag() == nil || *update.ResultSet.Tag() != tag {
+		t.Errorf("expected version and tag of the previous results, got %d and %v", update.ResultSet.Version(), update.ResultSet.Tag())
+	}
+	if update.ResultSet.ATLs()["p1"] != atls["p1"] {
+		t.Error("expected ATLs of the previous results")
+	}
+
+	//An override for all propositions applies to propositions without an override of their own
+	worker.handle(command.CreateHandleRTLUpdate(fullTmiID, map[string]subjectivelogic.QueryableOpinion{"": createOpinion(0.9, 0)}))
+	update = (<-workersToTam).(command.HandleATLUpdate)
+	if update.ResultSet.TrustDecisions()["p1"] != core.NOT_TRUSTWORTHY || update.ResultSet.TrustDecisions()["p2"] != core.NOT_TRUSTWORTHY {
+		t.Errorf("unexpected decisions after RTL update for all propositions: %v", update.ResultSet.TrustDecisions())
+	}
+
+	//Removing all overrides restores the RTLs of the TMI
+	worker.handle(command.CreateHandleRTLUpdate(fullTmiID, nil))
+	update = (<-workersToTam).(command.HandleATLUpdate)
+	if update.ResultSet.TrustDecisions()["p1"] != core.TRUSTWORTHY || update.ResultSet.TrustDecisions()["p2"] != core.TRUSTWORTHY {
+		t.Errorf("unexpected decisions after removing overrides: %v", update.ResultSet.TrustDecisions())
+	}
+
+	//RTL updates for TMIs without results are only recorded
+	otherTmiID := "//client/SES-1/T@0.0.1/vehicle_2"
+	worker.handle(command.CreateHandleRTLUpdate(otherTmiID, map[string]subjectivelogic.QueryableOpinion{"": createOpinion(0.9, 0)}))
+	if len(workersToTam) != 0 || worker.rtls[otherTmiID] == nil {
+		t.Error("expected RTL override to be recorded without results")
+	}
+}
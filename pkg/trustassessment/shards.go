@@ -0,0 +1,401 @@
+package trustassessment
+
+import (
+	"errors"
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/command"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+var ErrInvalidWorkerCount = errors.New("invalid worker count")
+
+/*
+WorkerStats contains load metrics of a worker. The metrics are written by the worker goroutine and may be read
+concurrently.
+*/
+type WorkerStats struct {
+	processed atomic.Uint64
+	busyNanos atomic.Int64
+	tmis      atomic.Int64
+}
+
+func (s *WorkerStats) record(commands int, busy time.Duration, tmis int) {
+	s.processed.Add(uint64(commands))
+	s.busyNanos.Add(int64(busy))
+	s.tmis.Store(int64(tmis))
+}
+
+/*
+A workerHandle is the TAM-side representation of a running worker.
+*/
+type workerHandle struct {
+	id       int
+	queue    chan core.Command
+	stats    *WorkerStats
+	retiring bool
+}
+
+/*
+A migration of a TMI from one worker to another that has been started, but not yet completed.
+*/
+type migration struct {
+	source int
+	target int
+}
+
+/*
+A tmiMove is a single migration proposed by planRebalance.
+*/
+type tmiMove struct {
+	FullTmiID string
+	Target    int
+}
+
+/*
+spawnWorker starts a new worker and adds it to the list of workers.
+*/
+func (tam *Manager) spawnWorker() *workerHandle {
+	handle := &workerHandle{
+		id:    tam.nextWorkerID,
+		queue: make(chan core.Command, tam.config.ChanBufSize),
+	}
+	tam.nextWorkerID++
+	worker := tam.SpawnNewWorker(handle.id, handle.queue, tam.workersToTam, tam.tafContext, tam.tmiListeners)
+	handle.stats = worker.stats
+	tam.workersLock.Lock()
+	tam.workers = append(tam.workers, handle)
+	tam.workersLock.Unlock()
+	go worker.Run()
+	return handle
+}
+
+func (tam *Manager) worker(id int) *workerHandle {
+	for _, handle := range tam.workers {
+		if handle.id == id {
+			return handle
+		}
+	}
+	return nil
+}
+
+/*
+activeWorkerIDs returns the IDs of all workers that are not retiring.
+*/
+func (tam *Manager) activeWorkerIDs() []int {
+	ids := make([]int, 0, len(tam.workers))
+	for _, handle := range tam.workers {
+		if !handle.retiring {
+			ids = append(ids, handle.id)
+		}
+	}
+	return ids
+}
+
+// Get shard worker based on provided ID and the currently active workers
+func (tam *Manager) getShardWorkerById(stringID string) int {
+	active := tam.activeWorkerIDs()
+	algorithm := fnv.New32a()
+	_, err := algorithm.Write([]byte(stringID))
+	if err != nil {
+		return active[0]
+	} else {
+		id := int(algorithm.Sum32())
+		return active[id%len(active)]
+	}
+}
+
+/*
+placedTMIs returns the number of TMIs placed on each of the given workers.
+*/
+func (tam *Manager) placedTMIs(workerIDs []int) map[int]int {
+	counts := make(map[int]int)
+	for _, id := range workerIDs {
+		counts[id] = 0
+	}
+	for _, id := range tam.placement {
+		if _, exists := counts[id]; exists {
+			counts[id]++
+		}
+	}
+	return counts
+}
+
+/*
+leastLoadedWorker returns the active worker with the fewest placed TMIs.
+*/
+func (tam *Manager) leastLoadedWorker() int {
+	active := tam.activeWorkerIDs()
+	counts := tam.placedTMIs(active)
+	target := active[0]
+	for _, id := range active {
+		if counts[id] < counts[target] {
+			target = id
+		}
+	}
+	return target
+}
+
+/*
+migrateTMI moves a TMI to another worker. The target is prepared first so that it buffers all commands for the TMI
+dispatched from now on. The source then releases the TMI after having processed all commands dispatched before; the TMI
+is handed over to the target once the TAM receives the released TMI (see HandleTMIReleased).
+*/
+func (tam *Manager) migrateTMI(fullTmiID string, target int) {
+	source, placed := tam.placement[fullTmiID]
+	if !placed || source == target {
+		return
+	}
+	if _, inFlight := tam.migrating[fullTmiID]; inFlight {
+		return
+	}
+	tam.logger.Debug("Migrating Trust Model Instance", "TMI", fullTmiID, "Source Worker", source, "Target Worker", target)
+	tam.worker(target).queue <- command.CreateHandleTMIMigrationPrepare(fullTmiID)
+	tam.worker(source).queue <- command.CreateHandleTMIMigrationRelease(fullTmiID)
+	tam.placement[fullTmiID] = target
+	tam.migrating[fullTmiID] = migration{source: source, target: target}
+}
+
+/*
+HandleTMIReleased completes a migration by handing over the released TMI to its target worker.
+*/
+func (tam *Manager) HandleTMIReleased(cmd command.HandleTMIReleased) {
+	ongoing, exists := tam.migrating[cmd.FullTmiID]
+	if !exists {
+		tam.logger.Warn("Released TMI without ongoing migration", "TMI", cmd.FullTmiID)
+		return
+	}
+	delete(tam.migrating, cmd.FullTmiID)
+	target := tam.worker(ongoing.target)
+	target.queue <- command.CreateHandleTMIMigrationAdopt(cmd.FullTmiID, cmd.TMI, cmd.State)
+	//The target may have been retired while the TMI was in flight
+	if target.retiring && tam.placement[cmd.FullTmiID] == target.id {
+		tam.migrateTMI(cmd.FullTmiID, tam.leastLoadedWorker())
+	}
+	tam.stopDrainedWorkers()
+}
+
+/*
+ScaleWorkers changes the number of workers at runtime. The change is applied asynchronously by the TAM goroutine.
+*/
+func (tam *Manager) ScaleWorkers(count int) error {
+	if count < 1 {
+		return fmt.Errorf("%w: %d", ErrInvalidWorkerCount, count)
+	}
+	tam.DispatchToSelf(command.CreateHandleScaleWorkers(count))
+	return nil
+}
+
+func (tam *Manager) handleScaleWorkers(cmd command.HandleScaleWorkers) {
+	active := tam.activeWorkerIDs()
+	tam.logger.Info("Scaling workers", "Current Worker Count", len(active), "Requested Worker Count", cmd.Count)
+	switch {
+	case cmd.Count > len(active):
+		for range cmd.Count - len(active) {
+			tam.spawnWorker()
+		}
+		tam.applyMoves(planRebalance(tam.unmigratingPlacement(), nil, tam.activeWorkerIDs(), 0))
+	case cmd.Count < len(active):
+		//Retire the most recently spawned workers
+		tam.workersLock.Lock()
+		for _, id := range active[cmd.Count:] {
+			tam.worker(id).retiring = true
+		}
+		tam.workersLock.Unlock()
+		for fullTmiID, id := range tam.unmigratingPlacement() {
+			if tam.worker(id).retiring {
+				tam.migrateTMI(fullTmiID, tam.leastLoadedWorker())
+			}
+		}
+		tam.stopDrainedWorkers()
+	}
+}
+
+/*
+stopDrainedWorkers stops all retiring workers that neither hold TMIs nor take part in an ongoing migration.
+*/
+func (tam *Manager) stopDrainedWorkers() {
+	busy := make(map[int]bool)
+	for _, id := range tam.placement {
+		busy[id] = true
+	}
+	for _, ongoing := range tam.migrating {
+		busy[ongoing.source] = true
+		busy[ongoing.target] = true
+	}
+	drained := make([]*workerHandle, 0)
+	tam.workersLock.Lock()
+	tam.workers = slices.DeleteFunc(tam.workers, func(handle *workerHandle) bool {
+		if !handle.retiring || busy[handle.id] {
+			return false
+		}
+		drained = append(drained, handle)
+		return true
+	})
+	tam.workersLock.Unlock()
+	for _, handle := range drained {
+		tam.logger.Info("Stopping drained worker", "Worker", handle.id)
+		handle.queue <- command.CreateHandleWorkerStop()
+	}
+}
+
+/*
+unmigratingPlacement returns the placement of all TMIs that are currently not being migrated.
+*/
+func (tam *Manager) unmigratingPlacement() map[string]int {
+	placement := make(map[string]int, len(tam.placement))
+	for fullTmiID, id := range tam.placement {
+		if _, inFlight := tam.migrating[fullTmiID]; !inFlight {
+			placement[fullTmiID] = id
+		}
+	}
+	return placement
+}
+
+func (tam *Manager) applyMoves(moves []tmiMove) {
+	for _, move := range moves {
+		tam.migrateTMI(move.FullTmiID, move.Target)
+	}
+}
+
+/*
+planRebalance greedily plans migrations of TMIs between workers so that the load of the workers is evened out. The load
+of a worker is the sum of the weights of its TMIs; if weights is nil, each TMI has a weight of 1 and TMIs without weight
+are never moved otherwise. In each step, the heaviest TMI of the most loaded worker that still reduces the load difference
+to the least loaded worker is moved. maxMoves limits the number of migrations (0 for no limit).
+*/
+func planRebalance(placement map[string]int, weights map[string]uint64, workers []int, maxMoves int) []tmiMove {
+	weight := func(fullTmiID string) uint64 {
+		if weights == nil {
+			return 1
+		}
+		return weights[fullTmiID]
+	}
+	loads := make(map[int]uint64)
+	tmisByWorker := make(map[int][]string)
+	for _, id := range workers {
+		loads[id] = 0
+	}
+	for fullTmiID, id := range placement {
+		if _, exists := loads[id]; !exists {
+			continue
+		}
+		loads[id] += weight(fullTmiID)
+		tmisByWorker[id] = append(tmisByWorker[id], fullTmiID)
+	}
+	for _, tmis := range tmisByWorker {
+		//heaviest first, ties broken by ID for deterministic plans
+		sort.Slice(tmis, func(i, j int) bool {
+			if weight(tmis[i]) != weight(tmis[j]) {
+				return weight(tmis[i]) > weight(tmis[j])
+			}
+			return tmis[i] < tmis[j]
+		})
+	}
+
+	moves := make([]tmiMove, 0)
+	for len(workers) > 1 && (maxMoves <= 0 || len(moves) < maxMoves) {
+		maxWorker, minWorker := workers[0], workers[0]
+		for _, id := range workers {
+			if loads[id] > loads[maxWorker] {
+				maxWorker = id
+			}
+			if loads[id] < loads[minWorker] {
+				minWorker = id
+			}
+		}
+		difference := loads[maxWorker] - loads[minWorker]
+		candidates := tmisByWorker[maxWorker]
+		index := slices.IndexFunc(candidates, func(fullTmiID string) bool {
+			return weight(fullTmiID) > 0 && weight(fullTmiID) < difference
+		})
+		if index < 0 {
+			break
+		}
+		fullTmiID := candidates[index]
+		tmisByWorker[maxWorker] = slices.Delete(candidates, index, index+1)
+		loads[maxWorker] -= weight(fullTmiID)
+		loads[minWorker] += weight(fullTmiID)
+		moves = append(moves, tmiMove{FullTmiID: fullTmiID, Target: minWorker})
+	}
+	return moves
+}
+
+/*
+runRebalancer periodically triggers a check of the worker load in the TAM goroutine.
+*/
+func (tam *Manager) runRebalancer() {
+	ticker := time.NewTicker(time.Duration(tam.config.TAM.Rebalancing.CheckInterval) * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-tam.tafContext.Context.Done():
+			return
+		case <-ticker.C:
+			select {
+			case tam.channels.TAMChannel <- command.CreateHandleRebalanceCheck():
+			case <-tam.tafContext.Context.Done():
+				return
+			}
+		}
+	}
+}
+
+/*
+handleRebalanceCheck checks whether the queue depths of the active workers are skewed. If the load has been skewed for
+the configured number of consecutive checks, TMIs are migrated based on the number of commands dispatched to them since
+the load became skewed.
+*/
+func (tam *Manager) handleRebalanceCheck() {
+	rebalancing := tam.config.TAM.Rebalancing
+	active := tam.activeWorkerIDs()
+	if len(active) < 2 {
+		return
+	}
+	maxDepth, totalDepth := 0, 0
+	for _, id := range active {
+		depth := len(tam.worker(id).queue)
+		totalDepth += depth
+		maxDepth = max(maxDepth, depth)
+	}
+	mean := float64(totalDepth) / float64(len(active))
+	if maxDepth < rebalancing.MinQueueDepth || float64(maxDepth) < rebalancing.SkewFactor*mean {
+		tam.skewedChecks = 0
+		clear(tam.dispatchCounts)
+		return
+	}
+	tam.skewedChecks++
+	if tam.skewedChecks < rebalancing.SkewedChecks {
+		return
+	}
+	moves := planRebalance(tam.unmigratingPlacement(), tam.dispatchCounts, active, rebalancing.MaxMigrations)
+	tam.logger.Info("Rebalancing skewed workers", "Max Queue Depth", maxDepth, "Mean Queue Depth", mean, "Migrations", len(moves))
+	tam.applyMoves(moves)
+	tam.skewedChecks = 0
+	clear(tam.dispatchCounts)
+}
+
+/*
+WorkerLoad returns load metrics of all current workers. It may be called from any goroutine.
+*/
+func (tam *Manager) WorkerLoad() []core.WorkerLoad {
+	tam.workersLock.RLock()
+	defer tam.workersLock.RUnlock()
+	loads := make([]core.WorkerLoad, 0, len(tam.workers))
+	for _, handle := range tam.workers {
+		loads = append(loads, core.WorkerLoad{
+			WorkerID:            handle.id,
+			QueueDepth:          len(handle.queue),
+			QueueCapacity:       cap(handle.queue),
+			TrustModelInstances: int(handle.stats.tmis.Load()),
+			ProcessedCommands:   handle.stats.processed.Load(),
+			BusyTime:            time.Duration(handle.stats.busyNanos.Load()),
+			Retiring:            handle.retiring,
+		})
+	}
+	return loads
+}
@@ -0,0 +1,104 @@
+package trustassessment
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/command"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func countByWorker(placement map[string]int, moves []tmiMove) map[int]int {
+	final := make(map[string]int)
+	for fullTmiID, id := range placement {
+		final[fullTmiID] = id
+	}
+	for _, move := range moves {
+		final[move.FullTmiID] = move.Target
+	}
+	counts := make(map[int]int)
+	for _, id := range final {
+		counts[id]++
+	}
+	return counts
+}
+
+func TestPlanRebalanceByCount(t *testing.T) {
+	placement := map[string]int{
+		"//c/s/T/1": 0, "//c/s/T/2": 0, "//c/s/T/3": 0, "//c/s/T/4": 0, "//c/s/T/5": 0, "//c/s/T/6": 0,
+	}
+	moves := planRebalance(placement, nil, []int{0, 1, 2}, 0)
+	if len(moves) != 4 {
+		t.Errorf("expected 4 migrations, got %d", len(moves))
+	}
+	for id, count := range countByWorker(placement, moves) {
+		if count != 2 {
+			t.Errorf("expected 2 TMIs on worker %d, got %d", id, count)
+		}
+	}
+
+	if moves := planRebalance(placement, nil, []int{0, 1, 2}, 1); len(moves) != 1 {
+		t.Errorf("expected number of migrations to be limited to 1, got %d", len(moves))
+	}
+	if moves := planRebalance(placement, nil, []int{0}, 0); len(moves) != 0 {
+		t.Error("expected no migrations for a single worker")
+	}
+}
+
+func TestPlanRebalanceByWeight(t *testing.T) {
+	placement := map[string]int{
+		"//c/s/T/hot":   0,
+		"//c/s/T/warm":  0,
+		"//c/s/T/idle":  0,
+		"//c/s/T/other": 1,
+	}
+	weights := map[string]uint64{
+		"//c/s/T/hot":   100,
+		"//c/s/T/warm":  30,
+		"//c/s/T/other": 90,
+	}
+	moves := planRebalance(placement, weights, []int{0, 1}, 0)
+	//Moving the hot TMI would only shift the skew to the other worker, so only the warm TMI is migrated
+	if len(moves) != 1 || moves[0].FullTmiID != "//c/s/T/warm" || moves[0].Target != 1 {
+		t.Errorf("expected only the warm TMI to be migrated, got %+v", moves)
+	}
+
+	//TMIs without load are never migrated
+	if moves := planRebalance(map[string]int{"//c/s/T/idle": 0}, weights, []int{0, 1}, 0); len(moves) != 0 {
+		t.Errorf("expected no migrations, got %+v", moves)
+	}
+}
+
+func TestWorkerMigrationBuffering(t *testing.T) {
+	workersToTam := make(chan core.Command, 4)
+	worker := Worker{
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		tmis:         make(map[string]core.TrustModelInstance),
+		tmiSessions:  make(map[string]string),
+		workersToTam: workersToTam,
+		incoming:     make(map[string][]core.Command),
+	}
+	fullTmiID := "//client/SES-1/T@0.0.1/vehicle_1"
+
+	//Releasing an unknown TMI hands back nil
+	worker.handle(command.CreateHandleTMIMigrationRelease(fullTmiID))
+	released := (<-workersToTam).(command.HandleTMIReleased)
+	if released.FullTmiID != fullTmiID || released.TMI != nil {
+		t.Errorf("unexpected released TMI: %+v", released)
+	}
+
+	worker.handle(command.CreateHandleTMIMigrationPrepare(fullTmiID))
+	worker.handle(command.CreateHandleTMIUpdate(fullTmiID, nil))
+	worker.handle(command.CreateHandleTMIDestroy(fullTmiID))
+	if len(worker.incoming[fullTmiID]) != 2 {
+		t.Fatalf("expected 2 buffered commands, got %d", len(worker.incoming[fullTmiID]))
+	}
+
+	worker.handle(command.CreateHandleTMIMigrationAdopt(fullTmiID, nil, command.TMIWorkerState{}))
+	if _, exists := worker.incoming[fullTmiID]; exists {
+		t.Error("expected buffer to be dropped after adopting a non-existing TMI")
+	}
+	if len(workersToTam) != 0 {
+		t.Error("expected no messages to the TAM for dropped commands")
+	}
+}
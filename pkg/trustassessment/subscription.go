@@ -2,6 +2,7 @@ package trustassessment
 
 import (
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
 	"math"
 )
@@ -150,6 +151,12 @@ const precision float64 = 0.000000000001
 // areIdenticalSubjectiveLogicOpinions compares two subjective logic opinions to determine if they are numerically identical.
 // It evaluates beliefs, disbeliefs, uncertainties, and base rates using a precision threshold for floating-point comparisons.
 func areIdenticalSubjectiveLogicOpinions(opinion1 subjectivelogic.QueryableOpinion, opinion2 subjectivelogic.QueryableOpinion) bool {
+	multinomial1, isMultinomial1 := opinion1.(*multinomial.Opinion)
+	multinomial2, isMultinomial2 := opinion2.(*multinomial.Opinion)
+	if isMultinomial1 || isMultinomial2 {
+		//changes within the focus or non-focus values do not show in the binomial view of multinomial opinions
+		return isMultinomial1 && isMultinomial2 && multinomial1.Equal(multinomial2, precision)
+	}
 	return math.Abs(opinion1.Belief()-opinion2.Belief()) < precision &&
 		math.Abs(opinion1.Disbelief()-opinion2.Disbelief()) < precision &&
 		math.Abs(opinion1.Uncertainty()-opinion2.Uncertainty()) < precision &&
@@ -0,0 +1,220 @@
+package trustassessment
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/listener"
+	taqimsg "github.com/horizon-connect-eu/go-taf/pkg/message/taqi"
+	"sort"
+)
+
+/*
+A TaqiSubscription represents a TAQI subscription of a client. In contrast to a TAS subscription, it is not bound to a
+session, but selects TMIs of all sessions by template, identifier pattern and propositions.
+*/
+type TaqiSubscription struct {
+	subscriptionID  string
+	client          string
+	subscriberTopic string
+	template        string
+	identifier      segmentPattern
+	//empty map -> all propositions
+	propositions map[string]struct{}
+	trigger      Trigger
+}
+
+/*
+NewTaqiSubscription creates a new TAQI subscription. The identifier may either be an exact TMI identifier or a
+pattern as supported by TMI queries ('*', 'prefix*', '*suffix'). An error wrapping ErrInvalidTMIQuery is returned for
+malformed patterns.
+*/
+func NewTaqiSubscription(subscriptionID string, client string, subscriberTopic string, template string, identifier string, propositions []string, trigger Trigger) (*TaqiSubscription, error) {
+	patterns, err := parseTMIQuery("//*/*/" + template + "/" + identifier)
+	if err != nil {
+		return nil, err
+	}
+	propositionMap := make(map[string]struct{})
+	for _, proposition := range propositions {
+		propositionMap[proposition] = struct{}{}
+	}
+	return &TaqiSubscription{
+		subscriptionID:  subscriptionID,
+		client:          client,
+		subscriberTopic: subscriberTopic,
+		template:        template,
+		identifier:      patterns[3],
+		propositions:    propositionMap,
+		trigger:         trigger,
+	}, nil
+}
+
+func (s *TaqiSubscription) SubscriptionID() string {
+	return s.subscriptionID
+}
+
+func (s *TaqiSubscription) Client() string {
+	return s.client
+}
+
+func (s *TaqiSubscription) SubscriberTopic() string {
+	return s.subscriberTopic
+}
+
+/*
+Query returns the TMI query expression selecting all TMIs covered by the subscription.
+*/
+func (s *TaqiSubscription) Query() string {
+	identifier := s.identifier.value
+	switch {
+	case s.identifier.wildcard:
+		identifier = tmiQueryWildcard
+	case s.identifier.prefix:
+		identifier = s.identifier.value + tmiQueryWildcard
+	case s.identifier.suffix:
+		identifier = tmiQueryWildcard + s.identifier.value
+	}
+	return "//*/*/" + s.template + "/" + identifier
+}
+
+/*
+Matches checks whether a TMI, given by its full identifier, is covered by the subscription.
+*/
+func (s *TaqiSubscription) Matches(fullTmiID string) bool {
+	_, _, tmtID, tmiID := core.SplitFullTMIIdentifier(fullTmiID)
+	return tmtID == s.template && s.identifier.matches(tmiID)
+}
+
+/*
+includesProposition checks whether a proposition is selected by the subscription.
+*/
+func (s *TaqiSubscription) includesProposition(propositionID string) bool {
+	if len(s.propositions) == 0 {
+		return true
+	}
+	_, exists := s.propositions[propositionID]
+	return exists
+}
+
+/*
+HandleUpdate checks whether an ATL update of a matching TMI is relevant for the subscription according to its trigger
+and the selected propositions. If so, the result entry to be included in a TAQI_NOTIFY is returned. Selected
+propositions of the previous result set that no longer exist are always relevant and included without an ATL.
+*/
+func (s *TaqiSubscription) HandleUpdate(fullTmiID string, oldATLs core.AtlResultSet, newATLs core.AtlResultSet) (ResultEntry, bool) {
+	if !s.Matches(fullTmiID) {
+		return ResultEntry{}, false
+	}
+	changed := false
+	for propositionID, newOpinion := range newATLs.ATLs() {
+		if !s.includesProposition(propositionID) {
+			continue
+		}
+		oldOpinion, exists := oldATLs.ATLs()[propositionID]
+		if !exists {
+			changed = true
+		} else if s.trigger == TRUST_DECISION {
+			changed = oldATLs.TrustDecisions()[propositionID] != newATLs.TrustDecisions()[propositionID]
+		} else {
+			changed = !areIdenticalSubjectiveLogicOpinions(oldOpinion, newOpinion)
+		}
+		if changed {
+			break
+		}
+	}
+	removedPropositionIDs := make([]string, 0)
+	for propositionID := range oldATLs.ATLs() {
+		if _, exists := newATLs.ATLs()[propositionID]; !exists && s.includesProposition(propositionID) {
+			removedPropositionIDs = append(removedPropositionIDs, propositionID)
+		}
+	}
+	if !changed && len(removedPropositionIDs) == 0 {
+		return ResultEntry{}, false
+	}
+	result := s.resultEntry(fullTmiID, newATLs)
+	sort.Strings(removedPropositionIDs)
+	for _, propositionID := range removedPropositionIDs {
+		result.Propositions = append(result.Propositions, Proposition{
+			PropositionID: propositionID,
+			TrustDecision: core.UNDECIDABLE,
+		})
+	}
+	return result, true
+}
+
+/*
+resultEntry converts a result set into a result entry that only contains the selected propositions.
+*/
+func (s *TaqiSubscription) resultEntry(fullTmiID string, resultSet core.AtlResultSet) ResultEntry {
+	propositionIDs := make([]string, 0, len(resultSet.ATLs()))
+	for propositionID := range resultSet.ATLs() {
+		if s.includesProposition(propositionID) {
+			propositionIDs = append(propositionIDs, propositionID)
+		}
+	}
+	sort.Strings(propositionIDs)
+	propositions := make([]Proposition, 0, len(propositionIDs))
+	for _, propositionID := range propositionIDs {
+		propositions = append(propositions, NewPropositionEntry(resultSet, propositionID))
+	}
+	_, _, _, tmiID := core.SplitFullTMIIdentifier(fullTmiID)
+	return ResultEntry{
+		TmiID:        tmiID,
+		Propositions: propositions,
+		version:      resultSet.Version(),
+		tag:          resultSet.Tag(),
+		timestamp:    resultSet.Timestamp(),
+	}
+}
+
+/*
+The taqiSubscriptionRegistry keeps track of all TAQI subscriptions. It is registered as an ActualTrustLevelListener at
+the TAM and hands TAQI_NOTIFY messages for matching ATL changes to the send function, which is called with the
+subscriber topic of the respective subscription.
+*/
+type taqiSubscriptionRegistry struct {
+	//sub ID->subscription
+	subscriptions map[string]*TaqiSubscription
+	send          func(subscriberTopic string, notify taqimsg.TaqiNotify)
+}
+
+func newTaqiSubscriptionRegistry(send func(subscriberTopic string, notify taqimsg.TaqiNotify)) *taqiSubscriptionRegistry {
+	return &taqiSubscriptionRegistry{
+		subscriptions: make(map[string]*TaqiSubscription),
+		send:          send,
+	}
+}
+
+func (r *taqiSubscriptionRegistry) add(subscription *TaqiSubscription) {
+	r.subscriptions[subscription.SubscriptionID()] = subscription
+}
+
+func (r *taqiSubscriptionRegistry) get(subscriptionID string) (*TaqiSubscription, bool) {
+	subscription, exists := r.subscriptions[subscriptionID]
+	return subscription, exists
+}
+
+func (r *taqiSubscriptionRegistry) remove(subscriptionID string) {
+	delete(r.subscriptions, subscriptionID)
+}
+
+func (r *taqiSubscriptionRegistry) OnATLUpdated(event listener.ATLUpdatedEvent) {
+	for subscriptionID, subscription := range r.subscriptions {
+		if result, relevant := subscription.HandleUpdate(event.FullTMI, event.OldATLs, event.NewATLs); relevant {
+			r.send(subscription.SubscriberTopic(), taqimsg.TaqiNotify{
+				SubscriptionID: subscriptionID,
+				Results:        []taqimsg.Result{result.toTaqiResultMsgStruct()},
+			})
+		}
+	}
+}
+
+func (r *taqiSubscriptionRegistry) OnATLRemoved(event listener.ATLRemovedEvent) {
+	_, _, _, tmiID := core.SplitFullTMIIdentifier(event.FullTMI)
+	for subscriptionID, subscription := range r.subscriptions {
+		if subscription.Matches(event.FullTMI) {
+			r.send(subscription.SubscriberTopic(), taqimsg.TaqiNotify{
+				SubscriptionID: subscriptionID,
+				Removed:        []string{tmiID},
+			})
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package trustassessment
+
+import (
+	"errors"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/listener"
+	taqimsg "github.com/horizon-connect-eu/go-taf/pkg/message/taqi"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"testing"
+	"time"
+)
+
+func createTaqiResultSet(tmiID string, version int, beliefs map[string]float64, decisions map[string]core.TrustDecision) core.AtlResultSet {
+	atls := make(map[string]subjectivelogic.QueryableOpinion)
+	pps := make(map[string]float64)
+	for propositionID, belief := range beliefs {
+		opinion, _ := subjectivelogic.NewOpinion(belief, 0, 1-belief, 0.5)
+		atls[propositionID] = &opinion
+		pps[propositionID] = opinion.ProjectedProbability()
+	}
+	return core.CreateAtlResultSet(tmiID, version, nil, time.Now(), atls, pps, decisions)
+}
+
+func TestTaqiSubscriptionMatching(t *testing.T) {
+	subscription, err := NewTaqiSubscription("SUB-1", "hmi", "taf-hmi", "IMA@0.0.1", "vehicle_*", nil, ACTUAL_TRUSTWORTHINESS_LEVEL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subscription.Query() != "//*/*/IMA@0.0.1/vehicle_*" {
+		t.Errorf("unexpected query for subscription: %s", subscription.Query())
+	}
+	tests := map[string]bool{
+		"//clientA/SES-1/IMA@0.0.1/vehicle_1":  true,
+		"//clientB/SES-2/IMA@0.0.1/vehicle_42": true,
+		"//clientA/SES-1/IMA@0.0.2/vehicle_1":  false,
+		"//clientA/SES-1/IMA@0.0.1/rsu_1":      false,
+	}
+	for fullTmiID, expected := range tests {
+		if subscription.Matches(fullTmiID) != expected {
+			t.Errorf("expected match of %s to be %v", fullTmiID, expected)
+		}
+	}
+
+	if _, err := NewTaqiSubscription("SUB-2", "hmi", "taf-hmi", "IMA@0.0.1", "ve*cle", nil, TRUST_DECISION); !errors.Is(err, ErrInvalidTMIQuery) {
+		t.Error("expected invalid identifier pattern to be rejected")
+	}
+}
+
+func TestTaqiSubscriptionTriggers(t *testing.T) {
+	fullTmiID := "//client/SES-1/IMA@0.0.1/vehicle_1"
+	old := createTaqiResultSet("vehicle_1", 1, map[string]float64{"A": 0.2, "B": 0.2}, map[string]core.TrustDecision{"A": core.NOT_TRUSTWORTHY, "B": core.NOT_TRUSTWORTHY})
+	//ATL of A and B change, but only the trust decision of B changes
+	updated := createTaqiResultSet("vehicle_1", 2, map[string]float64{"A": 0.3, "B": 0.9}, map[string]core.TrustDecision{"A": core.NOT_TRUSTWORTHY, "B": core.TRUSTWORTHY})
+
+	atlSubscription, _ := NewTaqiSubscription("SUB-1", "hmi", "taf-hmi", "IMA@0.0.1", "*", []string{"A"}, ACTUAL_TRUSTWORTHINESS_LEVEL)
+	result, relevant := atlSubscription.HandleUpdate(fullTmiID, old, updated)
+	if !relevant {
+		t.Fatal("expected ATL change of A to be relevant")
+	}
+	if len(result.Propositions) != 1 || result.Propositions[0].PropositionID != "A" || result.TmiID != "vehicle_1" {
+		t.Errorf("expected result to only contain proposition A of vehicle_1, got %+v", result)
+	}
+
+	tdSubscriptionA, _ := NewTaqiSubscription("SUB-2", "hmi", "taf-hmi", "IMA@0.0.1", "*", []string{"A"}, TRUST_DECISION)
+	if _, relevant := tdSubscriptionA.HandleUpdate(fullTmiID, old, updated); relevant {
+		t.Error("unchanged trust decision of A must not be relevant")
+	}
+	tdSubscriptionAll, _ := NewTaqiSubscription("SUB-3", "hmi", "taf-hmi", "IMA@0.0.1", "*", nil, TRUST_DECISION)
+	if result, relevant := tdSubscriptionAll.HandleUpdate(fullTmiID, old, updated); !relevant || len(result.Propositions) != 2 {
+		t.Error("expected changed trust decision of B to be relevant and all propositions to be included")
+	}
+	if _, relevant := tdSubscriptionAll.HandleUpdate(fullTmiID, core.AtlResultSet{}, updated); !relevant {
+		t.Error("first result of a TMI must always be relevant")
+	}
+}
+
+func TestTaqiSubscriptionRemovedPropositions(t *testing.T) {
+	fullTmiID := "//client/SES-1/IMA@0.0.1/vehicle_1"
+	old := createTaqiResultSet("vehicle_1", 1, map[string]float64{"A": 0.2, "B": 0.2}, map[string]core.TrustDecision{"A": core.NOT_TRUSTWORTHY, "B": core.NOT_TRUSTWORTHY})
+	//B disappears, A remains unchanged
+	updated := createTaqiResultSet("vehicle_1", 2, map[string]float64{"A": 0.2}, map[string]core.TrustDecision{"A": core.NOT_TRUSTWORTHY})
+
+	subscriptionA, _ := NewTaqiSubscription("SUB-1", "hmi", "taf-hmi", "IMA@0.0.1", "*", []string{"A"}, TRUST_DECISION)
+	if _, relevant := subscriptionA.HandleUpdate(fullTmiID, old, updated); relevant {
+		t.Error("removal of a proposition not selected by the subscription must not be relevant")
+	}
+
+	subscriptionAll, _ := NewTaqiSubscription("SUB-2", "hmi", "taf-hmi", "IMA@0.0.1", "*", nil, TRUST_DECISION)
+	result, relevant := subscriptionAll.HandleUpdate(fullTmiID, old, updated)
+	if !relevant {
+		t.Fatal("expected removal of B to be relevant")
+	}
+	msg := result.toTaqiResultMsgStruct()
+	if len(msg.Propositions) != 2 || msg.Propositions[1].PropositionID != "B" {
+		t.Fatalf("expected result to contain A and the removed proposition B, got %+v", msg.Propositions)
+	}
+	if len(msg.Propositions[1].ActualTrustworthinessLevel) != 0 || msg.Propositions[1].TrustDecision != nil {
+		t.Errorf("expected removed proposition without ATL and trust decision, got %+v", msg.Propositions[1])
+	}
+}
+
+func TestTaqiSubscriptionRegistry(t *testing.T) {
+	sent := make(map[string][]taqimsg.TaqiNotify)
+	registry := newTaqiSubscriptionRegistry(func(subscriberTopic string, notify taqimsg.TaqiNotify) {
+		sent[subscriberTopic] = append(sent[subscriberTopic], notify)
+	})
+	first, _ := NewTaqiSubscription("SUB-1", "hmi", "taf-hmi", "IMA@0.0.1", "*", nil, ACTUAL_TRUSTWORTHINESS_LEVEL)
+	second, _ := NewTaqiSubscription("SUB-2", "dashboard", "taf-dashboard", "IMA@0.0.1", "rsu_*", nil, ACTUAL_TRUSTWORTHINESS_LEVEL)
+	registry.add(first)
+	registry.add(second)
+
+	fullTmiID := "//client/SES-1/IMA@0.0.1/vehicle_1"
+	resultSet := createTaqiResultSet("vehicle_1", 1, map[string]float64{"A": 0.5}, map[string]core.TrustDecision{"A": core.TRUSTWORTHY})
+	registry.OnATLUpdated(listener.NewATLUpdatedEvent(fullTmiID, 1, core.AtlResultSet{}, resultSet))
+	registry.OnATLRemoved(listener.NewATLRemovedEvent(fullTmiID))
+
+	if len(sent["taf-dashboard"]) != 0 {
+		t.Error("non-matching subscription must not be notified")
+	}
+	if len(sent["taf-hmi"]) != 2 {
+		t.Fatalf("expected update and removal notification, got %d notifications", len(sent["taf-hmi"]))
+	}
+	if update := sent["taf-hmi"][0]; update.SubscriptionID != "SUB-1" || len(update.Results) != 1 || *update.Results[0].Version != 1 {
+		t.Errorf("unexpected update notification: %+v", update)
+	}
+	if removal := sent["taf-hmi"][1]; len(removal.Removed) != 1 || removal.Removed[0] != "vehicle_1" {
+		t.Errorf("unexpected removal notification: %+v", removal)
+	}
+
+	registry.remove("SUB-1")
+	registry.OnATLRemoved(listener.NewATLRemovedEvent(fullTmiID))
+	if len(sent["taf-hmi"]) != 2 {
+		t.Error("removed subscription must not be notified")
+	}
+}
@@ -9,10 +9,10 @@ import (
 	"github.com/horizon-connect-eu/go-taf/pkg/listener"
 	internaltlee "github.com/horizon-connect-eu/go-taf/pkg/tlee"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustdecision"
-	actualtlee "github.com/horizon-connect-eu/tlee-implementation/pkg/core"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
 	"github.com/vs-uulm/taf-tlee-interface/pkg/tleeinterface"
 	"log/slog"
+	"time"
 )
 
 /*
@@ -30,33 +30,66 @@ type Worker struct {
 	//tmiID->SessionID
 	tmiSessions  map[string]string
 	workersToTam chan<- core.Command
-	tlee         tleeinterface.TLEE
-	tmiListeners map[listener.TrustModelInstanceListener]bool
+	//TLEE backend key->TLEE instance of this worker
+	tlees          map[string]tleeinterface.TLEE
+	defaultBackend string
+	tmiListeners   map[listener.TrustModelInstanceListener]bool
+	stats          *WorkerStats
+	//full tmiID->commands received while the TMI is migrated to this worker
+	incoming     map[string][]core.Command
+	batchSize    int
+	batchLatency time.Duration
+	//decision policy for propositions without a policy declared by their template
+	defaultPolicy core.DecisionPolicy
+	//full tmiID->latest result set, used for the previous trust decisions and to re-evaluate decisions on RTL updates
+	results map[string]core.AtlResultSet
+	//full tmiID->proposition->RTL overriding the RTL of the TMI ("" for all propositions)
+	rtls    map[string]map[string]subjectivelogic.QueryableOpinion
+	explain bool
+	//full tmiID->provenance of the latest opinion per trust relationship and trust source, only if explain is set
+	provenance map[string][]core.TrustProvenance
+	//interval in which opinions are aged, 0 if aging is disabled
+	agingInterval time.Duration
+	//aging of opinions from trust sources whose aging is not declared by the template of a TMI
+	sourceAging map[core.TrustSource]core.OpinionAging
+	//full tmiID->latest opinion per trust relationship and trust source that is subject to aging
+	aging map[string][]core.AgingOpinion
 }
 
 /*
 SpawnNewWorker creates a new worker. The worker receives a channel for commands from the TAM and a channel to send back
-results to the TAM. The worker also receives a reference to the TLEE instance to be used for calculations.
+results to the TAM. TLEE instances are created lazily for each backend used by the TMIs of the worker.
 */
 func (tam *Manager) SpawnNewWorker(id int, workerQueue <-chan core.Command, workersToTam chan<- core.Command, tafContext core.TafContext, tmiListeners map[listener.TrustModelInstanceListener]bool) Worker {
 
 	tafConfig := tafContext.Configuration
-	var tlee tleeinterface.TLEE
+	defaultBackend := tafConfig.TLEE.Backend
 	if tafConfig.TLEE.UseInternalTLEE {
-		tlee = internaltlee.SpawnNewTLEE(logger.CreateChildLogger(tafContext.Logger, fmt.Sprintf("INTERNAL-TLEE-%d", id)), tafConfig.TLEE.FilePath, tafConfig.TLEE.DebuggingMode)
-	} else {
-		tlee = actualtlee.SpawnNewTLEE(logger.CreateChildLogger(tafContext.Logger, fmt.Sprintf("TLEE-%d", id)), tafConfig.TLEE.FilePath, tafConfig.TLEE.DebuggingMode)
+		defaultBackend = internaltlee.InternalBackend
 	}
 	return Worker{
-		tafContext:   tafContext,
-		id:           id,
-		workerQueue:  workerQueue,
-		logger:       logger.CreateChildLogger(tafContext.Logger, fmt.Sprintf("TAM-WORKER-%d", id)),
-		tmis:         make(map[string]core.TrustModelInstance),
-		tmiSessions:  make(map[string]string),
-		workersToTam: workersToTam,
-		tlee:         tlee,
-		tmiListeners: tmiListeners,
+		tafContext:     tafContext,
+		id:             id,
+		workerQueue:    workerQueue,
+		logger:         logger.CreateChildLogger(tafContext.Logger, fmt.Sprintf("TAM-WORKER-%d", id)),
+		tmis:           make(map[string]core.TrustModelInstance),
+		tmiSessions:    make(map[string]string),
+		workersToTam:   workersToTam,
+		tlees:          make(map[string]tleeinterface.TLEE),
+		defaultBackend: defaultBackend,
+		tmiListeners:   tmiListeners,
+		stats:          &WorkerStats{},
+		incoming:       make(map[string][]core.Command),
+		batchSize:      max(tafConfig.TAM.Batching.Size, 1),
+		batchLatency:   time.Duration(tafConfig.TAM.Batching.MaxLatency) * time.Millisecond,
+		defaultPolicy:  tam.defaultPolicy,
+		results:        make(map[string]core.AtlResultSet),
+		rtls:           make(map[string]map[string]subjectivelogic.QueryableOpinion),
+		explain:        tafConfig.TDE.Explanations,
+		provenance:     make(map[string][]core.TrustProvenance),
+		agingInterval:  time.Duration(tafConfig.TAM.Aging.CheckInterval) * time.Millisecond,
+		sourceAging:    createSourceAging(tafConfig.TAM.Aging),
+		aging:          make(map[string][]core.AgingOpinion),
 	}
 }
 
@@ -65,6 +98,13 @@ func (worker *Worker) Run() {
 		worker.logger.Info("Shutting down")
 	}()
 
+	var agingTicks <-chan time.Time
+	if worker.agingInterval > 0 {
+		ticker := time.NewTicker(worker.agingInterval)
+		defer ticker.Stop()
+		agingTicks = ticker.C
+	}
+
 	for {
 		// Each iteration, check whether we've been cancelled.
 		if err := context.Cause(worker.tafContext.Context); err != nil {
@@ -77,25 +117,116 @@ func (worker *Worker) Run() {
 			}
 			return
 		case incomingCmd := <-worker.workerQueue:
-			switch cmd := incomingCmd.(type) {
-			case command.HandleTMIInit:
-				worker.handleTMIInit(cmd)
-			case command.HandleTMIUpdate:
-				worker.handleTMIUpdate(cmd)
-			case command.HandleTMIDestroy:
-				worker.handleTMIDestroy(cmd)
-			default:
-				worker.logger.Warn("Command with no associated handling logic received by Worker", "Command Type", cmd.Type())
+			batch := worker.collectBatch(incomingCmd)
+			start := time.Now()
+			for _, cmd := range coalesceBatch(batch) {
+				if _, stop := cmd.(command.HandleWorkerStop); stop {
+					return
+				}
+				worker.handle(cmd)
 			}
+			worker.stats.record(len(batch), time.Since(start), len(worker.tmis))
+		case now := <-agingTicks:
+			worker.ageOpinions(now)
 		}
 	}
 }
 
+func (worker *Worker) handle(incomingCmd core.Command) {
+	switch cmd := incomingCmd.(type) {
+	case command.HandleTMIInit:
+		if !worker.bufferIfIncoming(cmd.FullTmiID, cmd) {
+			worker.handleTMIInit(cmd)
+		}
+	case command.HandleTMIUpdate:
+		if !worker.bufferIfIncoming(cmd.FullTmiID, cmd) {
+			worker.handleTMIUpdate(cmd)
+		}
+	case command.HandleTMIDestroy:
+		if !worker.bufferIfIncoming(cmd.FullTmiID, cmd) {
+			worker.handleTMIDestroy(cmd)
+		}
+	case command.HandleRTLUpdate:
+		if !worker.bufferIfIncoming(cmd.FullTmiID, cmd) {
+			worker.handleRTLUpdate(cmd)
+		}
+	case command.HandleTMIMigrationPrepare:
+		worker.incoming[cmd.FullTmiID] = make([]core.Command, 0)
+	case command.HandleTMIMigrationRelease:
+		worker.handleTMIMigrationRelease(cmd)
+	case command.HandleTMIMigrationAdopt:
+		worker.handleTMIMigrationAdopt(cmd)
+	default:
+		worker.logger.Warn("Command with no associated handling logic received by Worker", "Command Type", cmd.Type())
+	}
+}
+
+/*
+bufferIfIncoming holds back commands for a TMI that is currently migrated to this worker and has not yet arrived.
+*/
+func (worker *Worker) bufferIfIncoming(fullTmiID string, cmd core.Command) bool {
+	buffered, isIncoming := worker.incoming[fullTmiID]
+	if isIncoming {
+		worker.incoming[fullTmiID] = append(buffered, cmd)
+	}
+	return isIncoming
+}
+
+func (worker *Worker) handleTMIMigrationRelease(cmd command.HandleTMIMigrationRelease) {
+	tmi, exists := worker.tmis[cmd.FullTmiID]
+	state := command.TMIWorkerState{
+		Provenance: worker.provenance[cmd.FullTmiID],
+		RTLs:       worker.rtls[cmd.FullTmiID],
+		Aging:      worker.aging[cmd.FullTmiID],
+	}
+	if results, hasResults := worker.results[cmd.FullTmiID]; hasResults {
+		state.Results = &results
+	}
+	if exists {
+		worker.logger.Debug("Releasing Trust Model Instance for migration", "TMI", cmd.FullTmiID)
+		delete(worker.tmis, cmd.FullTmiID)
+		delete(worker.tmiSessions, cmd.FullTmiID)
+		worker.forgetTMIState(cmd.FullTmiID)
+	}
+	worker.workersToTam <- command.CreateHandleTMIReleased(cmd.FullTmiID, tmi, state)
+}
+
+func (worker *Worker) handleTMIMigrationAdopt(cmd command.HandleTMIMigrationAdopt) {
+	buffered := worker.incoming[cmd.FullTmiID]
+	delete(worker.incoming, cmd.FullTmiID)
+	if cmd.TMI == nil {
+		worker.logger.Debug("Migrated Trust Model Instance no longer exists; dropping buffered commands", "TMI", cmd.FullTmiID, "Commands", len(buffered))
+		return
+	}
+	worker.logger.Debug("Adopting migrated Trust Model Instance", "TMI", cmd.FullTmiID, "Buffered Commands", len(buffered))
+	worker.tmis[cmd.FullTmiID] = cmd.TMI
+	_, session, _, _ := core.SplitFullTMIIdentifier(cmd.FullTmiID)
+	worker.tmiSessions[cmd.FullTmiID] = session
+	if cmd.State.Results != nil {
+		worker.results[cmd.FullTmiID] = *cmd.State.Results
+	}
+	if cmd.State.Provenance != nil {
+		worker.provenance[cmd.FullTmiID] = cmd.State.Provenance
+	}
+	if cmd.State.RTLs != nil {
+		worker.rtls[cmd.FullTmiID] = cmd.State.RTLs
+	}
+	if cmd.State.Aging != nil {
+		worker.aging[cmd.FullTmiID] = cmd.State.Aging
+	}
+	for _, bufferedCmd := range buffered {
+		worker.handle(bufferedCmd)
+	}
+}
+
 func (worker *Worker) handleTMIInit(cmd command.HandleTMIInit) {
 	worker.logger.Info("Registering new Trust Model Instance with ID " + cmd.FullTmiID)
 	worker.tmis[cmd.FullTmiID] = cmd.TMI
 	_, session, _, _ := core.SplitFullTMIIdentifier(cmd.FullTmiID)
 	worker.tmiSessions[cmd.FullTmiID] = session
+	if cmd.RTLs != nil {
+		worker.rtls[cmd.FullTmiID] = cmd.RTLs
+	}
 
 	worker.notifyTMISpawned(cmd.FullTmiID, cmd.TMI)
 
@@ -125,14 +256,19 @@ func (worker *Worker) handleTMIUpdate(cmd command.HandleTMIUpdate) {
 	//(Batch-)Execute TMI Updates
 	runTlee := false
 	for _, update := range cmd.Updates {
-		if tmi.Update(update) {
+		if worker.explain {
+			worker.recordProvenance(cmd.FullTmiID, update, cmd.Tag)
+		}
+		applied := tmi.Update(update)
+		worker.trackAging(cmd.FullTmiID, tmi, update, applied)
+		if applied {
 			runTlee = true
 			worker.notifyTMIUpdated(cmd.FullTmiID, tmi, update)
 		}
 	}
 
-	//Only run TLEE if the trust model has indicated change.
-	if runTlee {
+	//Only run TLEE if the trust model has indicated change or a fresh result has been explicitly requested.
+	if runTlee || cmd.ForceEvaluation {
 		//Run TLEE
 		atls, err := worker.executeTLEE(cmd.FullTmiID, tmi)
 
@@ -145,11 +281,25 @@ func (worker *Worker) handleTMIUpdate(cmd command.HandleTMIUpdate) {
 
 			atlUpdateCmd := command.CreateHandleATLUpdate(resultSet, cmd.Tag, cmd.FullTmiID)
 			worker.workersToTam <- atlUpdateCmd
+			worker.reportPrecedingTags(cmd, tmi)
 		}
+	} else if cmd.Tag != nil {
+		//Let the TAM know that the tag has been reflected, although there is no new result
+		worker.workersToTam <- command.CreateHandleTMIUpdateApplied(cmd.FullTmiID, tmi.Version(), cmd.Tag)
+		worker.reportPrecedingTags(cmd, tmi)
 	}
 
 }
 
+/*
+reportPrecedingTags lets the TAM know that the tags of coalesced updates have been reflected as well.
+*/
+func (worker *Worker) reportPrecedingTags(cmd command.HandleTMIUpdate, tmi core.TrustModelInstance) {
+	for _, tag := range cmd.PrecedingTags {
+		worker.workersToTam <- command.CreateHandleTMIUpdateApplied(cmd.FullTmiID, tmi.Version(), &tag)
+	}
+}
+
 func (worker *Worker) handleTMIDestroy(cmd command.HandleTMIDestroy) {
 	worker.logger.Info("Deleting Trust Model Instance with ID " + cmd.FullTmiID)
 	tmi, exists := worker.tmis[cmd.FullTmiID]
@@ -160,16 +310,51 @@ func (worker *Worker) handleTMIDestroy(cmd command.HandleTMIDestroy) {
 	tmi.Cleanup()
 	delete(worker.tmis, cmd.FullTmiID)
 	delete(worker.tmiSessions, cmd.FullTmiID)
+	worker.forgetTMIState(cmd.FullTmiID)
 	worker.notifyTMIDeleted(cmd.FullTmiID)
 	//TODO: potential concurrency bug: send ATL update to wipe cache entry
 }
 
+/*
+handleRTLUpdate replaces the RTL overrides of a TMI and re-evaluates its trust decisions against the latest ATLs. The TLEE
+is not called, hence the version and tag of the results remain unchanged.
+*/
+func (worker *Worker) handleRTLUpdate(cmd command.HandleRTLUpdate) {
+	if len(cmd.RTLs) > 0 {
+		worker.rtls[cmd.FullTmiID] = cmd.RTLs
+	} else {
+		delete(worker.rtls, cmd.FullTmiID)
+	}
+	tmi, exists := worker.tmis[cmd.FullTmiID]
+	previous, hasResults := worker.results[cmd.FullTmiID]
+	if !exists || !hasResults {
+		return
+	}
+	worker.logger.Info("Re-evaluating trust decisions of Trust Model Instance with ID " + cmd.FullTmiID + " after RTL update")
+	resultSet := worker.executeTDE(cmd.FullTmiID, tmi, previous.Tag(), previous.ATLs())
+	worker.workersToTam <- command.CreateHandleATLUpdate(resultSet, previous.Tag(), cmd.FullTmiID)
+}
+
+/*
+forgetTMIState drops all state of a TMI that is kept by this worker besides the TMI itself.
+*/
+func (worker *Worker) forgetTMIState(fullTmiID string) {
+	delete(worker.results, fullTmiID)
+	delete(worker.rtls, fullTmiID)
+	delete(worker.provenance, fullTmiID)
+	delete(worker.aging, fullTmiID)
+	worker.forgetTLEEResults(fullTmiID)
+}
+
 func (worker *Worker) executeTLEE(fullTmiId string, tmi core.TrustModelInstance) (map[string]subjectivelogic.QueryableOpinion, error) {
 	var atls map[string]subjectivelogic.QueryableOpinion
 	//Only call TLEE when the graph structure is existing and not empty; otherwise skip and return empty ATL set
 	if tmi.Structure() != nil && len(tmi.Structure().AdjacencyList()) > 0 { //TODO: Values?
-		var err error
-		atls, err = worker.tlee.RunTLEE(fullTmiId, tmi.Version(), tmi.Fingerprint(), tmi.Structure(), tmi.Values())
+		tlee, err := worker.tleeFor(tmi)
+		if err != nil {
+			return nil, err
+		}
+		atls, err = tlee.RunTLEE(fullTmiId, tmi.Version(), tmi.Fingerprint(), tmi.Structure(), tmi.Values())
 		worker.logger.Debug("TLEE called", "Results", fmt.Sprintf("%+v", atls))
 		if err != nil {
 			return nil, err
@@ -181,24 +366,105 @@ func (worker *Worker) executeTLEE(fullTmiId string, tmi core.TrustModelInstance)
 	return atls, nil
 }
 
+/*
+tleeFor returns the TLEE instance of this worker for the backend declared by the template of a TMI. Instances are shared
+between all TMIs using the same backend and options.
+*/
+func (worker *Worker) tleeFor(tmi core.TrustModelInstance) (tleeinterface.TLEE, error) {
+	name, options := worker.defaultBackend, map[string]string(nil)
+	if provider, ok := tmi.Template().(core.TLEEBackendProvider); ok {
+		if declaredName, declaredOptions := provider.TLEEBackend(); declaredName != "" {
+			name, options = declaredName, declaredOptions
+		}
+	}
+	key := internaltlee.BackendKey(name, options)
+	if tlee, exists := worker.tlees[key]; exists {
+		return tlee, nil
+	}
+	tafConfig := worker.tafContext.Configuration
+	tlee, err := internaltlee.NewBackend(name, logger.CreateChildLogger(worker.tafContext.Logger, fmt.Sprintf("TLEE-%s-%d", name, worker.id)), internaltlee.BackendConfig{
+		FilePath:      tafConfig.TLEE.FilePath,
+		DebuggingMode: tafConfig.TLEE.DebuggingMode,
+		Options:       options,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tafConfig.TLEE.Incremental && internaltlee.HasCapability(name, internaltlee.IndependentScopes) {
+		tlee = internaltlee.NewIncrementalTLEE(tlee)
+	}
+	worker.logger.Info("Created TLEE instance", "Backend", key)
+	worker.tlees[key] = tlee
+	return tlee, nil
+}
+
+/*
+forgetTLEEResults drops cached TLEE results of a TMI that is no longer handled by this worker.
+*/
+func (worker *Worker) forgetTLEEResults(fullTmiID string) {
+	for _, tlee := range worker.tlees {
+		if incremental, ok := tlee.(*internaltlee.IncrementalTLEE); ok {
+			incremental.Forget(fullTmiID)
+		}
+	}
+}
+
 func (worker *Worker) executeTDE(fullTmiId string, tmi core.TrustModelInstance, tag *string, atls map[string]subjectivelogic.QueryableOpinion) core.AtlResultSet {
 	rtls := tmi.RTLs()
+	overrides := worker.rtls[fullTmiId]
+	previousDecisions := worker.results[fullTmiId].TrustDecisions()
 	projectedProbabilities := make(map[string]float64, len(atls))
 	trustDecisions := make(map[string]core.TrustDecision, len(atls))
+	policies := make(map[string]string, len(atls))
+	var explanations map[string]core.DecisionExplanation
+	if worker.explain {
+		explanations = make(map[string]core.DecisionExplanation, len(atls))
+	}
 	for proposition, atlOpinion := range atls {
-		rtlOpinion, exists := rtls[proposition]
+		rtlOpinion, exists := overrides[proposition]
+		if !exists {
+			rtlOpinion, exists = overrides[""]
+		}
+		if !exists {
+			rtlOpinion, exists = rtls[proposition]
+		}
 		if !exists {
 			worker.logger.Error("Could not find RTL in trust model instance for proposition "+proposition, "TMI ID", fullTmiId)
 			trustDecisions[proposition] = core.UNDECIDABLE //If no RTL is found, we set trust decision to UNDECIDABLE as default
 		} else {
-			trustDecisions[proposition] = trustdecision.Decide(atlOpinion, rtlOpinion)
+			policy := worker.policyFor(tmi, proposition)
+			var previous *core.TrustDecision
+			if decision, exists := previousDecisions[proposition]; exists {
+				previous = &decision
+			}
+			trustDecisions[proposition] = policy.Decide(atlOpinion, rtlOpinion, previous)
+			policies[proposition] = policy.Name()
+			if explanations != nil {
+				explanations[proposition] = explainDecision(tmi, proposition, atlOpinion, rtlOpinion, trustDecisions[proposition], policy, worker.provenance[fullTmiId])
+			}
 		}
 		projectedProbabilities[proposition] = trustdecision.ProjectProbability(atlOpinion)
 	}
-	resultSet := core.CreateAtlResultSet(tmi.ID(), tmi.Version(), tag, atls, projectedProbabilities, trustDecisions)
+	resultSet := core.CreateAtlResultSet(tmi.ID(), tmi.Version(), tag, time.Now(), atls, projectedProbabilities, trustDecisions).
+		WithDecisionPolicies(policies).
+		WithExplanations(explanations)
+	worker.results[fullTmiId] = resultSet
 	return resultSet
 }
 
+/*
+policyFor returns the decision policy for a proposition of a TMI, which is either declared by the template of the TMI or
+the default policy.
+*/
+func (worker *Worker) policyFor(tmi core.TrustModelInstance, proposition string) core.DecisionPolicy {
+	if provider, ok := tmi.Template().(core.DecisionPolicyProvider); ok {
+		if policy := provider.DecisionPolicy(proposition); policy != nil {
+			return policy
+		}
+	}
+	return worker.defaultPolicy
+}
+
 func (worker *Worker) notifyTMISpawned(FullTmiID string, tmi core.TrustModelInstance) {
 	if len(worker.tmiListeners) > 0 {
 		event := listener.NewTrustModelInstanceSpawnedEvent(tmi.ID(),
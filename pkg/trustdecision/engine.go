@@ -6,24 +6,18 @@ import (
 )
 
 /*
-Decide produces the final core.TrustDecision based on the actual and requested trust levels. There are three potential
-results: core.TRUSTWORTHY, core.NOT_TRUSTWORTHY, and core.UNDECIDABLE.
-The latter one is used in case uncertainty is too high to decide upon the trustworthiness.
+Decide produces the final core.TrustDecision based on the actual and requested trust levels using the
+ProjectedProbabilityPolicy. There are three potential results: core.TRUSTWORTHY, core.NOT_TRUSTWORTHY, and
+core.UNDECIDABLE. The latter one is used in case uncertainty is too high to decide upon the trustworthiness.
 */
 func Decide(atl subjectivelogic.QueryableOpinion, rtl subjectivelogic.QueryableOpinion) core.TrustDecision {
-	if atl.Uncertainty() == 1 {
-		return core.UNDECIDABLE
-	} else {
-		var probabilisticAtl = ProjectProbability(atl)
-		var probabilisticRtl = ProjectProbability(rtl)
-		if probabilisticAtl > probabilisticRtl {
-			return core.TRUSTWORTHY
-		} else {
-			return core.NOT_TRUSTWORTHY
-		}
-	}
+	return ProjectedProbabilityPolicy{}.Decide(atl, rtl, nil)
 }
 
+/*
+ProjectProbability returns the projected probability of an opinion. For multinomial opinions, this is the projected
+probability of their focus values.
+*/
 func ProjectProbability(opinion subjectivelogic.QueryableOpinion) float64 {
 	return opinion.Belief() + opinion.Uncertainty()*opinion.BaseRate()
 }
@@ -0,0 +1,236 @@
+package trustdecision
+
+import (
+	"errors"
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/config"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"slices"
+)
+
+var ErrUnknownPolicy = errors.New("unknown decision policy")
+
+const (
+	ProjectedProbability = "projected-probability"
+	BeliefThreshold      = "belief-threshold"
+	MaxUncertainty       = "max-uncertainty"
+	Dominance            = "dominance"
+	Hysteresis           = "hysteresis"
+	ValueWise            = "value-wise"
+)
+
+/*
+NewPolicy creates the built-in decision policy with the given name, using the parameters from the TDE configuration.
+*/
+func NewPolicy(name string, tdeConfig config.TDE) (core.DecisionPolicy, error) {
+	switch name {
+	case ProjectedProbability:
+		return ProjectedProbabilityPolicy{}, nil
+	case BeliefThreshold:
+		return BeliefThresholdPolicy{}, nil
+	case MaxUncertainty:
+		return MaxUncertaintyPolicy{Policy: ProjectedProbabilityPolicy{}}, nil
+	case Dominance:
+		return DominancePolicy{}, nil
+	case Hysteresis:
+		return HysteresisPolicy{Margin: tdeConfig.HysteresisMargin}, nil
+	case ValueWise:
+		return ValueWisePolicy{Policy: ProjectedProbabilityPolicy{}}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPolicy, name)
+	}
+}
+
+/*
+A RuleDescriber is a DecisionPolicy that can describe its decision rule for explanations.
+*/
+type RuleDescriber interface {
+	Rule() string
+}
+
+/*
+Rule returns a textual description of the decision rule of a policy, or its name if the policy does not describe its
+rule.
+*/
+func Rule(policy core.DecisionPolicy) string {
+	if describer, ok := policy.(RuleDescriber); ok {
+		return describer.Rule()
+	}
+	return policy.Name()
+}
+
+/*
+ProjectedProbabilityPolicy decides TRUSTWORTHY if the projected probability of the ATL exceeds the one of the RTL. A
+vacuous ATL is UNDECIDABLE.
+*/
+type ProjectedProbabilityPolicy struct{}
+
+func (p ProjectedProbabilityPolicy) Name() string {
+	return ProjectedProbability
+}
+
+func (p ProjectedProbabilityPolicy) Rule() string {
+	return "TRUSTWORTHY if P(ATL) > P(RTL); UNDECIDABLE if u(ATL) = 1"
+}
+
+func (p ProjectedProbabilityPolicy) Decide(atl subjectivelogic.QueryableOpinion, rtl subjectivelogic.QueryableOpinion, previous *core.TrustDecision) core.TrustDecision {
+	if atl.Uncertainty() == 1 {
+		return core.UNDECIDABLE
+	}
+	if ProjectProbability(atl) > ProjectProbability(rtl) {
+		return core.TRUSTWORTHY
+	}
+	return core.NOT_TRUSTWORTHY
+}
+
+/*
+BeliefThresholdPolicy only considers belief: it decides TRUSTWORTHY if the belief of the ATL exceeds the belief of the
+RTL, which serves as threshold. Unlike the projected probability, uncertainty never counts towards trustworthiness. A
+vacuous ATL is UNDECIDABLE.
+*/
+type BeliefThresholdPolicy struct{}
+
+func (p BeliefThresholdPolicy) Name() string {
+	return BeliefThreshold
+}
+
+func (p BeliefThresholdPolicy) Rule() string {
+	return "TRUSTWORTHY if b(ATL) > b(RTL); UNDECIDABLE if u(ATL) = 1"
+}
+
+func (p BeliefThresholdPolicy) Decide(atl subjectivelogic.QueryableOpinion, rtl subjectivelogic.QueryableOpinion, previous *core.TrustDecision) core.TrustDecision {
+	if atl.Uncertainty() == 1 {
+		return core.UNDECIDABLE
+	}
+	if atl.Belief() > rtl.Belief() {
+		return core.TRUSTWORTHY
+	}
+	return core.NOT_TRUSTWORTHY
+}
+
+/*
+MaxUncertaintyPolicy decides UNDECIDABLE if the uncertainty of the ATL exceeds the uncertainty of the RTL, which serves
+as maximum. Otherwise, the decision is taken by the wrapped policy.
+*/
+type MaxUncertaintyPolicy struct {
+	Policy core.DecisionPolicy
+}
+
+func (p MaxUncertaintyPolicy) Name() string {
+	return MaxUncertainty
+}
+
+func (p MaxUncertaintyPolicy) Rule() string {
+	return "UNDECIDABLE if u(ATL) > u(RTL); otherwise " + Rule(p.Policy)
+}
+
+func (p MaxUncertaintyPolicy) Decide(atl subjectivelogic.QueryableOpinion, rtl subjectivelogic.QueryableOpinion, previous *core.TrustDecision) core.TrustDecision {
+	if atl.Uncertainty() > rtl.Uncertainty() {
+		return core.UNDECIDABLE
+	}
+	return p.Policy.Decide(atl, rtl, previous)
+}
+
+/*
+DominancePolicy decides TRUSTWORTHY if the ATL dominates the RTL, i.e., it has at least the belief and at most the
+disbelief of the RTL. If the RTL dominates the ATL, the decision is NOT_TRUSTWORTHY. Otherwise, both opinions are
+incomparable and the decision is UNDECIDABLE.
+*/
+type DominancePolicy struct{}
+
+func (p DominancePolicy) Name() string {
+	return Dominance
+}
+
+func (p DominancePolicy) Rule() string {
+	return "TRUSTWORTHY if b(ATL) >= b(RTL) and d(ATL) <= d(RTL); NOT_TRUSTWORTHY if b(ATL) <= b(RTL) and d(ATL) >= d(RTL); otherwise UNDECIDABLE"
+}
+
+func (p DominancePolicy) Decide(atl subjectivelogic.QueryableOpinion, rtl subjectivelogic.QueryableOpinion, previous *core.TrustDecision) core.TrustDecision {
+	switch {
+	case atl.Belief() >= rtl.Belief() && atl.Disbelief() <= rtl.Disbelief():
+		return core.TRUSTWORTHY
+	case atl.Belief() <= rtl.Belief() && atl.Disbelief() >= rtl.Disbelief():
+		return core.NOT_TRUSTWORTHY
+	default:
+		return core.UNDECIDABLE
+	}
+}
+
+/*
+HysteresisPolicy compares projected probabilities like ProjectedProbabilityPolicy, but only changes a previous decision
+once the projected probability of the ATL crosses the one of the RTL by more than the margin. This avoids flapping
+decisions for ATLs close to the RTL.
+*/
+type HysteresisPolicy struct {
+	Margin float64
+}
+
+func (p HysteresisPolicy) Name() string {
+	return Hysteresis
+}
+
+func (p HysteresisPolicy) Rule() string {
+	return fmt.Sprintf("TRUSTWORTHY if P(ATL) > P(RTL), changing a previous decision only if P(ATL) crosses P(RTL) by more than %v; UNDECIDABLE if u(ATL) = 1", p.Margin)
+}
+
+func (p HysteresisPolicy) Decide(atl subjectivelogic.QueryableOpinion, rtl subjectivelogic.QueryableOpinion, previous *core.TrustDecision) core.TrustDecision {
+	if atl.Uncertainty() == 1 {
+		return core.UNDECIDABLE
+	}
+	threshold := ProjectProbability(rtl)
+	if previous != nil {
+		switch *previous {
+		case core.TRUSTWORTHY:
+			threshold -= p.Margin
+		case core.NOT_TRUSTWORTHY:
+			threshold += p.Margin
+		}
+	}
+	if ProjectProbability(atl) > threshold {
+		return core.TRUSTWORTHY
+	}
+	return core.NOT_TRUSTWORTHY
+}
+
+/*
+ValueWisePolicy decides on multinomial opinions by comparing the projected probabilities of the ATL and the RTL value by
+value: the decision is TRUSTWORTHY if each focus value is at least as probable and each other value at most as probable
+as required by the RTL. ATL and RTL over different domains or focus values are UNDECIDABLE, as is a vacuous ATL. If
+either opinion is binomial, the decision is taken by the wrapped policy.
+*/
+type ValueWisePolicy struct {
+	Policy core.DecisionPolicy
+}
+
+func (p ValueWisePolicy) Name() string {
+	return ValueWise
+}
+
+func (p ValueWisePolicy) Rule() string {
+	return "TRUSTWORTHY if P_ATL(x) >= P_RTL(x) for all focus values x and P_ATL(y) <= P_RTL(y) for all other values y; UNDECIDABLE if u(ATL) = 1 or domains differ; otherwise " + Rule(p.Policy) + " for binomial opinions"
+}
+
+func (p ValueWisePolicy) Decide(atl subjectivelogic.QueryableOpinion, rtl subjectivelogic.QueryableOpinion, previous *core.TrustDecision) core.TrustDecision {
+	multinomialATL, atlIsMultinomial := atl.(*multinomial.Opinion)
+	multinomialRTL, rtlIsMultinomial := rtl.(*multinomial.Opinion)
+	if !atlIsMultinomial || !rtlIsMultinomial {
+		return p.Policy.Decide(atl, rtl, previous)
+	}
+	if atl.Uncertainty() == 1 || !slices.Equal(multinomialATL.Domain(), multinomialRTL.Domain()) || !slices.Equal(multinomialATL.Focus(), multinomialRTL.Focus()) {
+		return core.UNDECIDABLE
+	}
+	actual, required := multinomialATL.ProjectedProbabilities(), multinomialRTL.ProjectedProbabilities()
+	focus := multinomialATL.Focus()
+	for _, value := range multinomialATL.Domain() {
+		if slices.Contains(focus, value) && actual[value] < required[value] {
+			return core.NOT_TRUSTWORTHY
+		}
+		if !slices.Contains(focus, value) && actual[value] > required[value] {
+			return core.NOT_TRUSTWORTHY
+		}
+	}
+	return core.TRUSTWORTHY
+}
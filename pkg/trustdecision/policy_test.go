@@ -0,0 +1,118 @@
+package trustdecision
+
+import (
+	"errors"
+	"github.com/horizon-connect-eu/go-taf/pkg/config"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"testing"
+)
+
+func opinion(belief float64, disbelief float64, uncertainty float64) subjectivelogic.QueryableOpinion {
+	o, _ := subjectivelogic.NewOpinion(belief, disbelief, uncertainty, 0.5)
+	return &o
+}
+
+func TestPolicies(t *testing.T) {
+	rtl := opinion(0.6, 0.2, 0.2)
+	tests := []struct {
+		policy   core.DecisionPolicy
+		atl      subjectivelogic.QueryableOpinion
+		expected core.TrustDecision
+	}{
+		{ProjectedProbabilityPolicy{}, opinion(0, 0, 1), core.UNDECIDABLE},
+		{ProjectedProbabilityPolicy{}, opinion(0.55, 0.05, 0.4), core.TRUSTWORTHY},
+		{ProjectedProbabilityPolicy{}, opinion(0.5, 0.3, 0.2), core.NOT_TRUSTWORTHY},
+		//Same projected probability as above, but the belief alone does not reach the RTL
+		{BeliefThresholdPolicy{}, opinion(0.55, 0.05, 0.4), core.NOT_TRUSTWORTHY},
+		{BeliefThresholdPolicy{}, opinion(0.7, 0.3, 0), core.TRUSTWORTHY},
+		{MaxUncertaintyPolicy{Policy: ProjectedProbabilityPolicy{}}, opinion(0.55, 0.05, 0.4), core.UNDECIDABLE},
+		{MaxUncertaintyPolicy{Policy: ProjectedProbabilityPolicy{}}, opinion(0.7, 0.1, 0.2), core.TRUSTWORTHY},
+		{DominancePolicy{}, opinion(0.6, 0.2, 0.2), core.TRUSTWORTHY},
+		{DominancePolicy{}, opinion(0.5, 0.3, 0.2), core.NOT_TRUSTWORTHY},
+		{DominancePolicy{}, opinion(0.7, 0.3, 0), core.UNDECIDABLE},
+		{DominancePolicy{}, opinion(0, 0, 1), core.UNDECIDABLE},
+	}
+	for _, test := range tests {
+		if decision := test.policy.Decide(test.atl, rtl, nil); decision != test.expected {
+			t.Errorf("%s: expected %d for ATL %s, got %d", test.policy.Name(), test.expected, test.atl, decision)
+		}
+	}
+}
+
+func TestHysteresisPolicy(t *testing.T) {
+	policy := HysteresisPolicy{Margin: 0.1}
+	//projected probability 0.7
+	rtl := opinion(0.6, 0.2, 0.2)
+	decisions := []struct {
+		atl      subjectivelogic.QueryableOpinion
+		expected core.TrustDecision
+	}{
+		{opinion(0.7, 0.2, 0.1), core.TRUSTWORTHY},     //0.75
+		{opinion(0.6, 0.3, 0.1), core.TRUSTWORTHY},     //0.65: within the margin
+		{opinion(0.5, 0.4, 0.1), core.NOT_TRUSTWORTHY}, //0.55
+		{opinion(0.7, 0.2, 0.1), core.NOT_TRUSTWORTHY}, //0.75: within the margin
+		{opinion(0.8, 0.1, 0.1), core.TRUSTWORTHY},     //0.85
+	}
+	var previous *core.TrustDecision
+	for i, step := range decisions {
+		decision := policy.Decide(step.atl, rtl, previous)
+		if decision != step.expected {
+			t.Errorf("step %d: expected %d, got %d", i, step.expected, decision)
+		}
+		previous = &decision
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	for _, name := range []string{ProjectedProbability, BeliefThreshold, MaxUncertainty, Dominance, Hysteresis, ValueWise} {
+		policy, err := NewPolicy(name, config.TDE{HysteresisMargin: 0.05})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if policy.Name() != name {
+			t.Errorf("expected policy %s, got %s", name, policy.Name())
+		}
+	}
+	if _, err := NewPolicy("unknown", config.TDE{}); !errors.Is(err, ErrUnknownPolicy) {
+		t.Errorf("expected unknown policy error, got %v", err)
+	}
+}
+
+func TestValueWisePolicy(t *testing.T) {
+	state := func(running float64, degraded float64, failed float64, uncertainty float64) subjectivelogic.QueryableOpinion {
+		o, err := multinomial.New(map[string]float64{"RUNNING": running, "DEGRADED": degraded, "FAILED": failed}, uncertainty,
+			map[string]float64{"RUNNING": 0.5, "DEGRADED": 0.3, "FAILED": 0.2}, []string{"RUNNING", "DEGRADED"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return o
+	}
+	policy := ValueWisePolicy{Policy: ProjectedProbabilityPolicy{}}
+	rtl := state(0.5, 0.1, 0.1, 0.3)
+	tests := []struct {
+		atl      subjectivelogic.QueryableOpinion
+		rtl      subjectivelogic.QueryableOpinion
+		expected core.TrustDecision
+	}{
+		{state(0.6, 0.2, 0.05, 0.15), rtl, core.TRUSTWORTHY},
+		//higher projected probability of the focus values, but less probably RUNNING than required
+		{state(0.3, 0.5, 0.05, 0.15), rtl, core.NOT_TRUSTWORTHY},
+		{state(0.5, 0.1, 0.3, 0.1), rtl, core.NOT_TRUSTWORTHY},
+		{state(0, 0, 0, 1), rtl, core.UNDECIDABLE},
+		//binomial opinions are decided by the wrapped policy
+		{opinion(0.7, 0.1, 0.2), opinion(0.6, 0.2, 0.2), core.TRUSTWORTHY},
+		{state(0.6, 0.2, 0.05, 0.15), opinion(0.9, 0, 0.1), core.NOT_TRUSTWORTHY},
+	}
+	for i, test := range tests {
+		if decision := policy.Decide(test.atl, test.rtl, nil); decision != test.expected {
+			t.Errorf("test %d: expected %d for ATL %s, got %d", i, test.expected, test.atl, decision)
+		}
+	}
+
+	other, _ := multinomial.New(map[string]float64{"ON": 0.8}, 0.2, map[string]float64{"ON": 0.5, "OFF": 0.5}, []string{"ON"})
+	if decision := policy.Decide(other, rtl, nil); decision != core.UNDECIDABLE {
+		t.Errorf("expected UNDECIDABLE for different domains, got %d", decision)
+	}
+}
@@ -0,0 +1,281 @@
+package declarative
+
+import (
+	"errors"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/tlee"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+)
+
+const tolerance = 1e-9
+
+func loadTemplates(t *testing.T) map[string]TrustModelTemplate {
+	t.Helper()
+	templates, err := LoadDirectory("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byIdentifier := make(map[string]TrustModelTemplate)
+	for _, template := range templates {
+		byIdentifier[template.Identifier()] = template
+	}
+	return byIdentifier
+}
+
+func assertOpinion(t *testing.T, actual subjectivelogic.QueryableOpinion, belief float64, disbelief float64, uncertainty float64) {
+	t.Helper()
+	if math.Abs(actual.Belief()-belief) > tolerance || math.Abs(actual.Disbelief()-disbelief) > tolerance || math.Abs(actual.Uncertainty()-uncertainty) > tolerance {
+		t.Errorf("expected (%v, %v, %v), got (%v, %v, %v)", belief, disbelief, uncertainty, actual.Belief(), actual.Disbelief(), actual.Uncertainty())
+	}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	templates := loadTemplates(t)
+	static, exists := templates["VEHICLE_COMPUTERS@0.0.1"]
+	if !exists || static.Type() != core.STATIC_TRUST_MODEL || len(static.EvidenceTypes()) != 4 {
+		t.Errorf("unexpected static template: %+v", static)
+	}
+	dynamic, exists := templates["OFFLOADING@0.0.1"]
+	if !exists || dynamic.Type() != core.TRUSTEE_TRIGGERED_TRUST_MODEL {
+		t.Errorf("unexpected dynamic template: %+v", dynamic)
+	}
+	if len(static.SigningHash()) != 128 || static.SigningHash() == dynamic.SigningHash() {
+		t.Errorf("expected SHA-512 signing hashes of the files, got %s and %s", static.SigningHash(), dynamic.SigningHash())
+	}
+}
+
+func TestStaticTemplate(t *testing.T) {
+	template := loadTemplates(t)["VEHICLE_COMPUTERS@0.0.1"]
+	tsqs, tmi, spawner, err := template.Spawn(map[string]string{"VEHICLE": "vehicle_1", "SECURE_BOOT_WEIGHT": "0.1"}, core.TafContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmi == nil || spawner != nil || tmi.ID() != "vehicle_1" || len(tsqs) != 2 {
+		t.Fatalf("unexpected spawn result: %v, %v, %d quantifiers", tmi, spawner, len(tsqs))
+	}
+
+	evidence := map[core.EvidenceType]interface{}{
+		core.AIV_SECURE_BOOT:                          1,
+		core.AIV_ACCESS_CONTROL:                       0,
+		core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION: -2,
+	}
+	ato := tsqs[0].Quantifier(evidence)
+	assertOpinion(t, ato, 0.1, 0.2, 0.7)
+	evidence[core.AIV_SECURE_BOOT] = 0
+	assertOpinion(t, tsqs[0].Quantifier(evidence), 0, 1, 0)
+
+	if !tmi.Update(trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "", "VC1", core.AIV)) {
+		t.Fatal("expected update of VC1 to change the instance")
+	}
+	if tmi.Update(trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "", "VC3", core.AIV)) {
+		t.Error("expected update of unknown trustee to be ignored")
+	}
+
+	backend := tlee.SpawnNewTLEE(slog.New(slog.NewTextHandler(io.Discard, nil)), "", false)
+	results, err := backend.RunTLEE(tmi.ID(), tmi.Version(), tmi.Fingerprint(), tmi.Structure(), tmi.Values())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOpinion(t, results["VC1"], 0.1, 0.2, 0.7)
+	assertOpinion(t, results["VC2"], 0, 0, 1)
+	assertOpinion(t, tmi.RTLs()["VC2"], 0.7, 0.1, 0.2)
+}
+
+func TestDynamicTemplate(t *testing.T) {
+	template := loadTemplates(t)["OFFLOADING@0.0.1"]
+	tsqs, tmi, spawner, err := template.Spawn(nil, core.TafContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmi != nil || spawner == nil || tsqs[0].Trustee != "vehicle_*" {
+		t.Fatalf("unexpected spawn result: %v, %v, %+v", tmi, spawner, tsqs)
+	}
+	if tmi, _ := spawner.OnNewVehicle("7", nil); tmi != nil {
+		t.Error("expected no instance for new vehicles")
+	}
+	tmi, err = spawner.OnNewTrustee("7", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := spawner.OnNewTrustee("8", nil)
+	if tmi.ID() != "7" || tmi.Fingerprint() == other.Fingerprint() {
+		t.Errorf("expected instance 7 with an individual structure, got %s", tmi.ID())
+	}
+	if _, exists := tmi.Values()["vehicle_7"]; !exists {
+		t.Errorf("expected scope vehicle_7, got %v", tmi.Values())
+	}
+
+	opinion, _ := subjectivelogic.NewOpinion(0.5, 0.2, 0.3, 0.5)
+	tmi.Update(trustmodelupdate.CreateAtomicTrustOpinionUpdate(&opinion, "MEC", "vehicle_7", core.TCH))
+	tmi.Update(trustmodelupdate.CreateAtomicTrustOpinionUpdate(&opinion, "MEC", "vehicle_7", core.MBD))
+	if relationships := tmi.Values()["vehicle_7"]; len(relationships) != 2 {
+		t.Errorf("expected one trust relationship per trust source, got %d", len(relationships))
+	}
+}
+
+func TestTrustObjectUpdates(t *testing.T) {
+	template := loadTemplates(t)["VEHICLE_COMPUTERS@0.0.1"]
+	_, tmi, _, err := template.Spawn(map[string]string{"VEHICLE": "vehicle_1"}, core.TafContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := tmi.Fingerprint()
+	opinion, _ := subjectivelogic.NewOpinion(0.5, 0.2, 0.3, 0.5)
+	tmi.Update(trustmodelupdate.CreateAtomicTrustOpinionUpdate(&opinion, "", "VC2", core.AIV))
+
+	if !tmi.Update(trustmodelupdate.CreateRemoveTrustObject("VC2")) {
+		t.Fatal("expected removal of VC2 to change the instance")
+	}
+	if _, exists := tmi.Values()["VC2"]; exists || tmi.Fingerprint() == fingerprint {
+		t.Errorf("expected scope VC2 to be removed, got %v", tmi.Values())
+	}
+	if tmi.Update(trustmodelupdate.CreateRemoveTrustObject("VC2")) {
+		t.Error("expected removal of unknown trust object to be ignored")
+	}
+
+	//Without relationships, the trust object is restored as defined, but without its previous opinions
+	if !tmi.Update(trustmodelupdate.CreateAddTrustObject("VC2")) || tmi.Fingerprint() != fingerprint {
+		t.Fatal("expected VC2 to be restored")
+	}
+	assertOpinion(t, tmi.Values()["VC2"][0].Opinion(), 0, 0, 1)
+
+	//New trust objects become propositions if an RTL is given
+	relationship := trustmodelupdate.TrustRelationship{Trustor: "TAF", Trustee: "VC3"}
+	if tmi.Update(trustmodelupdate.CreateAddTrustObject("VC3", relationship)) {
+		t.Error("expected new trust object without RTL to be ignored")
+	}
+	rtl, _ := subjectivelogic.NewOpinion(0.6, 0.1, 0.3, 0.5)
+	if !tmi.Update(trustmodelupdate.CreateAddTrustObject("VC3", relationship).WithRTL(&rtl)) {
+		t.Fatal("expected VC3 to be added")
+	}
+	assertOpinion(t, tmi.RTLs()["VC3"], 0.6, 0.1, 0.3)
+	if !tmi.Update(trustmodelupdate.CreateAtomicTrustOpinionUpdate(&opinion, "", "VC3", core.AIV)) {
+		t.Error("expected update of VC3 to change the instance")
+	}
+
+	backend := tlee.SpawnNewTLEE(slog.New(slog.NewTextHandler(io.Discard, nil)), "", false)
+	results, err := backend.RunTLEE(tmi.ID(), tmi.Version(), tmi.Fingerprint(), tmi.Structure(), tmi.Values())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOpinion(t, results["VC3"], 0.5, 0.2, 0.3)
+}
+
+func TestSnapshot(t *testing.T) {
+	template := loadTemplates(t)["VEHICLE_COMPUTERS@0.0.1"]
+	spawn := func() core.TrustModelInstance {
+		_, tmi, _, err := template.Spawn(map[string]string{"VEHICLE": "vehicle_1"}, core.TafContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tmi
+	}
+	tmi := spawn()
+	opinion, _ := subjectivelogic.NewOpinion(0.5, 0.2, 0.3, 0.5)
+	rtl, _ := subjectivelogic.NewOpinion(0.6, 0.1, 0.3, 0.5)
+	tmi.Update(trustmodelupdate.CreateAtomicTrustOpinionUpdate(&opinion, "", "VC1", core.AIV))
+	tmi.Update(trustmodelupdate.CreateRemoveTrustObject("VC2"))
+	tmi.Update(trustmodelupdate.CreateAddTrustObject("VC3", trustmodelupdate.TrustRelationship{Trustor: "TAF", Trustee: "VC3"}).WithRTL(&rtl))
+
+	snapshot, err := tmi.(core.SnapshotableTrustModelInstance).Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := spawn()
+	if err := restored.(core.SnapshotableTrustModelInstance).Restore(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Version() != tmi.Version() || restored.Fingerprint() != tmi.Fingerprint() {
+		t.Errorf("expected version %d and fingerprint %d, got %d and %d", tmi.Version(), tmi.Fingerprint(), restored.Version(), restored.Fingerprint())
+	}
+	if _, exists := restored.Values()["VC2"]; exists || len(restored.Values()) != 2 {
+		t.Errorf("expected scopes VC1 and VC3, got %v", restored.Values())
+	}
+	assertOpinion(t, restored.Values()["VC1"][0].Opinion(), 0.5, 0.2, 0.3)
+	assertOpinion(t, restored.RTLs()["VC3"], 0.6, 0.1, 0.3)
+
+	//The restored instance keeps the definition, so removed trust objects can still be restored
+	if !restored.Update(trustmodelupdate.CreateAddTrustObject("VC2")) {
+		t.Error("expected VC2 to be restored")
+	}
+
+	other, _ := loadTemplates(t)["OFFLOADING@0.0.1"].newInstance("1", map[string]string{"ID": "1"})
+	if err := other.Restore(snapshot); err == nil {
+		t.Error("expected snapshot of another template to be rejected")
+	}
+}
+
+func TestEntityTriggeredTemplate(t *testing.T) {
+	definition := `{"name": "T", "version": "1", "spawn": {"trigger": "NEW_ENTITY", "entities": ["MBD_SOURCE", "NTM_SOURCE"]},
+		"trustObjects": ["A", "vehicle_${ID}"],
+		"scopes": [{"id": "vehicle_${ID}", "rtl": {"belief": 1, "disbelief": 0, "uncertainty": 0, "baseRate": 0.5}, "edges": [{"trustor": "A", "trustee": "vehicle_${ID}"}]}]}`
+	parsed, err := Parse([]byte(definition), "entity.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := CreateTrustModelTemplate(parsed, "")
+	if template.Type() != core.ENTITY_TRIGGERED_TRUST_MODEL || !core.IsSpawnedBy(template, core.TRIGGER_NTM_SOURCE) || core.IsSpawnedBy(template, core.TRIGGER_V2X_SENDER) {
+		t.Errorf("unexpected spawn triggers %v of type %s", core.TemplateSpawnTriggers(template), template.Type())
+	}
+	_, _, spawner, err := template.Spawn(nil, core.TafContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmi, _ := core.SpawnOnTrigger(spawner, core.TRIGGER_V2X_SENDER, "7", nil); tmi != nil {
+		t.Error("expected no instance for new V2X senders")
+	}
+	tmi, err := core.SpawnOnTrigger(spawner, core.TRIGGER_MBD_SOURCE, "7", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmi == nil || tmi.ID() != "7" {
+		t.Errorf("expected instance 7 for new MBD source, got %v", tmi)
+	}
+}
+
+func TestInvalidDefinitions(t *testing.T) {
+	valid := `{"name": "T", "version": "1", "spawn": {"trigger": "STATIC"}, "trustObjects": ["A", "B"],
+		"scopes": [{"id": "B", "rtl": {"belief": 1, "disbelief": 0, "uncertainty": 0, "baseRate": 0.5}, "edges": [{"trustor": "A", "trustee": "B"}]}],
+		"quantifiers": [{"trustSource": "AIV", "trustee": "B", "evidence": [{"claim": "SECURE_BOOT", "weight": 1}]}]}`
+	if _, err := Parse([]byte(valid), "valid.json"); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string][2]string{
+		"unknown trigger":      {`"STATIC"`, `"ON_DEMAND"`},
+		"unknown trust object": {`"trustee": "B"}]`, `"trustee": "C"}]`},
+		"unknown claim":        {`"SECURE_BOOT"`, `"SECURE_LAUNCH"`},
+		"unknown parameter":    {`"weight": 1`, `"weight": "${WEIGHT}"`},
+		"invalid RTL":          {`"belief": 1`, `"belief": 0.5`},
+		"unknown field":        {`"spawn"`, `"spawning"`},
+		"unknown fusion":       {`"trustObjects"`, `"fusion": "MAX", "trustObjects"`},
+		"unknown entity":       {`{"trigger": "STATIC"}`, `{"trigger": "NEW_ENTITY", "entities": ["MBD_REPORTER"]}`},
+		"missing entities":     {`{"trigger": "STATIC"}`, `{"trigger": "NEW_ENTITY"}`},
+		"misplaced entities":   {`{"trigger": "STATIC"}`, `{"trigger": "STATIC", "entities": ["MBD_SOURCE"]}`},
+	}
+	for name, replacement := range tests {
+		definition := strings.Replace(valid, replacement[0], replacement[1], 1)
+		if _, err := Parse([]byte(definition), "invalid.json"); !errors.Is(err, ErrInvalidDefinition) {
+			t.Errorf("%s: expected invalid definition, got %v", name, err)
+		}
+	}
+}
+
+func TestParameterSchema(t *testing.T) {
+	schema := loadTemplates(t)["VEHICLE_COMPUTERS@0.0.1"].ParameterSchema()
+	values, err := schema.Validate(map[string]string{"VEHICLE": "vehicle_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params := values.Map(); len(params) != 2 || params["VEHICLE"] != "vehicle_1" || params["SECURE_BOOT_WEIGHT"] != "0.3" {
+		t.Errorf("expected parameters completed by defaults, got %v", params)
+	}
+	if unknown := schema.Unknown(map[string]string{"VEHICLES": "vehicle_1"}); len(unknown) != 1 || unknown[0] != "VEHICLES" {
+		t.Errorf("expected undeclared parameter to be reported, got %v", unknown)
+	}
+}
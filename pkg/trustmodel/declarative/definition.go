@@ -0,0 +1,401 @@
+/*
+Package declarative implements trust model templates that are defined declaratively in JSON or YAML files instead of Go
+code. A definition covers the trust objects, the scopes (propositions) with their edges and RTLs, the fusion and discount
+operators, the bindings of evidence to quantifiers, parameters and the spawn triggers of the template.
+
+String values of a definition may reference parameters as ${NAME}. Parameters are declared with default values and can
+be overridden by the params of a TAS_INIT_REQUEST. The identifier of the trust model instance is available as ${ID}.
+Numbers can be given either as numbers or as strings referencing parameters (e.g., "${SECURE_BOOT_WEIGHT}").
+*/
+package declarative
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidDefinition = errors.New("invalid trust model template definition")
+
+/*
+IDVariable is the name of the built-in variable that holds the identifier of the trust model instance.
+*/
+const IDVariable = "ID"
+
+const (
+	STATIC      = "STATIC"
+	NEW_VEHICLE = "NEW_VEHICLE"
+	NEW_TRUSTEE = "NEW_TRUSTEE"
+	NEW_ENTITY  = "NEW_ENTITY"
+)
+
+var variablePattern = regexp.MustCompile(`\$\{([A-Za-z0-9_]+)\}`)
+
+/*
+A Definition is the declarative description of a trust model template.
+*/
+type Definition struct {
+	Name        string            `json:"name" yaml:"name"`
+	Version     string            `json:"version" yaml:"version"`
+	Description string            `json:"description" yaml:"description"`
+	Deprecated  bool              `json:"deprecated" yaml:"deprecated"` //new sessions are still possible, but clients get a warning
+	Spawn       Spawn             `json:"spawn" yaml:"spawn"`
+	Parameters  map[string]string `json:"parameters" yaml:"parameters"` //parameter name->default value
+	//trust objects used as trustors and trustees of edges
+	TrustObjects []string     `json:"trustObjects" yaml:"trustObjects"`
+	Fusion       string       `json:"fusion" yaml:"fusion"`     //CUMULATIVE (default), AVERAGING, CONSTRAINT, WEIGHTED or NONE
+	Discount     string       `json:"discount" yaml:"discount"` //DEFAULT (default) or OPPOSITE_BELIEF
+	Scopes       []Scope      `json:"scopes" yaml:"scopes"`
+	Quantifiers  []Quantifier `json:"quantifiers" yaml:"quantifiers"`
+}
+
+/*
+Spawn defines when instances of the template are spawned: once per session (STATIC), for each new vehicle (NEW_VEHICLE),
+for each new trustee (NEW_TRUSTEE) or for each new entity of the spawn triggers listed in Entities (NEW_ENTITY, e.g.,
+MBD_SOURCE). For static templates, ID sets the identifier of the instance.
+*/
+type Spawn struct {
+	Trigger  string   `json:"trigger" yaml:"trigger"`
+	ID       string   `json:"id" yaml:"id"`
+	Entities []string `json:"entities" yaml:"entities"` //names of core.SpawnTrigger, only for NEW_ENTITY
+}
+
+/*
+A Scope is a proposition of the template, consisting of the edges evaluated by the TLEE and the RTL.
+*/
+type Scope struct {
+	ID    string  `json:"id" yaml:"id"`
+	RTL   Opinion `json:"rtl" yaml:"rtl"`
+	Edges []Edge  `json:"edges" yaml:"edges"`
+}
+
+/*
+An Edge is a trust relationship between two trust objects. Its opinion is updated by atomic trust opinions for the
+trustee; until then, the initial opinion is used, which defaults to full uncertainty. Edges without matching quantifier
+keep their initial opinion (e.g., full belief in a component of the trustor).
+*/
+type Edge struct {
+	Trustor string   `json:"trustor" yaml:"trustor"`
+	Trustee string   `json:"trustee" yaml:"trustee"`
+	Opinion *Opinion `json:"opinion" yaml:"opinion"`
+}
+
+type Opinion struct {
+	Belief      Number `json:"belief" yaml:"belief"`
+	Disbelief   Number `json:"disbelief" yaml:"disbelief"`
+	Uncertainty Number `json:"uncertainty" yaml:"uncertainty"`
+	BaseRate    Number `json:"baseRate" yaml:"baseRate"`
+}
+
+/*
+A Quantifier binds the evidence of a trust source to a weighted quantifier (see weightedQuantifier). Trustor, trustee and
+scope are passed to the trust source handlers as is, so they have to follow the patterns of the respective trust source
+(e.g., "MEC" and "vehicle_*" for TCH).
+*/
+type Quantifier struct {
+	TrustSource string     `json:"trustSource" yaml:"trustSource"`
+	Trustor     string     `json:"trustor" yaml:"trustor"`
+	Trustee     string     `json:"trustee" yaml:"trustee"`
+	Scope       string     `json:"scope" yaml:"scope"`
+	BaseRate    *Number    `json:"baseRate" yaml:"baseRate"` //defaults to 0.5
+	Evidence    []Evidence `json:"evidence" yaml:"evidence"`
+}
+
+/*
+Evidence binds a claim of the trust source to its weight. A negative appraisal of a critical claim results in full
+disbelief, independent of all other claims.
+*/
+type Evidence struct {
+	Claim    string `json:"claim" yaml:"claim"`
+	Weight   Number `json:"weight" yaml:"weight"`
+	Critical bool   `json:"critical" yaml:"critical"`
+}
+
+/*
+A Number is a numerical value of a definition, either a literal or a string that references parameters and is resolved
+when the template is spawned.
+*/
+type Number string
+
+func (n *Number) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		*n = Number(value)
+		return nil
+	}
+	var value float64
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*n = Number(data)
+	return nil
+}
+
+func (n *Number) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: expected number or string", node.Line)
+	}
+	*n = Number(node.Value)
+	return nil
+}
+
+/*
+Parse parses a definition from JSON or YAML, depending on the extension of the file name, and validates it.
+*/
+func Parse(data []byte, filename string) (Definition, error) {
+	var definition Definition
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&definition); err != nil {
+			return Definition{}, fmt.Errorf("%w: %s: %w", ErrInvalidDefinition, filename, err)
+		}
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&definition); err != nil {
+			return Definition{}, fmt.Errorf("%w: %s: %w", ErrInvalidDefinition, filename, err)
+		}
+	default:
+		return Definition{}, fmt.Errorf("%w: %s: unsupported file type", ErrInvalidDefinition, filename)
+	}
+	if err := definition.validate(); err != nil {
+		return Definition{}, fmt.Errorf("%w: %s: %w", ErrInvalidDefinition, filename, err)
+	}
+	return definition, nil
+}
+
+/*
+validate checks the static parts of a definition and resolves it once with the default parameters, so that errors in
+values referencing parameters are detected when loading the template.
+*/
+func (d Definition) validate() error {
+	if d.Name == "" || d.Version == "" {
+		return errors.New("name and version are required")
+	}
+	if strings.ContainsAny(d.Name+d.Version, "@/") {
+		return errors.New("name and version must not contain '@' or '/'")
+	}
+	if _, err := d.templateType(); err != nil {
+		return err
+	}
+	if _, err := d.spawnTriggers(); err != nil {
+		return err
+	}
+	if _, err := fusionOperator(d.Fusion); err != nil {
+		return err
+	}
+	if _, err := discountOperator(d.Discount); err != nil {
+		return err
+	}
+	if _, exists := d.Parameters[IDVariable]; exists {
+		return fmt.Errorf("parameter %s is reserved", IDVariable)
+	}
+	if len(d.Scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+	for _, scope := range d.Scopes {
+		if len(scope.Edges) == 0 {
+			return fmt.Errorf("scope %s: at least one edge is required", scope.ID)
+		}
+		for _, edge := range scope.Edges {
+			for _, object := range []string{edge.Trustor, edge.Trustee} {
+				if !slices.Contains(d.TrustObjects, object) {
+					return fmt.Errorf("scope %s: unknown trust object %s", scope.ID, object)
+				}
+			}
+		}
+	}
+	for _, quantifier := range d.Quantifiers {
+		if _, err := quantifier.evidenceTypes(); err != nil {
+			return err
+		}
+	}
+
+	vars := d.variables(nil, "id")
+	if _, _, err := d.resolveScopes(vars); err != nil {
+		return err
+	}
+	if _, err := d.trustSourceQuantifiers(vars); err != nil {
+		return err
+	}
+	if d.Spawn.Trigger == STATIC {
+		if _, err := d.staticID(vars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d Definition) templateType() (core.TrustModelTemplateType, error) {
+	switch d.Spawn.Trigger {
+	case STATIC:
+		return core.STATIC_TRUST_MODEL, nil
+	case NEW_VEHICLE:
+		return core.VEHICLE_TRIGGERED_TRUST_MODEL, nil
+	case NEW_TRUSTEE:
+		return core.TRUSTEE_TRIGGERED_TRUST_MODEL, nil
+	case NEW_ENTITY:
+		return core.ENTITY_TRIGGERED_TRUST_MODEL, nil
+	default:
+		return 0, fmt.Errorf("unknown spawn trigger '%s'", d.Spawn.Trigger)
+	}
+}
+
+func (d Definition) spawnTriggers() ([]core.SpawnTrigger, error) {
+	if d.Spawn.Trigger != NEW_ENTITY {
+		if len(d.Spawn.Entities) > 0 {
+			return nil, fmt.Errorf("entities are only allowed for spawn trigger %s", NEW_ENTITY)
+		}
+		return nil, nil
+	}
+	if len(d.Spawn.Entities) == 0 {
+		return nil, fmt.Errorf("spawn trigger %s requires at least one entity", NEW_ENTITY)
+	}
+	triggers := make([]core.SpawnTrigger, 0, len(d.Spawn.Entities))
+	for _, name := range d.Spawn.Entities {
+		trigger, exists := core.SpawnTriggerByName(name)
+		if !exists {
+			return nil, fmt.Errorf("unknown entity '%s'", name)
+		}
+		triggers = append(triggers, trigger)
+	}
+	return triggers, nil
+}
+
+func fusionOperator(name string) (trustmodelstructure.FusionOperator, error) {
+	switch name {
+	case "", "CUMULATIVE":
+		return trustmodelstructure.CumulativeFusion, nil
+	case "AVERAGING":
+		return trustmodelstructure.AveragingFusion, nil
+	case "CONSTRAINT":
+		return trustmodelstructure.ConstraintFusion, nil
+	case "WEIGHTED":
+		return trustmodelstructure.WeightedFusion, nil
+	case "NONE":
+		return trustmodelstructure.NoFusion, nil
+	default:
+		return 0, fmt.Errorf("unknown fusion operator '%s'", name)
+	}
+}
+
+func discountOperator(name string) (trustmodelstructure.DiscountOperator, error) {
+	switch name {
+	case "", "DEFAULT":
+		return trustmodelstructure.DefaultDiscount, nil
+	case "OPPOSITE_BELIEF":
+		return trustmodelstructure.OppositeBeliefDiscount, nil
+	default:
+		return 0, fmt.Errorf("unknown discount operator '%s'", name)
+	}
+}
+
+func (q Quantifier) trustSource() (core.TrustSource, error) {
+	for _, source := range []core.TrustSource{core.AIV, core.MBD, core.TCH, core.NTM} {
+		if source.String() == strings.ToUpper(q.TrustSource) {
+			return source, nil
+		}
+	}
+	return core.NONE, fmt.Errorf("unknown trust source '%s'", q.TrustSource)
+}
+
+func (q Quantifier) evidenceTypes() ([]core.EvidenceType, error) {
+	source, err := q.trustSource()
+	if err != nil {
+		return nil, err
+	}
+	if len(q.Evidence) == 0 {
+		return nil, fmt.Errorf("quantifier for %s: at least one evidence binding is required", q.TrustSource)
+	}
+	evidenceTypes := make([]core.EvidenceType, 0, len(q.Evidence))
+	for _, evidence := range q.Evidence {
+		evidenceType := core.EvidenceTypeBySourceAndName(source, evidence.Claim)
+		if evidenceType == core.UNKNOWN {
+			return nil, fmt.Errorf("quantifier for %s: unknown claim '%s'", q.TrustSource, evidence.Claim)
+		}
+		evidenceTypes = append(evidenceTypes, evidenceType)
+	}
+	return evidenceTypes, nil
+}
+
+/*
+variables merges the default parameters with the given params and adds the identifier of the instance. Params that are
+not declared by the definition are ignored.
+*/
+func (d Definition) variables(params map[string]string, id string) map[string]string {
+	vars := make(map[string]string, len(d.Parameters)+1)
+	for name, value := range d.Parameters {
+		vars[name] = value
+		if override, exists := params[name]; exists {
+			vars[name] = override
+		}
+	}
+	vars[IDVariable] = id
+	return vars
+}
+
+/*
+substitute replaces all references to variables in a string.
+*/
+func substitute(value string, vars map[string]string) (string, error) {
+	var err error
+	result := variablePattern.ReplaceAllStringFunc(value, func(reference string) string {
+		name := variablePattern.FindStringSubmatch(reference)[1]
+		replacement, exists := vars[name]
+		if !exists && err == nil {
+			err = fmt.Errorf("unknown parameter '%s'", name)
+		}
+		return replacement
+	})
+	return result, err
+}
+
+func (n Number) resolve(vars map[string]string) (float64, error) {
+	value, err := substitute(string(n), vars)
+	if err != nil {
+		return 0, err
+	}
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a number", value)
+	}
+	return number, nil
+}
+
+func (o Opinion) resolve(vars map[string]string) (subjectivelogic.Opinion, error) {
+	values := make([]float64, 4)
+	for i, number := range []Number{o.Belief, o.Disbelief, o.Uncertainty, o.BaseRate} {
+		value, err := number.resolve(vars)
+		if err != nil {
+			return subjectivelogic.Opinion{}, err
+		}
+		values[i] = value
+	}
+	return subjectivelogic.NewOpinion(values[0], values[1], values[2], values[3])
+}
+
+func (d Definition) staticID(vars map[string]string) (string, error) {
+	if d.Spawn.ID == "" {
+		return d.Name, nil
+	}
+	id, err := substitute(d.Spawn.ID, vars)
+	if err != nil {
+		return "", fmt.Errorf("spawn ID: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,344 @@
+package declarative
+
+import (
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	internaltrustmodelstructure "github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelstructure"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"strings"
+)
+
+var FullUncertainty, _ = subjectivelogic.NewOpinion(0, 0, 1, 0.5)
+
+type edge struct {
+	trustor string
+	trustee string
+}
+
+type resolvedScope struct {
+	id    string
+	rtl   subjectivelogic.Opinion
+	edges []edge
+}
+
+/*
+resolveScopes resolves the scopes of a definition and the initial opinions of their edges.
+*/
+func (d Definition) resolveScopes(vars map[string]string) ([]resolvedScope, map[edge]subjectivelogic.Opinion, error) {
+	scopes := make([]resolvedScope, 0, len(d.Scopes))
+	initial := make(map[edge]subjectivelogic.Opinion)
+	for _, scope := range d.Scopes {
+		id, err := substitute(scope.ID, vars)
+		if err != nil {
+			return nil, nil, fmt.Errorf("scope %s: %w", scope.ID, err)
+		}
+		rtl, err := scope.RTL.resolve(vars)
+		if err != nil {
+			return nil, nil, fmt.Errorf("scope %s: RTL: %w", scope.ID, err)
+		}
+		resolved := resolvedScope{id: id, rtl: rtl, edges: make([]edge, 0, len(scope.Edges))}
+		for _, definedEdge := range scope.Edges {
+			trustor, err := substitute(definedEdge.Trustor, vars)
+			if err != nil {
+				return nil, nil, fmt.Errorf("scope %s: %w", scope.ID, err)
+			}
+			trustee, err := substitute(definedEdge.Trustee, vars)
+			if err != nil {
+				return nil, nil, fmt.Errorf("scope %s: %w", scope.ID, err)
+			}
+			e := edge{trustor: trustor, trustee: trustee}
+			opinion := FullUncertainty
+			if definedEdge.Opinion != nil {
+				if opinion, err = definedEdge.Opinion.resolve(vars); err != nil {
+					return nil, nil, fmt.Errorf("scope %s: opinion from %s to %s: %w", scope.ID, trustor, trustee, err)
+				}
+			}
+			if existing, exists := initial[e]; exists && existing != opinion {
+				return nil, nil, fmt.Errorf("scope %s: conflicting opinions from %s to %s", scope.ID, trustor, trustee)
+			}
+			initial[e] = opinion
+			resolved.edges = append(resolved.edges, e)
+		}
+		scopes = append(scopes, resolved)
+	}
+	return scopes, initial, nil
+}
+
+/*
+A TrustModelInstance is an instance of a declarative trust model template. Its structure is resolved when it is spawned
+and can be changed by updates adding and removing trust objects. Atomic trust opinions update all edges to their trustee
+(and trustor, if given). Opinions of different trust sources on the same edge are kept separately and passed to the TLEE
+as parallel trust relationships, which are fused by the TLEE.
+*/
+type TrustModelInstance struct {
+	id       string
+	version  int
+	template TrustModelTemplate
+
+	scopes  []resolvedScope
+	initial map[edge]subjectivelogic.Opinion
+	//scopes and initial opinions as resolved from the definition, used to restore removed trust objects
+	defined        []resolvedScope
+	definedInitial map[edge]subjectivelogic.Opinion
+	//edge->trust source->latest opinion
+	opinions    map[edge]map[core.TrustSource]subjectivelogic.QueryableOpinion
+	structure   trustmodelstructure.TrustGraphStructure
+	fingerprint uint32
+}
+
+func (t TrustModelTemplate) newInstance(id string, vars map[string]string) (*TrustModelInstance, error) {
+	scopes, initial, err := t.definition.resolveScopes(vars)
+	if err != nil {
+		return nil, err
+	}
+	tmi := &TrustModelInstance{
+		id:             id,
+		template:       t,
+		scopes:         cloneScopes(scopes),
+		initial:        maps.Clone(initial),
+		defined:        scopes,
+		definedInitial: initial,
+		opinions:       make(map[edge]map[core.TrustSource]subjectivelogic.QueryableOpinion),
+	}
+	tmi.updateStructure()
+	return tmi, nil
+}
+
+/*
+updateStructure derives the adjacency list from the edges of all scopes and calculates the fingerprint of the structure.
+*/
+func (tmi *TrustModelInstance) updateStructure() {
+	//trustor->trustees
+	adjacency := make(map[string][]string)
+	for e := range tmi.initial {
+		adjacency[e.trustor] = append(adjacency[e.trustor], e.trustee)
+	}
+	trustors := make([]string, 0, len(adjacency))
+	for trustor := range adjacency {
+		trustors = append(trustors, trustor)
+	}
+	slices.Sort(trustors)
+
+	entries := make([]trustmodelstructure.AdjacencyListEntry, 0, len(trustors))
+	var fingerprint strings.Builder
+	for _, trustor := range trustors {
+		trustees := adjacency[trustor]
+		slices.Sort(trustees)
+		entries = append(entries, internaltrustmodelstructure.NewAdjacencyEntryDTO(trustor, trustees))
+		fingerprint.WriteString(trustor + "->" + strings.Join(trustees, ",") + ";")
+	}
+	fusion, _ := fusionOperator(tmi.template.definition.Fusion)
+	discount, _ := discountOperator(tmi.template.definition.Discount)
+	tmi.structure = internaltrustmodelstructure.NewTrustGraphDTO(fusion, discount, entries)
+
+	algorithm := fnv.New32a()
+	_, err := algorithm.Write([]byte(fingerprint.String()))
+	if err == nil {
+		tmi.fingerprint = algorithm.Sum32()
+	}
+}
+
+func (tmi *TrustModelInstance) ID() string {
+	return tmi.id
+}
+
+func (tmi *TrustModelInstance) Version() int {
+	return tmi.version
+}
+
+func (tmi *TrustModelInstance) Fingerprint() uint32 {
+	return tmi.fingerprint
+}
+
+func (tmi *TrustModelInstance) Structure() trustmodelstructure.TrustGraphStructure {
+	return tmi.structure
+}
+
+func (tmi *TrustModelInstance) Values() map[string][]trustmodelstructure.TrustRelationship {
+	values := make(map[string][]trustmodelstructure.TrustRelationship, len(tmi.scopes))
+	for _, scope := range tmi.scopes {
+		relationships := make([]trustmodelstructure.TrustRelationship, 0, len(scope.edges))
+		for _, e := range scope.edges {
+			opinions := tmi.opinions[e]
+			if len(opinions) == 0 {
+				initial := tmi.initial[e]
+				relationships = append(relationships, internaltrustmodelstructure.NewTrustRelationshipDTO(e.trustor, e.trustee, &initial))
+				continue
+			}
+			sources := make([]core.TrustSource, 0, len(opinions))
+			for source := range opinions {
+				sources = append(sources, source)
+			}
+			slices.Sort(sources)
+			for _, source := range sources {
+				relationships = append(relationships, internaltrustmodelstructure.NewTrustRelationshipDTO(e.trustor, e.trustee, opinions[source]))
+			}
+		}
+		values[scope.id] = relationships
+	}
+	return values
+}
+
+func (tmi *TrustModelInstance) Template() core.TrustModelTemplate {
+	return tmi.template
+}
+
+func (tmi *TrustModelInstance) Update(update core.Update) bool {
+	oldVersion := tmi.Version()
+	switch update := update.(type) {
+	case trustmodelupdate.UpdateAtomicTrustOpinion:
+		for e := range tmi.initial {
+			if e.trustee != update.Trustee() || (update.Trustor() != "" && e.trustor != update.Trustor()) {
+				continue
+			}
+			if tmi.opinions[e] == nil {
+				tmi.opinions[e] = make(map[core.TrustSource]subjectivelogic.QueryableOpinion)
+			}
+			tmi.opinions[e][update.TrustSource()] = update.Opinion()
+			if tmi.version == oldVersion {
+				tmi.version++
+			}
+		}
+	case trustmodelupdate.AddTrustObject:
+		if tmi.addTrustObject(update) {
+			tmi.updateStructure()
+			tmi.version++
+		}
+	case trustmodelupdate.RemoveTrustObject:
+		if tmi.removeTrustObject(update) {
+			tmi.updateStructure()
+			tmi.version++
+		}
+	default:
+		//ignore
+	}
+	return oldVersion != tmi.Version()
+}
+
+/*
+addTrustObject adds the relationships of a new trust object to the scope with the identifier of the trust object. The
+scope is created if the update specifies an RTL; otherwise, the relationships are only added to an existing scope.
+Without relationships, the edges of the definition that involve the trust object and have been removed are restored.
+The function returns whether the structure has changed.
+*/
+func (tmi *TrustModelInstance) addTrustObject(update trustmodelupdate.AddTrustObject) bool {
+	if len(update.Relationships()) == 0 {
+		return tmi.restoreTrustObject(update.TrustObject())
+	}
+	index := slices.IndexFunc(tmi.scopes, func(scope resolvedScope) bool { return scope.id == update.TrustObject() })
+	if index < 0 {
+		if update.RTL() == nil {
+			return false
+		}
+		rtl, err := subjectivelogic.NewOpinion(update.RTL().Belief(), update.RTL().Disbelief(), update.RTL().Uncertainty(), update.RTL().BaseRate())
+		if err != nil {
+			return false
+		}
+		tmi.scopes = append(tmi.scopes, resolvedScope{id: update.TrustObject(), rtl: rtl})
+		index = len(tmi.scopes) - 1
+	}
+	changed := false
+	for _, relationship := range update.Relationships() {
+		e := edge{trustor: relationship.Trustor, trustee: relationship.Trustee}
+		if !relationship.Involves(update.TrustObject()) || slices.Contains(tmi.scopes[index].edges, e) {
+			continue
+		}
+		tmi.scopes[index].edges = append(tmi.scopes[index].edges, e)
+		if _, exists := tmi.initial[e]; !exists {
+			tmi.initial[e] = FullUncertainty
+		}
+		changed = true
+	}
+	return changed
+}
+
+/*
+restoreTrustObject restores the removed edges of the definition that involve the trust object.
+*/
+func (tmi *TrustModelInstance) restoreTrustObject(trustObject string) bool {
+	changed := false
+	for _, defined := range tmi.defined {
+		for _, e := range defined.edges {
+			if e.trustor != trustObject && e.trustee != trustObject {
+				continue
+			}
+			index := slices.IndexFunc(tmi.scopes, func(scope resolvedScope) bool { return scope.id == defined.id })
+			if index < 0 {
+				tmi.scopes = append(tmi.scopes, resolvedScope{id: defined.id, rtl: defined.rtl})
+				index = len(tmi.scopes) - 1
+			}
+			if slices.Contains(tmi.scopes[index].edges, e) {
+				continue
+			}
+			tmi.scopes[index].edges = append(tmi.scopes[index].edges, e)
+			tmi.initial[e] = tmi.definedInitial[e]
+			changed = true
+		}
+	}
+	return changed
+}
+
+/*
+removeTrustObject removes the edges affected by the update from all scopes, together with their opinions. Scopes
+without edges are removed as well. The function returns whether the structure has changed.
+*/
+func (tmi *TrustModelInstance) removeTrustObject(update trustmodelupdate.RemoveTrustObject) bool {
+	changed := false
+	for e := range tmi.initial {
+		if update.Removes(trustmodelupdate.TrustRelationship{Trustor: e.trustor, Trustee: e.trustee}) {
+			delete(tmi.initial, e)
+			delete(tmi.opinions, e)
+			changed = true
+		}
+	}
+	if !changed {
+		return false
+	}
+	scopes := make([]resolvedScope, 0, len(tmi.scopes))
+	for _, scope := range tmi.scopes {
+		scope.edges = slices.DeleteFunc(scope.edges, func(e edge) bool {
+			_, exists := tmi.initial[e]
+			return !exists
+		})
+		if len(scope.edges) > 0 {
+			scopes = append(scopes, scope)
+		}
+	}
+	tmi.scopes = scopes
+	return true
+}
+
+func cloneScopes(scopes []resolvedScope) []resolvedScope {
+	cloned := make([]resolvedScope, len(scopes))
+	for i, scope := range scopes {
+		cloned[i] = resolvedScope{id: scope.id, rtl: scope.rtl, edges: slices.Clone(scope.edges)}
+	}
+	return cloned
+}
+
+func (tmi *TrustModelInstance) Initialize(params map[string]interface{}) {
+	//the structure has already been resolved when spawning the instance
+	return
+}
+
+func (tmi *TrustModelInstance) Cleanup() {
+	return
+}
+
+func (tmi *TrustModelInstance) RTLs() map[string]subjectivelogic.QueryableOpinion {
+	rtls := make(map[string]subjectivelogic.QueryableOpinion, len(tmi.scopes))
+	for _, scope := range tmi.scopes {
+		rtl := scope.rtl
+		rtls[scope.id] = &rtl
+	}
+	return rtls
+}
+
+func (tmi *TrustModelInstance) String() string {
+	return core.TMIAsString(tmi)
+}
@@ -0,0 +1,128 @@
+package declarative
+
+import (
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+)
+
+/*
+trustSourceQuantifiers resolves the quantifiers of a definition.
+*/
+func (d Definition) trustSourceQuantifiers(vars map[string]string) ([]core.TrustSourceQuantifier, error) {
+	tsqs := make([]core.TrustSourceQuantifier, 0, len(d.Quantifiers))
+	for _, quantifier := range d.Quantifiers {
+		source, err := quantifier.trustSource()
+		if err != nil {
+			return nil, err
+		}
+		evidenceTypes, err := quantifier.evidenceTypes()
+		if err != nil {
+			return nil, err
+		}
+		weights := make(map[core.EvidenceType]float64, len(evidenceTypes))
+		critical := make(map[core.EvidenceType]bool, len(evidenceTypes))
+		for i, evidence := range quantifier.Evidence {
+			weight, err := evidence.Weight.resolve(vars)
+			if err != nil {
+				return nil, fmt.Errorf("quantifier for %s: weight of %s: %w", quantifier.TrustSource, evidence.Claim, err)
+			}
+			if weight < 0 {
+				return nil, fmt.Errorf("quantifier for %s: negative weight of %s", quantifier.TrustSource, evidence.Claim)
+			}
+			weights[evidenceTypes[i]] = weight
+			critical[evidenceTypes[i]] = evidence.Critical
+		}
+		baseRate := 0.5
+		if quantifier.BaseRate != nil {
+			baseRate, err = quantifier.BaseRate.resolve(vars)
+			if err != nil {
+				return nil, fmt.Errorf("quantifier for %s: base rate: %w", quantifier.TrustSource, err)
+			}
+			if baseRate < 0 || baseRate > 1 {
+				return nil, fmt.Errorf("quantifier for %s: base rate out of range", quantifier.TrustSource)
+			}
+		}
+
+		tsq := core.TrustSourceQuantifier{
+			TrustSource: source,
+			Evidence:    evidenceTypes,
+			Quantifier:  weightedQuantifier(weights, critical, baseRate),
+		}
+		for _, field := range []struct {
+			value  string
+			target *string
+		}{{quantifier.Trustor, &tsq.Trustor}, {quantifier.Trustee, &tsq.Trustee}, {quantifier.Scope, &tsq.Scope}} {
+			if *field.target, err = substitute(field.value, vars); err != nil {
+				return nil, fmt.Errorf("quantifier for %s: %w", quantifier.TrustSource, err)
+			}
+		}
+		tsqs = append(tsqs, tsq)
+	}
+	return tsqs, nil
+}
+
+/*
+weightedQuantifier creates a quantifier that adds the weight of each claim to belief for positive appraisals and to
+disbelief for negative appraisals (failed or not implemented). Claims without (known) appraisal add to uncertainty. A
+negative appraisal of a critical claim results in full disbelief. Weights summing up to more than 1 are normalized.
+*/
+func weightedQuantifier(weights map[core.EvidenceType]float64, critical map[core.EvidenceType]bool, baseRate float64) core.Quantifier {
+	sum := 0.0
+	for _, weight := range weights {
+		sum += weight
+	}
+	if sum > 1 {
+		normalized := make(map[core.EvidenceType]float64, len(weights))
+		for evidenceType, weight := range weights {
+			normalized[evidenceType] = weight / sum
+		}
+		weights = normalized
+	}
+
+	return func(values map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
+		belief, disbelief := 0.0, 0.0
+		for evidenceType, weight := range weights {
+			switch appraisal(values[evidenceType]) {
+			case positive:
+				belief += weight
+			case negative:
+				if critical[evidenceType] {
+					opinion, _ := subjectivelogic.NewOpinion(0, 1, 0, baseRate)
+					return &opinion
+				}
+				disbelief += weight
+			}
+		}
+		opinion, _ := subjectivelogic.NewOpinion(belief, disbelief, max(0, 1-belief-disbelief), baseRate)
+		return &opinion
+	}
+}
+
+const (
+	unknown = iota
+	positive
+	negative
+)
+
+/*
+appraisal classifies an evidence value. Appraisals of AIV and TCH claims are 1 (passed), 0 (failed), -1 (not
+implemented) or -2 (unknown); boolean evidence is supported as well.
+*/
+func appraisal(value interface{}) int {
+	switch value := value.(type) {
+	case int:
+		switch {
+		case value > 0:
+			return positive
+		case value == 0 || value == -1:
+			return negative
+		}
+	case bool:
+		if value {
+			return positive
+		}
+		return negative
+	}
+	return unknown
+}
@@ -0,0 +1,97 @@
+package declarative
+
+import (
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelsnapshot"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+)
+
+/*
+snapshotState is the state of an instance kept in snapshots. Edges that are part of several scopes are kept in each of
+them, together with their opinions.
+*/
+type snapshotState struct {
+	Scopes []snapshotScope `json:"scopes"`
+}
+
+type snapshotScope struct {
+	ID    string                      `json:"id"`
+	RTL   *trustmodelsnapshot.Opinion `json:"rtl"`
+	Edges []snapshotEdge              `json:"edges"`
+}
+
+type snapshotEdge struct {
+	Trustor  string                                           `json:"trustor"`
+	Trustee  string                                           `json:"trustee"`
+	Initial  *trustmodelsnapshot.Opinion                      `json:"initial"`
+	Opinions map[core.TrustSource]*trustmodelsnapshot.Opinion `json:"opinions,omitempty"`
+}
+
+func (tmi *TrustModelInstance) Snapshot() ([]byte, error) {
+	state := snapshotState{Scopes: make([]snapshotScope, 0, len(tmi.scopes))}
+	for _, scope := range tmi.scopes {
+		snapshotted := snapshotScope{
+			ID:    scope.id,
+			RTL:   trustmodelsnapshot.FromOpinion(&scope.rtl),
+			Edges: make([]snapshotEdge, 0, len(scope.edges)),
+		}
+		for _, e := range scope.edges {
+			initial := tmi.initial[e]
+			opinions := make(map[core.TrustSource]*trustmodelsnapshot.Opinion, len(tmi.opinions[e]))
+			for source, opinion := range tmi.opinions[e] {
+				opinions[source] = trustmodelsnapshot.FromOpinion(opinion)
+			}
+			snapshotted.Edges = append(snapshotted.Edges, snapshotEdge{
+				Trustor:  e.trustor,
+				Trustee:  e.trustee,
+				Initial:  trustmodelsnapshot.FromOpinion(&initial),
+				Opinions: opinions,
+			})
+		}
+		state.Scopes = append(state.Scopes, snapshotted)
+	}
+	return trustmodelsnapshot.Marshal(tmi, state)
+}
+
+func (tmi *TrustModelInstance) Restore(snapshot []byte) error {
+	var state snapshotState
+	version, err := trustmodelsnapshot.Unmarshal(tmi, snapshot, &state)
+	if err != nil {
+		return err
+	}
+	scopes := make([]resolvedScope, 0, len(state.Scopes))
+	initial := make(map[edge]subjectivelogic.Opinion)
+	opinions := make(map[edge]map[core.TrustSource]subjectivelogic.QueryableOpinion)
+	for _, snapshotted := range state.Scopes {
+		rtl, err := snapshotted.RTL.Binomial()
+		if err != nil {
+			return fmt.Errorf("scope %s: RTL: %w", snapshotted.ID, err)
+		}
+		scope := resolvedScope{id: snapshotted.ID, rtl: rtl, edges: make([]edge, 0, len(snapshotted.Edges))}
+		for _, snapshottedEdge := range snapshotted.Edges {
+			e := edge{trustor: snapshottedEdge.Trustor, trustee: snapshottedEdge.Trustee}
+			if initial[e], err = snapshottedEdge.Initial.Binomial(); err != nil {
+				return fmt.Errorf("scope %s: opinion from %s to %s: %w", snapshotted.ID, e.trustor, e.trustee, err)
+			}
+			for source, opinion := range snapshottedEdge.Opinions {
+				restored, err := opinion.Opinion()
+				if err != nil {
+					return fmt.Errorf("scope %s: opinion from %s to %s: %w", snapshotted.ID, e.trustor, e.trustee, err)
+				}
+				if opinions[e] == nil {
+					opinions[e] = make(map[core.TrustSource]subjectivelogic.QueryableOpinion)
+				}
+				opinions[e][source] = restored
+			}
+			scope.edges = append(scope.edges, e)
+		}
+		scopes = append(scopes, scope)
+	}
+	tmi.scopes = scopes
+	tmi.initial = initial
+	tmi.opinions = opinions
+	tmi.version = version
+	tmi.updateStructure()
+	return nil
+}
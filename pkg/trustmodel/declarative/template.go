@@ -0,0 +1,221 @@
+package declarative
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/parameter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+/*
+TrustModelTemplate is a core.TrustModelTemplate based on a Definition.
+*/
+type TrustModelTemplate struct {
+	definition    Definition
+	evidenceTypes []core.EvidenceType
+	signingHash   string
+}
+
+/*
+CreateTrustModelTemplate creates a template from a validated definition. The signing hash is the SHA-512 hash of the
+definition file.
+*/
+func CreateTrustModelTemplate(definition Definition, signingHash string) TrustModelTemplate {
+	evidenceTypes := make([]core.EvidenceType, 0)
+	for _, quantifier := range definition.Quantifiers {
+		quantifierEvidenceTypes, _ := quantifier.evidenceTypes()
+		for _, evidenceType := range quantifierEvidenceTypes {
+			if !slices.Contains(evidenceTypes, evidenceType) {
+				evidenceTypes = append(evidenceTypes, evidenceType)
+			}
+		}
+	}
+	return TrustModelTemplate{
+		definition:    definition,
+		evidenceTypes: evidenceTypes,
+		signingHash:   signingHash,
+	}
+}
+
+/*
+Load loads a template from a JSON or YAML file.
+*/
+func Load(path string) (TrustModelTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TrustModelTemplate{}, err
+	}
+	definition, err := Parse(data, filepath.Base(path))
+	if err != nil {
+		return TrustModelTemplate{}, err
+	}
+	hash := sha512.Sum512(data)
+	return CreateTrustModelTemplate(definition, hex.EncodeToString(hash[:])), nil
+}
+
+/*
+LoadDirectory loads the templates of all JSON and YAML files in a directory, ordered by file name. Loading fails if any
+of the files is invalid or two files define the same template version.
+*/
+func LoadDirectory(directory string) ([]TrustModelTemplate, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]TrustModelTemplate, 0)
+	identifiers := make(map[string]string)
+	for _, entry := range entries {
+		extension := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (extension != ".json" && extension != ".yaml" && extension != ".yml") {
+			continue
+		}
+		template, err := Load(filepath.Join(directory, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if file, exists := identifiers[template.Identifier()]; exists {
+			return nil, fmt.Errorf("%w: %s: template %s already defined in %s", ErrInvalidDefinition, entry.Name(), template.Identifier(), file)
+		}
+		identifiers[template.Identifier()] = entry.Name()
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+func (t TrustModelTemplate) TemplateName() string {
+	return t.definition.Name
+}
+
+func (t TrustModelTemplate) Version() string {
+	return t.definition.Version
+}
+
+func (t TrustModelTemplate) Identifier() string {
+	return fmt.Sprintf("%s@%s", t.TemplateName(), t.Version())
+}
+
+func (t TrustModelTemplate) Description() string {
+	return t.definition.Description
+}
+
+/*
+Deprecated returns whether the definition marks this template version as deprecated.
+*/
+func (t TrustModelTemplate) Deprecated() bool {
+	return t.definition.Deprecated
+}
+
+func (t TrustModelTemplate) Type() core.TrustModelTemplateType {
+	templateType, _ := t.definition.templateType()
+	return templateType
+}
+
+/*
+SpawnTriggers returns the spawn triggers of templates spawned by NEW_ENTITY.
+*/
+func (t TrustModelTemplate) SpawnTriggers() []core.SpawnTrigger {
+	triggers, _ := t.definition.spawnTriggers()
+	return triggers
+}
+
+func (t TrustModelTemplate) EvidenceTypes() []core.EvidenceType {
+	return t.evidenceTypes
+}
+
+func (t TrustModelTemplate) SigningHash() string {
+	return t.signingHash
+}
+
+/*
+ParameterSchema declares the parameters of the definition. Values are substituted as text, so all parameters are STRING
+parameters with the default values of the definition.
+*/
+func (t TrustModelTemplate) ParameterSchema() parameter.Schema {
+	names := make([]string, 0, len(t.definition.Parameters))
+	for name := range t.definition.Parameters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	schema := parameter.Schema{Parameters: make([]parameter.Definition, 0, len(names))}
+	for _, name := range names {
+		value := t.definition.Parameters[name]
+		schema.Parameters = append(schema.Parameters, parameter.Definition{
+			Name:    name,
+			Type:    parameter.STRING,
+			Default: &value,
+		})
+	}
+	return schema
+}
+
+func (t TrustModelTemplate) Spawn(params map[string]string, context core.TafContext) ([]core.TrustSourceQuantifier, core.TrustModelInstance, core.DynamicTrustModelInstanceSpawner, error) {
+	id := ""
+	if t.definition.Spawn.Trigger == STATIC {
+		var err error
+		if id, err = t.definition.staticID(t.definition.variables(params, "")); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	vars := t.definition.variables(params, id)
+	tsqs, err := t.definition.trustSourceQuantifiers(vars)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if t.definition.Spawn.Trigger != STATIC {
+		return tsqs, nil, DynamicTrustModelInstanceSpawner{template: t, params: params}, nil
+	}
+	tmi, err := t.newInstance(id, vars)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return tsqs, tmi, nil, nil
+}
+
+/*
+DynamicTrustModelInstanceSpawner spawns instances of templates triggered by new vehicles, trustees or other entities,
+using the identifier of the entity as instance identifier.
+*/
+type DynamicTrustModelInstanceSpawner struct {
+	template TrustModelTemplate
+	params   map[string]string
+}
+
+func (s DynamicTrustModelInstanceSpawner) OnNewVehicle(identifier string, params map[string]string) (core.TrustModelInstance, error) {
+	if s.template.definition.Spawn.Trigger != NEW_VEHICLE {
+		return nil, nil
+	}
+	return s.spawn(identifier, params)
+}
+
+func (s DynamicTrustModelInstanceSpawner) OnNewTrustee(identifier string, params map[string]string) (core.TrustModelInstance, error) {
+	if s.template.definition.Spawn.Trigger != NEW_TRUSTEE {
+		return nil, nil
+	}
+	return s.spawn(identifier, params)
+}
+
+func (s DynamicTrustModelInstanceSpawner) OnNewEntity(trigger core.SpawnTrigger, identifier string, params map[string]string) (core.TrustModelInstance, error) {
+	if !core.IsSpawnedBy(s.template, trigger) {
+		return nil, nil
+	}
+	return s.spawn(identifier, params)
+}
+
+/*
+spawn creates a new instance with the parameters set at Spawn() call, overwritten by the given parameters.
+*/
+func (s DynamicTrustModelInstanceSpawner) spawn(identifier string, params map[string]string) (core.TrustModelInstance, error) {
+	merged := make(map[string]string, len(s.params)+len(params))
+	for key, value := range s.params {
+		merged[key] = value
+	}
+	for key, value := range params {
+		merged[key] = value
+	}
+	return s.template.newInstance(identifier, s.template.definition.variables(merged, identifier))
+}
@@ -12,11 +12,14 @@ import (
 	tasmsg "github.com/horizon-connect-eu/go-taf/pkg/message/tas"
 	tchmsg "github.com/horizon-connect-eu/go-taf/pkg/message/tch"
 	v2xmsg "github.com/horizon-connect-eu/go-taf/pkg/message/v2x"
-	session2 "github.com/horizon-connect-eu/go-taf/pkg/trustmodel/session"
+	"github.com/horizon-connect-eu/go-taf/pkg/parameter"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/declarative"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
 	"log/slog"
+	"maps"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 type Manager struct {
@@ -26,23 +29,46 @@ type Manager struct {
 	tsm        manager.TrustSourceManager
 	//trustmodeltemplate identifier->TMT
 	trustModelTemplateRepo map[string]core.TrustModelTemplate
-	v2xObserver            EntityObserver //observer based on V2X_CPM messages
-	tchObserver            EntityObserver //observer based on TCH messages
-	crypto                 *crypto.Crypto
-	outbox                 chan core.Message
+	//trustmodeltemplate identifier->deprecated
+	deprecatedTemplates map[string]bool
+	//trustmodeltemplate identifier->TMT loaded from the template directory
+	directoryTemplates map[string]declarative.TrustModelTemplate
+	//signed manifest of attested signing hashes, nil if not configured
+	manifest *Manifest
+	//guards the template repository, which is modified in the TAM goroutine and read by other components
+	templateLock sync.RWMutex
+	//spawn trigger->observer of the entities of this trigger, only for triggers used by templates
+	observers  map[core.SpawnTrigger]*EntityObserver
+	tamChannel chan core.Command
+	crypto     *crypto.Crypto
+	outbox     chan core.Message
 }
 
 func NewManager(tafContext core.TafContext, channels core.TafChannels) (*Manager, error) {
 	tmm := &Manager{
 		tafContext:             tafContext,
 		logger:                 logging.CreateChildLogger(tafContext.Logger, "TMM"),
-		trustModelTemplateRepo: TemplateRepository,
-		v2xObserver:            CreateListener(tafContext.Configuration.V2X.NodeTTLsec, tafContext.Configuration.V2X.CheckIntervalSec),
-		tchObserver:            CreateListener(tafContext.Configuration.V2X.NodeTTLsec, tafContext.Configuration.V2X.CheckIntervalSec),
+		trustModelTemplateRepo: maps.Clone(TemplateRepository),
+		deprecatedTemplates:    make(map[string]bool),
+		directoryTemplates:     make(map[string]declarative.TrustModelTemplate),
+		observers:              make(map[core.SpawnTrigger]*EntityObserver),
+		tamChannel:             channels.TAMChannel,
 		crypto:                 tafContext.Crypto,
 		outbox:                 channels.OutgoingMessageChannel,
 	}
 
+	if tafContext.Configuration.TMM.RequireVerified && tafContext.Configuration.TMM.Manifest == "" {
+		return nil, fmt.Errorf("%w: verified templates are required, but no manifest is configured", ErrInvalidManifest)
+	}
+	//templates loaded at startup must not collide with compiled-in templates or with each other
+	if err := tmm.ReloadTemplates(); err != nil {
+		return nil, err
+	}
+	tmm.verifyCompiledTemplates()
+	for _, tmt := range tmm.trustModelTemplateRepo {
+		tmm.registerObservers(tmt)
+	}
+
 	tmtNames := make([]string, len(tmm.trustModelTemplateRepo))
 	i := 0
 	for k := range tmm.trustModelTemplateRepo {
@@ -52,33 +78,13 @@ func NewManager(tafContext core.TafContext, channels core.TafChannels) (*Manager
 
 	tmm.logger.Info("Initializing Trust Model Manager", "Available trust models", strings.Join(tmtNames, ", "))
 
-	tmm.initializeTrustModelTemplateTypes()
 	tmm.initializeTrustModelTemplates()
 
-	return tmm, nil
-}
-
-func (tmm *Manager) initializeTrustModelTemplateTypes() {
-
-	availableTypes := make(map[core.TrustModelTemplateType]bool)
-	for _, tmt := range tmm.trustModelTemplateRepo {
-		availableTypes[tmt.Type()] = true
-	}
-
-	for tmtType, available := range availableTypes {
-		if available {
-			switch tmtType {
-			case core.VEHICLE_TRIGGERED_TRUST_MODEL:
-				//register TMM as handler for V2X observer
-				tmm.v2xObserver.registerObserver(newV2xObserver(tmm))
-			case core.TRUSTEE_TRIGGERED_TRUST_MODEL:
-				//register TMM as handler for TCH observer
-				tmm.tchObserver.registerObserver(newTchObserver(tmm))
-			default: //Nothing to do
-			}
-		}
+	if tafContext.Configuration.TMM.TemplateDirectory != "" && tafContext.Configuration.TMM.ReloadIntervalSec > 0 {
+		go tmm.runTemplateReloader()
 	}
 
+	return tmm, nil
 }
 
 func (tmm *Manager) initializeTrustModelTemplates() {
@@ -101,18 +107,21 @@ func (tmm *Manager) HandleTchNotify(cmd command.HandleNotify[tchmsg.TchNotify])
 		return
 	}
 	trusteeId := match[1]
-	tmm.tchObserver.AddNode(trusteeId)
+	tmm.observe(core.TRIGGER_TCH_TRUSTEE, trusteeId)
 }
 
 func (tmm *Manager) HandleV2xCpmMessage(cmd command.HandleOneWay[v2xmsg.V2XCpm]) {
 	sender := fmt.Sprintf("%g", cmd.OneWay.SourceID)
-	tmm.v2xObserver.AddNode(sender)
+	tmm.observe(core.TRIGGER_V2X_SENDER, sender)
+	for _, object := range cmd.OneWay.PerceivedObjectContainer.Objects {
+		tmm.observe(core.TRIGGER_CPM_OBJECT, fmt.Sprintf("%g", object.ObjectID))
+	}
 
 	//check whether TMIs are interested in RefreshCPM messages
 	targetTMIIDs := make([]string, 0)
 	for _, tmt := range tmm.trustModelTemplateRepo {
-		if tmt.Type() == core.VEHICLE_TRIGGERED_TRUST_MODEL {
-			//relevant TMIs must have a VEHICLE_TRIGGERED_TRUST_MODEL TMT and the TMI ID must be identical to the sender
+		if core.IsSpawnedBy(tmt, core.TRIGGER_V2X_SENDER) {
+			//relevant TMIs must have a TMT spawned by V2X senders and the TMI ID must be identical to the sender
 			results, err := tmm.tam.QueryTMIs("//*/*/" + tmt.Identifier() + "/" + sender)
 			if err == nil {
 				targetTMIIDs = append(targetTMIIDs, results...)
@@ -135,6 +144,8 @@ func (tmm *Manager) HandleV2xCpmMessage(cmd command.HandleOneWay[v2xmsg.V2XCpm])
 }
 
 func (tmm *Manager) ResolveTMT(identifier string) core.TrustModelTemplate {
+	tmm.templateLock.RLock()
+	defer tmm.templateLock.RUnlock()
 	tmt, exists := tmm.trustModelTemplateRepo[identifier]
 	if !exists {
 		return nil
@@ -143,126 +154,47 @@ func (tmm *Manager) ResolveTMT(identifier string) core.TrustModelTemplate {
 	}
 }
 
-func (tmm *Manager) GetAllTMTs() []core.TrustModelTemplate {
-	tmts := make([]core.TrustModelTemplate, len(tmm.trustModelTemplateRepo))
-
-	i := 0
-	for _, v := range tmm.trustModelTemplateRepo {
-		tmts[i] = v
-		i++
+/*
+ValidateParameters validates the parameters of a TAS_INIT_REQUEST against the parameter schema of the template, if the
+template declares one, and returns the parameters to be passed to Spawn: the declared parameters by their declared names,
+including defaults. Parameters of templates without a schema are returned unchanged. Errors wrap
+parameter.ErrInvalidParameters.
+*/
+func (tmm *Manager) ValidateParameters(tmt core.TrustModelTemplate, params map[string]string) (map[string]string, error) {
+	provider, ok := tmt.(core.ParameterSchemaProvider)
+	if !ok {
+		return params, nil
 	}
-	return tmts
-}
-
-func (tmm *Manager) handleV2XNodeAdded(identifier string) {
-	tmm.logger.Debug("New sender vehicle added", "Identifier", identifier)
-	for sessionID, session := range tmm.tam.Sessions() {
-		if session.TrustModelTemplate().Type() == core.VEHICLE_TRIGGERED_TRUST_MODEL && session.State() == session2.ESTABLISHED {
-			spawner := session.DynamicSpawner()
-			if spawner != nil {
-				tmi, err := spawner.OnNewVehicle(identifier, nil)
-				if err != nil {
-					tmm.logger.Warn("Error while spawning trust model instance", "TMT", session.TrustModelTemplate(), "Identifier used for dynamic spawning", identifier)
-				} else {
-					tmi.Initialize(map[string]interface{}{
-						"sourceID": identifier,
-					})
-					tmm.tam.AddNewTrustModelInstance(tmi, sessionID)
-				}
-			}
-		}
+	values, err := provider.ParameterSchema().Validate(params)
+	if err != nil {
+		return nil, err
 	}
-
+	return values.Map(), nil
 }
 
-func (tmm *Manager) handleV2XNodeRemoved(identifier string) {
-	tmm.logger.Debug("Sender vehicle removed", "Identifier", identifier)
-
-	targetTMIIDs := make([]string, 0)
-	for _, tmt := range tmm.trustModelTemplateRepo {
-		if tmt.Type() == core.VEHICLE_TRIGGERED_TRUST_MODEL {
-			results, err := tmm.tam.QueryTMIs("//*/*/" + tmt.Identifier() + "/" + identifier)
-			if err == nil {
-				targetTMIIDs = append(targetTMIIDs, results...)
-			}
-		}
-	}
-
-	sessions := tmm.tam.Sessions()
-
-	for _, fullTMIID := range targetTMIIDs {
-		_, sessionID, _, tmiID := core.SplitFullTMIIdentifier(fullTMIID)
-		if session, exists := sessions[sessionID]; exists && sessions[sessionID].State() == session2.ESTABLISHED {
-			sessionTMIs := session.TrustModelInstances()
-			if _, tmiExists := sessionTMIs[tmiID]; tmiExists {
-				tmm.tam.RemoveTrustModelInstance(fullTMIID, sessionID)
-			}
-		}
-	}
-}
-
-func (tmm *Manager) handleTCHNodeAdded(identifier string) {
-	tmm.logger.Debug("New trustee added", "Identifier", identifier)
-	for sessionID, session := range tmm.tam.Sessions() {
-		if session.TrustModelTemplate().Type() == core.TRUSTEE_TRIGGERED_TRUST_MODEL && session.State() == session2.ESTABLISHED {
-			spawner := session.DynamicSpawner()
-			if spawner != nil {
-				tmi, err := spawner.OnNewTrustee(identifier, nil)
-				if err != nil {
-					tmm.logger.Warn("Error while spawning trust model instance", "TMT", session.TrustModelTemplate(), "Identifier used for dynamic spawning", identifier)
-				} else {
-					tmi.Initialize(map[string]interface{}{
-						"trusteeID": identifier,
-					})
-					tmm.tam.AddNewTrustModelInstance(tmi, sessionID)
-				}
-			}
-		}
-	}
-}
-
-func (tmm *Manager) HandleObserverEvent(cmd command.HandleObserverEvent) {
-	if cmd.Event == command.EVENT_NODE_ADDED {
-		switch cmd.Source {
-		case command.SOURCE_V2X:
-			tmm.handleV2XNodeAdded(cmd.Identifier)
-		case command.SOURCE_TCH:
-			tmm.handleTCHNodeAdded(cmd.Identifier)
-		}
-	} else if cmd.Event == command.EVENT_NODE_REMOVED {
-		switch cmd.Source {
-		case command.SOURCE_V2X:
-			tmm.handleV2XNodeRemoved(cmd.Identifier)
-		case command.SOURCE_TCH:
-			tmm.handleTCHNodeRemoved(cmd.Identifier)
-		}
+/*
+UnknownParameters returns the parameters of a TAS_INIT_REQUEST that are not declared in the parameter schema of the
+template, if the template declares one. Such parameters are ignored by the template.
+*/
+func (tmm *Manager) UnknownParameters(tmt core.TrustModelTemplate, params map[string]string) []string {
+	provider, ok := tmt.(core.ParameterSchemaProvider)
+	if !ok {
+		return nil
 	}
+	return provider.ParameterSchema().Unknown(params)
 }
 
-func (tmm *Manager) handleTCHNodeRemoved(identifier string) {
-	tmm.logger.Debug("TCH trustee removed", "Identifier", identifier)
-
-	targetTMIIDs := make([]string, 0)
-	for _, tmt := range tmm.trustModelTemplateRepo {
-		if tmt.Type() == core.TRUSTEE_TRIGGERED_TRUST_MODEL {
-			results, err := tmm.tam.QueryTMIs("//*/*/" + tmt.Identifier() + "/" + identifier)
-			if err == nil {
-				targetTMIIDs = append(targetTMIIDs, results...)
-			}
-		}
-	}
-
-	sessions := tmm.tam.Sessions()
+func (tmm *Manager) GetAllTMTs() []core.TrustModelTemplate {
+	tmm.templateLock.RLock()
+	defer tmm.templateLock.RUnlock()
+	tmts := make([]core.TrustModelTemplate, len(tmm.trustModelTemplateRepo))
 
-	for _, fullTMIID := range targetTMIIDs {
-		_, sessionID, _, tmiID := core.SplitFullTMIIdentifier(fullTMIID)
-		if session, exists := sessions[sessionID]; exists && sessions[sessionID].State() == session2.ESTABLISHED {
-			sessionTMIs := session.TrustModelInstances()
-			if _, tmiExists := sessionTMIs[tmiID]; tmiExists {
-				tmm.tam.RemoveTrustModelInstance(fullTMIID, sessionID)
-			}
-		}
+	i := 0
+	for _, v := range tmm.trustModelTemplateRepo {
+		tmts[i] = v
+		i++
 	}
+	return tmts
 }
 
 func (tmm *Manager) HandleTasTmtDiscover(cmd command.HandleRequest[tasmsg.TasTmtDiscover]) {
@@ -277,6 +209,17 @@ func (tmm *Manager) HandleTasTmtDiscover(cmd command.HandleRequest[tasmsg.TasTmt
 				Description: tmt.Description(),
 				Hash:        tmt.SigningHash(),
 			}
+			template := tmts[tmt.Identifier()]
+			if provider, ok := tmt.(core.ParameterSchemaProvider); ok {
+				template.ParameterSchema = createParameterSchemaMessage(provider.ParameterSchema())
+			}
+			if tmm.IsDeprecated(tmt.Identifier()) {
+				deprecated := true
+				template.Deprecated = &deprecated
+			}
+			verified := tmm.IsVerified(tmt.Identifier())
+			template.Verified = &verified
+			tmts[tmt.Identifier()] = template
 		}
 
 		response := tasmsg.TasTmtOffer{
@@ -294,44 +237,44 @@ func (tmm *Manager) HandleTasTmtDiscover(cmd command.HandleRequest[tasmsg.TasTmt
 	}
 }
 
-func (tmm *Manager) ListRecentV2XNodes() []string {
-	return tmm.v2xObserver.Nodes()
-}
-func (tmm *Manager) ListRecentTrustees() []string {
-	return tmm.tchObserver.Nodes()
-}
-
-type v2xObserver struct {
-	tmm *Manager
-}
-
-func newV2xObserver(tmm *Manager) *v2xObserver {
-	return &v2xObserver{
-		tmm: tmm,
+func createParameterSchemaMessage(schema parameter.Schema) *tasmsg.ParameterSchema {
+	optional := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
 	}
-}
-
-func (v2xObs *v2xObserver) handleNodeAdded(identifier string) {
-	v2xObs.tmm.tam.DispatchToSelf(command.CreateHandleObserverEvent(identifier, command.EVENT_NODE_ADDED, command.SOURCE_V2X))
-}
-func (v2xObs *v2xObserver) handleNodeRemoved(identifier string) {
-	v2xObs.tmm.tam.DispatchToSelf(command.CreateHandleObserverEvent(identifier, command.EVENT_NODE_REMOVED, command.SOURCE_V2X))
-
-}
-
-type tchObserver struct {
-	tmm *Manager
-}
-
-func newTchObserver(tmm *Manager) *tchObserver {
-	return &tchObserver{
-		tmm: tmm,
+	message := &tasmsg.ParameterSchema{
+		Parameters:  make([]tasmsg.Parameter, 0, len(schema.Parameters)),
+		Constraints: make([]tasmsg.Constraint, 0, len(schema.Constraints)),
 	}
-}
-
-func (tchObs *tchObserver) handleNodeAdded(identifier string) {
-	tchObs.tmm.tam.DispatchToSelf(command.CreateHandleObserverEvent(identifier, command.EVENT_NODE_ADDED, command.SOURCE_TCH))
-}
-func (tchObs *tchObserver) handleNodeRemoved(identifier string) {
-	tchObs.tmm.tam.DispatchToSelf(command.CreateHandleObserverEvent(identifier, command.EVENT_NODE_REMOVED, command.SOURCE_TCH))
+	for _, definition := range schema.Parameters {
+		var required *bool
+		if definition.Required {
+			required = &definition.Required
+		}
+		message.Parameters = append(message.Parameters, tasmsg.Parameter{
+			Default:     definition.Default,
+			Description: optional(definition.Description),
+			Maximum:     definition.Maximum,
+			Minimum:     definition.Minimum,
+			Name:        definition.Name,
+			Required:    required,
+			Type:        tasmsg.ParameterType(definition.Type),
+			Values:      definition.Values,
+		})
+	}
+	for _, constraint := range schema.Constraints {
+		var limit *float64
+		if constraint.Type != parameter.ALL_OR_NONE {
+			limit = &constraint.Limit
+		}
+		message.Constraints = append(message.Constraints, tasmsg.Constraint{
+			Description: optional(constraint.Description),
+			Limit:       limit,
+			Parameters:  constraint.Parameters,
+			Type:        tasmsg.ConstraintType(constraint.Type),
+		})
+	}
+	return message
 }
@@ -0,0 +1,111 @@
+package trustmodel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/crypto"
+	"os"
+)
+
+var (
+	ErrInvalidManifest     = errors.New("invalid trust model manifest")
+	ErrTemplateNotVerified = errors.New("trust model template version not verified")
+)
+
+/*
+A Manifest lists the signing hashes of attested trust model template versions. The signature covers the JSON encoding
+of Hashes and is verified with the key referenced by KeyRef in the key folder of the crypto library.
+*/
+type Manifest struct {
+	//trustmodeltemplate identifier->signing hash
+	Hashes    map[string]string `json:"hashes"`
+	KeyRef    string            `json:"keyRef"`
+	Signature string            `json:"signature"`
+}
+
+/*
+LoadManifest reads a manifest from a JSON file and verifies its signature.
+*/
+func LoadManifest(path string, cr *crypto.Crypto) (Manifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("%w: %s: %s", ErrInvalidManifest, path, err.Error())
+	}
+	if manifest.Hashes == nil {
+		return Manifest{}, fmt.Errorf("%w: %s: no hashes", ErrInvalidManifest, path)
+	}
+	verified, err := cr.VerifyTrustModelManifest(manifest.Hashes, manifest.Signature, manifest.KeyRef)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("%w: %s: %s", ErrInvalidManifest, path, err.Error())
+	}
+	if !verified {
+		return Manifest{}, fmt.Errorf("%w: %s: signature verification failed", ErrInvalidManifest, path)
+	}
+	return manifest, nil
+}
+
+/*
+Attests returns whether the manifest lists the signing hash of the trust model template version.
+*/
+func (m Manifest) Attests(tmt core.TrustModelTemplate) bool {
+	hash, exists := m.Hashes[tmt.Identifier()]
+	return exists && hash != "" && hash == tmt.SigningHash()
+}
+
+/*
+reloadManifest loads the configured manifest. If the manifest cannot be loaded or verified, the previous manifest is
+kept.
+*/
+func (tmm *Manager) reloadManifest() error {
+	path := tmm.tafContext.Configuration.TMM.Manifest
+	if path == "" {
+		return nil
+	}
+	manifest, err := LoadManifest(path, tmm.crypto)
+	if err != nil {
+		return err
+	}
+	tmm.templateLock.Lock()
+	defer tmm.templateLock.Unlock()
+	tmm.manifest = &manifest
+	return nil
+}
+
+/*
+IsVerified returns whether the signing hash of the trust model template version is attested by the current manifest.
+Without a manifest, no template version is verified.
+*/
+func (tmm *Manager) IsVerified(identifier string) bool {
+	tmm.templateLock.RLock()
+	defer tmm.templateLock.RUnlock()
+	tmt, exists := tmm.trustModelTemplateRepo[identifier]
+	return exists && tmm.manifest != nil && tmm.manifest.Attests(tmt)
+}
+
+/*
+verifyCompiledTemplates checks the compiled-in templates against the manifest at startup. Unverified template versions
+are removed if verified templates are required, and flagged otherwise.
+*/
+func (tmm *Manager) verifyCompiledTemplates() {
+	if tmm.manifest == nil {
+		return
+	}
+	for identifier := range TemplateRepository {
+		tmt, exists := tmm.trustModelTemplateRepo[identifier]
+		if !exists || tmm.manifest.Attests(tmt) {
+			continue
+		}
+		if tmm.tafContext.Configuration.TMM.RequireVerified {
+			delete(tmm.trustModelTemplateRepo, identifier)
+			tmm.logger.Warn("Trust model template refused, signing hash not attested by manifest", "TMT", identifier, "Signing Hash", tmt.SigningHash())
+		} else {
+			tmm.logger.Warn("Trust model template not verified, signing hash not attested by manifest", "TMT", identifier, "Signing Hash", tmt.SigningHash())
+		}
+	}
+}
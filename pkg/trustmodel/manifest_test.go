@@ -0,0 +1,76 @@
+package trustmodel
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeManifest(t *testing.T, directory string, hashes map[string]string) string {
+	t.Helper()
+	content, err := json.Marshal(Manifest{Hashes: hashes, KeyRef: "tmm", Signature: "signature"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTemplate(t, directory, "manifest.json", string(content))
+	return filepath.Join(directory, "manifest.json")
+}
+
+func TestManifest(t *testing.T) {
+	directory := t.TempDir()
+	manifestDirectory := t.TempDir()
+	writeTemplate(t, directory, "vehicle-0.0.1.json", templateV1)
+	tmm, _ := createTemplateManager(t, directory)
+	if err := tmm.ReloadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	v1 := tmm.ResolveTMT("VEHICLE@0.0.1")
+	if tmm.IsVerified("VEHICLE@0.0.1") {
+		t.Error("template must not be verified without a manifest")
+	}
+
+	//unattested templates are flagged unless verified templates are required
+	tmm.tafContext.Configuration.TMM.Manifest = writeManifest(t, manifestDirectory, map[string]string{"VEHICLE@0.0.1": v1.SigningHash()})
+	tmm.tafContext.Configuration.TMM.RequireVerified = true
+	writeTemplate(t, directory, "vehicle-0.0.2.json", strings.Replace(templateV1, `"version": "0.0.1"`, `"version": "0.0.2"`, 1))
+	if err := tmm.ReloadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	if !tmm.IsVerified("VEHICLE@0.0.1") {
+		t.Error("attested template has not been verified")
+	}
+	if tmm.ResolveTMT("VEHICLE@0.0.2") != nil {
+		t.Error("unattested template has not been refused")
+	}
+
+	//without the requirement, unattested templates are added but not verified until the manifest attests them
+	tmm.tafContext.Configuration.TMM.RequireVerified = false
+	if err := tmm.ReloadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	if tmm.ResolveTMT("VEHICLE@0.0.2") == nil || tmm.IsVerified("VEHICLE@0.0.2") {
+		t.Fatal("unattested template has not been added as unverified template")
+	}
+	hashes := map[string]string{
+		"VEHICLE@0.0.1": v1.SigningHash(),
+		"VEHICLE@0.0.2": tmm.ResolveTMT("VEHICLE@0.0.2").SigningHash(),
+	}
+	tmm.tafContext.Configuration.TMM.Manifest = writeManifest(t, manifestDirectory, hashes)
+	if err := tmm.ReloadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	if !tmm.IsVerified("VEHICLE@0.0.2") {
+		t.Error("template has not been verified with the updated manifest")
+	}
+
+	//invalid manifests are rejected and the previous manifest is kept
+	writeTemplate(t, manifestDirectory, "manifest.json", `{"keyRef": "tmm"}`)
+	if err := tmm.ReloadTemplates(); !errors.Is(err, ErrInvalidManifest) {
+		t.Errorf("expected %v, got %v", ErrInvalidManifest, err)
+	}
+	if !tmm.IsVerified("VEHICLE@0.0.2") {
+		t.Error("previous manifest has not been kept")
+	}
+}
@@ -27,6 +27,9 @@ func CreateListener(ttlSeconds int, checkIntervalSeconds int) EntityObserver {
 		observers: make(map[observer]bool),
 		lock:      &sync.RWMutex{},
 	}
+	if checkIntervalSeconds <= 0 {
+		return listener
+	}
 	go func() {
 		for now := range time.Tick(time.Duration(checkIntervalSeconds) * time.Second) {
 			listener.lock.Lock()
@@ -43,10 +46,14 @@ func CreateListener(ttlSeconds int, checkIntervalSeconds int) EntityObserver {
 }
 
 func (l *EntityObserver) registerObserver(observer observer) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
 	l.observers[observer] = true
 }
 
 func (l *EntityObserver) removeObserver(observer observer) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
 	delete(l.observers, observer)
 }
 
@@ -84,6 +91,16 @@ func (l *EntityObserver) RemoveNode(identifier string) {
 	}
 }
 
+/*
+HasNode returns whether an entity is currently considered present.
+*/
+func (l *EntityObserver) HasNode(identifier string) bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	_, exists := l.nodes[identifier]
+	return exists
+}
+
 func (l *EntityObserver) Nodes() []string {
 	l.lock.RLock()
 	defer l.lock.RUnlock()
@@ -1,6 +1,9 @@
 package session
 
-import "github.com/horizon-connect-eu/go-taf/pkg/core"
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+)
 
 /*
 State specifies the state a Session is in.
@@ -106,6 +109,27 @@ type Session interface {
 		TrustSourceQuantifiers returns the list of core.TrustSourceQuantifier(s) set of this Session.
 	*/
 	TrustSourceQuantifiers() []core.TrustSourceQuantifier
+
+	/*
+		SetRTL overrides the RTL of a proposition for TMIs of this Session at runtime. An empty tmiID applies the RTL to all
+		TMIs of this Session, an empty propositionID to all propositions. A nil rtl removes the override again.
+	*/
+	SetRTL(tmiID string, propositionID string, rtl subjectivelogic.QueryableOpinion)
+
+	/*
+		RTLs returns the RTL overrides that apply to the TMI with the given (short) TMI-ID, keyed by proposition. The entry
+		with the empty key applies to all propositions without an entry of their own. Overrides for the TMI take precedence
+		over overrides for the whole Session. The map is nil if there are no overrides.
+	*/
+	RTLs(tmiID string) map[string]subjectivelogic.QueryableOpinion
+}
+
+/*
+rtlScope identifies the TMI and proposition an RTL override applies to; empty fields match all TMIs or propositions.
+*/
+type rtlScope struct {
+	tmiID       string
+	proposition string
 }
 
 type Instance struct {
@@ -117,6 +141,7 @@ type Instance struct {
 	subscriptions map[string]bool
 	spawner       core.DynamicTrustModelInstanceSpawner
 	tsqs          []core.TrustSourceQuantifier
+	rtls          map[rtlScope]subjectivelogic.QueryableOpinion
 }
 
 func NewInstance(id, client string, tmt core.TrustModelTemplate) Session {
@@ -124,6 +149,7 @@ func NewInstance(id, client string, tmt core.TrustModelTemplate) Session {
 		id:            id,
 		tMIs:          make(map[string]string),
 		subscriptions: make(map[string]bool),
+		rtls:          make(map[rtlScope]subjectivelogic.QueryableOpinion),
 		client:        client,
 		tmt:           tmt,
 		state:         INITIALIZING,
@@ -203,3 +229,41 @@ func (s *Instance) SetTrustSourceQuantifiers(tsqs []core.TrustSourceQuantifier)
 func (s *Instance) TrustSourceQuantifiers() []core.TrustSourceQuantifier {
 	return s.tsqs
 }
+
+func (s *Instance) SetRTL(tmiID string, propositionID string, rtl subjectivelogic.QueryableOpinion) {
+	scope := rtlScope{tmiID: tmiID, proposition: propositionID}
+	if rtl == nil {
+		delete(s.rtls, scope)
+	} else {
+		s.rtls[scope] = rtl
+	}
+}
+
+func (s *Instance) RTLs(tmiID string) map[string]subjectivelogic.QueryableOpinion {
+	var rtls map[string]subjectivelogic.QueryableOpinion
+	set := func(proposition string, rtl subjectivelogic.QueryableOpinion) {
+		if rtls == nil {
+			rtls = make(map[string]subjectivelogic.QueryableOpinion)
+		}
+		rtls[proposition] = rtl
+	}
+	tmiWide, hasTmiWide := s.rtls[rtlScope{tmiID: tmiID}]
+	hasTmiWide = hasTmiWide && tmiID != ""
+	for scope, rtl := range s.rtls {
+		switch {
+		case scope.tmiID == "" && scope.proposition != "" && !hasTmiWide:
+			//session-wide override of a proposition, unless superseded by an override for all propositions of the TMI
+			if _, exists := s.rtls[rtlScope{tmiID: tmiID, proposition: scope.proposition}]; !exists {
+				set(scope.proposition, rtl)
+			}
+		case scope.tmiID == tmiID && tmiID != "" && scope.proposition != "":
+			set(scope.proposition, rtl)
+		}
+	}
+	if hasTmiWide {
+		set("", tmiWide)
+	} else if sessionWide, exists := s.rtls[rtlScope{}]; exists {
+		set("", sessionWide)
+	}
+	return rtls
+}
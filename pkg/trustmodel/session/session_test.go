@@ -0,0 +1,52 @@
+package session
+
+import (
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"testing"
+)
+
+func createRTL(belief float64) subjectivelogic.QueryableOpinion {
+	opinion, _ := subjectivelogic.NewOpinion(belief, 0, 1-belief, 0.5)
+	return &opinion
+}
+
+func TestRTLOverrides(t *testing.T) {
+	sess := NewInstance("SES-1", "client", nil)
+	if rtls := sess.RTLs("vehicle_1"); rtls != nil {
+		t.Fatalf("expected no overrides, got %v", rtls)
+	}
+
+	sess.SetRTL("", "", createRTL(0.1))
+	sess.SetRTL("", "p1", createRTL(0.2))
+	sess.SetRTL("vehicle_1", "p2", createRTL(0.3))
+
+	rtls := sess.RTLs("vehicle_1")
+	expected := map[string]float64{"": 0.1, "p1": 0.2, "p2": 0.3}
+	if len(rtls) != len(expected) {
+		t.Fatalf("expected %d overrides, got %v", len(expected), rtls)
+	}
+	for proposition, belief := range expected {
+		if rtls[proposition].Belief() != belief {
+			t.Errorf("expected belief %f for proposition '%s', got %f", belief, proposition, rtls[proposition].Belief())
+		}
+	}
+	if rtls := sess.RTLs("vehicle_2"); len(rtls) != 2 || rtls["p2"] != nil {
+		t.Errorf("expected only session-wide overrides for other TMIs, got %v", rtls)
+	}
+
+	//An override for all propositions of a TMI supersedes session-wide overrides of single propositions
+	sess.SetRTL("vehicle_1", "", createRTL(0.4))
+	rtls = sess.RTLs("vehicle_1")
+	if len(rtls) != 2 || rtls[""].Belief() != 0.4 || rtls["p2"].Belief() != 0.3 {
+		t.Errorf("unexpected overrides: %v", rtls)
+	}
+
+	//Removing overrides
+	sess.SetRTL("vehicle_1", "", nil)
+	sess.SetRTL("vehicle_1", "p2", nil)
+	sess.SetRTL("", "p1", nil)
+	sess.SetRTL("", "", nil)
+	if rtls := sess.RTLs("vehicle_1"); rtls != nil {
+		t.Errorf("expected no overrides after removal, got %v", rtls)
+	}
+}
@@ -0,0 +1,180 @@
+package trustmodel
+
+import (
+	"errors"
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/command"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/declarative"
+	"time"
+)
+
+var (
+	ErrTemplateExists   = errors.New("trust model template version already registered")
+	ErrUnknownTemplate  = errors.New("unknown trust model template version")
+	ErrTemplateInUse    = errors.New("trust model template version still in use")
+	ErrTemplateModified = errors.New("trust model template version has been modified")
+)
+
+/*
+AddTemplate registers a new trust model template version at runtime. Template versions are immutable: registering a
+different template under an existing identifier fails, whereas registering the same template (i.e., with the same signing
+hash) again has no effect. Different versions of a template coexist. If verified templates are required, templates whose
+signing hash is not attested by the manifest are refused.
+*/
+func (tmm *Manager) AddTemplate(tmt core.TrustModelTemplate) error {
+	tmm.templateLock.Lock()
+	defer tmm.templateLock.Unlock()
+
+	if existing, exists := tmm.trustModelTemplateRepo[tmt.Identifier()]; exists {
+		if existing.SigningHash() != "" && existing.SigningHash() == tmt.SigningHash() {
+			return nil
+		}
+		return fmt.Errorf("%w: %s", ErrTemplateExists, tmt.Identifier())
+	}
+	verified := tmm.manifest != nil && tmm.manifest.Attests(tmt)
+	if !verified && tmm.tafContext.Configuration.TMM.RequireVerified {
+		return fmt.Errorf("%w: signing hash of %s not attested by manifest", ErrTemplateNotVerified, tmt.Identifier())
+	}
+	tmm.trustModelTemplateRepo[tmt.Identifier()] = tmt
+	tmm.registerObservers(tmt)
+	tmm.logger.Info("Trust model template added", "TMT", tmt.Identifier(), "Signing Hash", tmt.SigningHash(), "Verified", verified)
+	return nil
+}
+
+/*
+DeprecateTemplate marks a trust model template version as deprecated or not. Sessions can still be initialized with a
+deprecated version, but the TAS_INIT_RESPONSE contains a warning.
+*/
+func (tmm *Manager) DeprecateTemplate(identifier string, deprecated bool) error {
+	tmm.templateLock.Lock()
+	defer tmm.templateLock.Unlock()
+
+	if _, exists := tmm.trustModelTemplateRepo[identifier]; !exists {
+		return fmt.Errorf("%w: %s", ErrUnknownTemplate, identifier)
+	}
+	if deprecated {
+		tmm.deprecatedTemplates[identifier] = true
+		tmm.logger.Info("Trust model template deprecated", "TMT", identifier)
+	} else {
+		delete(tmm.deprecatedTemplates, identifier)
+	}
+	return nil
+}
+
+func (tmm *Manager) IsDeprecated(identifier string) bool {
+	tmm.templateLock.RLock()
+	defer tmm.templateLock.RUnlock()
+	return tmm.deprecatedTemplates[identifier]
+}
+
+/*
+RemoveTemplate retires a trust model template version so that no new sessions can be initialized with it. Removal is
+refused as long as sessions use the version; these sessions keep the version they have been initialized with until they
+are torn down. RemoveTemplate must be called from the TAM goroutine.
+*/
+func (tmm *Manager) RemoveTemplate(identifier string) error {
+	inUse := 0
+	for _, session := range tmm.tam.Sessions() {
+		if session.TrustModelTemplate().Identifier() == identifier {
+			inUse++
+		}
+	}
+
+	tmm.templateLock.Lock()
+	defer tmm.templateLock.Unlock()
+
+	if _, exists := tmm.trustModelTemplateRepo[identifier]; !exists {
+		return fmt.Errorf("%w: %s", ErrUnknownTemplate, identifier)
+	}
+	if inUse > 0 {
+		return fmt.Errorf("%w: %s is used by %d session(s)", ErrTemplateInUse, identifier, inUse)
+	}
+	delete(tmm.trustModelTemplateRepo, identifier)
+	delete(tmm.deprecatedTemplates, identifier)
+	tmm.logger.Info("Trust model template removed", "TMT", identifier)
+	return nil
+}
+
+/*
+ReloadTemplates synchronizes the declarative templates with the template directory: templates of new files are added,
+deprecation flags are updated, and templates whose files have been deleted are removed unless they are still in use (in
+which case removal is retried with the next reload). Modified files of already loaded template versions are rejected, as
+template versions are immutable. If the directory cannot be loaded completely, nothing is changed. The manifest is
+reloaded beforehand, so that refused template versions are added once they have been attested.
+*/
+func (tmm *Manager) ReloadTemplates() error {
+	if err := tmm.reloadManifest(); err != nil {
+		return err
+	}
+	directory := tmm.tafContext.Configuration.TMM.TemplateDirectory
+	if directory == "" {
+		return nil
+	}
+	templates, err := declarative.LoadDirectory(directory)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, 0)
+	loaded := make(map[string]bool, len(templates))
+	for _, template := range templates {
+		loaded[template.Identifier()] = true
+		if existing, known := tmm.directoryTemplates[template.Identifier()]; !known {
+			if err := tmm.AddTemplate(template); errors.Is(err, ErrTemplateNotVerified) {
+				//refused templates are retried with the next reload
+				tmm.logger.Warn("Trust model template refused", "Error", err)
+				continue
+			} else if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			tmm.directoryTemplates[template.Identifier()] = template
+		} else if existing.SigningHash() != template.SigningHash() {
+			errs = append(errs, fmt.Errorf("%w: %s (add a new version instead)", ErrTemplateModified, template.Identifier()))
+			continue
+		}
+		if template.Deprecated() != tmm.IsDeprecated(template.Identifier()) {
+			if err := tmm.DeprecateTemplate(template.Identifier(), template.Deprecated()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	for identifier := range tmm.directoryTemplates {
+		if loaded[identifier] {
+			continue
+		}
+		if err := tmm.RemoveTemplate(identifier); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delete(tmm.directoryTemplates, identifier)
+	}
+	return errors.Join(errs...)
+}
+
+func (tmm *Manager) HandleTemplateReload(cmd command.HandleTemplateReload) {
+	if err := tmm.ReloadTemplates(); err != nil {
+		tmm.logger.Warn("Error while reloading trust model templates", "Error", err)
+	}
+}
+
+/*
+runTemplateReloader periodically triggers a reload of the template directory in the TAM goroutine.
+*/
+func (tmm *Manager) runTemplateReloader() {
+	ticker := time.NewTicker(time.Duration(tmm.tafContext.Configuration.TMM.ReloadIntervalSec) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-tmm.tafContext.Context.Done():
+			return
+		case <-ticker.C:
+			select {
+			case tmm.tamChannel <- command.CreateHandleTemplateReload():
+			case <-tmm.tafContext.Context.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package trustmodel
+
+import (
+	"errors"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/crypto"
+	"github.com/horizon-connect-eu/go-taf/pkg/manager"
+	"github.com/horizon-connect-eu/go-taf/pkg/parameter"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/declarative"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/session"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const templateV1 = `{
+  "name": "VEHICLE",
+  "version": "0.0.1",
+  "description": "Trustworthiness of the ego vehicle.",
+  "spawn": {"trigger": "STATIC"},
+  "trustObjects": ["TAF", "VC"],
+  "fusion": "CUMULATIVE",
+  "discount": "DEFAULT",
+  "scopes": [{"id": "VC", "rtl": {"belief": 0.7, "disbelief": 0.1, "uncertainty": 0.2, "baseRate": 0.5}, "edges": [{"trustor": "TAF", "trustee": "VC"}]}],
+  "quantifiers": [{"trustSource": "AIV", "trustee": "VC", "scope": "VC", "evidence": [{"claim": "SECURE_BOOT", "weight": 0.5}]}]
+}`
+
+/*
+fakeTAM provides the sessions of a TAM; all other functions of the interface are not used by the template management.
+*/
+type fakeTAM struct {
+	manager.TrustAssessmentManager
+	sessions map[string]session.Session
+}
+
+func (tam *fakeTAM) Sessions() map[string]session.Session {
+	return tam.sessions
+}
+
+func createTemplateManager(t *testing.T, directory string) (*Manager, *fakeTAM) {
+	t.Helper()
+	tam := &fakeTAM{sessions: make(map[string]session.Session)}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cr, err := crypto.NewCrypto(logger, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmm := &Manager{
+		logger:                 logger,
+		crypto:                 cr,
+		tam:                    tam,
+		trustModelTemplateRepo: make(map[string]core.TrustModelTemplate),
+		deprecatedTemplates:    make(map[string]bool),
+		directoryTemplates:     make(map[string]declarative.TrustModelTemplate),
+		observers:              make(map[core.SpawnTrigger]*EntityObserver),
+	}
+	tmm.tafContext.Configuration.TMM.TemplateDirectory = directory
+	return tmm, tam
+}
+
+func writeTemplate(t *testing.T, directory string, file string, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(directory, file), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAddTemplate(t *testing.T) {
+	directory := t.TempDir()
+	writeTemplate(t, directory, "vehicle.json", templateV1)
+	template, err := declarative.Load(filepath.Join(directory, "vehicle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmm, _ := createTemplateManager(t, "")
+
+	if err := tmm.AddTemplate(template); err != nil {
+		t.Fatal(err)
+	}
+	if tmm.ResolveTMT("VEHICLE@0.0.1") == nil {
+		t.Error("added template cannot be resolved")
+	}
+	if err := tmm.AddTemplate(template); err != nil {
+		t.Errorf("adding the same template again should have no effect, got %v", err)
+	}
+
+	writeTemplate(t, directory, "vehicle.json", templateV1+"\n")
+	modified, err := declarative.Load(filepath.Join(directory, "vehicle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tmm.AddTemplate(modified); !errors.Is(err, ErrTemplateExists) {
+		t.Errorf("expected %v, got %v", ErrTemplateExists, err)
+	}
+}
+
+func TestDeprecateTemplate(t *testing.T) {
+	directory := t.TempDir()
+	writeTemplate(t, directory, "vehicle.json", templateV1)
+	tmm, _ := createTemplateManager(t, directory)
+	if err := tmm.ReloadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tmm.DeprecateTemplate("VEHICLE@0.0.1", true); err != nil || !tmm.IsDeprecated("VEHICLE@0.0.1") {
+		t.Errorf("template has not been deprecated: %v", err)
+	}
+	if err := tmm.DeprecateTemplate("VEHICLE@0.0.1", false); err != nil || tmm.IsDeprecated("VEHICLE@0.0.1") {
+		t.Errorf("deprecation has not been revoked: %v", err)
+	}
+	if err := tmm.DeprecateTemplate("VEHICLE@9.9.9", true); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("expected %v, got %v", ErrUnknownTemplate, err)
+	}
+}
+
+func TestReloadTemplates(t *testing.T) {
+	directory := t.TempDir()
+	writeTemplate(t, directory, "vehicle-0.0.1.json", templateV1)
+	tmm, tam := createTemplateManager(t, directory)
+	if err := tmm.ReloadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	v1 := tmm.ResolveTMT("VEHICLE@0.0.1")
+	if v1 == nil {
+		t.Fatal("template has not been loaded")
+	}
+
+	//new versions coexist with old ones, which can be deprecated via their files
+	writeTemplate(t, directory, "vehicle-0.0.2.json", strings.Replace(templateV1, `"version": "0.0.1"`, `"version": "0.0.2"`, 1))
+	writeTemplate(t, directory, "vehicle-0.0.1.json", strings.Replace(templateV1, `"version": "0.0.1",`, `"version": "0.0.1", "deprecated": true,`, 1))
+	err := tmm.ReloadTemplates()
+	if !errors.Is(err, ErrTemplateModified) {
+		t.Errorf("expected %v, got %v", ErrTemplateModified, err)
+	}
+	if tmm.ResolveTMT("VEHICLE@0.0.2") == nil {
+		t.Error("new template version has not been loaded")
+	}
+	if tmm.ResolveTMT("VEHICLE@0.0.1").SigningHash() != v1.SigningHash() || tmm.IsDeprecated("VEHICLE@0.0.1") {
+		t.Error("modified template version must not replace the loaded one")
+	}
+
+	//templates in use are retired once their sessions have been torn down
+	if err := os.Remove(filepath.Join(directory, "vehicle-0.0.1.json")); err != nil {
+		t.Fatal(err)
+	}
+	tam.sessions["session"] = session.NewInstance("session", "client", v1)
+	if err := tmm.ReloadTemplates(); !errors.Is(err, ErrTemplateInUse) {
+		t.Errorf("expected %v, got %v", ErrTemplateInUse, err)
+	}
+	if tmm.ResolveTMT("VEHICLE@0.0.1") == nil {
+		t.Error("template version in use has been removed")
+	}
+	delete(tam.sessions, "session")
+	if err := tmm.ReloadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	if tmm.ResolveTMT("VEHICLE@0.0.1") != nil || tmm.ResolveTMT("VEHICLE@0.0.2") == nil {
+		t.Error("only the deleted template version should have been removed")
+	}
+}
+
+/*
+schemaTemplate is a template declaring a parameter schema; all other functions of the interface are not used.
+*/
+type schemaTemplate struct {
+	core.TrustModelTemplate
+	schema parameter.Schema
+}
+
+func (t schemaTemplate) ParameterSchema() parameter.Schema {
+	return t.schema
+}
+
+func TestValidateParameters(t *testing.T) {
+	tmm, _ := createTemplateManager(t, "")
+	weight := "0.5"
+	tmt := schemaTemplate{schema: parameter.Schema{Parameters: []parameter.Definition{
+		{Name: "WEIGHT", Type: parameter.NUMBER, Default: &weight},
+		{Name: "ENABLED", Type: parameter.BOOLEAN},
+	}}}
+
+	params, err := tmm.ValidateParameters(tmt, map[string]string{"enabled": "true", "FOO": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(params) != 2 || params["ENABLED"] != "true" || params["WEIGHT"] != "0.5" {
+		t.Errorf("expected declared parameters by declared names including defaults, got %v", params)
+	}
+	if _, err := tmm.ValidateParameters(tmt, map[string]string{"ENABLED": "maybe"}); !errors.Is(err, parameter.ErrInvalidParameters) {
+		t.Errorf("expected invalid parameters to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package trustmodel
+
+import (
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/command"
+	"github.com/horizon-connect-eu/go-taf/pkg/config"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	aivmsg "github.com/horizon-connect-eu/go-taf/pkg/message/aiv"
+	mbdmsg "github.com/horizon-connect-eu/go-taf/pkg/message/mbd"
+	v2xmsg "github.com/horizon-connect-eu/go-taf/pkg/message/v2x"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/session"
+	"strconv"
+)
+
+/*
+registerObservers creates the observers of the spawn triggers of a template, unless they exist already.
+*/
+func (tmm *Manager) registerObservers(tmt core.TrustModelTemplate) {
+	for _, trigger := range core.TemplateSpawnTriggers(tmt) {
+		if _, exists := tmm.observers[trigger]; exists {
+			continue
+		}
+		settings := observerSettings(tmm.tafContext.Configuration.TMM.SpawnTriggers, trigger)
+		observer := CreateListener(settings.TTLsec, settings.CheckIntervalSec)
+		observer.registerObserver(&triggerObserver{tmm: tmm, trigger: trigger})
+		tmm.observers[trigger] = &observer
+		tmm.logger.Debug("Observing entities for spawn trigger", "Trigger", trigger.String(), "TTL", settings.TTLsec)
+	}
+}
+
+func observerSettings(settings config.SpawnTriggers, trigger core.SpawnTrigger) config.EntityObserver {
+	switch trigger {
+	case core.TRIGGER_V2X_SENDER:
+		return settings.V2XSender
+	case core.TRIGGER_TCH_TRUSTEE:
+		return settings.TCHTrustee
+	case core.TRIGGER_MBD_SOURCE:
+		return settings.MBDSource
+	case core.TRIGGER_NTM_SOURCE:
+		return settings.NTMSource
+	case core.TRIGGER_AIV_TRUSTEE:
+		return settings.AIVTrustee
+	default:
+		return settings.CPMObject
+	}
+}
+
+/*
+observe marks an entity of a spawn trigger as present. Entities of triggers not used by any template are ignored.
+*/
+func (tmm *Manager) observe(trigger core.SpawnTrigger, identifier string) {
+	if observer, exists := tmm.observers[trigger]; exists {
+		observer.AddNode(identifier)
+	}
+}
+
+/*
+ListRecentEntities returns the entities of a spawn trigger that are currently considered present.
+*/
+func (tmm *Manager) ListRecentEntities(trigger core.SpawnTrigger) []string {
+	if observer, exists := tmm.observers[trigger]; exists {
+		return observer.Nodes()
+	}
+	return []string{}
+}
+
+func (tmm *Manager) HandleMbdNotify(cmd command.HandleNotify[mbdmsg.MBDNotify]) {
+	tmm.observe(core.TRIGGER_MBD_SOURCE, fmt.Sprintf("%g", cmd.Notify.CpmReport.Content.V2XPduEvidence.SourceID))
+}
+
+func (tmm *Manager) HandleV2xNtm(cmd command.HandleNotify[v2xmsg.V2XNtm]) {
+	for _, entry := range cmd.Notify.V2XSourceSet {
+		tmm.observe(core.TRIGGER_NTM_SOURCE, strconv.FormatInt(entry.V2XSourceID, 10))
+	}
+}
+
+func (tmm *Manager) HandleAivNotify(cmd command.HandleNotify[aivmsg.AivNotify]) {
+	for _, report := range cmd.Notify.TrusteeReports {
+		if report.TrusteeID != nil {
+			tmm.observe(core.TRIGGER_AIV_TRUSTEE, *report.TrusteeID)
+		}
+	}
+}
+
+func (tmm *Manager) HandleObserverEvent(cmd command.HandleObserverEvent) {
+	if cmd.Event == command.EVENT_NODE_ADDED {
+		tmm.handleEntityAdded(cmd.Trigger, cmd.Identifier)
+	} else if cmd.Event == command.EVENT_NODE_REMOVED {
+		tmm.handleEntityRemoved(cmd.Trigger, cmd.Identifier)
+	}
+}
+
+/*
+SpawnForRecentEntities spawns trust model instances of a new session for all present entities of the spawn triggers of
+its template.
+*/
+func (tmm *Manager) SpawnForRecentEntities(sessionID string) {
+	sess, exists := tmm.tam.Sessions()[sessionID]
+	if !exists || sess.DynamicSpawner() == nil {
+		return
+	}
+	for _, trigger := range core.TemplateSpawnTriggers(sess.TrustModelTemplate()) {
+		for _, identifier := range tmm.ListRecentEntities(trigger) {
+			tmm.spawnForEntity(sessionID, sess, trigger, identifier)
+		}
+	}
+}
+
+func (tmm *Manager) handleEntityAdded(trigger core.SpawnTrigger, identifier string) {
+	tmm.logger.Debug("New entity added", "Trigger", trigger.String(), "Identifier", identifier)
+	for sessionID, sess := range tmm.tam.Sessions() {
+		if sess.State() == session.ESTABLISHED && sess.DynamicSpawner() != nil && core.IsSpawnedBy(sess.TrustModelTemplate(), trigger) {
+			tmm.spawnForEntity(sessionID, sess, trigger, identifier)
+		}
+	}
+}
+
+/*
+spawnForEntity spawns a trust model instance of a session for a new entity, unless the spawner declines or the session
+already contains an instance with the same identifier (e.g., spawned by another trigger).
+*/
+func (tmm *Manager) spawnForEntity(sessionID string, sess session.Session, trigger core.SpawnTrigger, identifier string) {
+	tmi, err := core.SpawnOnTrigger(sess.DynamicSpawner(), trigger, identifier, nil)
+	if err != nil {
+		tmm.logger.Warn("Error while spawning trust model instance", "TMT", sess.TrustModelTemplate(), "Trigger", trigger.String(), "Identifier used for dynamic spawning", identifier)
+		return
+	}
+	if tmi == nil || sess.HasTMI(tmi.ID()) {
+		return
+	}
+	tmi.Initialize(SpawnParameters(trigger, identifier))
+	tmm.tam.AddNewTrustModelInstance(tmi, sessionID)
+}
+
+/*
+SpawnParameters returns the runtime parameters passed to the Initialize function of a spawned trust model instance.
+Besides the trigger and the identifier of the entity, the parameters include the identifier under the names used by
+instances spawned for vehicles ("SourceId") and trustees ("trusteeID").
+*/
+func SpawnParameters(trigger core.SpawnTrigger, identifier string) map[string]interface{} {
+	params := map[string]interface{}{
+		"trigger":    trigger.String(),
+		"identifier": identifier,
+	}
+	switch trigger {
+	case core.TRIGGER_V2X_SENDER:
+		params["SourceId"] = identifier
+	case core.TRIGGER_TCH_TRUSTEE:
+		params["trusteeID"] = identifier
+	}
+	return params
+}
+
+/*
+handleEntityRemoved removes the trust model instances spawned for an entity that is no longer present. Instances of
+templates with several spawn triggers are only removed once the entity is absent for all of them.
+*/
+func (tmm *Manager) handleEntityRemoved(trigger core.SpawnTrigger, identifier string) {
+	tmm.logger.Debug("Entity removed", "Trigger", trigger.String(), "Identifier", identifier)
+
+	targetTMIIDs := make([]string, 0)
+	for _, tmt := range tmm.trustModelTemplateRepo {
+		if !core.IsSpawnedBy(tmt, trigger) || tmm.isPresent(tmt, identifier) {
+			continue
+		}
+		results, err := tmm.tam.QueryTMIs("//*/*/" + tmt.Identifier() + "/" + identifier)
+		if err == nil {
+			targetTMIIDs = append(targetTMIIDs, results...)
+		}
+	}
+
+	sessions := tmm.tam.Sessions()
+
+	for _, fullTMIID := range targetTMIIDs {
+		_, sessionID, _, tmiID := core.SplitFullTMIIdentifier(fullTMIID)
+		if sess, exists := sessions[sessionID]; exists && sess.State() == session.ESTABLISHED && sess.HasTMI(tmiID) {
+			tmm.tam.RemoveTrustModelInstance(fullTMIID, sessionID)
+		}
+	}
+}
+
+/*
+isPresent returns whether an entity is present for any spawn trigger of a template.
+*/
+func (tmm *Manager) isPresent(tmt core.TrustModelTemplate, identifier string) bool {
+	for _, trigger := range core.TemplateSpawnTriggers(tmt) {
+		if observer, exists := tmm.observers[trigger]; exists && observer.HasNode(identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+triggerObserver forwards the events of the observer of a spawn trigger to the TAM goroutine.
+*/
+type triggerObserver struct {
+	tmm     *Manager
+	trigger core.SpawnTrigger
+}
+
+func (o *triggerObserver) handleNodeAdded(identifier string) {
+	o.tmm.tam.DispatchToSelf(command.CreateHandleObserverEvent(identifier, command.EVENT_NODE_ADDED, o.trigger))
+}
+
+func (o *triggerObserver) handleNodeRemoved(identifier string) {
+	o.tmm.tam.DispatchToSelf(command.CreateHandleObserverEvent(identifier, command.EVENT_NODE_REMOVED, o.trigger))
+}
@@ -0,0 +1,118 @@
+package trustmodel
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/command"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	mbdmsg "github.com/horizon-connect-eu/go-taf/pkg/message/mbd"
+	v2xmsg "github.com/horizon-connect-eu/go-taf/pkg/message/v2x"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/declarative"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/session"
+	"slices"
+	"testing"
+)
+
+const entityTemplate = `{
+  "name": "SOURCES",
+  "version": "0.0.1",
+  "spawn": {"trigger": "NEW_ENTITY", "entities": ["MBD_SOURCE", "NTM_SOURCE"]},
+  "trustObjects": ["TAF", "vehicle_${ID}"],
+  "scopes": [{"id": "vehicle_${ID}", "rtl": {"belief": 0.7, "disbelief": 0.1, "uncertainty": 0.2, "baseRate": 0.5}, "edges": [{"trustor": "TAF", "trustee": "vehicle_${ID}"}]}]
+}`
+
+/*
+triggerTAM records the observer events dispatched by the TMM and the TMIs added to and removed from sessions.
+*/
+type triggerTAM struct {
+	fakeTAM
+	events  []command.HandleObserverEvent
+	removed []string
+}
+
+func (tam *triggerTAM) DispatchToSelf(cmd core.Command) {
+	tam.events = append(tam.events, cmd.(command.HandleObserverEvent))
+}
+
+func (tam *triggerTAM) AddNewTrustModelInstance(instance core.TrustModelInstance, sessionID string) {
+	sess := tam.sessions[sessionID]
+	sess.TrustModelInstances()[instance.ID()] = core.MergeFullTMIIdentifier(sess.Client(), sessionID, sess.TrustModelTemplate().Identifier(), instance.ID())
+}
+
+func (tam *triggerTAM) RemoveTrustModelInstance(fullTMIid string, sessionID string) {
+	tam.removed = append(tam.removed, fullTMIid)
+}
+
+func (tam *triggerTAM) QueryTMIs(query string) ([]string, error) {
+	results := make([]string, 0)
+	for _, sess := range tam.sessions {
+		for _, fullTMIID := range sess.TrustModelInstances() {
+			_, _, tmtID, tmiID := core.SplitFullTMIIdentifier(fullTMIID)
+			if query == "//*/*/"+tmtID+"/"+tmiID {
+				results = append(results, fullTMIID)
+			}
+		}
+	}
+	return results, nil
+}
+
+/*
+handleEvents lets the TMM handle all dispatched observer events.
+*/
+func (tam *triggerTAM) handleEvents(tmm *Manager) {
+	events := tam.events
+	tam.events = nil
+	for _, event := range events {
+		tmm.HandleObserverEvent(event)
+	}
+}
+
+func TestEntityTriggers(t *testing.T) {
+	tmm, _ := createTemplateManager(t, "")
+	tam := &triggerTAM{fakeTAM: fakeTAM{sessions: make(map[string]session.Session)}}
+	tmm.tam = tam
+
+	definition, err := declarative.Parse([]byte(entityTemplate), "sources.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := declarative.CreateTrustModelTemplate(definition, "")
+	if err := tmm.AddTemplate(template); err != nil {
+		t.Fatal(err)
+	}
+	if _, observed := tmm.observers[core.TRIGGER_V2X_SENDER]; observed || len(tmm.observers) != 2 {
+		t.Errorf("expected observers for MBD and NTM sources only, got %d observers", len(tmm.observers))
+	}
+
+	sess := session.NewInstance("SES-1", "client", template)
+	_, _, spawner, err := template.Spawn(nil, core.TafContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess.SetDynamicSpawner(spawner)
+	sess.Established()
+	tam.sessions[sess.ID()] = sess
+
+	mbdNotify := mbdmsg.MBDNotify{}
+	mbdNotify.CpmReport.Content.V2XPduEvidence.SourceID = 7
+	tmm.HandleMbdNotify(command.HandleNotify[mbdmsg.MBDNotify]{Notify: mbdNotify})
+	tmm.HandleV2xNtm(command.HandleNotify[v2xmsg.V2XNtm]{Notify: v2xmsg.V2XNtm{V2XSourceSet: []v2xmsg.V2XSourceSet{{V2XSourceID: 7}}}})
+	tmm.HandleV2xCpmMessage(command.HandleOneWay[v2xmsg.V2XCpm]{OneWay: v2xmsg.V2XCpm{SourceID: 8}})
+	if len(tam.events) != 2 || tam.events[0].Trigger != core.TRIGGER_MBD_SOURCE || tam.events[1].Trigger != core.TRIGGER_NTM_SOURCE {
+		t.Fatalf("expected events for MBD and NTM sources, got %+v", tam.events)
+	}
+	tam.handleEvents(tmm)
+	if tmis := sess.TrustModelInstances(); len(tmis) != 1 || !sess.HasTMI("7") {
+		t.Fatalf("expected a single instance for source 7, got %v", tmis)
+	}
+
+	//The instance is only removed once the source is absent for both triggers
+	tmm.observers[core.TRIGGER_MBD_SOURCE].RemoveNode("7")
+	tam.handleEvents(tmm)
+	if len(tam.removed) != 0 {
+		t.Errorf("expected instance to be kept while source is present for NTM, got removals %v", tam.removed)
+	}
+	tmm.observers[core.TRIGGER_NTM_SOURCE].RemoveNode("7")
+	tam.handleEvents(tmm)
+	if !slices.Equal(tam.removed, []string{"//client/SES-1/SOURCES@0.0.1/7"}) {
+		t.Errorf("expected instance to be removed, got removals %v", tam.removed)
+	}
+}
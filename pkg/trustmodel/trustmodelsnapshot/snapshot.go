@@ -0,0 +1,152 @@
+/*
+Package trustmodelsnapshot provides helpers for trust model instances to implement core.SnapshotableTrustModelInstance.
+
+A snapshot is a JSON document that contains the TMT, ID, and version of the TMI as well as the model-specific state of the
+TMI. Snapshots can only be restored into TMIs of the same TMT, but the ID may differ (e.g., for TMIs of static trust
+models, which get a new ID whenever they are spawned). Opinions in the state are serialized as Opinion, which also preserves multinomial
+opinions.
+*/
+package trustmodelsnapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+)
+
+var (
+	ErrInvalidSnapshot  = errors.New("invalid snapshot")
+	ErrSnapshotMismatch = errors.New("snapshot of another trust model template")
+)
+
+type snapshot struct {
+	Template string          `json:"template"`
+	ID       string          `json:"id"`
+	Version  int             `json:"version"`
+	State    json.RawMessage `json:"state"`
+}
+
+/*
+Marshal creates a snapshot of a TMI with the given model-specific state, which must be serializable to JSON.
+*/
+func Marshal(tmi core.TrustModelInstance, state interface{}) ([]byte, error) {
+	encodedState, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(snapshot{
+		Template: tmi.Template().Identifier(),
+		ID:       tmi.ID(),
+		Version:  tmi.Version(),
+		State:    encodedState,
+	})
+}
+
+/*
+Unmarshal decodes the model-specific state of a snapshot taken from a TMI of the same TMT as the given TMI into state and
+returns the version of the TMI at the time of the snapshot.
+*/
+func Unmarshal(tmi core.TrustModelInstance, data []byte, state interface{}) (int, error) {
+	var decoded snapshot
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidSnapshot, err)
+	}
+	if decoded.Template != tmi.Template().Identifier() {
+		return 0, fmt.Errorf("%w: %s instead of %s", ErrSnapshotMismatch, decoded.Template, tmi.Template().Identifier())
+	}
+	if err := json.Unmarshal(decoded.State, state); err != nil {
+		return 0, fmt.Errorf("%w: state: %s", ErrInvalidSnapshot, err)
+	}
+	return decoded.Version, nil
+}
+
+/*
+Opinion is the serializable form of an opinion in a snapshot. Multinomial opinions are kept as such; for all other
+opinions, only the binomial view is kept.
+*/
+type Opinion struct {
+	Belief      float64              `json:"belief"`
+	Disbelief   float64              `json:"disbelief"`
+	Uncertainty float64              `json:"uncertainty"`
+	BaseRate    float64              `json:"baseRate"`
+	Multinomial *multinomial.Opinion `json:"multinomial,omitempty"`
+}
+
+/*
+FromOpinion converts an opinion into its serializable form. A nil opinion is converted into nil.
+*/
+func FromOpinion(opinion subjectivelogic.QueryableOpinion) *Opinion {
+	if opinion == nil {
+		return nil
+	}
+	serialized := &Opinion{
+		Belief:      opinion.Belief(),
+		Disbelief:   opinion.Disbelief(),
+		Uncertainty: opinion.Uncertainty(),
+		BaseRate:    opinion.BaseRate(),
+	}
+	if multinomialOpinion, ok := opinion.(*multinomial.Opinion); ok {
+		serialized.Multinomial = multinomialOpinion
+	}
+	return serialized
+}
+
+/*
+Opinion restores the opinion. A nil Opinion is restored as nil.
+*/
+func (o *Opinion) Opinion() (subjectivelogic.QueryableOpinion, error) {
+	if o == nil {
+		return nil, nil
+	}
+	if o.Multinomial != nil {
+		return o.Multinomial, nil
+	}
+	opinion, err := o.Binomial()
+	if err != nil {
+		return nil, err
+	}
+	return &opinion, nil
+}
+
+/*
+Binomial restores the binomial view of the opinion.
+*/
+func (o *Opinion) Binomial() (subjectivelogic.Opinion, error) {
+	if o == nil {
+		return subjectivelogic.Opinion{}, fmt.Errorf("%w: missing opinion", ErrInvalidSnapshot)
+	}
+	opinion, err := subjectivelogic.NewOpinion(o.Belief, o.Disbelief, o.Uncertainty, o.BaseRate)
+	if err != nil {
+		return subjectivelogic.Opinion{}, fmt.Errorf("%w: %s", ErrInvalidSnapshot, err)
+	}
+	return opinion, nil
+}
+
+/*
+FromOpinions converts a map of opinions into their serializable form.
+*/
+func FromOpinions(opinions map[string]subjectivelogic.QueryableOpinion) map[string]*Opinion {
+	serialized := make(map[string]*Opinion, len(opinions))
+	for key, opinion := range opinions {
+		serialized[key] = FromOpinion(opinion)
+	}
+	return serialized
+}
+
+/*
+Opinions restores a map of opinions.
+*/
+func Opinions(serialized map[string]*Opinion) (map[string]subjectivelogic.QueryableOpinion, error) {
+	opinions := make(map[string]subjectivelogic.QueryableOpinion, len(serialized))
+	for key, opinion := range serialized {
+		restored, err := opinion.Opinion()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		opinions[key] = restored
+	}
+	return opinions, nil
+}
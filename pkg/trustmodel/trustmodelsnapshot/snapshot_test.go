@@ -0,0 +1,75 @@
+package trustmodelsnapshot
+
+import (
+	"errors"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/multinomial"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"testing"
+)
+
+type testTemplate struct {
+	core.TrustModelTemplate
+	identifier string
+}
+
+func (t testTemplate) Identifier() string { return t.identifier }
+
+type testTMI struct {
+	core.TrustModelInstance
+	id       string
+	version  int
+	template testTemplate
+}
+
+func (tmi testTMI) ID() string                        { return tmi.id }
+func (tmi testTMI) Version() int                      { return tmi.version }
+func (tmi testTMI) Template() core.TrustModelTemplate { return tmi.template }
+
+type testState struct {
+	Opinions map[string]*Opinion `json:"opinions"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	tmi := testTMI{id: "1", version: 7, template: testTemplate{identifier: "T@0.0.1"}}
+	binomial, _ := subjectivelogic.NewOpinion(0.2, 0.3, 0.5, 0.4)
+	nodeState, err := multinomial.New(map[string]float64{"RUNNING": 0.6}, 0.4, map[string]float64{"RUNNING": 0.5, "FAILED": 0.5}, []string{"RUNNING"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Marshal(tmi, testState{Opinions: FromOpinions(map[string]subjectivelogic.QueryableOpinion{
+		"binomial":    &binomial,
+		"multinomial": nodeState,
+		"nil":         nil,
+	})})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var state testState
+	version, err := Unmarshal(tmi, data, &state)
+	if err != nil || version != 7 {
+		t.Fatalf("expected version 7, got %d (%v)", version, err)
+	}
+	opinions, err := Opinions(state.Opinions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored := opinions["binomial"].(*subjectivelogic.Opinion); *restored != binomial {
+		t.Errorf("expected %v, got %v", &binomial, restored)
+	}
+	if restored, ok := opinions["multinomial"].(*multinomial.Opinion); !ok || !restored.Equal(nodeState, 1e-9) {
+		t.Errorf("expected multinomial opinion %v, got %v", nodeState, opinions["multinomial"])
+	}
+	if opinions["nil"] != nil {
+		t.Errorf("expected nil opinion, got %v", opinions["nil"])
+	}
+
+	other := testTMI{id: "1", template: testTemplate{identifier: "T@0.0.2"}}
+	if _, err := Unmarshal(other, data, &state); !errors.Is(err, ErrSnapshotMismatch) {
+		t.Errorf("expected mismatch error, got %v", err)
+	}
+	if _, err := Unmarshal(tmi, []byte(`{"template": "T@0.0.1", "id": "1", "state": []}`), &state); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("expected invalid snapshot error, got %v", err)
+	}
+}
@@ -0,0 +1,353 @@
+/*
+Package trustmodeltest provides a test kit for trust model templates. A Scenario describes how a TMI is spawned, a
+sequence of steps with evidence and updates, and the ATLs, projected probabilities, and trust decisions expected after
+each step. Scenarios are run in a worker of the TAM using the internal TLEE, i.e., the same way the TAF runs the TMI.
+*/
+package trustmodeltest
+
+import (
+	"context"
+	"fmt"
+	"github.com/horizon-connect-eu/go-taf/pkg/command"
+	"github.com/horizon-connect-eu/go-taf/pkg/config"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/listener"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustassessment"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"log/slog"
+	"math"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+DefaultTolerance is the maximum deviation of ATL components and projected probabilities from their expected values if an
+expectation does not specify a tolerance.
+*/
+const DefaultTolerance = 0.001
+
+/*
+Timeout is the maximum time to wait for the result of a step. The worker does not report results if the TLEE fails, hence
+a missing result is reported as a failure after this time.
+*/
+const Timeout = 5 * time.Second
+
+/*
+A Scenario describes the lifecycle of a single TMI of a template.
+*/
+type Scenario struct {
+	Name string
+	//Session parameters passed to the Spawn function of the template
+	Params map[string]string
+	//Trigger and identifier of the entity a TMI is spawned for; only used for templates that spawn TMIs dynamically
+	Trigger core.SpawnTrigger
+	Entity  string
+	//Expectations on the results of the TMI directly after its initialization
+	Initial []Expectation
+	Steps   []Step
+}
+
+/*
+A Step applies updates and evidence to the TMI at once and checks the results afterward. Updates are applied before the
+atomic trust opinions quantified from the evidence.
+*/
+type Step struct {
+	Name     string
+	Updates  []core.Update
+	Evidence []Evidence
+	Expect   []Expectation
+}
+
+/*
+Evidence is quantified by the trust source quantifier of the TMI with the same trust source whose trustee pattern matches
+the trustee. The resulting atomic trust opinion is applied to the trust relationship from the trustor to the trustee.
+*/
+type Evidence struct {
+	TrustSource core.TrustSource
+	//Trustor of the atomic trust opinion; the trustor of the trust source quantifier is used if not set
+	Trustor string
+	Trustee string
+	Values  map[core.EvidenceType]interface{}
+}
+
+/*
+An Expectation checks the result of a proposition. Only the checks that are set are applied. If Absent is set, the
+proposition must not have a result.
+*/
+type Expectation struct {
+	Proposition          string
+	ATL                  *Opinion
+	ProjectedProbability *float64
+	Decision             *core.TrustDecision
+	Absent               bool
+	//Maximum deviation of ATL components and projected probabilities; DefaultTolerance is used if not set
+	Tolerance float64
+}
+
+/*
+Opinion is an expected binomial ATL.
+*/
+type Opinion struct {
+	Belief      float64
+	Disbelief   float64
+	Uncertainty float64
+	BaseRate    float64
+}
+
+/*
+ExpectATL expects the ATL of a proposition to be the given opinion.
+*/
+func ExpectATL(proposition string, belief float64, disbelief float64, uncertainty float64, baseRate float64) Expectation {
+	return Expectation{Proposition: proposition, ATL: &Opinion{Belief: belief, Disbelief: disbelief, Uncertainty: uncertainty, BaseRate: baseRate}}
+}
+
+/*
+ExpectProbability expects the projected probability of the ATL of a proposition.
+*/
+func ExpectProbability(proposition string, probability float64) Expectation {
+	return Expectation{Proposition: proposition, ProjectedProbability: &probability}
+}
+
+/*
+ExpectDecision expects the trust decision on a proposition.
+*/
+func ExpectDecision(proposition string, decision core.TrustDecision) Expectation {
+	return Expectation{Proposition: proposition, Decision: &decision}
+}
+
+/*
+ExpectAbsent expects a proposition to have no result.
+*/
+func ExpectAbsent(proposition string) Expectation {
+	return Expectation{Proposition: proposition, Absent: true}
+}
+
+/*
+Within returns a copy of the expectation with the given tolerance.
+*/
+func (e Expectation) Within(tolerance float64) Expectation {
+	e.Tolerance = tolerance
+	return e
+}
+
+/*
+Run runs each scenario as a subtest with a fresh TMI of the template.
+*/
+func Run(t *testing.T, tmt core.TrustModelTemplate, scenarios ...Scenario) {
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			runScenario(t, tmt, scenario)
+		})
+	}
+}
+
+/*
+runner runs the TMI of a scenario in a worker and collects its results.
+*/
+type runner struct {
+	t            *testing.T
+	fullTmiID    string
+	tsqs         []core.TrustSourceQuantifier
+	workerQueue  chan core.Command
+	workersToTam chan core.Command
+}
+
+func runScenario(t *testing.T, tmt core.TrustModelTemplate, scenario Scenario) {
+	tafContext := createTafContext(t)
+	//like the TMM, validate the parameters before spawning
+	params := scenario.Params
+	if provider, ok := tmt.(core.ParameterSchemaProvider); ok {
+		values, err := provider.ParameterSchema().Validate(params)
+		if err != nil {
+			t.Fatalf("invalid parameters for %s: %v", tmt.Identifier(), err)
+		}
+		params = values.Map()
+	}
+	tsqs, tmi, spawner, err := tmt.Spawn(params, tafContext)
+	if err != nil {
+		t.Fatalf("spawning %s failed: %v", tmt.Identifier(), err)
+	}
+	if tmi != nil {
+		tmi.Initialize(nil)
+	} else if spawner != nil {
+		if !core.IsSpawnedBy(tmt, scenario.Trigger) {
+			t.Fatalf("%s is not spawned by trigger %s", tmt.Identifier(), scenario.Trigger.String())
+		}
+		tmi, err = core.SpawnOnTrigger(spawner, scenario.Trigger, scenario.Entity, nil)
+		if err != nil {
+			t.Fatalf("spawning %s for %s failed: %v", tmt.Identifier(), scenario.Entity, err)
+		}
+		if tmi == nil {
+			t.Fatalf("%s declined to spawn a TMI for %s", tmt.Identifier(), scenario.Entity)
+		}
+		tmi.Initialize(trustmodel.SpawnParameters(scenario.Trigger, scenario.Entity))
+	} else {
+		t.Fatalf("%s spawned neither a TMI nor a dynamic spawner", tmt.Identifier())
+	}
+
+	tam, err := trustassessment.NewManager(tafContext, core.TafChannels{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := runner{
+		t:            t,
+		fullTmiID:    core.MergeFullTMIIdentifier("trustmodeltest", "SES-1", tmt.Identifier(), tmi.ID()),
+		tsqs:         tsqs,
+		workerQueue:  make(chan core.Command, 1),
+		workersToTam: make(chan core.Command, tafContext.Configuration.ChanBufSize),
+	}
+	worker := tam.SpawnNewWorker(0, r.workerQueue, r.workersToTam, tafContext, map[listener.TrustModelInstanceListener]bool{})
+	stopped := make(chan bool)
+	go func() {
+		worker.Run()
+		close(stopped)
+	}()
+	//Logging to t is not allowed after the test has completed, so the worker has to be stopped before
+	defer func() {
+		r.workerQueue <- command.CreateHandleWorkerStop()
+		<-stopped
+	}()
+
+	r.workerQueue <- command.CreateHandleTMIInit(r.fullTmiID, tmi, nil)
+	r.check("initialization", r.awaitResult("initialization"), scenario.Initial)
+
+	for i, step := range scenario.Steps {
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("step %d", i+1)
+		}
+		updates := append(make([]core.Update, 0, len(step.Updates)+len(step.Evidence)), step.Updates...)
+		for _, evidence := range step.Evidence {
+			updates = append(updates, r.quantify(name, evidence))
+		}
+		update := command.CreateHandleTMIUpdate(r.fullTmiID, nil, updates...)
+		update.ForceEvaluation = true
+		r.workerQueue <- update
+		r.check(name, r.awaitResult(name), step.Expect)
+	}
+}
+
+/*
+quantify turns evidence into an atomic trust opinion update using the matching trust source quantifier.
+*/
+func (r *runner) quantify(step string, evidence Evidence) core.Update {
+	for _, tsq := range r.tsqs {
+		if tsq.TrustSource != evidence.TrustSource {
+			continue
+		}
+		if matches, _ := path.Match(tsq.Trustee, evidence.Trustee); !matches {
+			continue
+		}
+		trustor := evidence.Trustor
+		if trustor == "" {
+			trustor = tsq.Trustor
+		}
+		return trustmodelupdate.CreateAtomicTrustOpinionUpdate(tsq.Quantifier(evidence.Values), trustor, evidence.Trustee, evidence.TrustSource)
+	}
+	r.t.Fatalf("%s: no trust source quantifier for %s evidence on %s", step, evidence.TrustSource.String(), evidence.Trustee)
+	return nil
+}
+
+/*
+awaitResult waits for the next result set of the TMI and skips other commands sent by the worker.
+*/
+func (r *runner) awaitResult(step string) core.AtlResultSet {
+	timeout := time.After(Timeout)
+	for {
+		select {
+		case cmd := <-r.workersToTam:
+			if update, ok := cmd.(command.HandleATLUpdate); ok && update.FullTmiID == r.fullTmiID {
+				return update.ResultSet
+			}
+		case <-timeout:
+			r.t.Fatalf("%s: no result within %s; see the log for TLEE errors", step, Timeout)
+		}
+	}
+}
+
+func (r *runner) check(step string, results core.AtlResultSet, expectations []Expectation) {
+	for _, expected := range expectations {
+		tolerance := expected.Tolerance
+		if tolerance == 0 {
+			tolerance = DefaultTolerance
+		}
+		atl, exists := results.ATLs()[expected.Proposition]
+		if expected.Absent {
+			if exists {
+				r.t.Errorf("%s: expected no result for %s, got ATL %v", step, expected.Proposition, atl)
+			}
+			continue
+		}
+		if !exists {
+			r.t.Errorf("%s: no result for %s, results exist for %v", step, expected.Proposition, propositions(results))
+			continue
+		}
+		if expected.ATL != nil {
+			if math.Abs(atl.Belief()-expected.ATL.Belief) > tolerance ||
+				math.Abs(atl.Disbelief()-expected.ATL.Disbelief) > tolerance ||
+				math.Abs(atl.Uncertainty()-expected.ATL.Uncertainty) > tolerance ||
+				math.Abs(atl.BaseRate()-expected.ATL.BaseRate) > tolerance {
+				r.t.Errorf("%s: expected ATL of %s to be %v, got %v", step, expected.Proposition, *expected.ATL, atl)
+			}
+		}
+		if expected.ProjectedProbability != nil {
+			if probability := results.ProjectedProbabilities()[expected.Proposition]; math.Abs(probability-*expected.ProjectedProbability) > tolerance {
+				r.t.Errorf("%s: expected projected probability of %s to be %.3f, got %.3f", step, expected.Proposition, *expected.ProjectedProbability, probability)
+			}
+		}
+		if expected.Decision != nil {
+			if decision := results.TrustDecisions()[expected.Proposition]; decision != *expected.Decision {
+				r.t.Errorf("%s: expected trust decision on %s to be %s, got %s", step, expected.Proposition, decisionName(*expected.Decision), decisionName(decision))
+			}
+		}
+	}
+}
+
+func propositions(results core.AtlResultSet) []string {
+	names := make([]string, 0, len(results.ATLs()))
+	for proposition := range results.ATLs() {
+		names = append(names, proposition)
+	}
+	return names
+}
+
+func decisionName(decision core.TrustDecision) string {
+	switch decision {
+	case core.TRUSTWORTHY:
+		return "TRUSTWORTHY"
+	case core.NOT_TRUSTWORTHY:
+		return "NOT_TRUSTWORTHY"
+	case core.UNDECIDABLE:
+		return "UNDECIDABLE"
+	default:
+		return fmt.Sprintf("decision %d", decision)
+	}
+}
+
+/*
+createTafContext creates a context using the default configuration with the internal TLEE and without opinion aging, so
+results only depend on the steps of a scenario. Log output of the worker is written to the test log.
+*/
+func createTafContext(t *testing.T) core.TafContext {
+	configuration := config.DefaultConfig
+	configuration.TLEE.UseInternalTLEE = true
+	configuration.TAM.Aging.CheckInterval = 0
+	return core.TafContext{
+		Configuration: configuration,
+		Logger:        slog.New(slog.NewTextHandler(testLogWriter{t}, nil)),
+		Context:       context.Background(),
+		Identifier:    "taf",
+	}
+}
+
+type testLogWriter struct {
+	t *testing.T
+}
+
+func (w testLogWriter) Write(p []byte) (int, error) {
+	w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
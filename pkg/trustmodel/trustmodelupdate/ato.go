@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"maps"
 )
 
 /*
@@ -14,6 +15,8 @@ type UpdateAtomicTrustOpinion struct {
 	trustSource core.TrustSource
 	trustee     string
 	trustor     string
+	//evidence the opinion has been quantified from, if known
+	evidence map[core.EvidenceType]interface{}
 }
 
 func (u UpdateAtomicTrustOpinion) Opinion() subjectivelogic.QueryableOpinion {
@@ -32,6 +35,21 @@ func (u UpdateAtomicTrustOpinion) Trustor() string {
 	return u.trustor
 }
 
+/*
+Evidence returns the evidence the opinion has been quantified from, or nil if unknown.
+*/
+func (u UpdateAtomicTrustOpinion) Evidence() map[core.EvidenceType]interface{} {
+	return u.evidence
+}
+
+/*
+WithEvidence returns a copy of the update that records the evidence the opinion has been quantified from.
+*/
+func (u UpdateAtomicTrustOpinion) WithEvidence(evidence map[core.EvidenceType]interface{}) UpdateAtomicTrustOpinion {
+	u.evidence = maps.Clone(evidence)
+	return u
+}
+
 func CreateAtomicTrustOpinionUpdate(opinion subjectivelogic.QueryableOpinion, trustor string, trustee string, source core.TrustSource) UpdateAtomicTrustOpinion {
 	return UpdateAtomicTrustOpinion{
 		opinion:     opinion,
@@ -0,0 +1,133 @@
+package trustmodelupdate
+
+import (
+	"encoding/json"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"slices"
+)
+
+/*
+TrustRelationship identifies a trust relationship between two trust objects of a trust model.
+*/
+type TrustRelationship struct {
+	Trustor string `json:"trustor"`
+	Trustee string `json:"trustee"`
+}
+
+/*
+Involves returns whether the trust object is the trustor or the trustee of the relationship.
+*/
+func (r TrustRelationship) Involves(trustObject string) bool {
+	return r.Trustor == trustObject || r.Trustee == trustObject
+}
+
+/*
+AddTrustObject is an TMI update operation that adds a trust object and its trust relationships to the structure of a
+trust model. The opinions of new trust relationships are fully uncertain until atomic trust opinions arrive. If no
+relationships are given, the TMI connects the trust object as specified by its trust model, if applicable.
+*/
+type AddTrustObject struct {
+	trustObject   string
+	relationships []TrustRelationship
+	//RTL of the trust object, if it becomes a new proposition
+	rtl subjectivelogic.QueryableOpinion
+}
+
+func (u AddTrustObject) TrustObject() string {
+	return u.trustObject
+}
+
+func (u AddTrustObject) Relationships() []TrustRelationship {
+	return u.relationships
+}
+
+/*
+RTL returns the RTL for the trust object, or nil if the trust model should determine it.
+*/
+func (u AddTrustObject) RTL() subjectivelogic.QueryableOpinion {
+	return u.rtl
+}
+
+/*
+WithRTL returns a copy of the update that specifies the RTL for the trust object.
+*/
+func (u AddTrustObject) WithRTL(rtl subjectivelogic.QueryableOpinion) AddTrustObject {
+	u.rtl = rtl
+	return u
+}
+
+func CreateAddTrustObject(trustObject string, relationships ...TrustRelationship) AddTrustObject {
+	return AddTrustObject{
+		trustObject:   trustObject,
+		relationships: slices.Clone(relationships),
+	}
+}
+
+func (u AddTrustObject) Type() core.UpdateOp {
+	return core.ADD_TRUST_OBJECT
+}
+
+func (u AddTrustObject) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		TrustObject   string                           `json:"trustObject"`
+		Relationships []TrustRelationship              `json:"relationships,omitempty"`
+		RTL           subjectivelogic.QueryableOpinion `json:"rtl,omitempty"`
+		Update        string                           `json:"update"`
+	}{
+		TrustObject:   u.trustObject,
+		Relationships: u.relationships,
+		RTL:           u.rtl,
+		Update:        u.Type().String(),
+	})
+}
+
+/*
+RemoveTrustObject is an TMI update operation that removes trust relationships of a trust object from the structure of a
+trust model. If no relationships are given, the trust object is removed together with all its trust relationships.
+*/
+type RemoveTrustObject struct {
+	trustObject   string
+	relationships []TrustRelationship
+}
+
+func (u RemoveTrustObject) TrustObject() string {
+	return u.trustObject
+}
+
+func (u RemoveTrustObject) Relationships() []TrustRelationship {
+	return u.relationships
+}
+
+/*
+Removes returns whether the update removes the given trust relationship.
+*/
+func (u RemoveTrustObject) Removes(relationship TrustRelationship) bool {
+	if len(u.relationships) == 0 {
+		return relationship.Involves(u.trustObject)
+	}
+	return slices.Contains(u.relationships, relationship)
+}
+
+func CreateRemoveTrustObject(trustObject string, relationships ...TrustRelationship) RemoveTrustObject {
+	return RemoveTrustObject{
+		trustObject:   trustObject,
+		relationships: slices.Clone(relationships),
+	}
+}
+
+func (u RemoveTrustObject) Type() core.UpdateOp {
+	return core.REMOVE_TRUST_OBJECT
+}
+
+func (u RemoveTrustObject) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		TrustObject   string              `json:"trustObject"`
+		Relationships []TrustRelationship `json:"relationships,omitempty"`
+		Update        string              `json:"update"`
+	}{
+		TrustObject:   u.trustObject,
+		Relationships: u.relationships,
+		Update:        u.Type().String(),
+	})
+}
@@ -15,7 +15,6 @@ import (
 	messages "github.com/horizon-connect-eu/go-taf/pkg/message"
 	aivmsg "github.com/horizon-connect-eu/go-taf/pkg/message/aiv"
 	mbdmsg "github.com/horizon-connect-eu/go-taf/pkg/message/mbd"
-	tasmsg "github.com/horizon-connect-eu/go-taf/pkg/message/tas"
 	tchmsg "github.com/horizon-connect-eu/go-taf/pkg/message/tch"
 	v2xmsg "github.com/horizon-connect-eu/go-taf/pkg/message/v2x"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/session"
@@ -23,7 +22,6 @@ import (
 	"github.com/horizon-connect-eu/go-taf/pkg/trustsource/trustsourcehandler"
 	"log/slog"
 	"strings"
-	"time"
 )
 
 type Manager struct {
@@ -283,7 +281,11 @@ func (tsm *Manager) RegisterCallback(messageType messages.MessageSchema, request
 	tsm.pendingMessageCallbacks[messageType][requestID] = fn
 }
 
-func (tsm *Manager) DispatchAivRequest(session session.Session, originalCmd command.HandleRequest[tasmsg.TasTaRequest]) {
+/*
+The DispatchAivRequest function requests fresh evidence for all trustees of a session from the AIV. Once the response
+arrives, all TMIs of the session are updated and forced to compute new results, even if the evidence has not changed.
+*/
+func (tsm *Manager) DispatchAivRequest(session session.Session) {
 
 	query := make(map[core.TrustSource]map[string][]core.EvidenceType)
 	quantifiers := make(map[core.TrustSource]core.Quantifier)
@@ -351,20 +353,14 @@ func (tsm *Manager) DispatchAivRequest(session session.Session, originalCmd comm
 					//call quantifier
 					ato := quantifiers[core.AIV](evidenceCollection)
 					tsm.logger.Debug("Opinion for " + *trusteeReport.TrusteeID + ": " + ato.String())
-					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "", *trusteeReport.TrusteeID, core.AIV))
+					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "", *trusteeReport.TrusteeID, core.AIV).WithEvidence(evidenceCollection))
 				}
 				//create update operation for all TMIs of session
 				for tmiID, fullTmiID := range session.TrustModelInstances() {
 					tmiUpdateCmd := command.CreateHandleTMIUpdate(fullTmiID, cmd.Response.Tag, updates...)
+					tmiUpdateCmd.ForceEvaluation = true
 					tsm.tam.DispatchToWorker(session, tmiID, tmiUpdateCmd)
 				}
-
-				go func() {
-					time.Sleep(25 * time.Millisecond) // Wait until the update has propagated through the system.
-					allowCachedNow := true
-					originalCmd.Request.AllowCache = &allowCachedNow
-					tsm.tam.DispatchToSelf(originalCmd) // We replay the original request here, now with caching enabled.
-				}()
 			default:
 				//Nothing to do
 			}
@@ -111,7 +111,7 @@ func (h *AivHandler) HandleNotify(cmd command.HandleNotify[aivmsg.AivNotify]) {
 			} else if tsq.Trustee == trustee {
 				ato := tsq.Quantifier(h.latestSubscriptionEvidence[subID][trustee])
 				h.logger.Debug("Opinion for "+trustee, "SL", ato.String(), "Input", fmt.Sprintf("%v", h.latestSubscriptionEvidence[subID][trustee]))
-				updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "", trustee, core.AIV))
+				updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "", trustee, core.AIV).WithEvidence(h.latestSubscriptionEvidence[subID][trustee]))
 			}
 		}
 	}
@@ -113,11 +113,11 @@ func (h *MbdHandler) HandleNotify(cmd command.HandleNotify[mbdmsg.MBDNotify]) {
 				} else if tsq.Trustor == "V_ego" && tsq.Trustee == "C_*_*" {
 					ato := tsq.Quantifier(h.latestSubscriptionEvidence[trustee])
 					h.logger.Debug("Opinion for "+trustee, "SL", ato.String(), "Input", fmt.Sprintf("%v", h.latestSubscriptionEvidence[trustee]))
-					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, tsq.Trustor, trustee, core.MBD))
+					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, tsq.Trustor, trustee, core.MBD).WithEvidence(h.latestSubscriptionEvidence[trustee]))
 				} else if tsq.Trustor == "MEC" && tsq.Trustee == "vehicle_*" {
 					ato := tsq.Quantifier(h.latestSubscriptionEvidence[trustee])
 					h.logger.Debug("Opinion for "+trustee, "SL", ato.String(), "Input", fmt.Sprintf("%v", h.latestSubscriptionEvidence[trustee]))
-					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, tsq.Trustor, fmt.Sprintf("vehicle_%d", int(sourceID)), core.MBD))
+					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, tsq.Trustor, fmt.Sprintf("vehicle_%d", int(sourceID)), core.MBD).WithEvidence(h.latestSubscriptionEvidence[trustee]))
 				}
 			}
 		}
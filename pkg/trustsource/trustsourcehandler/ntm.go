@@ -90,11 +90,12 @@ func (n *NtmHandler) HandleNotify(cmd command.HandleNotify[v2xmsg.V2XNtm]) {
 					continue
 				} else if tsq.Trustor == "MEC" && tsq.Trustee == "V_*" {
 					//This is a bit bogus as we already have the opinion. But the quantifier might modify it in the future.
-					ato := tsq.Quantifier(map[core.EvidenceType]interface{}{
+					evidence := map[core.EvidenceType]interface{}{
 						core.NTM_REMOTE_OPINION: receivedNtmOpinions[opinionTarget],
-					})
+					}
+					ato := tsq.Quantifier(evidence)
 					n.logger.Debug("Opinion for "+strconv.FormatInt(opinionTarget, 10), "SL", ato.String())
-					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "V_ego", "V_"+strconv.FormatInt(opinionTarget, 10), core.NTM))
+					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "V_ego", "V_"+strconv.FormatInt(opinionTarget, 10), core.NTM).WithEvidence(evidence))
 				}
 			}
 		}
@@ -9,6 +9,7 @@ import (
 	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/session"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -18,12 +19,16 @@ type TchHandler struct {
 	latestSubscriptionEvidence map[string]map[core.EvidenceType]interface{}
 	tam                        TAMAccess
 	logger                     *slog.Logger
+
+	//trustee->identifiers of the components reported in the latest notification
+	latestComponents map[string][]string
 }
 
 func CreateTchHandler(tam TAMAccess, logger *slog.Logger) *TchHandler {
 	return &TchHandler{
 		sessionTsqs:                make(map[string][]core.TrustSourceQuantifier),
 		latestSubscriptionEvidence: make(map[string]map[core.EvidenceType]interface{}),
+		latestComponents:           make(map[string][]string),
 		logger:                     logger,
 		tam:                        tam,
 	}
@@ -65,7 +70,7 @@ func (h *TchHandler) HandleNotify(cmd command.HandleNotify[tchmsg.TchNotify]) {
 		componentID := trusteeReport.ComponentID
 		id := trusteeID
 		if componentID != nil {
-			id = fmt.Sprintf("%s~%s", trusteeID, *componentID)
+			id = core.MergeComponentIdentifier(trusteeID, *componentID)
 		}
 		_, idExists := updatedEvidence[id]
 
@@ -83,12 +88,16 @@ func (h *TchHandler) HandleNotify(cmd command.HandleNotify[tchmsg.TchNotify]) {
 	for id, evidence := range updatedEvidence {
 		h.latestSubscriptionEvidence[id] = evidence
 	}
+	topologyUpdates := h.updateComponents(trusteeID, updatedEvidence)
 
 	//Iterate over all sessions register for TCH, call quantifiers and relay updates
 	for sessionId, tsqs := range h.sessionTsqs {
-		sess := h.tam.Sessions()[sessionId]
+		sess, ok := h.tam.Sessions()[sessionId]
+		if !ok {
+			continue
+		}
 		//loop through all updated trustees and find fitting quantifiers; if successful, apply quantifier and add ATO update
-		updates := make([]core.Update, 0)
+		updates := slices.Clone(topologyUpdates)
 		for trustee := range updatedTrustees {
 			for _, tsq := range tsqs {
 				if tsq.TrustSource != core.TCH {
@@ -96,15 +105,15 @@ func (h *TchHandler) HandleNotify(cmd command.HandleNotify[tchmsg.TchNotify]) {
 				} else if tsq.Trustor == "MEC" && tsq.Trustee == "vehicle_*" && strings.HasPrefix(trustee, "vehicle_") {
 					ato := tsq.Quantifier(h.latestSubscriptionEvidence[trustee])
 					h.logger.Debug("Opinion for "+trustee, "SL", ato.String(), "Input", fmt.Sprintf("%v", h.latestSubscriptionEvidence[trustee]))
-					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "MEC", trustee, core.TCH))
+					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "MEC", trustee, core.TCH).WithEvidence(h.latestSubscriptionEvidence[trustee]))
 				} else if tsq.Trustor == "V_ego" && tsq.Trustee == "V_*" && strings.HasPrefix(trustee, "vehicle_") {
 					ato := tsq.Quantifier(h.latestSubscriptionEvidence[trustee])
 					h.logger.Debug("Opinion for "+trustee, "SL", ato.String(), "Input", fmt.Sprintf("%v", h.latestSubscriptionEvidence[trustee]))
-					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "V_ego", trustee, core.TCH))
+					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "V_ego", trustee, core.TCH).WithEvidence(h.latestSubscriptionEvidence[trustee]))
 				} else if _, err := strconv.Atoi(trustee); err == nil && tsq.Trustor == "V_ego" && tsq.Trustee == "V_*" {
 					ato := tsq.Quantifier(h.latestSubscriptionEvidence[trustee])
 					h.logger.Debug("Opinion for "+trustee, "SL", ato.String(), "Input", fmt.Sprintf("%v", h.latestSubscriptionEvidence[trustee]))
-					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "V_ego", "V_"+trustee, core.TCH))
+					updates = append(updates, trustmodelupdate.CreateAtomicTrustOpinionUpdate(ato, "V_ego", "V_"+trustee, core.TCH).WithEvidence(h.latestSubscriptionEvidence[trustee]))
 				}
 			}
 		}
@@ -116,3 +125,36 @@ func (h *TchHandler) HandleNotify(cmd command.HandleNotify[tchmsg.TchNotify]) {
 		}
 	}
 }
+
+/*
+updateComponents compares the components reported for a trustee with the components of the previous notification. For
+components that have disappeared, trust object removals are returned and their evidence is dropped; for new components,
+trust object additions are returned. Components are identified by core.MergeComponentIdentifier, which trust models
+map to their trust objects.
+*/
+func (h *TchHandler) updateComponents(trusteeID string, reportedEvidence map[string]map[core.EvidenceType]interface{}) []core.Update {
+	components := make([]string, 0)
+	for id := range reportedEvidence {
+		if id != trusteeID {
+			components = append(components, id)
+		}
+	}
+	slices.Sort(components)
+	previous := h.latestComponents[trusteeID]
+	h.latestComponents[trusteeID] = components
+
+	updates := make([]core.Update, 0)
+	for _, component := range previous {
+		if !slices.Contains(components, component) {
+			h.logger.Debug("Component of trustee disappeared", "Trustee", trusteeID, "Component", component)
+			delete(h.latestSubscriptionEvidence, component)
+			updates = append(updates, trustmodelupdate.CreateRemoveTrustObject(component))
+		}
+	}
+	for _, component := range components {
+		if !slices.Contains(previous, component) {
+			updates = append(updates, trustmodelupdate.CreateAddTrustObject(component))
+		}
+	}
+	return updates
+}
@@ -0,0 +1,135 @@
+package trustsourcehandler_test
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/command"
+	"github.com/horizon-connect-eu/go-taf/pkg/config"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	tchmsg "github.com/horizon-connect-eu/go-taf/pkg/message/tch"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/session"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustsource/trustsourcehandler"
+	ntm "github.com/horizon-connect-eu/go-taf/plugins/trustmodels/intersectionmovementassist/trustmodel-ntm-standalone_v0.0.1"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+/*
+fakeTAM records the commands dispatched by a handler.
+*/
+type fakeTAM struct {
+	sessions   map[string]session.Session
+	dispatched []command.HandleTMIUpdate
+}
+
+func (tam *fakeTAM) Sessions() map[string]session.Session {
+	return tam.sessions
+}
+
+func (tam *fakeTAM) DispatchToWorker(session session.Session, tmiID string, cmd core.Command) {
+	tam.dispatched = append(tam.dispatched, cmd.(command.HandleTMIUpdate))
+}
+
+func tchNotify(trusteeID string, components ...string) command.HandleNotify[tchmsg.TchNotify] {
+	reports := []tchmsg.TrusteeReport{{AttestationReport: []tchmsg.AttestationReport{{Claim: "secure_boot", Appraisal: 1}}}}
+	for _, component := range components {
+		reports = append(reports, tchmsg.TrusteeReport{
+			AttestationReport: []tchmsg.AttestationReport{{Claim: "secure_boot", Appraisal: 1}},
+			ComponentID:       &component,
+		})
+	}
+	return command.CreateTchNotify(tchmsg.TchNotify{TchReport: tchmsg.TchReport{TrusteeID: trusteeID, TrusteeReports: reports}}, "TCH")
+}
+
+/*
+components returns the trust objects the MEC node of a TMI structure points to besides the trustee.
+*/
+func components(tmi core.TrustModelInstance, trustee string) []string {
+	result := make([]string, 0)
+	for _, entry := range tmi.Structure().AdjacencyList() {
+		if entry.SourceNode() != "MEC" {
+			continue
+		}
+		for _, target := range entry.TargetNodes() {
+			if target != trustee {
+				result = append(result, target)
+			}
+		}
+	}
+	return result
+}
+
+func TestTchComponentUpdates(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tmt := ntm.CreateTrustModelTemplate("NTM_STANDALONE", "0.0.1")
+	tsqs, _, spawner, err := tmt.Spawn(nil, core.TafContext{Configuration: config.DefaultConfig, Logger: logger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmi, err := spawner.OnNewTrustee("5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmi.Initialize(map[string]interface{}{"trusteeID": "5"})
+
+	sess := session.NewInstance("SES-1", "client", tmt)
+	sess.SetTrustSourceQuantifiers(tsqs)
+	sess.TrustModelInstances()[tmi.ID()] = core.MergeFullTMIIdentifier(sess.Client(), sess.ID(), tmt.Identifier(), tmi.ID())
+	tam := &fakeTAM{sessions: map[string]session.Session{sess.ID(): sess}}
+	handler := trustsourcehandler.CreateTchHandler(tam, logger)
+	handler.AddSession(sess, nil)
+
+	//apply dispatches the recorded updates to the TMI, like a worker would
+	apply := func() []core.Update {
+		updates := make([]core.Update, 0)
+		for _, cmd := range tam.dispatched {
+			for _, update := range cmd.Updates {
+				tmi.Update(update)
+			}
+			updates = append(updates, cmd.Updates...)
+		}
+		tam.dispatched = nil
+		return updates
+	}
+	component := core.MergeComponentIdentifier("vehicle_5", "ecu")
+	fingerprint := tmi.Fingerprint()
+
+	handler.HandleNotify(tchNotify("vehicle_5", "ecu"))
+	updates := apply()
+	if !slices.ContainsFunc(updates, func(update core.Update) bool {
+		add, ok := update.(trustmodelupdate.AddTrustObject)
+		return ok && add.TrustObject() == component
+	}) {
+		t.Fatalf("expected addition of component '%s', got %v", component, updates)
+	}
+	if actual := components(tmi, "vehicle_5"); !slices.Equal(actual, []string{component}) {
+		t.Fatalf("expected component '%s' in structure, got %v", component, actual)
+	}
+	if tmi.Fingerprint() == fingerprint {
+		t.Errorf("expected fingerprint to change after adding a component")
+	}
+
+	//The component is no longer reported
+	handler.HandleNotify(tchNotify("vehicle_5"))
+	updates = apply()
+	if !slices.ContainsFunc(updates, func(update core.Update) bool {
+		remove, ok := update.(trustmodelupdate.RemoveTrustObject)
+		return ok && remove.TrustObject() == component
+	}) {
+		t.Fatalf("expected removal of component '%s', got %v", component, updates)
+	}
+	if actual := components(tmi, "vehicle_5"); len(actual) != 0 {
+		t.Errorf("expected no components in structure, got %v", actual)
+	}
+	if tmi.Fingerprint() != fingerprint {
+		t.Errorf("expected initial fingerprint after removing the component")
+	}
+
+	//Sessions unknown to the TAM are skipped
+	delete(tam.sessions, sess.ID())
+	handler.HandleNotify(tchNotify("vehicle_5", "ecu"))
+	if len(tam.dispatched) != 0 {
+		t.Errorf("expected no updates for unknown sessions, got %v", tam.dispatched)
+	}
+}
@@ -14,6 +14,7 @@ REST API: Implemented Routes:
 /tmis
 /tmis/:clientid/:sessionid/:tmt-id/:tmi-id/:version
 /tmis/:clientid/:sessionid/:tmt-id/:tmi-id/ => /tmis/:clientid/:sessionid/:tmt-id/:tmi-id/latest
+/tmis/:clientid/:sessionid/:tmt-id/:tmi-id/explanations
 
 /info
 
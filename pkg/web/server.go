@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"log/slog"
 	"net/http"
+	"strconv"
         "strings"
 	"time"
 
@@ -30,8 +31,9 @@ type Webserver struct {
 	listenerChannel  chan listener.ListenerEvent
 	websocketChannel chan WebSocketEvent
 	state            *State
-	tmts             map[string]interface{}
 	trustSources     map[string]map[string]bool
+	tam              manager.TrustAssessmentManager
+	tmm              manager.TrustModelManager
 }
 
 func New(tafContext core.TafContext) (*Webserver, error) {
@@ -42,7 +44,6 @@ func New(tafContext core.TafContext) (*Webserver, error) {
 		listenerChannel:  make(chan listener.ListenerEvent),
 		websocketChannel: make(chan WebSocketEvent),
 		state:            NewState(logger),
-		tmts:             make(map[string]interface{}),
 		trustSources:     make(map[string]map[string]bool),
 	}, nil
 }
@@ -119,15 +120,18 @@ func (s *Webserver) Run() {
 	s.router.GET("/api/tmis/:client/:session/:tmt/:tmiID/latest", s.state.getTMILatest)
 	s.router.GET("/api/tmis/:client/:session/:tmt/:tmiID/updates", s.state.getTMIUpdates)
 	s.router.GET("/api/tmis/:client/:session/:tmt/:tmiID/all", s.state.getTMIFull)
+	s.router.GET("/api/tmis/:client/:session/:tmt/:tmiID/explanations", s.state.getTMIExplanations)
 	s.router.GET("/api/tmis/:client/:session/:tmt/:tmiID/:version", s.state.getVersionTMI)
 	s.router.GET("/api/trustmodels/:tmt-identifier", s.getTrustModel)
 	s.router.GET("/api/trustsources", s.getTrustSources)
 	s.router.GET("/api/trustmodels", s.getTrustModels)
+	s.router.GET("/api/workers", s.getWorkers)
+	s.router.PUT("/api/workers/:count", s.scaleWorkers)
 	s.router.Run(fmt.Sprintf(":%d", s.tafContext.Configuration.WebUI.Port))
 }
 
 func (s *Webserver) getTrustModels(ctx *gin.Context) {
-	ctx.IndentedJSON(http.StatusOK, s.tmts)
+	ctx.IndentedJSON(http.StatusOK, s.trustModels())
 }
 
 func (s *Webserver) getTrustSources(ctx *gin.Context) {
@@ -135,7 +139,7 @@ func (s *Webserver) getTrustSources(ctx *gin.Context) {
 }
 
 func (s *Webserver) getTrustModel(ctx *gin.Context) {
-	tmt, exists := s.tmts[ctx.Param("tmt-identifier")]
+	tmt, exists := s.trustModels()[ctx.Param("tmt-identifier")]
 	if !exists {
 		ctx.JSON(http.StatusNotFound, gin.H{"code": "NOT_FOUND"})
 	} else {
@@ -147,9 +151,43 @@ func (s *Webserver) getInfo(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"Version": version.Version, "Build": version.Build, "Configuration": s.tafContext.Configuration})
 }
 
+func (s *Webserver) getWorkers(ctx *gin.Context) {
+	ctx.IndentedJSON(http.StatusOK, s.tam.WorkerLoad())
+}
+
+func (s *Webserver) scaleWorkers(ctx *gin.Context) {
+	count, err := strconv.Atoi(ctx.Param("count"))
+	if err == nil {
+		err = s.tam.ScaleWorkers(count)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_WORKER_COUNT", "message": err.Error()})
+	} else {
+		ctx.JSON(http.StatusAccepted, gin.H{"count": count})
+	}
+}
+
 func (s *Webserver) SetManagers(managers manager.TafManagers) {
+	s.tam = managers.TAM
+	s.tmm = managers.TMM
 
 	for _, tmt := range managers.TMM.GetAllTMTs() {
+		for _, evidenceType := range tmt.EvidenceTypes() {
+			if s.trustSources[evidenceType.Source().String()] == nil {
+				s.trustSources[evidenceType.Source().String()] = make(map[string]bool)
+			}
+			s.trustSources[evidenceType.Source().String()][evidenceType.String()] = true
+		}
+	}
+}
+
+/*
+trustModels describes the currently available trust model templates. As templates can be added and removed at runtime,
+the description is created for each request.
+*/
+func (s *Webserver) trustModels() map[string]interface{} {
+	tmts := make(map[string]interface{})
+	for _, tmt := range s.tmm.GetAllTMTs() {
 
 		evidence := make(map[string]map[string]bool)
 		for _, evidenceType := range tmt.EvidenceTypes() {
@@ -157,23 +195,23 @@ func (s *Webserver) SetManagers(managers manager.TafManagers) {
 				evidence[evidenceType.Source().String()] = make(map[string]bool)
 			}
 			evidence[evidenceType.Source().String()][evidenceType.String()] = true
-
-			if s.trustSources[evidenceType.Source().String()] == nil {
-				s.trustSources[evidenceType.Source().String()] = make(map[string]bool)
-			}
-			s.trustSources[evidenceType.Source().String()][evidenceType.String()] = true
 		}
 
-		s.tmts[tmt.Identifier()] = struct {
+		tmts[tmt.Identifier()] = struct {
 			Description   string
 			Name          string
 			Version       string
+			Deprecated    bool
+			Verified      bool
 			EvidenceTypes map[string]map[string]bool
 		}{
 			Description:   tmt.Description(),
 			Name:          tmt.TemplateName(),
 			Version:       tmt.Version(),
+			Deprecated:    s.tmm.IsDeprecated(tmt.Identifier()),
+			Verified:      s.tmm.IsVerified(tmt.Identifier()),
 			EvidenceTypes: evidence,
 		}
 	}
+	return tmts
 }
@@ -413,6 +413,34 @@ func (s *State) getTMILatest(ctx *gin.Context) {
 	}
 }
 
+/*
+getTMIExplanations returns the explanations of the trust decisions of the latest ATL results of a TMI. The result is empty
+if explanations are disabled in the TAF.
+*/
+func (s *State) getTMIExplanations(ctx *gin.Context) {
+	fullTMI := core.MergeFullTMIIdentifier(ctx.Param("client"), ctx.Param("session"), ctx.Param("tmt"), ctx.Param("tmiID"))
+	tmi, exists := s.tmis[fullTMI]
+	if !exists {
+		ctx.JSON(http.StatusNotFound, gin.H{"code": "NOT_FOUND"})
+		return
+	}
+	atls, atlExist := tmi.ATLs[tmi.LatestVersion]
+	if !atlExist {
+		ctx.JSON(http.StatusNotFound, gin.H{"code": "NO_RESULTS"})
+		return
+	}
+	explanations := atls.Explanations()
+	if explanations == nil {
+		explanations = make(map[string]core.DecisionExplanation)
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"id":           tmi.ID,
+		"fullTMI":      tmi.FullTMI,
+		"version":      atls.Version(),
+		"explanations": explanations,
+	})
+}
+
 func (s *State) getAllTMIs(ctx *gin.Context) {
 
 	tmis := make(map[string]interface{})
@@ -0,0 +1,82 @@
+//go:build differential
+
+package tleeimplementation
+
+import (
+	"flag"
+	"fmt"
+	internaltlee "github.com/horizon-connect-eu/go-taf/pkg/tlee"
+	"github.com/horizon-connect-eu/go-taf/pkg/tlee/differential"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"io"
+	"log/slog"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var (
+	recorded        = flag.String("recorded", "testdata/recorded", "directory with recorded TLEE inputs, e.g., from Debug.RecordTLEEInputs")
+	counterexamples = flag.String("counterexamples", filepath.Join(os.TempDir(), "tlee-counterexamples"), "directory to write minimized counterexamples to")
+	randomInputs    = flag.Int("random", 100, "number of random inputs per operator combination")
+	seed            = flag.Int64("seed", 1, "seed for random inputs")
+	tolerance       = flag.Float64("tolerance", 1e-6, "maximum difference of opinion components")
+)
+
+/*
+createHarness compares the internal TLEE as candidate against tlee-implementation as reference.
+*/
+func createHarness(t *testing.T) *differential.Harness {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	reference, err := internaltlee.NewBackend("tlee-implementation", logger, internaltlee.BackendConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reference == nil {
+		t.Fatal("tlee-implementation is not available")
+	}
+	return differential.NewHarness(reference, internaltlee.SpawnNewTLEE(logger, "", false), *tolerance)
+}
+
+func check(t *testing.T, harness *differential.Harness, name string, input differential.Input) {
+	t.Helper()
+	result, err := harness.Compare(input)
+	if err != nil {
+		t.Fatalf("invalid input %s: %v", name, err)
+	}
+	if !result.Failed() {
+		return
+	}
+	path, err := harness.WriteCounterexample(*counterexamples, name, input)
+	if err != nil {
+		t.Errorf("cannot write counterexample for %s: %v", name, err)
+	}
+	t.Errorf("TLEEs differ for %s (minimized counterexample: %s):\n%s", name, path, result)
+}
+
+func TestDifferentialRecorded(t *testing.T) {
+	harness := createHarness(t)
+	inputs, err := differential.ReadInputs(*recorded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			check(t, harness, name, input)
+		})
+	}
+}
+
+func TestDifferentialRandom(t *testing.T) {
+	harness := createHarness(t)
+	random := rand.New(rand.NewSource(*seed))
+	for _, fusionOperator := range []trustmodelstructure.FusionOperator{trustmodelstructure.CumulativeFusion, trustmodelstructure.AveragingFusion} {
+		for _, discountOperator := range []trustmodelstructure.DiscountOperator{trustmodelstructure.DefaultDiscount, trustmodelstructure.OppositeBeliefDiscount} {
+			for i := range *randomInputs {
+				name := fmt.Sprintf("random-%d-%d-%d-%d", *seed, fusionOperator, discountOperator, i)
+				check(t, harness, name, differential.RandomInput(random, fusionOperator, discountOperator, 1+random.Intn(4), 3))
+			}
+		}
+	}
+}
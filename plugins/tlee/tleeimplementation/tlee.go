@@ -0,0 +1,14 @@
+package tleeimplementation
+
+import (
+	internaltlee "github.com/horizon-connect-eu/go-taf/pkg/tlee"
+	actualtlee "github.com/horizon-connect-eu/tlee-implementation/pkg/core"
+	"github.com/vs-uulm/taf-tlee-interface/pkg/tleeinterface"
+	"log/slog"
+)
+
+func init() {
+	internaltlee.RegisterBackend("tlee-implementation", func(logger *slog.Logger, config internaltlee.BackendConfig) (tleeinterface.TLEE, error) {
+		return actualtlee.SpawnNewTLEE(logger, config.FilePath, config.DebuggingMode), nil
+	})
+}
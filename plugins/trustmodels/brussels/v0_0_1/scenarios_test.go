@@ -0,0 +1,116 @@
+package brussels_0_0_1
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodeltest"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"testing"
+)
+
+func aivEvidence(trustee string, secureBoot int, accessControl int) trustmodeltest.Evidence {
+	return trustmodeltest.Evidence{
+		TrustSource: core.AIV,
+		Trustee:     trustee,
+		Values: map[core.EvidenceType]interface{}{
+			core.AIV_SECURE_BOOT:                          secureBoot,
+			core.AIV_SECURE_OTA:                           1,
+			core.AIV_ACCESS_CONTROL:                       accessControl,
+			core.AIV_APPLICATION_ISOLATION:                1,
+			core.AIV_CONTROL_FLOW_INTEGRITY:               1,
+			core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION: 1,
+		},
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	opinion, _ := subjectivelogic.NewOpinion(0.76, 0.1, 0.14, 0.5)
+	tmt := CreateTrustModelTemplate("BRUSSELS", "0.0.1", "Testing")
+	trustmodeltest.Run(t, tmt,
+		trustmodeltest.Scenario{
+			Name: "default parameters",
+			Initial: []trustmodeltest.Expectation{
+				trustmodeltest.ExpectATL("VC1", 0, 0, 1, 0.5),
+				trustmodeltest.ExpectATL("VC2", 0, 0, 1, 0.5),
+			},
+			Steps: []trustmodeltest.Step{
+				{
+					Name:     "all security controls pass",
+					Evidence: []trustmodeltest.Evidence{aivEvidence("VC1", 1, 1), aivEvidence("VC2", 1, 1)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("VC1", 0.76, 0.1, 0.14, 0.5),
+						trustmodeltest.ExpectDecision("VC1", core.TRUSTWORTHY),
+						trustmodeltest.ExpectATL("VC2", 0.76, 0.1, 0.14, 0.5),
+						trustmodeltest.ExpectDecision("VC2", core.TRUSTWORTHY),
+					},
+				},
+				{
+					//Failing access control adds belief instead of disbelief
+					Name:     "access control of VC2 fails",
+					Evidence: []trustmodeltest.Evidence{aivEvidence("VC2", 1, 0)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("VC2", 0.76, 0.1, 0.14, 0.5),
+						trustmodeltest.ExpectDecision("VC2", core.TRUSTWORTHY),
+					},
+				},
+				{
+					Name:     "secure boot of VC2 fails",
+					Evidence: []trustmodeltest.Evidence{aivEvidence("VC2", 0, 1)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("VC2", 0, 1, 0, 0.5),
+						trustmodeltest.ExpectDecision("VC2", core.NOT_TRUSTWORTHY),
+						trustmodeltest.ExpectDecision("VC1", core.TRUSTWORTHY),
+					},
+				},
+			},
+		},
+		trustmodeltest.Scenario{
+			Name: "stricter RTL of VC1",
+			Params: map[string]string{
+				"VC1_RTL_BELIEF":      "0.9",
+				"VC1_RTL_DISBELIEF":   "0",
+				"VC1_RTL_UNCERTAINTY": "0.1",
+				"VC1_RTL_BASERATE":    "0.5",
+			},
+			Steps: []trustmodeltest.Step{
+				{
+					Name:     "all security controls pass",
+					Evidence: []trustmodeltest.Evidence{aivEvidence("VC1", 1, 1)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectProbability("VC1", 0.83),
+						trustmodeltest.ExpectDecision("VC1", core.NOT_TRUSTWORTHY),
+					},
+				},
+			},
+		},
+		trustmodeltest.Scenario{
+			Name: "vehicle computers added and removed",
+			Steps: []trustmodeltest.Step{
+				{
+					Name:    "added vehicle computer is fully uncertain",
+					Updates: []core.Update{trustmodelupdate.CreateAddTrustObject("VC3")},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("VC3", 0, 0, 1, 0.5),
+						trustmodeltest.ExpectDecision("VC3", core.UNDECIDABLE),
+					},
+				},
+				{
+					Name:    "atomic trust opinion of VC3",
+					Updates: []core.Update{trustmodelupdate.CreateAtomicTrustOpinionUpdate(&opinion, "TAF", "VC3", core.AIV)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("VC3", 0.76, 0.1, 0.14, 0.5),
+						trustmodeltest.ExpectDecision("VC3", core.TRUSTWORTHY),
+					},
+				},
+				{
+					Name:    "removed vehicle computer",
+					Updates: []core.Update{trustmodelupdate.CreateRemoveTrustObject("VC2")},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectAbsent("VC2"),
+						trustmodeltest.ExpectATL("VC1", 0, 0, 1, 0.5),
+					},
+				},
+			},
+		},
+	)
+}
@@ -0,0 +1,36 @@
+package trustmodel_example_v0_0_1
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/config"
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"log/slog"
+	"testing"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	tmt := CreateTrustModelTemplate("EXAMPLE", "0.0.1")
+	_, tmi, _, _ := tmt.Spawn(nil, createTafContext())
+	tmi.(*TrustModelInstance).version = 3
+
+	snapshot, err := tmi.(core.SnapshotableTrustModelInstance).Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, restored, _, _ := tmt.Spawn(nil, createTafContext())
+	if err := restored.(core.SnapshotableTrustModelInstance).Restore(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Version() != tmi.Version() {
+		t.Errorf("expected restored version %d, got %d", tmi.Version(), restored.Version())
+	}
+}
+
+func createTafContext() core.TafContext {
+	return core.TafContext{
+		Configuration: config.Configuration{},
+		Logger:        slog.Default(),
+		Context:       nil,
+		Identifier:    "taf",
+		Crypto:        nil,
+	}
+}
@@ -0,0 +1,49 @@
+package trustmodel_example_v0_0_1
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodeltest"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"testing"
+)
+
+func TestScenarios(t *testing.T) {
+	opinion, _ := subjectivelogic.NewOpinion(0.8, 0.1, 0.1, 0.5)
+	rtl, _ := subjectivelogic.NewOpinion(0.6, 0.2, 0.2, 0.5)
+	trustmodeltest.Run(t, CreateTrustModelTemplate("EXAMPLE", "0.0.1"),
+		trustmodeltest.Scenario{
+			Name: "no trust relationships",
+			Steps: []trustmodeltest.Step{
+				{
+					Name:    "atomic trust opinions are ignored",
+					Updates: []core.Update{trustmodelupdate.CreateAtomicTrustOpinionUpdate(&opinion, "TAF", "VC1", core.AIV)},
+					Expect:  []trustmodeltest.Expectation{trustmodeltest.ExpectAbsent("VC1")},
+				},
+			},
+		},
+		trustmodeltest.Scenario{
+			Name: "trust objects",
+			Steps: []trustmodeltest.Step{
+				{
+					Name:    "added trust object is fully uncertain",
+					Updates: []core.Update{trustmodelupdate.CreateAddTrustObject("VC1").WithRTL(&rtl)},
+					Expect:  []trustmodeltest.Expectation{trustmodeltest.ExpectATL("VC1", 0, 0, 1, 0.5)},
+				},
+				{
+					Name:    "atomic trust opinion of the trust object",
+					Updates: []core.Update{trustmodelupdate.CreateAtomicTrustOpinionUpdate(&opinion, "TAF", "VC1", core.AIV)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("VC1", 0.8, 0.1, 0.1, 0.5),
+						trustmodeltest.ExpectDecision("VC1", core.TRUSTWORTHY),
+					},
+				},
+				{
+					Name:    "removed trust object",
+					Updates: []core.Update{trustmodelupdate.CreateRemoveTrustObject("VC1")},
+					Expect:  []trustmodeltest.Expectation{trustmodeltest.ExpectAbsent("VC1")},
+				},
+			},
+		},
+	)
+}
@@ -1,18 +1,29 @@
 package trustmodel_example_v0_0_1
 
 import (
-	"github.com/horizon-connect-eu/go-taf/internal/util"
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelsnapshot"
+	internaltrustmodelstructure "github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelstructure"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
 	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"hash/fnv"
+	"slices"
+	"strings"
 )
 
+var FullUncertainty, _ = subjectivelogic.NewOpinion(0, 0, 1, 0.5)
+
 type TrustModelInstance struct {
 	id      string
 	version int
 
 	template TrustModelTemplate
+
+	//trust object -> opinion TAF -> trust object
+	opinions    map[string]subjectivelogic.QueryableOpinion
+	rtls        map[string]subjectivelogic.QueryableOpinion
+	fingerprint uint32
 }
 
 func (e *TrustModelInstance) ID() string {
@@ -20,23 +31,54 @@ func (e *TrustModelInstance) ID() string {
 }
 
 func (e *TrustModelInstance) Version() int {
-	//TODO implement me
-	panic("implement me")
+	return e.version
 }
 
 func (e *TrustModelInstance) Fingerprint() uint32 {
-	//TODO implement me
-	panic("implement me")
+	return e.fingerprint
 }
 
+/*
+Structure returns no trust graph until trust objects have been added, as the example model does not have any trust
+relationships of its own. The TLEE is not called for TMIs without structure, hence there are no ATLs.
+*/
 func (e *TrustModelInstance) Structure() trustmodelstructure.TrustGraphStructure {
-	//TODO implement me
-	panic("implement me")
+	if len(e.opinions) == 0 {
+		return nil
+	}
+	return internaltrustmodelstructure.NewTrustGraphDTO(trustmodelstructure.CumulativeFusion, trustmodelstructure.OppositeBeliefDiscount, []trustmodelstructure.AdjacencyListEntry{
+		internaltrustmodelstructure.NewAdjacencyEntryDTO("TAF", e.trustObjects()),
+	})
 }
 
 func (e *TrustModelInstance) Values() map[string][]trustmodelstructure.TrustRelationship {
-	//TODO implement me
-	panic("implement me")
+	values := make(map[string][]trustmodelstructure.TrustRelationship, len(e.opinions))
+	for trustObject, opinion := range e.opinions {
+		values[trustObject] = []trustmodelstructure.TrustRelationship{
+			internaltrustmodelstructure.NewTrustRelationshipDTO("TAF", trustObject, opinion),
+		}
+	}
+	return values
+}
+
+/*
+trustObjects returns the identifiers of all trust objects in ascending order.
+*/
+func (e *TrustModelInstance) trustObjects() []string {
+	trustObjects := make([]string, 0, len(e.opinions))
+	for trustObject := range e.opinions {
+		trustObjects = append(trustObjects, trustObject)
+	}
+	slices.Sort(trustObjects)
+	return trustObjects
+}
+
+func (e *TrustModelInstance) updateFingerprint() {
+	algorithm := fnv.New32a()
+	_, err := algorithm.Write([]byte(strings.Join(e.trustObjects(), "")))
+	if err == nil {
+		e.fingerprint = algorithm.Sum32()
+	}
 }
 
 func (e *TrustModelInstance) Template() core.TrustModelTemplate {
@@ -48,15 +90,35 @@ func (e *TrustModelInstance) TrustSourceQuantifiers() []core.TrustSourceQuantifi
 }
 
 func (e *TrustModelInstance) Update(update core.Update) bool {
-	//TODO implement me
+	oldVersion := e.Version()
 	switch update := update.(type) {
 	case trustmodelupdate.UpdateAtomicTrustOpinion:
-		//TODO
-		util.UNUSED(update)
+		if _, exists := e.opinions[update.Trustee()]; exists {
+			e.opinions[update.Trustee()] = update.Opinion()
+			e.version++
+		}
+	case trustmodelupdate.AddTrustObject:
+		//trust objects are only trusted by TAF
+		relationship := trustmodelupdate.TrustRelationship{Trustor: "TAF", Trustee: update.TrustObject()}
+		if _, exists := e.opinions[update.TrustObject()]; !exists && (len(update.Relationships()) == 0 || slices.Contains(update.Relationships(), relationship)) {
+			e.opinions[update.TrustObject()] = &FullUncertainty
+			if update.RTL() != nil {
+				e.rtls[update.TrustObject()] = update.RTL()
+			}
+			e.updateFingerprint()
+			e.version++
+		}
+	case trustmodelupdate.RemoveTrustObject:
+		if _, exists := e.opinions[update.TrustObject()]; exists && update.Removes(trustmodelupdate.TrustRelationship{Trustor: "TAF", Trustee: update.TrustObject()}) {
+			delete(e.opinions, update.TrustObject())
+			delete(e.rtls, update.TrustObject())
+			e.updateFingerprint()
+			e.version++
+		}
 	default:
 		//ignore
 	}
-	return true
+	return oldVersion != e.Version()
 }
 
 func (e *TrustModelInstance) Initialize(params map[string]interface{}) {
@@ -67,9 +129,45 @@ func (e *TrustModelInstance) Cleanup() {
 	return
 }
 func (e *TrustModelInstance) RTLs() map[string]subjectivelogic.QueryableOpinion {
-	return map[string]subjectivelogic.QueryableOpinion{}
+	return e.rtls
 }
 
 func (e *TrustModelInstance) String() string {
 	return core.TMIAsString(e)
 }
+
+/*
+snapshotState is the state of a TMI kept in snapshots.
+*/
+type snapshotState struct {
+	Opinions map[string]*trustmodelsnapshot.Opinion `json:"opinions,omitempty"`
+	RTLs     map[string]*trustmodelsnapshot.Opinion `json:"rtls,omitempty"`
+}
+
+func (e *TrustModelInstance) Snapshot() ([]byte, error) {
+	return trustmodelsnapshot.Marshal(e, snapshotState{
+		Opinions: trustmodelsnapshot.FromOpinions(e.opinions),
+		RTLs:     trustmodelsnapshot.FromOpinions(e.rtls),
+	})
+}
+
+func (e *TrustModelInstance) Restore(snapshot []byte) error {
+	var state snapshotState
+	version, err := trustmodelsnapshot.Unmarshal(e, snapshot, &state)
+	if err != nil {
+		return err
+	}
+	opinions, err := trustmodelsnapshot.Opinions(state.Opinions)
+	if err != nil {
+		return err
+	}
+	rtls, err := trustmodelsnapshot.Opinions(state.RTLs)
+	if err != nil {
+		return err
+	}
+	e.opinions = opinions
+	e.rtls = rtls
+	e.version = version
+	e.updateFingerprint()
+	return nil
+}
@@ -3,6 +3,7 @@ package trustmodel_example_v0_0_1
 import (
 	"fmt"
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
 	"math/rand/v2"
 )
 
@@ -35,6 +36,8 @@ func (t TrustModelTemplate) Spawn(params map[string]string, context core.TafCont
 		id:       fmt.Sprintf("%000000d", rand.IntN(999999)),
 		version:  0,
 		template: t,
+		opinions: make(map[string]subjectivelogic.QueryableOpinion),
+		rtls:     make(map[string]subjectivelogic.QueryableOpinion),
 	}, nil, nil
 }
 
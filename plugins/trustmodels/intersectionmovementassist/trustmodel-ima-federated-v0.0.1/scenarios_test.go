@@ -0,0 +1,59 @@
+package trustmodel_ima_federated_v0_0_1
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodeltest"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"testing"
+)
+
+func ntmEvidence(trustee string, belief float64, disbelief float64) trustmodeltest.Evidence {
+	remoteOpinion, _ := subjectivelogic.NewOpinion(belief, disbelief, 1-belief-disbelief, 0.5)
+	return trustmodeltest.Evidence{
+		TrustSource: core.NTM,
+		Trustee:     trustee,
+		Values:      map[core.EvidenceType]interface{}{core.NTM_REMOTE_OPINION: &remoteOpinion},
+	}
+}
+
+func mbdEvidence(trustee string, report int) trustmodeltest.Evidence {
+	return trustmodeltest.Evidence{
+		TrustSource: core.MBD,
+		Trustee:     trustee,
+		Values:      map[core.EvidenceType]interface{}{core.MBD_MISBEHAVIOR_REPORT: report},
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	trustmodeltest.Run(t, CreateTrustModelTemplate("IMA_FEDERATED", "0.0.1"),
+		trustmodeltest.Scenario{
+			Name:    "remote opinion on the sender",
+			Trigger: core.TRIGGER_V2X_SENDER,
+			Entity:  "27",
+			Initial: []trustmodeltest.Expectation{
+				trustmodeltest.ExpectATL("C_27_27", 0, 0, 1, 0.5),
+			},
+			Steps: []trustmodeltest.Step{
+				{
+					Name:     "trusted sender without misbehavior",
+					Updates:  []core.Update{trustmodelupdate.CreateRefreshCPM("27", []string{"19"})},
+					Evidence: []trustmodeltest.Evidence{ntmEvidence("V_27", 0.8, 0.1), mbdEvidence("C_27_19", 0)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("C_27_27", 0.8, 0.1, 0.1, 0.5),
+						trustmodeltest.ExpectATL("C_27_19", 0.938, 0.031, 0.031, 0.5),
+					},
+				},
+				{
+					Name:     "distrusted sender",
+					Evidence: []trustmodeltest.Evidence{ntmEvidence("V_27", 0, 0.9)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("C_27_27", 0, 0.9, 0.1, 0.5),
+						trustmodeltest.ExpectATL("C_27_19", 0.690, 0.279, 0.031, 0.5),
+						trustmodeltest.ExpectDecision("C_27_19", core.NOT_TRUSTWORTHY),
+					},
+				},
+			},
+		},
+	)
+}
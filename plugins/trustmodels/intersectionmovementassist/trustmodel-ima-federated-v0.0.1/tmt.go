@@ -3,6 +3,7 @@ package trustmodel_ima_federated_v0_0_1
 import (
 	"fmt"
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/parameter"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
 	"strconv"
 )
@@ -23,7 +24,8 @@ type TrustModelTemplate struct {
 func CreateTrustModelTemplate(name string, version string) core.TrustModelTemplate {
 
 	//Extract list of used trust sources from TrustSourceQuantifiers
-	tsqs, _ := createTrustSourceQuantifiers(nil)
+	defaults := parameterSchema.Values(nil)
+	tsqs, _ := createTrustSourceQuantifiers(defaults)
 	evidenceMap := make(map[core.EvidenceType]bool)
 	for _, quantifier := range tsqs {
 		for _, evidence := range quantifier.Evidence {
@@ -53,7 +55,8 @@ func (t TrustModelTemplate) TemplateName() string {
 }
 
 func (t TrustModelTemplate) Spawn(params map[string]string, context core.TafContext) ([]core.TrustSourceQuantifier, core.TrustModelInstance, core.DynamicTrustModelInstanceSpawner, error) {
-	tsqs, err := createTrustSourceQuantifiers(params)
+	values := parameterSchema.Values(params)
+	tsqs, err := createTrustSourceQuantifiers(values)
 	if err != nil {
 		return nil, nil, nil, err
 	} else {
@@ -62,6 +65,10 @@ func (t TrustModelTemplate) Spawn(params map[string]string, context core.TafCont
 	}
 }
 
+func (t TrustModelTemplate) ParameterSchema() parameter.Schema {
+	return parameterSchema
+}
+
 func (t TrustModelTemplate) Description() string {
 	return "IMA Trust Model, standalone variant. This trust model supports configurable exponentially weighted averaging of ATOs from trust source MBD."
 }
@@ -1,33 +1,14 @@
 package trustmodel_ima_federated_v0_0_1
 
 import (
-	"errors"
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/parameter"
+	"github.com/horizon-connect-eu/go-taf/pkg/quantifier"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustsource"
-	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
-	"math"
 	"strconv"
 	"strings"
 )
 
-var defaultTCHExistenceWeights = map[core.EvidenceType]float64{
-	core.TCH_SECURE_BOOT:                          0.24,
-	core.TCH_ACCESS_CONTROL:                       0.16,
-	core.TCH_CONTROL_FLOW_INTEGRITY:               0.08,
-	core.TCH_SECURE_OTA:                           0.08,
-	core.TCH_APPLICATION_ISOLATION:                0.16,
-	core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION: 0.24,
-}
-
-var defaultTCHOutputWeights = map[core.EvidenceType]float64{
-	core.TCH_SECURE_BOOT:                          2,
-	core.TCH_ACCESS_CONTROL:                       1,
-	core.TCH_CONTROL_FLOW_INTEGRITY:               2,
-	core.TCH_SECURE_OTA:                           0,
-	core.TCH_APPLICATION_ISOLATION:                0,
-	core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION: 2,
-}
-
 var defaultMBDWeightsNoDetection = map[trustsource.MisbehaviorDetector]float64{
 	trustsource.MBD_DIST_PLAU:                   1,
 	trustsource.MBD_SPEE_PLAU:                   1,
@@ -50,160 +31,84 @@ var defaultMBDWeightsDetection = map[trustsource.MisbehaviorDetector]float64{
 	trustsource.MBD_LOCAL_PERCEPTION_VERIF:      2,
 }
 
-func createTrustSourceQuantifiers(params map[string]string) ([]core.TrustSourceQuantifier, error) {
-	mbdWeightsDetection := make(map[trustsource.MisbehaviorDetector]float64)
+var misbehaviorDetectors = []trustsource.MisbehaviorDetector{trustsource.MBD_DIST_PLAU, trustsource.MBD_SPEE_PLAU, trustsource.MBD_SPEE_CONS, trustsource.MBD_POS_SPEE_CONS, trustsource.MBD_KALMAN_POS_CONS, trustsource.MBD_KALMAN_POS_SPEED_CONS_SPEED, trustsource.MBD_KALMAN_POS_SPEED_CONS_POS, trustsource.MBD_LOCAL_PERCEPTION_VERIF}
 
-	for key, defaultValue := range defaultMBDWeightsDetection {
-		mbdWeightsDetection[key] = defaultValue
-	}
-
-	mbdWeightsNoDetection := make(map[trustsource.MisbehaviorDetector]float64)
+/*
+parameterSchema declares the weights of the MBD quantifier with the default weights above as defaults: weights of
+detectors that did (MBD_D_<detector>) or did not (MBD_ND_<detector>) detect misbehavior, and the smoothing factor
+MBD_EWMA_ALPHA of MBD opinions.
+*/
+var parameterSchema = createParameterSchema()
 
-	for key, defaultValue := range defaultMBDWeightsNoDetection {
-		mbdWeightsNoDetection[key] = defaultValue
+func createParameterSchema() parameter.Schema {
+	format := func(value float64) *string {
+		formatted := strconv.FormatFloat(value, 'f', -1, 64)
+		return &formatted
 	}
-
-	tchExistenceWeights := make(map[core.EvidenceType]float64)
-
-	for key, defaultValue := range defaultTCHExistenceWeights {
-		tchExistenceWeights[key] = defaultValue
-	}
-
-	tchOutputWeights := make(map[core.EvidenceType]float64)
-
-	for key, defaultValue := range defaultTCHOutputWeights {
-		tchOutputWeights[key] = defaultValue
+	schema := parameter.Schema{}
+	for _, detector := range misbehaviorDetectors {
+		minimum := 0.0
+		name := strings.TrimPrefix(detector.String(), "MBD_")
+		schema.Parameters = append(schema.Parameters, parameter.Definition{
+			Name:        "MBD_D_" + name,
+			Type:        parameter.NUMBER,
+			Description: "Weight of " + detector.String() + " if misbehavior has been detected",
+			Default:     format(defaultMBDWeightsDetection[detector]),
+			Minimum:     &minimum,
+		}, parameter.Definition{
+			Name:        "MBD_ND_" + name,
+			Type:        parameter.NUMBER,
+			Description: "Weight of " + detector.String() + " if no misbehavior has been detected",
+			Default:     format(defaultMBDWeightsNoDetection[detector]),
+			Minimum:     &minimum,
+		})
 	}
+	minimum, maximum := parameter.Range(0, 1)
+	schema.Parameters = append(schema.Parameters, parameter.Definition{
+		Name:        "MBD_EWMA_ALPHA",
+		Type:        parameter.NUMBER,
+		Description: "Smoothing factor of the exponentially weighted moving average of MBD opinions (1: no averaging)",
+		Default:     format(DEFAULT_MBD_EWMA_ALPHA),
+		Minimum:     minimum,
+		Maximum:     maximum,
+	})
+	return schema
+}
 
-	if params != nil {
-		//TODO: update  with params
-
-		for key, value := range params {
-			if strings.Contains(key, "MBD_D") {
-				detector := strings.SplitAfterN(key, "_", 3)[2]
-				if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
-					detectorType := trustsource.MisbehaviorDetectorByName("MBD_" + detector)
-					if detectorType == trustsource.MBD_UNKNOWN {
-						return nil, errors.New("Key" + key + "is not valid")
-					} else {
-						mbdWeightsDetection[detectorType] = floatValue
-					}
-				} else {
-					return nil, errors.New("Key" + key + "is not a float value")
-				}
-			} else if strings.Contains(key, "MBD_ND") {
-				detector := strings.SplitAfterN(key, "_", 3)[2]
-				if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
-					detectorType := trustsource.MisbehaviorDetectorByName("MBD_" + detector)
-					if detectorType == trustsource.MBD_UNKNOWN {
-						return nil, errors.New("Key" + key + "is not valid")
-					} else {
-						mbdWeightsNoDetection[detectorType] = floatValue
-					}
-				} else {
-					return nil, errors.New("Key" + key + "is not a float value")
-				}
-			} else if strings.Contains(key, "TCH_EXISTENCE") {
-				control := strings.SplitAfterN(key, "_", 3)[2]
-				if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
-					controlType := core.EvidenceTypeBySourceAndName(core.TCH, control)
-					if controlType == core.UNKNOWN {
-						return nil, errors.New("Key" + key + "is not valid")
-					} else {
-						tchExistenceWeights[controlType] = floatValue
-					}
-				} else {
-					return nil, errors.New("Key" + key + "is not a float value")
-				}
-			} else if strings.Contains(key, "TCH_OUTPUT") {
-				control := strings.SplitAfterN(key, "_", 3)[2]
-				if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
-					controlType := core.EvidenceTypeBySourceAndName(core.TCH, control)
-					if controlType == core.UNKNOWN {
-						return nil, errors.New("Key" + key + "is not valid")
-					} else {
-						tchOutputWeights[controlType] = floatValue
-					}
-				} else {
-					return nil, errors.New("Key" + key + "is not a float value")
-				}
-			}
-		}
-	}
+func createTrustSourceQuantifiers(values parameter.Values) ([]core.TrustSourceQuantifier, error) {
+	mbdWeightsDetection := make(map[trustsource.MisbehaviorDetector]float64)
+	mbdWeightsNoDetection := make(map[trustsource.MisbehaviorDetector]float64)
 
-	// normalization of TCH_EXISTENCE weights in case the sum is more than 1.0
-	sum := 0.0
-	for _, value := range tchExistenceWeights {
-		sum = sum + value
+	for _, detector := range misbehaviorDetectors {
+		name := strings.TrimPrefix(detector.String(), "MBD_")
+		mbdWeightsDetection[detector] = values.Float("MBD_D_" + name)
+		mbdWeightsNoDetection[detector] = values.Float("MBD_ND_" + name)
 	}
 
-	if sum > 1.0 {
-		for key, value := range tchExistenceWeights {
-			tchExistenceWeights[key] = value / sum
-		}
+	mbdQuantifier, err := quantifier.NewMisbehaviorDetection(quantifier.MisbehaviorDetection{
+		DetectionWeights:   mbdWeightsDetection,
+		NoDetectionWeights: mbdWeightsNoDetection,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	tchQuantifier := core.TrustSourceQuantifier{
-		Trustor:     "MEC",
-		Trustee:     "V_*",
-		Scope:       "C_*_*",
-		TrustSource: core.NTM,
-		Evidence:    []core.EvidenceType{core.NTM_REMOTE_OPINION},
-		Quantifier: func(m map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
-
-			//Return first entry
-			for _, opinion := range m {
-				return opinion.(subjectivelogic.QueryableOpinion)
-			}
-
-			return &FullUncertainty
+	return []core.TrustSourceQuantifier{
+		{
+			Trustor:     "MEC",
+			Trustee:     "V_*",
+			Scope:       "C_*_*",
+			TrustSource: core.NTM,
+			Evidence:    []core.EvidenceType{core.NTM_REMOTE_OPINION},
+			Quantifier:  quantifier.RemoteOpinion(nil),
 		},
-	}
-
-	mbdQuantifier := core.TrustSourceQuantifier{
-		Trustor:     "V_ego",
-		Trustee:     "C_*_*",
-		Scope:       "C_*_*",
-		TrustSource: core.MBD,
-		Evidence:    []core.EvidenceType{core.MBD_MISBEHAVIOR_REPORT},
-		Quantifier: func(m map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
-			binaryFormat := strconv.FormatInt(int64(m[core.MBD_MISBEHAVIOR_REPORT].(int)), 2)
-
-			for i := len(binaryFormat); i < 8; i++ {
-				binaryFormat = "0" + binaryFormat
-			}
-
-			sumWeights := 0.0
-			sumBelief := 0.0
-			sumDisbelief := 0.0
-
-			for i := 0; i < 8; i++ {
-				detector := trustsource.MisbehaviorDetector(7 - i)
-				if string(binaryFormat[i]) == "0" {
-					sumWeights = sumWeights + mbdWeightsNoDetection[detector]
-					sumBelief = sumBelief + mbdWeightsNoDetection[detector]
-				} else {
-					sumWeights = sumWeights + mbdWeightsDetection[detector]
-					sumDisbelief = sumDisbelief + mbdWeightsDetection[detector]
-				}
-			}
-
-			exponentialValue := -math.Pow(1.3, -float64(sumWeights)) + 1
-			belief := (sumBelief / sumWeights) * exponentialValue
-			disbelief := (sumDisbelief / sumWeights) * exponentialValue
-			//uncertainty := 1 - exponentialValue
-
-			sl, _ := subjectivelogic.NewOpinion(belief, disbelief, 1-belief-disbelief, 0.5)
-
-			return &sl
+		{
+			Trustor:     "V_ego",
+			Trustee:     "C_*_*",
+			Scope:       "C_*_*",
+			TrustSource: core.MBD,
+			Evidence:    []core.EvidenceType{core.MBD_MISBEHAVIOR_REPORT},
+			Quantifier:  mbdQuantifier,
 		},
-	}
-
-	//TODO: return TSQs with local mbdWeightsDetection, etc.
-
-	return []core.TrustSourceQuantifier{tchQuantifier, mbdQuantifier}, nil
-}
-
-func init() {
-	//TODO: Validate weights here instead of inside quantifier function
+	}, nil
 }
@@ -149,3 +149,58 @@ func printATLs(t *testing.T, atls map[string]subjectivelogic.QueryableOpinion, r
 		t.Log("[" + proposition + "]" + "\t" + opinion.String() + " \t Decision: " + decision + " (ATL:" + fmt.Sprintf("%.2f", trustdecision.ProjectProbability(opinion)) + " <=?=> RTL: " + fmt.Sprintf("%.2f", trustdecision.ProjectProbability(rtl)) + ")")
 	}
 }
+
+func TestTrustObjectUpdates(t *testing.T) {
+	tmt := CreateTrustModelTemplate("IMA_STANDALONE", "0.0.1")
+	_, _, spawner, err := tmt.Spawn(nil, createTafContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmi, _ := spawner.OnNewVehicle("27", nil)
+	tmi.Initialize(nil)
+
+	fingerprint := tmi.Fingerprint()
+	if !tmi.Update(trustmodelupdate.CreateAddTrustObject("C_27_19")) || tmi.Fingerprint() == fingerprint {
+		t.Fatal("expected C_27_19 to be added")
+	}
+	if _, exists := tmi.Values()["C_27_19"]; !exists {
+		t.Errorf("expected scope C_27_19, got %v", tmi.Values())
+	}
+	if tmi.Update(trustmodelupdate.CreateAddTrustObject("C_28_19")) {
+		t.Error("expected observation of another vehicle to be ignored")
+	}
+	if tmi.Update(trustmodelupdate.CreateRemoveTrustObject("C_27_27")) {
+		t.Error("expected observation of the vehicle on itself to be kept")
+	}
+	if !tmi.Update(trustmodelupdate.CreateRemoveTrustObject("C_27_19")) || tmi.Fingerprint() != fingerprint {
+		t.Error("expected C_27_19 to be removed")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	tmt := CreateTrustModelTemplate("IMA_STANDALONE", "0.0.1")
+	_, _, spawner, err := tmt.Spawn(nil, createTafContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmi, _ := spawner.OnNewVehicle("27", nil)
+	tmi.Initialize(nil)
+	opinion, _ := subjectivelogic.NewOpinion(0.5, 0.2, 0.3, 0.5)
+	tmi.Update(trustmodelupdate.CreateRefreshCPM("27", []string{"19"}))
+	tmi.Update(trustmodelupdate.CreateAtomicTrustOpinionUpdate(&opinion, "V_ego", "C_27_19", core.MBD))
+
+	snapshot, err := tmi.(core.SnapshotableTrustModelInstance).Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, _ := spawner.OnNewVehicle("27", nil)
+	if err := restored.(core.SnapshotableTrustModelInstance).Restore(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Version() != tmi.Version() || restored.Fingerprint() != tmi.Fingerprint() {
+		t.Errorf("expected version %d and fingerprint %d, got %d and %d", tmi.Version(), tmi.Fingerprint(), restored.Version(), restored.Fingerprint())
+	}
+	if relationships := restored.Values()["C_27_19"]; len(relationships) != 3 || *relationships[1].Opinion().(*subjectivelogic.Opinion) != opinion {
+		t.Errorf("expected restored opinion on C_27_19, got %v", restored.Values())
+	}
+}
@@ -0,0 +1,88 @@
+package trustmodel_ima_standalone_v0_0_1
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodeltest"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"testing"
+)
+
+func tchEvidence(trustee string, secureBoot int) trustmodeltest.Evidence {
+	return trustmodeltest.Evidence{
+		TrustSource: core.TCH,
+		Trustee:     trustee,
+		Values: map[core.EvidenceType]interface{}{
+			core.TCH_SECURE_BOOT:                          secureBoot,
+			core.TCH_SECURE_OTA:                           1,
+			core.TCH_ACCESS_CONTROL:                       1,
+			core.TCH_APPLICATION_ISOLATION:                1,
+			core.TCH_CONTROL_FLOW_INTEGRITY:               1,
+			core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION: 1,
+		},
+	}
+}
+
+func mbdEvidence(trustee string, report int) trustmodeltest.Evidence {
+	return trustmodeltest.Evidence{
+		TrustSource: core.MBD,
+		Trustee:     trustee,
+		Values:      map[core.EvidenceType]interface{}{core.MBD_MISBEHAVIOR_REPORT: report},
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	trustmodeltest.Run(t, CreateTrustModelTemplate("IMA_STANDALONE", "0.0.1"),
+		trustmodeltest.Scenario{
+			Name:    "trustworthy sender",
+			Trigger: core.TRIGGER_V2X_SENDER,
+			Entity:  "27",
+			Initial: []trustmodeltest.Expectation{
+				trustmodeltest.ExpectATL("C_27_27", 0, 0, 1, 0.5),
+			},
+			Steps: []trustmodeltest.Step{
+				{
+					Name:    "CPM with a perceived object",
+					Updates: []core.Update{trustmodelupdate.CreateRefreshCPM("27", []string{"19"})},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("C_27_19", 0, 0, 1, 0.5),
+					},
+				},
+				{
+					Name:     "attested sender without misbehavior",
+					Evidence: []trustmodeltest.Evidence{tchEvidence("V_27", 1), mbdEvidence("C_27_19", 0)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("C_27_27", 0.96, 0, 0.04, 0.5),
+						trustmodeltest.ExpectATL("C_27_19", 0.979, 0, 0.021, 0.5),
+						//The RTL requires full belief, which is not reached with any remaining uncertainty
+						trustmodeltest.ExpectDecision("C_27_19", core.NOT_TRUSTWORTHY),
+					},
+				},
+				{
+					Name:    "perceived object is removed",
+					Updates: []core.Update{trustmodelupdate.CreateRemoveTrustObject("C_27_19")},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectAbsent("C_27_19"),
+						trustmodeltest.ExpectATL("C_27_27", 0.96, 0, 0.04, 0.5),
+					},
+				},
+			},
+		},
+		trustmodeltest.Scenario{
+			Name:    "misbehaving sender",
+			Trigger: core.TRIGGER_V2X_SENDER,
+			Entity:  "27",
+			Steps: []trustmodeltest.Step{
+				{
+					Name:     "failed secure boot and misbehavior reports",
+					Updates:  []core.Update{trustmodelupdate.CreateRefreshCPM("27", []string{"19"})},
+					Evidence: []trustmodeltest.Evidence{tchEvidence("V_27", 0), mbdEvidence("C_27_19", 63)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("C_27_27", 0, 1, 0, 0.5),
+						trustmodeltest.ExpectATL("C_27_19", 0, 1, 0, 0.5),
+						trustmodeltest.ExpectDecision("C_27_19", core.NOT_TRUSTWORTHY),
+					},
+				},
+			},
+		},
+	)
+}
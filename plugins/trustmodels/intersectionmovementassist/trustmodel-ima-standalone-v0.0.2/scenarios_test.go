@@ -0,0 +1,84 @@
+package trustmodel_ima_standalone_v0_0_2
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodeltest"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"testing"
+)
+
+func tchEvidence(trustee string, secureBoot int) trustmodeltest.Evidence {
+	return trustmodeltest.Evidence{
+		TrustSource: core.TCH,
+		Trustee:     trustee,
+		Values: map[core.EvidenceType]interface{}{
+			core.TCH_SECURE_BOOT:                          secureBoot,
+			core.TCH_SECURE_OTA:                           1,
+			core.TCH_ACCESS_CONTROL:                       1,
+			core.TCH_APPLICATION_ISOLATION:                1,
+			core.TCH_CONTROL_FLOW_INTEGRITY:               1,
+			core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION: 1,
+		},
+	}
+}
+
+func mbdEvidence(trustee string, report int) trustmodeltest.Evidence {
+	return trustmodeltest.Evidence{
+		TrustSource: core.MBD,
+		Trustee:     trustee,
+		Values:      map[core.EvidenceType]interface{}{core.MBD_MISBEHAVIOR_REPORT: report},
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	trustmodeltest.Run(t, CreateTrustModelTemplate("IMA_STANDALONE", "0.0.2"),
+		trustmodeltest.Scenario{
+			Name:    "latest misbehavior report",
+			Trigger: core.TRIGGER_V2X_SENDER,
+			Entity:  "27",
+			Steps: []trustmodeltest.Step{
+				{
+					Name:     "attested sender without misbehavior",
+					Updates:  []core.Update{trustmodelupdate.CreateRefreshCPM("27", []string{"19"})},
+					Evidence: []trustmodeltest.Evidence{tchEvidence("V_27", 1), mbdEvidence("C_27_19", 0)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("C_27_27", 0.96, 0, 0.04, 0.5),
+						trustmodeltest.ExpectATL("C_27_19", 0.979, 0, 0.021, 0.5),
+					},
+				},
+				{
+					Name:     "misbehavior report replaces the previous one",
+					Evidence: []trustmodeltest.Evidence{mbdEvidence("C_27_19", 63)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("C_27_19", 0.552, 0.433, 0.016, 0.5),
+						trustmodeltest.ExpectDecision("C_27_19", core.NOT_TRUSTWORTHY),
+					},
+				},
+			},
+		},
+		trustmodeltest.Scenario{
+			Name:    "averaged misbehavior reports",
+			Params:  map[string]string{"MBD_EWMA_ALPHA": "0.5"},
+			Trigger: core.TRIGGER_V2X_SENDER,
+			Entity:  "27",
+			Steps: []trustmodeltest.Step{
+				{
+					Name:     "attested sender without misbehavior",
+					Updates:  []core.Update{trustmodelupdate.CreateRefreshCPM("27", []string{"19"})},
+					Evidence: []trustmodeltest.Evidence{tchEvidence("V_27", 1), mbdEvidence("C_27_19", 0)},
+					Expect: []trustmodeltest.Expectation{
+						//The first report is averaged with the initial opinion of full uncertainty
+						trustmodeltest.ExpectATL("C_27_19", 0.961, 0, 0.039, 0.5),
+					},
+				},
+				{
+					Name:     "misbehavior report is averaged with the previous one",
+					Evidence: []trustmodeltest.Evidence{mbdEvidence("C_27_19", 63)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("C_27_19", 0.918, 0.046, 0.036, 0.5),
+					},
+				},
+			},
+		},
+	)
+}
@@ -3,6 +3,7 @@ package trustmodel_ima_standalone_v0_0_2
 import (
 	"fmt"
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelsnapshot"
 	internaltrustmodelstructure "github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelstructure"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
@@ -58,9 +59,31 @@ func (e *TrustModelInstance) Update(update core.Update) bool {
 			e.incrementVersion()
 			e.updateValues()
 		}
+	case trustmodelupdate.AddTrustObject:
+		if e.processTrustObjectUpdate(update.TrustObject(), true) {
+			e.updateStructure()
+			e.updateFingerprint()
+			e.incrementVersion()
+			e.updateValues()
+		}
+	case trustmodelupdate.RemoveTrustObject:
+		//relationships of observations are given by the trust model, so only entire observations can be removed
+		if len(update.Relationships()) == 0 && e.processTrustObjectUpdate(update.TrustObject(), false) {
+			e.updateStructure()
+			e.updateFingerprint()
+			e.incrementVersion()
+			e.updateValues()
+		}
 	case trustmodelupdate.UpdateAtomicTrustOpinion:
 		trustee := update.Trustee()
-		if strings.HasPrefix(trustee, "V_") || strings.HasPrefix(trustee, "vehicle_") {
+		if vecID, objID, err := parseComponentIdentifier(trustee); err == nil {
+			//components of the sender vehicle are observations of the vehicle
+			if _, ok := e.objects[objID]; ok && vecID == e.sourceID {
+				e.objects[objID] = update.Opinion()
+				e.updateValues()
+				e.incrementVersion()
+			}
+		} else if strings.HasPrefix(trustee, "V_") || strings.HasPrefix(trustee, "vehicle_") {
 			id, err := parseVehicleIdentifier(trustee)
 			if err == nil && id == e.sourceID {
 				e.sourceOpinion = update.Opinion()
@@ -141,6 +164,30 @@ func (e *TrustModelInstance) processTopologyUpdate(latestObjects []string) bool
 	return topologyChanged
 }
 
+/*
+processTrustObjectUpdate adds or removes an observation of the sender vehicle, given as observation (C_sourceID_{X}) or as
+component of the vehicle ({vehicle}~{X}), from the internal topology. In case the topology is modified, the function
+returns true. The observation of the vehicle on itself is never removed.
+*/
+func (e *TrustModelInstance) processTrustObjectUpdate(trustObject string, add bool) bool {
+	vecID, objID, err := parseObjectIdentifier(trustObject)
+	if err != nil {
+		vecID, objID, err = parseComponentIdentifier(trustObject)
+	}
+	if err != nil || vecID != e.sourceID {
+		return false
+	}
+	_, exists := e.objects[objID]
+	if add && !exists {
+		e.objects[objID] = &FullUncertainty
+		return true
+	} else if !add && exists && objID != e.sourceID {
+		delete(e.objects, objID)
+		return true
+	}
+	return false
+}
+
 /*
 updateFingerprint calculates the current fingerprint for the TMI.
 Therefore, it takes the all the dynamic nodes, concatenates their
@@ -306,6 +353,21 @@ func parseObjectIdentifier(str string) (string, string, error) {
 	}
 }
 
+/*
+parseComponentIdentifier is a helper function to extract plain identifiers from the identifier of a component of a
+vehicle (see core.MergeComponentIdentifier), where the vehicle is given by its plain, V_ or vehicle_ identifier.
+*/
+func parseComponentIdentifier(str string) (string, string, error) {
+	trustee, component, ok := core.SplitComponentIdentifier(str)
+	if ok {
+		pattern := regexp.MustCompile(`^(?:V_|vehicle_)?(\d+)$`)
+		if res := pattern.FindStringSubmatch(trustee); res != nil {
+			return res[1], component, nil
+		}
+	}
+	return "", "", fmt.Errorf("Invalid component identifier '%s'", str)
+}
+
 /*
 parseVehicleIdentifier is a helper function to extract plain identifiers from a vehicle identifier string.
 */
@@ -322,3 +384,44 @@ func parseVehicleIdentifier(str string) (string, error) {
 func (e *TrustModelInstance) String() string {
 	return core.TMIAsString(e)
 }
+
+/*
+snapshotState is the state of a TMI kept in snapshots. Structure, values, and RTLs are derived from it.
+*/
+type snapshotState struct {
+	SourceID      string                                 `json:"sourceID"`
+	SourceOpinion *trustmodelsnapshot.Opinion            `json:"sourceOpinion"`
+	Objects       map[string]*trustmodelsnapshot.Opinion `json:"objects"`
+}
+
+func (e *TrustModelInstance) Snapshot() ([]byte, error) {
+	return trustmodelsnapshot.Marshal(e, snapshotState{
+		SourceID:      e.sourceID,
+		SourceOpinion: trustmodelsnapshot.FromOpinion(e.sourceOpinion),
+		Objects:       trustmodelsnapshot.FromOpinions(e.objects),
+	})
+}
+
+func (e *TrustModelInstance) Restore(snapshot []byte) error {
+	var state snapshotState
+	version, err := trustmodelsnapshot.Unmarshal(e, snapshot, &state)
+	if err != nil {
+		return err
+	}
+	sourceOpinion, err := state.SourceOpinion.Opinion()
+	if err != nil {
+		return err
+	}
+	objects, err := trustmodelsnapshot.Opinions(state.Objects)
+	if err != nil {
+		return err
+	}
+	e.sourceID = state.SourceID
+	e.sourceOpinion = sourceOpinion
+	e.objects = objects
+	e.version = version
+	e.updateStructure()
+	e.updateFingerprint()
+	e.updateValues()
+	return nil
+}
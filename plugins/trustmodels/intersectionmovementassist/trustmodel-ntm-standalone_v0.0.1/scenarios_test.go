@@ -0,0 +1,122 @@
+package trustmodel_ntm_standalone_v0_0_1
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodeltest"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"testing"
+)
+
+func tchEvidence(trustee string, secureBoot int) trustmodeltest.Evidence {
+	return trustmodeltest.Evidence{
+		TrustSource: core.TCH,
+		Trustee:     trustee,
+		Values: map[core.EvidenceType]interface{}{
+			core.TCH_SECURE_BOOT:                          secureBoot,
+			core.TCH_SECURE_OTA:                           1,
+			core.TCH_ACCESS_CONTROL:                       1,
+			core.TCH_APPLICATION_ISOLATION:                1,
+			core.TCH_CONTROL_FLOW_INTEGRITY:               1,
+			core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION: 1,
+		},
+	}
+}
+
+func mbdEvidence(trustee string, report int) trustmodeltest.Evidence {
+	return trustmodeltest.Evidence{
+		TrustSource: core.MBD,
+		Trustee:     trustee,
+		Values:      map[core.EvidenceType]interface{}{core.MBD_MISBEHAVIOR_REPORT: report},
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	trustmodeltest.Run(t, CreateTrustModelTemplate("NTM_STANDALONE", "0.0.1"),
+		trustmodeltest.Scenario{
+			Name:    "attestation and misbehavior of a trustee",
+			Trigger: core.TRIGGER_TCH_TRUSTEE,
+			Entity:  "5",
+			Initial: []trustmodeltest.Expectation{
+				trustmodeltest.ExpectATL("vehicle_5", 0, 0, 1, 0.5),
+				trustmodeltest.ExpectDecision("vehicle_5", core.UNDECIDABLE),
+			},
+			Steps: []trustmodeltest.Step{
+				{
+					Name:     "successful attestation",
+					Evidence: []trustmodeltest.Evidence{tchEvidence("vehicle_5", 1)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("vehicle_5", 0.96, 0, 0.04, 0.5),
+						trustmodeltest.ExpectDecision("vehicle_5", core.TRUSTWORTHY),
+					},
+				},
+				{
+					Name:     "no misbehavior detected",
+					Evidence: []trustmodeltest.Evidence{mbdEvidence("vehicle_5", 0)},
+					Expect: []trustmodeltest.Expectation{
+						//Opinions of both trust sources are multiplied, including their base rates
+						trustmodeltest.ExpectATL("vehicle_5", 0.945, 0, 0.055, 0.25),
+						trustmodeltest.ExpectDecision("vehicle_5", core.TRUSTWORTHY),
+					},
+				},
+				{
+					Name:     "failed secure boot",
+					Evidence: []trustmodeltest.Evidence{tchEvidence("vehicle_5", 0)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("vehicle_5", 0, 1, 0, 0.25),
+						trustmodeltest.ExpectDecision("vehicle_5", core.NOT_TRUSTWORTHY),
+					},
+				},
+			},
+		},
+		trustmodeltest.Scenario{
+			Name:    "averaged misbehavior reports",
+			Params:  map[string]string{"MBD_EWMA_ALPHA": "0.5"},
+			Trigger: core.TRIGGER_TCH_TRUSTEE,
+			Entity:  "5",
+			Steps: []trustmodeltest.Step{
+				{
+					Name:     "misbehavior detected",
+					Evidence: []trustmodeltest.Evidence{mbdEvidence("vehicle_5", 63)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("vehicle_5", 0.139, 0.348, 0.513, 0.5),
+					},
+				},
+				{
+					Name:     "misbehavior detected again",
+					Evidence: []trustmodeltest.Evidence{mbdEvidence("vehicle_5", 63)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("vehicle_5", 0.209, 0.522, 0.269, 0.5),
+					},
+				},
+			},
+		},
+		trustmodeltest.Scenario{
+			Name:    "components of the trustee",
+			Trigger: core.TRIGGER_TCH_TRUSTEE,
+			Entity:  "5",
+			Steps: []trustmodeltest.Step{
+				{
+					Name:    "added component is fully uncertain",
+					Updates: []core.Update{trustmodelupdate.CreateAddTrustObject(core.MergeComponentIdentifier("vehicle_5", "ecu"))},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("vehicle_5~ecu", 0, 0, 1, 0.5),
+						trustmodeltest.ExpectATL("vehicle_5", 0, 0, 1, 0.5),
+					},
+				},
+				{
+					Name:    "components of other trustees are ignored",
+					Updates: []core.Update{trustmodelupdate.CreateAddTrustObject(core.MergeComponentIdentifier("vehicle_6", "ecu"))},
+					Expect:  []trustmodeltest.Expectation{trustmodeltest.ExpectAbsent("vehicle_6~ecu")},
+				},
+				{
+					Name:    "removed component",
+					Updates: []core.Update{trustmodelupdate.CreateRemoveTrustObject(core.MergeComponentIdentifier("vehicle_5", "ecu"))},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectAbsent("vehicle_5~ecu"),
+						trustmodeltest.ExpectATL("vehicle_5", 0, 0, 1, 0.5),
+					},
+				},
+			},
+		},
+	)
+}
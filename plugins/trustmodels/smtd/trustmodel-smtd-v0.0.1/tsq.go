@@ -1,12 +1,10 @@
 package trustmodel_smtd_v0_0_1
 
 import (
-	"errors"
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/parameter"
+	"github.com/horizon-connect-eu/go-taf/pkg/quantifier"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustsource"
-	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
-	"log"
-	"math"
 	"strconv"
 	"strings"
 )
@@ -51,202 +49,113 @@ var defaultMBDWeightsDetection = map[trustsource.MisbehaviorDetector]float64{
 	trustsource.MBD_LOCAL_PERCEPTION_VERIF:      2,
 }
 
-func createTrustSourceQuantifiers(params map[string]string) ([]core.TrustSourceQuantifier, error) {
-	mbdWeightsDetection := make(map[trustsource.MisbehaviorDetector]float64)
+var tchEvidenceTypes = []core.EvidenceType{core.TCH_SECURE_BOOT, core.TCH_SECURE_OTA, core.TCH_ACCESS_CONTROL, core.TCH_APPLICATION_ISOLATION, core.TCH_CONTROL_FLOW_INTEGRITY, core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION}
 
-	for key, defaultValue := range defaultMBDWeightsDetection {
-		mbdWeightsDetection[key] = defaultValue
-	}
+var misbehaviorDetectors = []trustsource.MisbehaviorDetector{trustsource.MBD_DIST_PLAU, trustsource.MBD_SPEE_PLAU, trustsource.MBD_SPEE_CONS, trustsource.MBD_POS_SPEE_CONS, trustsource.MBD_KALMAN_POS_CONS, trustsource.MBD_KALMAN_POS_SPEED_CONS_SPEED, trustsource.MBD_KALMAN_POS_SPEED_CONS_POS, trustsource.MBD_LOCAL_PERCEPTION_VERIF}
 
-	mbdWeightsNoDetection := make(map[trustsource.MisbehaviorDetector]float64)
+/*
+parameterSchema declares the weights of the quantifiers with the default weights above as defaults: existence weights
+(TCH_EXISTENCE_<control>) and output weights (TCH_OUTPUT_<control>) of the TCH quantifier and weights of detectors that
+did (MBD_D_<detector>) or did not (MBD_ND_<detector>) detect misbehavior for the MBD quantifier.
+*/
+var parameterSchema = createParameterSchema()
 
-	for key, defaultValue := range defaultMBDWeightsNoDetection {
-		mbdWeightsNoDetection[key] = defaultValue
+func createParameterSchema() parameter.Schema {
+	format := func(value float64) *string {
+		formatted := strconv.FormatFloat(value, 'f', -1, 64)
+		return &formatted
 	}
-
-	tchExistenceWeights := make(map[core.EvidenceType]float64)
-
-	for key, defaultValue := range defaultTCHExistenceWeights {
-		tchExistenceWeights[key] = defaultValue
+	schema := parameter.Schema{}
+	for _, evidenceType := range tchEvidenceTypes {
+		minimum := 0.0
+		schema.Parameters = append(schema.Parameters, parameter.Definition{
+			Name:        "TCH_EXISTENCE_" + evidenceType.String(),
+			Type:        parameter.NUMBER,
+			Description: "Existence weight of " + evidenceType.String() + " (weights summing up to more than 1 are normalized)",
+			Default:     format(defaultTCHExistenceWeights[evidenceType]),
+			Minimum:     &minimum,
+		})
+	}
+	for _, evidenceType := range tchEvidenceTypes {
+		minimum, maximum := parameter.Range(0, 2)
+		schema.Parameters = append(schema.Parameters, parameter.Definition{
+			Name:        "TCH_OUTPUT_" + evidenceType.String(),
+			Type:        parameter.INTEGER,
+			Description: "Output weight of " + evidenceType.String() + " (0: belief, 1: disbelief, 2: complete disbelief on failure)",
+			Default:     format(defaultTCHOutputWeights[evidenceType]),
+			Minimum:     minimum,
+			Maximum:     maximum,
+		})
+	}
+	for _, detector := range misbehaviorDetectors {
+		minimum := 0.0
+		name := strings.TrimPrefix(detector.String(), "MBD_")
+		schema.Parameters = append(schema.Parameters, parameter.Definition{
+			Name:        "MBD_D_" + name,
+			Type:        parameter.NUMBER,
+			Description: "Weight of " + detector.String() + " if misbehavior has been detected",
+			Default:     format(defaultMBDWeightsDetection[detector]),
+			Minimum:     &minimum,
+		}, parameter.Definition{
+			Name:        "MBD_ND_" + name,
+			Type:        parameter.NUMBER,
+			Description: "Weight of " + detector.String() + " if no misbehavior has been detected",
+			Default:     format(defaultMBDWeightsNoDetection[detector]),
+			Minimum:     &minimum,
+		})
 	}
+	return schema
+}
 
-	tchOutputWeights := make(map[core.EvidenceType]float64)
+func createTrustSourceQuantifiers(values parameter.Values) ([]core.TrustSourceQuantifier, error) {
+	mbdWeightsDetection := make(map[trustsource.MisbehaviorDetector]float64)
+	mbdWeightsNoDetection := make(map[trustsource.MisbehaviorDetector]float64)
 
-	for key, defaultValue := range defaultTCHOutputWeights {
-		tchOutputWeights[key] = defaultValue
+	for _, detector := range misbehaviorDetectors {
+		name := strings.TrimPrefix(detector.String(), "MBD_")
+		mbdWeightsDetection[detector] = values.Float("MBD_D_" + name)
+		mbdWeightsNoDetection[detector] = values.Float("MBD_ND_" + name)
 	}
 
-	if params != nil {
-		//TODO: update  with params
+	tchExistenceWeights := make(map[core.EvidenceType]float64)
+	tchOutputWeights := make(map[core.EvidenceType]quantifier.OutputWeight)
 
-		for key, value := range params {
-			if strings.Contains(key, "MBD_D") {
-				detector := strings.SplitAfterN(key, "_", 3)[2]
-				if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
-					detectorType := trustsource.MisbehaviorDetectorByName("MBD_" + detector)
-					if detectorType == trustsource.MBD_UNKNOWN {
-						return nil, errors.New("Key" + key + "is not valid")
-					} else {
-						mbdWeightsDetection[detectorType] = floatValue
-					}
-				} else {
-					return nil, errors.New("Key" + key + "is not a float value")
-				}
-			} else if strings.Contains(key, "MBD_ND") {
-				detector := strings.SplitAfterN(key, "_", 3)[2]
-				if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
-					detectorType := trustsource.MisbehaviorDetectorByName("MBD_" + detector)
-					if detectorType == trustsource.MBD_UNKNOWN {
-						return nil, errors.New("Key" + key + "is not valid")
-					} else {
-						mbdWeightsNoDetection[detectorType] = floatValue
-					}
-				} else {
-					return nil, errors.New("Key" + key + "is not a float value")
-				}
-			} else if strings.Contains(key, "TCH_EXISTENCE") {
-				control := strings.SplitAfterN(key, "_", 3)[2]
-				if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
-					controlType := core.EvidenceTypeBySourceAndName(core.TCH, control)
-					if controlType == core.UNKNOWN {
-						return nil, errors.New("Key" + key + "is not valid")
-					} else {
-						tchExistenceWeights[controlType] = floatValue
-					}
-				} else {
-					return nil, errors.New("Key" + key + "is not a float value")
-				}
-			} else if strings.Contains(key, "TCH_OUTPUT") {
-				control := strings.SplitAfterN(key, "_", 3)[2]
-				if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
-					controlType := core.EvidenceTypeBySourceAndName(core.TCH, control)
-					if controlType == core.UNKNOWN {
-						return nil, errors.New("Key" + key + "is not valid")
-					} else {
-						tchOutputWeights[controlType] = floatValue
-					}
-				} else {
-					return nil, errors.New("Key" + key + "is not a float value")
-				}
-			}
-		}
+	for _, evidenceType := range tchEvidenceTypes {
+		tchExistenceWeights[evidenceType] = values.Float("TCH_EXISTENCE_" + evidenceType.String())
+		tchOutputWeights[evidenceType] = quantifier.OutputWeight(values.Int("TCH_OUTPUT_" + evidenceType.String()))
 	}
 
-	// normalization of TCH_EXISTENCE weights in case the sum is more than 1.0
-	sum := 0.0
-	for _, value := range tchExistenceWeights {
-		sum = sum + value
+	tchQuantifier, err := quantifier.NewSecurityControls(quantifier.SecurityControls{
+		ExistenceWeights: tchExistenceWeights,
+		OutputWeights:    tchOutputWeights,
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	if sum > 1.0 {
-		for key, value := range tchExistenceWeights {
-			tchExistenceWeights[key] = value / sum
-		}
+	mbdQuantifier, err := quantifier.NewMisbehaviorDetection(quantifier.MisbehaviorDetection{
+		DetectionWeights:   mbdWeightsDetection,
+		NoDetectionWeights: mbdWeightsNoDetection,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	tchQuantifier := core.TrustSourceQuantifier{
-		Trustor:     "V_ego",
-		Trustee:     "V_*",
-		Scope:       "C_*_*",
-		TrustSource: core.TCH,
-		Evidence:    []core.EvidenceType{core.TCH_SECURE_BOOT, core.TCH_SECURE_OTA, core.TCH_ACCESS_CONTROL, core.TCH_APPLICATION_ISOLATION, core.TCH_CONTROL_FLOW_INTEGRITY, core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION},
-		Quantifier: func(m map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
-
-			var sum = 0.0
-			for _, val := range tchExistenceWeights {
-				sum += val
-			}
-
-			if sum > 1.0 { // sum of existence weights is not allowed to exceed 1.0
-				log.Fatalf("Sum existence weights of the TCH trust source exceeds 1.0")
-			}
-
-			belief := 0.0
-			disbelief := 0.0
-			//uncertainty := 1.0
-
-			for control, rawAppraisal := range m {
-				appraisal := rawAppraisal.(int)
-				delta, ok := tchExistenceWeights[control]
-
-				if ok { // Only if control is one of the foreseen controls, belief and disbelief will be adjusted
-					if appraisal == -1 { // control not implemented
-						disbelief = disbelief + delta
-						//uncertainty = uncertainty - delta
-					} else if appraisal == 0 {
-						if tchOutputWeights[control] == 0 { // still add belief
-							belief = belief + delta
-							//uncertainty = uncertainty - delta
-						} else if tchOutputWeights[control] == 1 { // add disbelief
-							disbelief = disbelief + delta
-							//uncertainty = uncertainty - delta
-						} else if tchOutputWeights[control] == 2 { // complete disbelief
-							belief = 0.0
-							disbelief = 1.0
-							//uncertainty = 0.0
-							break // complete disbelief because negative evidence of critical securityControl
-						} else {
-							// Invalid weight
-							// TODO: Error handling
-						}
-					} else if appraisal == 1 {
-						belief = belief + delta
-						//uncertainty = uncertainty - delta
-					} else {
-						// No evidence for the control, e.g. appraisal -2 or no evidence received -> Results in higher uncertainty
-					}
-				}
-			}
-			sl, _ := subjectivelogic.NewOpinion(belief, disbelief, 1-belief-disbelief, 0.5)
-
-			return &sl
+	return []core.TrustSourceQuantifier{
+		{
+			Trustor:     "V_ego",
+			Trustee:     "V_*",
+			Scope:       "C_*_*",
+			TrustSource: core.TCH,
+			Evidence:    tchEvidenceTypes,
+			Quantifier:  tchQuantifier,
 		},
-	}
-
-	mbdQuantifier := core.TrustSourceQuantifier{
-		Trustor:     "V_ego",
-		Trustee:     "C_*_*",
-		Scope:       "C_*_*",
-		TrustSource: core.MBD,
-		Evidence:    []core.EvidenceType{core.MBD_MISBEHAVIOR_REPORT},
-		Quantifier: func(m map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
-			binaryFormat := strconv.FormatInt(int64(m[core.MBD_MISBEHAVIOR_REPORT].(int)), 2)
-
-			for i := len(binaryFormat); i < 8; i++ {
-				binaryFormat = "0" + binaryFormat
-			}
-
-			sumWeights := 0.0
-			sumBelief := 0.0
-			sumDisbelief := 0.0
-
-			for i := 0; i < 8; i++ {
-				detector := trustsource.MisbehaviorDetector(7 - i)
-				if string(binaryFormat[i]) == "0" {
-					sumWeights = sumWeights + mbdWeightsNoDetection[detector]
-					sumBelief = sumBelief + mbdWeightsNoDetection[detector]
-				} else {
-					sumWeights = sumWeights + mbdWeightsDetection[detector]
-					sumDisbelief = sumDisbelief + mbdWeightsDetection[detector]
-				}
-			}
-
-			exponentialValue := -math.Pow(1.3, -float64(sumWeights)) + 1
-			belief := (sumBelief / sumWeights) * exponentialValue
-			disbelief := (sumDisbelief / sumWeights) * exponentialValue
-			//uncertainty := 1 - exponentialValue
-
-			sl, _ := subjectivelogic.NewOpinion(belief, disbelief, 1-belief-disbelief, 0.5)
-
-			return &sl
+		{
+			Trustor:     "V_ego",
+			Trustee:     "C_*_*",
+			Scope:       "C_*_*",
+			TrustSource: core.MBD,
+			Evidence:    []core.EvidenceType{core.MBD_MISBEHAVIOR_REPORT},
+			Quantifier:  mbdQuantifier,
 		},
-	}
-
-	//TODO: return TSQs with local mbdWeightsDetection, etc.
-
-	return []core.TrustSourceQuantifier{tchQuantifier, mbdQuantifier}, nil
-}
-
-func init() {
-	//TODO: Validate weights here instead of inside quantifier function
+	}, nil
 }
@@ -0,0 +1,118 @@
+package trustmodel_to_v0_0_1
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodeltest"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"testing"
+)
+
+func tchEvidence(trustee string, secureBoot int, applicationIsolation int) trustmodeltest.Evidence {
+	return trustmodeltest.Evidence{
+		TrustSource: core.TCH,
+		Trustee:     trustee,
+		Values: map[core.EvidenceType]interface{}{
+			core.TCH_SECURE_BOOT:                          secureBoot,
+			core.TCH_SECURE_OTA:                           1,
+			core.TCH_ACCESS_CONTROL:                       1,
+			core.TCH_APPLICATION_ISOLATION:                applicationIsolation,
+			core.TCH_CONTROL_FLOW_INTEGRITY:               1,
+			core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION: 1,
+		},
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	tmt := CreateTrustModelTemplate("TO", "0.0.1")
+	trustmodeltest.Run(t, tmt,
+		trustmodeltest.Scenario{
+			Name:    "attestation of a trustee",
+			Trigger: core.TRIGGER_TCH_TRUSTEE,
+			Entity:  "5",
+			Initial: []trustmodeltest.Expectation{
+				trustmodeltest.ExpectATL("vehicle_5", 0, 0, 1, 0.5),
+				trustmodeltest.ExpectDecision("vehicle_5", core.UNDECIDABLE),
+			},
+			Steps: []trustmodeltest.Step{
+				{
+					Name:     "evidence on other trustees is ignored",
+					Evidence: []trustmodeltest.Evidence{tchEvidence("vehicle_6", 1, 1)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("vehicle_5", 0, 0, 1, 0.5),
+						trustmodeltest.ExpectAbsent("vehicle_6"),
+					},
+				},
+				{
+					Name:     "successful attestation",
+					Evidence: []trustmodeltest.Evidence{tchEvidence("vehicle_5", 1, 1)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("vehicle_5", 0.96, 0, 0.04, 0.5),
+						trustmodeltest.ExpectDecision("vehicle_5", core.TRUSTWORTHY),
+					},
+				},
+				{
+					//A failed application isolation adds belief nonetheless by default
+					Name:     "failed application isolation",
+					Evidence: []trustmodeltest.Evidence{tchEvidence("vehicle_5", 1, 0)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("vehicle_5", 0.96, 0, 0.04, 0.5),
+						trustmodeltest.ExpectDecision("vehicle_5", core.TRUSTWORTHY),
+					},
+				},
+				{
+					Name:     "failed secure boot",
+					Evidence: []trustmodeltest.Evidence{tchEvidence("vehicle_5", 0, 1)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("vehicle_5", 0, 1, 0, 0.5),
+						trustmodeltest.ExpectDecision("vehicle_5", core.NOT_TRUSTWORTHY),
+					},
+				},
+			},
+		},
+		trustmodeltest.Scenario{
+			Name:    "non-critical secure boot",
+			Params:  map[string]string{"TCH_OUTPUT_SECURE_BOOT": "1"},
+			Trigger: core.TRIGGER_TCH_TRUSTEE,
+			Entity:  "5",
+			Steps: []trustmodeltest.Step{
+				{
+					Name:     "failed secure boot",
+					Evidence: []trustmodeltest.Evidence{tchEvidence("vehicle_5", 0, 1)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("vehicle_5", 0.72, 0.24, 0.04, 0.5),
+						trustmodeltest.ExpectProbability("vehicle_5", 0.74),
+						trustmodeltest.ExpectDecision("vehicle_5", core.NOT_TRUSTWORTHY),
+					},
+				},
+			},
+		},
+		trustmodeltest.Scenario{
+			Name:    "components of the trustee",
+			Trigger: core.TRIGGER_TCH_TRUSTEE,
+			Entity:  "5",
+			Steps: []trustmodeltest.Step{
+				{
+					Name:    "added component is fully uncertain",
+					Updates: []core.Update{trustmodelupdate.CreateAddTrustObject(core.MergeComponentIdentifier("vehicle_5", "ecu"))},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("vehicle_5~ecu", 0, 0, 1, 0.5),
+						trustmodeltest.ExpectATL("vehicle_5", 0, 0, 1, 0.5),
+					},
+				},
+				{
+					Name:    "components of other trustees are ignored",
+					Updates: []core.Update{trustmodelupdate.CreateAddTrustObject(core.MergeComponentIdentifier("vehicle_6", "ecu"))},
+					Expect:  []trustmodeltest.Expectation{trustmodeltest.ExpectAbsent("vehicle_6~ecu")},
+				},
+				{
+					Name:    "removed component",
+					Updates: []core.Update{trustmodelupdate.CreateRemoveTrustObject(core.MergeComponentIdentifier("vehicle_5", "ecu"))},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectAbsent("vehicle_5~ecu"),
+						trustmodeltest.ExpectATL("vehicle_5", 0, 0, 1, 0.5),
+					},
+				},
+			},
+		},
+	)
+}
@@ -3,11 +3,14 @@ package trustmodel_to_v0_0_1
 import (
 	"fmt"
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelsnapshot"
 	internaltrustmodelstructure "github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelstructure"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
 	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
 	"hash/fnv"
+	"slices"
+	"strings"
 )
 
 type TrustModelInstance struct {
@@ -20,6 +23,8 @@ type TrustModelInstance struct {
 
 	currentFingerprint uint32
 	targetTrustee      string
+	//identifier of a component of the target trustee -> opinion MEC -> component
+	components map[string]subjectivelogic.QueryableOpinion
 }
 
 func (tmi *TrustModelInstance) ID() string {
@@ -48,17 +53,35 @@ func (tmi *TrustModelInstance) Template() core.TrustModelTemplate {
 
 func (tmi *TrustModelInstance) Structure() trustmodelstructure.TrustGraphStructure {
 	return internaltrustmodelstructure.NewTrustGraphDTO(trustmodelstructure.CumulativeFusion, trustmodelstructure.OppositeBeliefDiscount, []trustmodelstructure.AdjacencyListEntry{
-		internaltrustmodelstructure.NewAdjacencyEntryDTO("MEC", []string{trusteeIdentifier(tmi.targetTrustee)}),
+		internaltrustmodelstructure.NewAdjacencyEntryDTO("MEC", append([]string{trusteeIdentifier(tmi.targetTrustee)}, tmi.sortedComponents()...)),
 	})
 }
 
+/*
+sortedComponents returns the identifiers of the components of the target trustee in ascending order.
+*/
+func (tmi *TrustModelInstance) sortedComponents() []string {
+	components := make([]string, 0, len(tmi.components))
+	for component := range tmi.components {
+		components = append(components, component)
+	}
+	slices.Sort(components)
+	return components
+}
+
 func (tmi *TrustModelInstance) Values() map[string][]trustmodelstructure.TrustRelationship {
 	trusteeOpinion, _ := subjectivelogic.NewOpinion(tmi.omega.Belief(), tmi.omega.Disbelief(), tmi.omega.Uncertainty(), tmi.omega.BaseRate())
-	return map[string][]trustmodelstructure.TrustRelationship{
+	values := map[string][]trustmodelstructure.TrustRelationship{
 		trusteeIdentifier(tmi.targetTrustee): {
 			internaltrustmodelstructure.NewTrustRelationshipDTO("MEC", trusteeIdentifier(tmi.targetTrustee), &trusteeOpinion),
 		},
 	}
+	for component, opinion := range tmi.components {
+		values[component] = []trustmodelstructure.TrustRelationship{
+			internaltrustmodelstructure.NewTrustRelationshipDTO("MEC", component, opinion),
+		}
+	}
+	return values
 }
 
 func (tmi *TrustModelInstance) Initialize(params map[string]interface{}) {
@@ -72,6 +95,7 @@ func (tmi *TrustModelInstance) Initialize(params map[string]interface{}) {
 
 	tmi.version = 0
 	tmi.currentFingerprint = 0
+	tmi.components = make(map[string]subjectivelogic.QueryableOpinion)
 
 	tmi.updateFingerprint()
 	return
@@ -80,7 +104,7 @@ func (tmi *TrustModelInstance) Initialize(params map[string]interface{}) {
 func (tmi *TrustModelInstance) updateFingerprint() {
 
 	algorithm := fnv.New32a()
-	_, err := algorithm.Write([]byte(tmi.targetTrustee))
+	_, err := algorithm.Write([]byte(tmi.targetTrustee + strings.Join(tmi.sortedComponents(), "")))
 	if err == nil {
 		tmi.currentFingerprint = algorithm.Sum32()
 	}
@@ -102,6 +126,21 @@ func (tmi *TrustModelInstance) Update(update core.Update) bool {
 		if update.Trustee() == trusteeIdentifier(tmi.targetTrustee) {
 			tmi.omega.Modify(update.Opinion().Belief(), update.Opinion().Disbelief(), update.Opinion().Uncertainty(), update.Opinion().BaseRate())
 			tmi.version++
+		} else if _, exists := tmi.components[update.Trustee()]; exists {
+			tmi.components[update.Trustee()] = update.Opinion()
+			tmi.version++
+		}
+	case trustmodelupdate.AddTrustObject:
+		if tmi.isComponent(update.TrustObject()) && !tmi.hasComponent(update.TrustObject()) {
+			tmi.components[update.TrustObject()] = &FullUncertainty
+			tmi.updateFingerprint()
+			tmi.version++
+		}
+	case trustmodelupdate.RemoveTrustObject:
+		if tmi.hasComponent(update.TrustObject()) && update.Removes(trustmodelupdate.TrustRelationship{Trustor: "MEC", Trustee: update.TrustObject()}) {
+			delete(tmi.components, update.TrustObject())
+			tmi.updateFingerprint()
+			tmi.version++
 		}
 	default:
 		//ignore
@@ -111,7 +150,64 @@ func (tmi *TrustModelInstance) Update(update core.Update) bool {
 }
 
 func (tmi *TrustModelInstance) RTLs() map[string]subjectivelogic.QueryableOpinion {
-	return map[string]subjectivelogic.QueryableOpinion{
+	rtls := map[string]subjectivelogic.QueryableOpinion{
 		trusteeIdentifier(tmi.targetTrustee): &DefaultRTL,
 	}
+	for component := range tmi.components {
+		rtls[component] = &DefaultRTL
+	}
+	return rtls
+}
+
+/*
+isComponent checks whether a trust object is a component of the target trustee (see core.MergeComponentIdentifier).
+Components are trusted by MEC like the target trustee.
+*/
+func (tmi *TrustModelInstance) isComponent(trustObject string) bool {
+	trustee, _, ok := core.SplitComponentIdentifier(trustObject)
+	return ok && trustee == trusteeIdentifier(tmi.targetTrustee)
+}
+
+func (tmi *TrustModelInstance) hasComponent(trustObject string) bool {
+	_, exists := tmi.components[trustObject]
+	return exists
+}
+
+/*
+snapshotState is the state of a TMI kept in snapshots.
+*/
+type snapshotState struct {
+	TargetTrustee string                                 `json:"targetTrustee"`
+	Omega         *trustmodelsnapshot.Opinion            `json:"omega"`
+	Components    map[string]*trustmodelsnapshot.Opinion `json:"components,omitempty"`
+}
+
+func (tmi *TrustModelInstance) Snapshot() ([]byte, error) {
+	return trustmodelsnapshot.Marshal(tmi, snapshotState{
+		TargetTrustee: tmi.targetTrustee,
+		Omega:         trustmodelsnapshot.FromOpinion(&tmi.omega),
+		Components:    trustmodelsnapshot.FromOpinions(tmi.components),
+	})
+}
+
+func (tmi *TrustModelInstance) Restore(snapshot []byte) error {
+	var state snapshotState
+	version, err := trustmodelsnapshot.Unmarshal(tmi, snapshot, &state)
+	if err != nil {
+		return err
+	}
+	omega, err := state.Omega.Binomial()
+	if err != nil {
+		return err
+	}
+	components, err := trustmodelsnapshot.Opinions(state.Components)
+	if err != nil {
+		return err
+	}
+	tmi.targetTrustee = state.TargetTrustee
+	tmi.omega = omega
+	tmi.components = components
+	tmi.version = version
+	tmi.updateFingerprint()
+	return nil
 }
@@ -1,12 +1,10 @@
 package trustmodel_to_v0_0_1
 
 import (
-	"errors"
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
-	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
-	"log"
+	"github.com/horizon-connect-eu/go-taf/pkg/parameter"
+	"github.com/horizon-connect-eu/go-taf/pkg/quantifier"
 	"strconv"
-	"strings"
 )
 
 var defaultTCHExistenceWeights = map[core.EvidenceType]float64{
@@ -27,126 +25,68 @@ var defaultTCHOutputWeights = map[core.EvidenceType]float64{
 	core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION: 2,
 }
 
-func createTrustSourceQuantifiers(params map[string]string) ([]core.TrustSourceQuantifier, error) {
-
-	tchExistenceWeights := make(map[core.EvidenceType]float64)
-
-	for key, defaultValue := range defaultTCHExistenceWeights {
-		tchExistenceWeights[key] = defaultValue
+var tchEvidenceTypes = []core.EvidenceType{core.TCH_SECURE_BOOT, core.TCH_SECURE_OTA, core.TCH_ACCESS_CONTROL, core.TCH_APPLICATION_ISOLATION, core.TCH_CONTROL_FLOW_INTEGRITY, core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION}
+
+/*
+parameterSchema declares the existence weights (TCH_EXISTENCE_<control>) and output weights (TCH_OUTPUT_<control>) of the
+TCH quantifier, with the default weights above as defaults.
+*/
+var parameterSchema = createParameterSchema()
+
+func createParameterSchema() parameter.Schema {
+	schema := parameter.Schema{}
+	for _, evidenceType := range tchEvidenceTypes {
+		existenceWeight := strconv.FormatFloat(defaultTCHExistenceWeights[evidenceType], 'f', -1, 64)
+		minimum := 0.0
+		schema.Parameters = append(schema.Parameters, parameter.Definition{
+			Name:        "TCH_EXISTENCE_" + evidenceType.String(),
+			Type:        parameter.NUMBER,
+			Description: "Existence weight of " + evidenceType.String() + " (weights summing up to more than 1 are normalized)",
+			Default:     &existenceWeight,
+			Minimum:     &minimum,
+		})
 	}
-
-	tchOutputWeights := make(map[core.EvidenceType]float64)
-
-	for key, defaultValue := range defaultTCHOutputWeights {
-		tchOutputWeights[key] = defaultValue
+	for _, evidenceType := range tchEvidenceTypes {
+		outputWeight := strconv.FormatFloat(defaultTCHOutputWeights[evidenceType], 'f', -1, 64)
+		minimum, maximum := parameter.Range(0, 2)
+		schema.Parameters = append(schema.Parameters, parameter.Definition{
+			Name:        "TCH_OUTPUT_" + evidenceType.String(),
+			Type:        parameter.INTEGER,
+			Description: "Output weight of " + evidenceType.String() + " (0: belief, 1: disbelief, 2: complete disbelief on failure)",
+			Default:     &outputWeight,
+			Minimum:     minimum,
+			Maximum:     maximum,
+		})
 	}
+	return schema
+}
 
-	if params != nil {
-		//TODO: update  with params
+func createTrustSourceQuantifiers(values parameter.Values) ([]core.TrustSourceQuantifier, error) {
 
-		for key, value := range params {
-			if strings.Contains(key, "TCH_EXISTENCE") {
-				control := strings.SplitAfterN(key, "_", 3)[2]
-				if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
-					controlType := core.EvidenceTypeBySourceAndName(core.TCH, control)
-					if controlType == core.UNKNOWN {
-						return nil, errors.New("Key" + key + "is not valid")
-					} else {
-						tchExistenceWeights[controlType] = floatValue
-					}
-				} else {
-					return nil, errors.New("Key" + key + "is not a float value")
-				}
-			} else if strings.Contains(key, "TCH_OUTPUT") {
-				control := strings.SplitAfterN(key, "_", 3)[2]
-				if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
-					controlType := core.EvidenceTypeBySourceAndName(core.TCH, control)
-					if controlType == core.UNKNOWN {
-						return nil, errors.New("Key" + key + "is not valid")
-					} else {
-						tchOutputWeights[controlType] = floatValue
-					}
-				} else {
-					return nil, errors.New("Key" + key + "is not a float value")
-				}
-			}
-		}
-	}
+	tchExistenceWeights := make(map[core.EvidenceType]float64)
+	tchOutputWeights := make(map[core.EvidenceType]quantifier.OutputWeight)
 
-	// normalization of TCH_EXISTENCE weights in case the sum is more than 1.0
-	sum := 0.0
-	for _, value := range tchExistenceWeights {
-		sum = sum + value
+	for _, evidenceType := range tchEvidenceTypes {
+		tchExistenceWeights[evidenceType] = values.Float("TCH_EXISTENCE_" + evidenceType.String())
+		tchOutputWeights[evidenceType] = quantifier.OutputWeight(values.Int("TCH_OUTPUT_" + evidenceType.String()))
 	}
 
-	if sum > 1.0 {
-		for key, value := range tchExistenceWeights {
-			tchExistenceWeights[key] = value / sum
-		}
+	tchQuantifier, err := quantifier.NewSecurityControls(quantifier.SecurityControls{
+		ExistenceWeights: tchExistenceWeights,
+		OutputWeights:    tchOutputWeights,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	tchQuantifier := core.TrustSourceQuantifier{
-		Trustor:     "MEC",
-		Trustee:     "vehicle_*",
-		Scope:       "vehicle_*",
-		TrustSource: core.TCH,
-		Evidence:    []core.EvidenceType{core.TCH_SECURE_BOOT, core.TCH_SECURE_OTA, core.TCH_ACCESS_CONTROL, core.TCH_APPLICATION_ISOLATION, core.TCH_CONTROL_FLOW_INTEGRITY, core.TCH_CONFIGURATION_INTEGRITY_VERIFICATION},
-		Quantifier: func(m map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
-
-			var sum = 0.0
-			for _, val := range tchExistenceWeights {
-				sum += val
-			}
-
-			if sum > 1.0 { // sum of existence weights is not allowed to exceed 1.0
-				log.Fatalf("Sum existence weights of the TCH trust source exceeds 1.0")
-			}
-
-			belief := 0.0
-			disbelief := 0.0
-			//uncertainty := 1.0
-
-			for control, rawAppraisal := range m {
-				appraisal := rawAppraisal.(int)
-				delta, ok := tchExistenceWeights[control]
-
-				if ok { // Only if control is one of the foreseen controls, belief and disbelief will be adjusted
-					if appraisal == -1 { // control not implemented
-						disbelief = disbelief + delta
-						//uncertainty = uncertainty - delta
-					} else if appraisal == 0 {
-						if tchOutputWeights[control] == 0 { // still add belief
-							belief = belief + delta
-							//uncertainty = uncertainty - delta
-						} else if tchOutputWeights[control] == 1 { // add disbelief
-							disbelief = disbelief + delta
-							//uncertainty = uncertainty - delta
-						} else if tchOutputWeights[control] == 2 { // complete disbelief
-							belief = 0.0
-							disbelief = 1.0
-							//uncertainty = 0.0
-							break // complete disbelief because negative evidence of critical securityControl
-						} else {
-							// Invalid weight
-							// TODO: Error handling
-						}
-					} else if appraisal == 1 {
-						belief = belief + delta
-						//uncertainty = uncertainty - delta
-					} else {
-						// No evidence for the control, e.g. appraisal -2 or no evidence received -> Results in higher uncertainty
-					}
-				}
-			}
-			sl, _ := subjectivelogic.NewOpinion(belief, disbelief, 1-belief-disbelief, 0.5)
-
-			return &sl
+	return []core.TrustSourceQuantifier{
+		{
+			Trustor:     "MEC",
+			Trustee:     "vehicle_*",
+			Scope:       "vehicle_*",
+			TrustSource: core.TCH,
+			Evidence:    tchEvidenceTypes,
+			Quantifier:  tchQuantifier,
 		},
-	}
-
-	return []core.TrustSourceQuantifier{tchQuantifier}, nil
-}
-
-func init() {
-	//TODO: Validate weights here instead of inside quantifier function
+	}, nil
 }
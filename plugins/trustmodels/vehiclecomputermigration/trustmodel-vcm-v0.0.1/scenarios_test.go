@@ -0,0 +1,95 @@
+package trustmodel_vcm_v0_0_1
+
+import (
+	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodeltest"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
+	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"testing"
+)
+
+func aivEvidence(trustee string, secureBoot int, applicationIsolation int) trustmodeltest.Evidence {
+	return trustmodeltest.Evidence{
+		TrustSource: core.AIV,
+		Trustee:     trustee,
+		Values: map[core.EvidenceType]interface{}{
+			core.AIV_SECURE_BOOT:                          secureBoot,
+			core.AIV_SECURE_OTA:                           1,
+			core.AIV_ACCESS_CONTROL:                       1,
+			core.AIV_APPLICATION_ISOLATION:                applicationIsolation,
+			core.AIV_CONTROL_FLOW_INTEGRITY:               1,
+			core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION: 1,
+		},
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	opinion, _ := subjectivelogic.NewOpinion(0.76, 0.1, 0.14, 0.5)
+	trustmodeltest.Run(t, CreateTrustModelTemplate("VCM", "0.0.1", "Testing"),
+		trustmodeltest.Scenario{
+			Name: "attested vehicle computers",
+			Initial: []trustmodeltest.Expectation{
+				trustmodeltest.ExpectATL("VC1", 0, 0, 1, 0.5),
+				trustmodeltest.ExpectDecision("VC1", core.UNDECIDABLE),
+			},
+			Steps: []trustmodeltest.Step{
+				{
+					Name:     "all security controls of VC1 pass",
+					Evidence: []trustmodeltest.Evidence{aivEvidence("VC1", 1, 1)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("VC1", 0.76, 0.1, 0.14, 0.5),
+						trustmodeltest.ExpectProbability("VC1", 0.83),
+						trustmodeltest.ExpectDecision("VC1", core.TRUSTWORTHY),
+						trustmodeltest.ExpectATL("VC2", 0, 0, 1, 0.5),
+					},
+				},
+				{
+					Name:     "application isolation of VC2 fails",
+					Evidence: []trustmodeltest.Evidence{aivEvidence("VC2", 1, 0)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("VC2", 0.69, 0.17, 0.14, 0.5),
+						trustmodeltest.ExpectProbability("VC2", 0.76),
+						trustmodeltest.ExpectDecision("VC2", core.TRUSTWORTHY),
+					},
+				},
+				{
+					Name:     "secure boot of VC1 fails",
+					Evidence: []trustmodeltest.Evidence{aivEvidence("VC1", 0, 1)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("VC1", 0, 1, 0, 0.5),
+						trustmodeltest.ExpectDecision("VC1", core.NOT_TRUSTWORTHY),
+					},
+				},
+			},
+		},
+		trustmodeltest.Scenario{
+			Name: "vehicle computers added and removed",
+			Steps: []trustmodeltest.Step{
+				{
+					Name:    "added vehicle computer is fully uncertain",
+					Updates: []core.Update{trustmodelupdate.CreateAddTrustObject("VC3")},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("VC3", 0, 0, 1, 0.5),
+						trustmodeltest.ExpectDecision("VC3", core.UNDECIDABLE),
+					},
+				},
+				{
+					Name:    "atomic trust opinion of VC3",
+					Updates: []core.Update{trustmodelupdate.CreateAtomicTrustOpinionUpdate(&opinion, "TAF", "VC3", core.AIV)},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectATL("VC3", 0.76, 0.1, 0.14, 0.5),
+						trustmodeltest.ExpectDecision("VC3", core.TRUSTWORTHY),
+					},
+				},
+				{
+					Name:    "removed vehicle computer",
+					Updates: []core.Update{trustmodelupdate.CreateRemoveTrustObject("VC2")},
+					Expect: []trustmodeltest.Expectation{
+						trustmodeltest.ExpectAbsent("VC2"),
+						trustmodeltest.ExpectATL("VC1", 0, 0, 1, 0.5),
+					},
+				},
+			},
+		},
+	)
+}
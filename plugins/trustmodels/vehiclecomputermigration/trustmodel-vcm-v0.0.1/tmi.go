@@ -2,10 +2,14 @@ package trustmodel_vcm_v0_0_1
 
 import (
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelsnapshot"
 	internaltrustmodelstructure "github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelstructure"
 	"github.com/horizon-connect-eu/go-taf/pkg/trustmodel/trustmodelupdate"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
 	"github.com/vs-uulm/taf-tlee-interface/pkg/trustmodelstructure"
+	"hash/fnv"
+	"slices"
+	"strings"
 )
 
 type TrustModelInstance struct {
@@ -14,8 +18,9 @@ type TrustModelInstance struct {
 
 	template TrustModelTemplate
 
-	omega1      subjectivelogic.Opinion
-	omega2      subjectivelogic.Opinion
+	//vehicle computer -> opinion TAF -> vehicle computer
+	opinions    map[string]subjectivelogic.QueryableOpinion
+	rtls        map[string]subjectivelogic.QueryableOpinion
 	fingerprint uint32
 }
 
@@ -33,20 +38,38 @@ func (e *TrustModelInstance) Fingerprint() uint32 {
 
 func (e *TrustModelInstance) Structure() trustmodelstructure.TrustGraphStructure {
 	return internaltrustmodelstructure.NewTrustGraphDTO(trustmodelstructure.CumulativeFusion, trustmodelstructure.OppositeBeliefDiscount, []trustmodelstructure.AdjacencyListEntry{
-		internaltrustmodelstructure.NewAdjacencyEntryDTO("TAF", []string{"VC1", "VC2"}),
+		internaltrustmodelstructure.NewAdjacencyEntryDTO("TAF", e.vehicleComputers()),
 	})
 }
 
 func (e *TrustModelInstance) Values() map[string][]trustmodelstructure.TrustRelationship {
-	opinionVC1, _ := subjectivelogic.NewOpinion(e.omega1.Belief(), e.omega1.Disbelief(), e.omega1.Uncertainty(), e.omega1.BaseRate())
-	opinionVC2, _ := subjectivelogic.NewOpinion(e.omega2.Belief(), e.omega2.Disbelief(), e.omega2.Uncertainty(), e.omega2.BaseRate())
-	return map[string][]trustmodelstructure.TrustRelationship{
-		"VC1": {
-			internaltrustmodelstructure.NewTrustRelationshipDTO("TAF", "VC1", &opinionVC1),
-		},
-		"VC2": {
-			internaltrustmodelstructure.NewTrustRelationshipDTO("TAF", "VC2", &opinionVC2),
-		},
+	values := make(map[string][]trustmodelstructure.TrustRelationship, len(e.opinions))
+	for vc, opinion := range e.opinions {
+		copied, _ := subjectivelogic.NewOpinion(opinion.Belief(), opinion.Disbelief(), opinion.Uncertainty(), opinion.BaseRate())
+		values[vc] = []trustmodelstructure.TrustRelationship{
+			internaltrustmodelstructure.NewTrustRelationshipDTO("TAF", vc, &copied),
+		}
+	}
+	return values
+}
+
+/*
+vehicleComputers returns the identifiers of all vehicle computers in ascending order.
+*/
+func (e *TrustModelInstance) vehicleComputers() []string {
+	vcs := make([]string, 0, len(e.opinions))
+	for vc := range e.opinions {
+		vcs = append(vcs, vc)
+	}
+	slices.Sort(vcs)
+	return vcs
+}
+
+func (e *TrustModelInstance) updateFingerprint() {
+	algorithm := fnv.New32a()
+	_, err := algorithm.Write([]byte(strings.Join(e.vehicleComputers(), "")))
+	if err == nil {
+		e.fingerprint = algorithm.Sum32()
 	}
 }
 
@@ -57,11 +80,27 @@ func (e *TrustModelInstance) Template() core.TrustModelTemplate {
 func (e *TrustModelInstance) Update(update core.Update) bool {
 	switch update := update.(type) {
 	case trustmodelupdate.UpdateAtomicTrustOpinion:
-		if update.Trustee() == "VC1" {
-			e.omega1.Modify(update.Opinion().Belief(), update.Opinion().Disbelief(), update.Opinion().Uncertainty(), update.Opinion().BaseRate())
+		if _, exists := e.opinions[update.Trustee()]; exists {
+			e.opinions[update.Trustee()] = update.Opinion()
 			e.version++
-		} else if update.Trustee() == "VC2" {
-			e.omega2.Modify(update.Opinion().Belief(), update.Opinion().Disbelief(), update.Opinion().Uncertainty(), update.Opinion().BaseRate())
+		}
+	case trustmodelupdate.AddTrustObject:
+		//vehicle computers are only trusted by TAF
+		relationship := trustmodelupdate.TrustRelationship{Trustor: "TAF", Trustee: update.TrustObject()}
+		if _, exists := e.opinions[update.TrustObject()]; !exists && (len(update.Relationships()) == 0 || slices.Contains(update.Relationships(), relationship)) {
+			e.opinions[update.TrustObject()] = &FullUncertainty
+			e.rtls[update.TrustObject()] = update.RTL()
+			if update.RTL() == nil {
+				e.rtls[update.TrustObject()] = &e.template.rTL1
+			}
+			e.updateFingerprint()
+			e.version++
+		}
+	case trustmodelupdate.RemoveTrustObject:
+		if _, exists := e.opinions[update.TrustObject()]; exists && update.Removes(trustmodelupdate.TrustRelationship{Trustor: "TAF", Trustee: update.TrustObject()}) {
+			delete(e.opinions, update.TrustObject())
+			delete(e.rtls, update.TrustObject())
+			e.updateFingerprint()
 			e.version++
 		}
 	default:
@@ -71,10 +110,7 @@ func (e *TrustModelInstance) Update(update core.Update) bool {
 }
 
 func (e *TrustModelInstance) RTLs() map[string]subjectivelogic.QueryableOpinion {
-	return map[string]subjectivelogic.QueryableOpinion{
-		"VC1": &e.template.rTL1,
-		"VC2": &e.template.rTL2,
-	}
+	return e.rtls
 }
 
 func (e *TrustModelInstance) Initialize(params map[string]interface{}) {
@@ -88,3 +124,46 @@ func (e *TrustModelInstance) Cleanup() {
 func (e *TrustModelInstance) String() string {
 	return core.TMIAsString(e)
 }
+
+/*
+snapshotState is the state of a TMI kept in snapshots. Snapshots taken before vehicle computers could be added or removed
+only contain the opinions on VC1 and VC2.
+*/
+type snapshotState struct {
+	Opinions map[string]*trustmodelsnapshot.Opinion `json:"opinions,omitempty"`
+	RTLs     map[string]*trustmodelsnapshot.Opinion `json:"rtls,omitempty"`
+	Omega1   *trustmodelsnapshot.Opinion            `json:"omega1,omitempty"`
+	Omega2   *trustmodelsnapshot.Opinion            `json:"omega2,omitempty"`
+}
+
+func (e *TrustModelInstance) Snapshot() ([]byte, error) {
+	return trustmodelsnapshot.Marshal(e, snapshotState{
+		Opinions: trustmodelsnapshot.FromOpinions(e.opinions),
+		RTLs:     trustmodelsnapshot.FromOpinions(e.rtls),
+	})
+}
+
+func (e *TrustModelInstance) Restore(snapshot []byte) error {
+	var state snapshotState
+	version, err := trustmodelsnapshot.Unmarshal(e, snapshot, &state)
+	if err != nil {
+		return err
+	}
+	if state.Opinions == nil {
+		state.Opinions = map[string]*trustmodelsnapshot.Opinion{"VC1": state.Omega1, "VC2": state.Omega2}
+		state.RTLs = trustmodelsnapshot.FromOpinions(map[string]subjectivelogic.QueryableOpinion{"VC1": &e.template.rTL1, "VC2": &e.template.rTL2})
+	}
+	opinions, err := trustmodelsnapshot.Opinions(state.Opinions)
+	if err != nil {
+		return err
+	}
+	rtls, err := trustmodelsnapshot.Opinions(state.RTLs)
+	if err != nil {
+		return err
+	}
+	e.opinions = opinions
+	e.rtls = rtls
+	e.version = version
+	e.updateFingerprint()
+	return nil
+}
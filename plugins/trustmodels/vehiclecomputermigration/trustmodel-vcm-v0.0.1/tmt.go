@@ -1,13 +1,12 @@
 package trustmodel_vcm_v0_0_1
 
 import (
-	"errors"
 	"fmt"
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/parameter"
+	"github.com/horizon-connect-eu/go-taf/pkg/quantifier"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
 	"math/rand/v2"
-	"strconv"
-	"strings"
 )
 
 var trustSources []core.EvidenceType
@@ -36,6 +35,7 @@ type TrustModelTemplate struct {
 	description            string
 	rTL1                   subjectivelogic.Opinion
 	rTL2                   subjectivelogic.Opinion
+	parameterSchema        parameter.Schema
 }
 
 func CreateTrustModelTemplate(name string, version string, description string) core.TrustModelTemplate {
@@ -48,6 +48,7 @@ func CreateTrustModelTemplate(name string, version string, description string) c
 		description:            description,
 		rTL1:                   rtl1,
 		rTL2:                   rtl2,
+		parameterSchema:        createParameterSchema(trustSourceQuantifiers),
 	}
 }
 
@@ -67,202 +68,110 @@ func (tmt TrustModelTemplate) Description() string {
 	return tmt.description
 }
 
-func getExistenceWeightsFromInit(params map[string]string, key string) (float64, error) {
-	if strValue, found := params[key]; found {
-		if floatValue, err := strconv.ParseFloat(strValue, 64); err == nil {
-			return floatValue, nil
-		} else {
-			return -1.0, errors.New("Key" + key + "is not a float value")
+/*
+createParameterSchema declares the parameters of the template for both vehicle computers: existence weights and output
+weights per evidence type, the design-time trust opinion (DTI) and the RTL. Weights of a vehicle computer are either
+set completely or not at all.
+*/
+func createParameterSchema(quantifiers []core.TrustSourceQuantifier) parameter.Schema {
+	schema := parameter.Schema{}
+	for _, tsq := range quantifiers {
+		existenceWeights := make([]string, 0, len(tsq.Evidence))
+		outputWeights := make([]string, 0, len(tsq.Evidence))
+		for _, evidenceType := range tsq.Evidence {
+			minimum, maximum := parameter.Range(0, 1)
+			schema.Parameters = append(schema.Parameters, parameter.Definition{
+				Name:        tsq.Trustee + "_EXISTENCE_" + evidenceType.String(),
+				Type:        parameter.NUMBER,
+				Description: "Existence weight of " + evidenceType.String() + " for " + tsq.Trustee,
+				Minimum:     minimum,
+				Maximum:     maximum,
+			})
+			existenceWeights = append(existenceWeights, tsq.Trustee+"_EXISTENCE_"+evidenceType.String())
 		}
-	} else {
-		return -1.0, errors.New("Key" + key + "not provided")
-	}
-}
-
-func getOutputWeightsFromInit(params map[string]string, key string) (int, error) {
-	if strValue, found := params[key]; found {
-		if intValue, err := strconv.Atoi(strValue); err == nil {
-			return intValue, nil
-		} else {
-			return -1, errors.New("Key" + key + "is not an int value")
+		for _, evidenceType := range tsq.Evidence {
+			minimum, maximum := parameter.Range(0, 2)
+			schema.Parameters = append(schema.Parameters, parameter.Definition{
+				Name:        tsq.Trustee + "_OUTPUT_" + evidenceType.String(),
+				Type:        parameter.INTEGER,
+				Description: "Output weight of " + evidenceType.String() + " for " + tsq.Trustee + " (0: belief, 1: disbelief, 2: complete disbelief on failure)",
+				Minimum:     minimum,
+				Maximum:     maximum,
+			})
+			outputWeights = append(outputWeights, tsq.Trustee+"_OUTPUT_"+evidenceType.String())
 		}
-	} else {
-		return -1, errors.New("Key" + key + "not provided")
-	}
-}
-
-func getOpinionFromInit(params map[string]string, opinionKey string) (subjectivelogic.Opinion, error) {
-	belief, err := getExistenceWeightsFromInit(params, opinionKey+"_BELIEF")
-	if err != nil {
-		return subjectivelogic.Opinion{}, err
-	}
-
-	disbelief, err := getExistenceWeightsFromInit(params, opinionKey+"_DISBELIEF")
-	if err != nil {
-		return subjectivelogic.Opinion{}, err
-	}
-
-	uncertainty, err := getExistenceWeightsFromInit(params, opinionKey+"_UNCERTAINTY")
-	if err != nil {
-		return subjectivelogic.Opinion{}, err
-	}
-
-	baserate, err := getExistenceWeightsFromInit(params, opinionKey+"_BASERATE")
-	if err != nil {
-		return subjectivelogic.Opinion{}, err
-	}
-
-	opinion, err := subjectivelogic.NewOpinion(belief, disbelief, uncertainty, baserate)
-
-	return opinion, err
-}
-
-func checkSetParameters(params map[string]string) map[string]bool {
-	setParams := make(map[string]bool)
-
-	for k := range params {
-		if strings.HasPrefix(k, "VC1_EXISTENCE") {
-			setParams["VC1_EXISTENCE"] = true
-		} else if strings.HasPrefix(k, "VC2_EXISTENCE") {
-			setParams["VC2_EXISTENCE"] = true
-		} else if strings.HasPrefix(k, "VC1_OUTPUT") {
-			setParams["VC1_OUTPUT"] = true
-		} else if strings.HasPrefix(k, "VC2_OUTPUT") {
-			setParams["VC2_OUTPUT"] = true
-		} else if strings.HasPrefix(k, "VC1_DTI") {
-			setParams["VC1_DTI"] = true
-		} else if strings.HasPrefix(k, "VC2_DTI") {
-			setParams["VC2_DTI"] = true
-		} else if strings.HasPrefix(k, "VC1_RTL") {
-			setParams["VC1_RTL"] = true
-		} else if strings.HasPrefix(k, "VC2_RTL") {
-			setParams["VC2_RTL"] = true
+		schema.Constraints = append(schema.Constraints,
+			parameter.Constraint{Type: parameter.ALL_OR_NONE, Parameters: existenceWeights},
+			parameter.Constraint{Type: parameter.SUM_AT_MOST, Parameters: existenceWeights, Limit: 1, Description: "existence weights of " + tsq.Trustee + " must not sum up to more than 1"},
+			parameter.Constraint{Type: parameter.ALL_OR_NONE, Parameters: outputWeights},
+		)
+		for _, opinion := range []struct {
+			prefix      string
+			description string
+		}{
+			{tsq.Trustee + "_DTI", "Design-time trust opinion on " + tsq.Trustee},
+			{tsq.Trustee + "_RTL", "RTL of " + tsq.Trustee},
+		} {
+			definitions, constraints := parameter.Opinion(opinion.prefix, opinion.description)
+			schema.Parameters = append(schema.Parameters, definitions...)
+			schema.Constraints = append(schema.Constraints, constraints...)
 		}
 	}
+	return schema
+}
 
-	return setParams
+func (tmt TrustModelTemplate) ParameterSchema() parameter.Schema {
+	return tmt.parameterSchema
 }
 
 func (tmt TrustModelTemplate) Spawn(params map[string]string, context core.TafContext) ([]core.TrustSourceQuantifier, core.TrustModelInstance, core.DynamicTrustModelInstanceSpawner, error) {
-	setParams := checkSetParameters(params)
-
-	omega1, _ := subjectivelogic.NewOpinion(0.0, 0.0, 1.0, 0.5)
-	omega2, _ := subjectivelogic.NewOpinion(0.0, 0.0, 1.0, 0.5)
-
-	if len(params) > 0 {
-		// get existence parameters for VC1
-		sum := 0.0
-
-		if _, found := setParams["VC1_EXISTENCE"]; found {
-			for _, typeEvidence := range tmt.trustSourceQuantifiers[0].Evidence {
-				value, err := getExistenceWeightsFromInit(params, "VC1_EXISTENCE_"+typeEvidence.String())
-				if err != nil {
-					return nil, nil, nil, err
-				}
-				vc1ExistenceWeights[typeEvidence] = value
-
-				sum = sum + value
-			}
-
-			if sum > 1 {
-				return nil, nil, nil, errors.New("Values for existence weights of VC1 sum up to more than 1")
-			}
-
-		}
-
-		// get existence parameters for VC2
-		sum = 0.0
-
-		if _, found := setParams["VC2_EXISTENCE"]; found {
-			for _, typeEvidence := range tmt.trustSourceQuantifiers[1].Evidence {
-				value, err := getExistenceWeightsFromInit(params, "VC2_EXISTENCE_"+typeEvidence.String())
-				if err != nil {
-					return nil, nil, nil, err
-				}
-				vc2ExistenceWeights[typeEvidence] = value
-
-				sum = sum + value
-			}
-
-			if sum > 1 {
-				return nil, nil, nil, errors.New("Values for existence weights of VC2 sum up to more than 1")
-			}
-		}
+	values := tmt.parameterSchema.Values(params)
 
-		// get output parameters for VC1
-		if _, found := setParams["VC1_OUTPUT"]; found {
-			for _, typeEvidence := range tmt.trustSourceQuantifiers[0].Evidence {
-				value, err := getOutputWeightsFromInit(params, "VC1_OUTPUT_"+typeEvidence.String())
-				if err != nil {
-					return nil, nil, nil, err
-				}
-				vc1OutputWeights[typeEvidence] = value
-
-				if value < 0 || value > 2 {
-					return nil, nil, nil, errors.New("Invalid value for VC1_OUTPUT_" + typeEvidence.String() + "- value has to be between 0 and 2")
-				}
+	vehicleComputers := defaultVehicleComputers()
+	for i, vc := range vehicleComputers {
+		for _, evidenceType := range aivEvidenceTypes {
+			if key := vc.trustee + "_EXISTENCE_" + evidenceType.String(); values.IsSet(key) {
+				vc.existenceWeights[evidenceType] = values.Float(key)
 			}
-		}
-
-		// get output parameters for VC2
-		if _, found := setParams["VC2_OUTPUT"]; found {
-			for _, typeEvidence := range tmt.trustSourceQuantifiers[1].Evidence {
-				value, err := getOutputWeightsFromInit(params, "VC2_OUTPUT_"+typeEvidence.String())
-				if err != nil {
-					return nil, nil, nil, err
-				}
-				vc2OutputWeights[typeEvidence] = value
-
-				if value < 0 || value > 2 {
-					return nil, nil, nil, errors.New("Invalid value for VC2_OUTPUT_" + typeEvidence.String() + "- value has to be between 0 and 2")
-				}
+			if key := vc.trustee + "_OUTPUT_" + evidenceType.String(); values.IsSet(key) {
+				vc.outputWeights[evidenceType] = quantifier.OutputWeight(values.Int(key))
 			}
 		}
-
-		// get DTI for VC1
-		if _, found := setParams["VC1_DTI"]; found {
-			err := errors.New("")
-			vc1DTI, err = getOpinionFromInit(params, "VC1_DTI")
+		if values.IsSet(vc.trustee + "_DTI_BELIEF") {
+			dti, err := values.Opinion(vc.trustee + "_DTI")
 			if err != nil {
 				return nil, nil, nil, err
 			}
+			vehicleComputers[i].dti = &dti
 		}
-
-		// get DTI for VC1
-		if _, found := setParams["VC2_DTI"]; found {
-			err := errors.New("")
-			vc2DTI, err = getOpinionFromInit(params, "VC2_DTI")
-			if err != nil {
-				return nil, nil, nil, err
-			}
-		}
-
-		// get RTL for VC1
-		if _, found := setParams["VC1_RTL"]; found {
-			err := errors.New("")
-			tmt.rTL1, err = getOpinionFromInit(params, "VC1_RTL")
-			if err != nil {
-				return nil, nil, nil, err
-			}
+	}
+	tsqs, err := createTrustSourceQuantifiers(vehicleComputers)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if values.IsSet("VC1_RTL_BELIEF") {
+		if tmt.rTL1, err = values.Opinion("VC1_RTL"); err != nil {
+			return nil, nil, nil, err
 		}
-
-		// get DTI for VC1
-		if _, found := setParams["VC2_RTL"]; found {
-			err := errors.New("")
-			tmt.rTL2, err = getOpinionFromInit(params, "VC2_RTL")
-			if err != nil {
-				return nil, nil, nil, err
-			}
+	}
+	if values.IsSet("VC2_RTL_BELIEF") {
+		if tmt.rTL2, err = values.Opinion("VC2_RTL"); err != nil {
+			return nil, nil, nil, err
 		}
-
 	}
 
-	return tmt.trustSourceQuantifiers, &TrustModelInstance{
-		id:          fmt.Sprintf("%000000d", rand.IntN(999999)),
-		version:     0,
-		template:    tmt,
-		omega1:      omega1,
-		omega2:      omega2,
+	return tsqs, &TrustModelInstance{
+		id:       fmt.Sprintf("%000000d", rand.IntN(999999)),
+		version:  0,
+		template: tmt,
+		opinions: map[string]subjectivelogic.QueryableOpinion{
+			"VC1": &FullUncertainty,
+			"VC2": &FullUncertainty,
+		},
+		rtls: map[string]subjectivelogic.QueryableOpinion{
+			"VC1": &tmt.rTL1,
+			"VC2": &tmt.rTL2,
+		},
 		fingerprint: rand.Uint32N(999999999),
 	}, nil, nil
 }
@@ -1,14 +1,12 @@
 package trustmodel_vcm_v0_0_1
 
 import (
-	"fmt"
 	"github.com/horizon-connect-eu/go-taf/pkg/core"
+	"github.com/horizon-connect-eu/go-taf/pkg/quantifier"
 	"github.com/vs-uulm/go-subjectivelogic/pkg/subjectivelogic"
+	"maps"
 )
 
-var vc1DTI, _ = subjectivelogic.NewOpinion(0.2, 0.1, 0.7, 0.5)
-var vc2DTI, _ = subjectivelogic.NewOpinion(0.2, 0.1, 0.7, 0.5)
-
 var vc1ExistenceWeights = map[core.EvidenceType]float64{
 	core.AIV_SECURE_BOOT:                          0.2,
 	core.AIV_ACCESS_CONTROL:                       0.2,
@@ -18,15 +16,17 @@ var vc1ExistenceWeights = map[core.EvidenceType]float64{
 	core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION: 0.1,
 }
 
-var vc1OutputWeights = map[core.EvidenceType]int{
-	core.AIV_SECURE_BOOT:                          2,
-	core.AIV_ACCESS_CONTROL:                       0,
-	core.AIV_CONTROL_FLOW_INTEGRITY:               2,
-	core.AIV_SECURE_OTA:                           0,
-	core.AIV_APPLICATION_ISOLATION:                1,
-	core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION: 2,
+var vc1OutputWeights = map[core.EvidenceType]quantifier.OutputWeight{
+	core.AIV_SECURE_BOOT:                          quantifier.FAILURE_IS_CRITICAL,
+	core.AIV_ACCESS_CONTROL:                       quantifier.FAILURE_ADDS_BELIEF,
+	core.AIV_CONTROL_FLOW_INTEGRITY:               quantifier.FAILURE_IS_CRITICAL,
+	core.AIV_SECURE_OTA:                           quantifier.FAILURE_ADDS_BELIEF,
+	core.AIV_APPLICATION_ISOLATION:                quantifier.FAILURE_ADDS_DISBELIEF,
+	core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION: quantifier.FAILURE_IS_CRITICAL,
 }
 
+var vc1DTI, _ = subjectivelogic.NewOpinion(0.2, 0.1, 0.7, 0.5)
+
 var vc2ExistenceWeights = map[core.EvidenceType]float64{
 	core.AIV_SECURE_BOOT:                          0.2,
 	core.AIV_ACCESS_CONTROL:                       0.2,
@@ -36,79 +36,63 @@ var vc2ExistenceWeights = map[core.EvidenceType]float64{
 	core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION: 0.1,
 }
 
-var vc2OutputWeights = map[core.EvidenceType]int{
-	core.AIV_SECURE_BOOT:                          2,
-	core.AIV_ACCESS_CONTROL:                       0,
-	core.AIV_CONTROL_FLOW_INTEGRITY:               2,
-	core.AIV_SECURE_OTA:                           0,
-	core.AIV_APPLICATION_ISOLATION:                1,
-	core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION: 2,
+var vc2OutputWeights = map[core.EvidenceType]quantifier.OutputWeight{
+	core.AIV_SECURE_BOOT:                          quantifier.FAILURE_IS_CRITICAL,
+	core.AIV_ACCESS_CONTROL:                       quantifier.FAILURE_ADDS_BELIEF,
+	core.AIV_CONTROL_FLOW_INTEGRITY:               quantifier.FAILURE_IS_CRITICAL,
+	core.AIV_SECURE_OTA:                           quantifier.FAILURE_ADDS_BELIEF,
+	core.AIV_APPLICATION_ISOLATION:                quantifier.FAILURE_ADDS_DISBELIEF,
+	core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION: quantifier.FAILURE_IS_CRITICAL,
 }
 
-func quantifier(values map[core.EvidenceType]interface{}, designTimeTrustOp subjectivelogic.QueryableOpinion, existenceWeights map[core.EvidenceType]float64, outputWeights map[core.EvidenceType]int) subjectivelogic.QueryableOpinion {
-	sl, _ := subjectivelogic.NewOpinion(.0, .0, 1.0, 0.5)
+var vc2DTI, _ = subjectivelogic.NewOpinion(0.2, 0.1, 0.7, 0.5)
 
-	fmt.Printf("%+v ", existenceWeights)
+var FullUncertainty, _ = subjectivelogic.NewOpinion(0, 0, 1, 0.5)
 
-	belief := designTimeTrustOp.Belief()
-	disbelief := designTimeTrustOp.Disbelief()
-	uncertainty := designTimeTrustOp.Uncertainty()
+var aivEvidenceTypes = []core.EvidenceType{core.AIV_SECURE_BOOT, core.AIV_SECURE_OTA, core.AIV_ACCESS_CONTROL, core.AIV_APPLICATION_ISOLATION, core.AIV_CONTROL_FLOW_INTEGRITY, core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION}
 
-	for control, rawAppraisal := range values {
-		appraisal := rawAppraisal.(int)
-		delta := existenceWeights[control] * designTimeTrustOp.Uncertainty()
+/*
+vehicleComputer holds the configuration of the AIV quantifier of a vehicle computer.
+*/
+type vehicleComputer struct {
+	trustee          string
+	dti              subjectivelogic.QueryableOpinion
+	existenceWeights map[core.EvidenceType]float64
+	outputWeights    map[core.EvidenceType]quantifier.OutputWeight
+}
 
-		if appraisal == -1 { // control not implemented
-			disbelief = disbelief + delta
-			uncertainty = uncertainty - delta
-		} else if appraisal == 0 {
-			if outputWeights[control] == 0 { // still add belief
-				belief = belief + delta
-				uncertainty = uncertainty - delta
-			} else if outputWeights[control] == 1 { // add disbelief
-				disbelief = disbelief + delta
-				uncertainty = uncertainty - delta
-			} else if outputWeights[control] == 2 { // complete disbelief
-				belief = 0.0
-				disbelief = 1.0
-				uncertainty = 0.0
-				break // complete disbelief because negative evidence of critical securityControl
-			} else {
-				// Invalid weight
-				// TODO: Error handling
-			}
-		} else if appraisal == 1 {
-			belief = belief + delta
-			uncertainty = uncertainty - delta
-		} else {
-			// No evidence for the control, e.g. appraisal -2 or no evidence received -> Results in higher uncertainty
-		}
+/*
+defaultVehicleComputers returns a copy of the default configuration of both vehicle computers that can be adapted to the
+parameters of a session.
+*/
+func defaultVehicleComputers() []vehicleComputer {
+	return []vehicleComputer{
+		{trustee: "VC1", dti: &vc1DTI, existenceWeights: maps.Clone(vc1ExistenceWeights), outputWeights: maps.Clone(vc1OutputWeights)},
+		{trustee: "VC2", dti: &vc2DTI, existenceWeights: maps.Clone(vc2ExistenceWeights), outputWeights: maps.Clone(vc2OutputWeights)},
 	}
-
-	sl.Modify(belief, disbelief, uncertainty, sl.BaseRate())
-
-	return &sl
 }
 
-var trustSourceQuantifiers = []core.TrustSourceQuantifier{
-	{
-		Trustor:     "TAF",
-		Trustee:     "VC1",
-		Scope:       "VC1",
-		TrustSource: core.AIV,
-		Evidence:    []core.EvidenceType{core.AIV_SECURE_BOOT, core.AIV_SECURE_OTA, core.AIV_ACCESS_CONTROL, core.AIV_APPLICATION_ISOLATION, core.AIV_CONTROL_FLOW_INTEGRITY, core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION},
-		Quantifier: func(m map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
-			return quantifier(m, &vc1DTI, vc1ExistenceWeights, vc1OutputWeights)
-		},
-	},
-	{
-		Trustor:     "TAF",
-		Trustee:     "VC2",
-		Scope:       "VC2",
-		TrustSource: core.AIV,
-		Evidence:    []core.EvidenceType{core.AIV_SECURE_BOOT, core.AIV_SECURE_OTA, core.AIV_ACCESS_CONTROL, core.AIV_APPLICATION_ISOLATION, core.AIV_CONTROL_FLOW_INTEGRITY, core.AIV_CONFIGURATION_INTEGRITY_VERIFICATION},
-		Quantifier: func(m map[core.EvidenceType]interface{}) subjectivelogic.QueryableOpinion {
-			return quantifier(m, &vc2DTI, vc2ExistenceWeights, vc2OutputWeights)
-		},
-	},
+func createTrustSourceQuantifiers(vehicleComputers []vehicleComputer) ([]core.TrustSourceQuantifier, error) {
+	tsqs := make([]core.TrustSourceQuantifier, 0, len(vehicleComputers))
+	for _, vc := range vehicleComputers {
+		aivQuantifier, err := quantifier.NewSecurityControls(quantifier.SecurityControls{
+			ExistenceWeights: vc.existenceWeights,
+			OutputWeights:    vc.outputWeights,
+			Prior:            vc.dti,
+		})
+		if err != nil {
+			return nil, err
+		}
+		tsqs = append(tsqs, core.TrustSourceQuantifier{
+			Trustor:     "TAF",
+			Trustee:     vc.trustee,
+			Scope:       vc.trustee,
+			TrustSource: core.AIV,
+			Evidence:    aivEvidenceTypes,
+			Quantifier:  aivQuantifier,
+		})
+	}
+	return tsqs, nil
 }
+
+var trustSourceQuantifiers, _ = createTrustSourceQuantifiers(defaultVehicleComputers())
@@ -136,3 +136,26 @@ func printATLs(t *testing.T, atls map[string]subjectivelogic.QueryableOpinion, r
 		t.Log("[" + proposition + "]" + "\t" + opinion.String() + " \t Decision: " + decision + " (ATL:" + fmt.Sprintf("%.2f", trustdecision.ProjectProbability(opinion)) + " <=?=> RTL: " + fmt.Sprintf("%.2f", trustdecision.ProjectProbability(rtl)) + ")")
 	}
 }
+
+func TestSnapshot(t *testing.T) {
+	tmt := CreateTrustModelTemplate("VCM", "0.0.1", "Testing")
+	_, tmi, _, err := tmt.Spawn(nil, createTafContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmi.Initialize(nil)
+	opinion, _ := subjectivelogic.NewOpinion(0.5, 0.2, 0.3, 0.5)
+	tmi.Update(trustmodelupdate.CreateAtomicTrustOpinionUpdate(&opinion, "TAF", "VC2", core.AIV))
+
+	snapshot, err := tmi.(core.SnapshotableTrustModelInstance).Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, restored, _, _ := tmt.Spawn(nil, createTafContext())
+	if err := restored.(core.SnapshotableTrustModelInstance).Restore(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Version() != tmi.Version() || restored.Values()["VC2"][0].Opinion().Belief() != 0.5 {
+		t.Errorf("expected restored state, got version %d and values %v", restored.Version(), restored.Values())
+	}
+}